@@ -5,9 +5,12 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/defenseunicorns/pkg/helpers"
@@ -16,6 +19,7 @@ import (
 	"github.com/defenseunicorns/uds-cli/src/pkg/bundle"
 	"github.com/defenseunicorns/uds-cli/src/pkg/bundle/tui/deploy"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	zarfCommon "github.com/defenseunicorns/zarf/src/cmd/common"
 	zarfConfig "github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/pkg/message"
 	zarfTypes "github.com/defenseunicorns/zarf/src/types"
@@ -61,6 +65,10 @@ func setBundleFile(args []string) {
 		}
 		pathToBundleFile = filepath.Join(args[0])
 	}
+	// the bundle file was given with --file
+	if bundleCfg.CreateOpts.BundleFile != "" {
+		return
+	}
 	// Handle .yaml or .yml
 	bundleYml := strings.Replace(config.BundleYAML, ".yaml", ".yml", 1)
 	if _, err := os.Stat(filepath.Join(pathToBundleFile, config.BundleYAML)); err == nil {
@@ -98,4 +106,21 @@ func cliSetup(cmd *cobra.Command) {
 			message.Fatalf(err, "Error configuring logs")
 		}
 	}
+
+	if config.CommonOptions.CACert != "" {
+		if err := utils.TrustCACert(config.CommonOptions.CACert); err != nil {
+			message.Fatalf(err, lang.RootCmdErrCACert)
+		}
+	}
+}
+
+// interruptContext returns a context that's canceled on Ctrl-C or SIGTERM so in-flight transfers are aborted cleanly,
+// the global interrupt handler that exits right away is suppressed until stop restores the default signal handling
+func interruptContext() (context.Context, context.CancelFunc) {
+	zarfCommon.SuppressGlobalInterrupt = true
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	return ctx, func() {
+		stop()
+		zarfCommon.SuppressGlobalInterrupt = false
+	}
 }
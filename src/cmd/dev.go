@@ -64,7 +64,11 @@ var devDeployCmd = &cobra.Command{
 
 		// Create dev bundle
 		config.Dev = true
-		if err := bndlClient.Create(); err != nil {
+		ctx, stop := interruptContext()
+		err = bndlClient.Create(ctx)
+		// the deploy doesn't take a context, so Ctrl-C exits through the global interrupt handler again
+		stop()
+		if err != nil {
 			bndlClient.ClearPaths()
 			message.Fatalf(err, "Failed to create bundle: %s", err.Error())
 		}
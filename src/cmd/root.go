@@ -70,11 +70,15 @@ func init() {
 
 	v.SetDefault(V_LOG_LEVEL, "info")
 	v.SetDefault(V_ARCHITECTURE, "")
+	v.SetDefault(V_OS, "")
 	v.SetDefault(V_NO_LOG_FILE, false)
 	v.SetDefault(V_NO_PROGRESS, false)
 	v.SetDefault(V_INSECURE, false)
+	v.SetDefault(V_INSECURE_SKIP_TLS_VERIFY, false)
 	v.SetDefault(V_TMP_DIR, "")
 	v.SetDefault(V_BNDL_OCI_CONCURRENCY, 3)
+	v.SetDefault(V_OCI_RETRIES, 3)
+	v.SetDefault(V_BNDL_CREATE_MAX_CONCURRENCY, 1)
 	v.SetDefault(V_NO_TEA, false) // by default use the BubbleTea TUI
 
 	homeDir, _ := os.UserHomeDir()
@@ -82,11 +86,15 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", v.GetString(V_LOG_LEVEL), lang.RootCmdFlagLogLevel)
 	rootCmd.PersistentFlags().StringVarP(&config.CLIArch, "architecture", "a", v.GetString(V_ARCHITECTURE), lang.RootCmdFlagArch)
+	rootCmd.PersistentFlags().StringVar(&config.CLIOS, "os", v.GetString(V_OS), lang.RootCmdFlagOS)
 	rootCmd.PersistentFlags().BoolVar(&config.SkipLogFile, "no-log-file", v.GetBool(V_NO_LOG_FILE), lang.RootCmdFlagSkipLogFile)
 	rootCmd.PersistentFlags().BoolVar(&message.NoProgress, "no-progress", v.GetBool(V_NO_PROGRESS), lang.RootCmdFlagNoProgress)
 	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.CachePath, "uds-cache", v.GetString(V_UDS_CACHE), lang.RootCmdFlagCachePath)
 	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.TempDirectory, "tmpdir", v.GetString(V_TMP_DIR), lang.RootCmdFlagTempDir)
 	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.Insecure, "insecure", v.GetBool(V_INSECURE), lang.RootCmdFlagInsecure)
+	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.InsecureSkipTLSVerify, "insecure-skip-tls-verify", v.GetBool(V_INSECURE_SKIP_TLS_VERIFY), lang.RootCmdFlagInsecureSkipTLSVerify)
 	rootCmd.PersistentFlags().IntVar(&config.CommonOptions.OCIConcurrency, "oci-concurrency", v.GetInt(V_BNDL_OCI_CONCURRENCY), lang.CmdBundleFlagConcurrency)
+	rootCmd.PersistentFlags().IntVar(&config.CommonOptions.OCIRetries, "oci-retries", v.GetInt(V_OCI_RETRIES), lang.CmdBundleFlagRetries)
 	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.NoTea, "no-tea", v.GetBool(V_NO_TEA), lang.RootCmdNoTea)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.CACert, "ca-cert", v.GetString(V_CA_CERT), lang.RootCmdFlagCACert)
 }
@@ -41,11 +41,18 @@ var createCmd = &cobra.Command{
 			srcDir = args[0]
 		}
 		bundleCfg.CreateOpts.SourceDirectory = srcDir
+		if bundleCfg.CreateOpts.Quiet {
+			// info and debug messages and spinners are written at the info level, only warnings and errors are kept
+			message.SetLogLevel(message.WarnLevel)
+			message.NoProgress = true
+		}
 
 		bndlClient := bundle.NewOrDie(&bundleCfg)
 		defer bndlClient.ClearPaths()
 
-		if err := bndlClient.Create(); err != nil {
+		ctx, stop := interruptContext()
+		defer stop()
+		if err := bndlClient.Create(ctx); err != nil {
 			bndlClient.ClearPaths()
 			message.Fatalf(err, "Failed to create bundle: %s", err.Error())
 		}
@@ -57,6 +64,11 @@ var deployCmd = &cobra.Command{
 	Aliases: []string{"d"},
 	Short:   lang.CmdBundleDeployShort,
 	Args:    cobra.MaximumNArgs(1),
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		if cmd.Flag("json").Value.String() == "true" && cmd.Flag("dry-run").Value.String() == "false" {
+			message.Fatal(nil, "cannot use 'json' flag without 'dry-run' flag")
+		}
+	},
 	Run: func(_ *cobra.Command, args []string) {
 		bundleCfg.DeployOpts.Source = chooseBundle(args)
 		configureZarf()
@@ -72,6 +84,15 @@ var deployCmd = &cobra.Command{
 		bndlClient := bundle.NewOrDie(&bundleCfg)
 		defer bndlClient.ClearPaths()
 
+		// print what would be deployed without deploying it
+		if bundleCfg.DeployOpts.DryRun {
+			if err := bndlClient.DryRunDeploy(); err != nil {
+				bndlClient.ClearPaths()
+				message.Fatalf(err, "Failed to plan the bundle deployment: %s", err.Error())
+			}
+			return
+		}
+
 		// don't use bubbletea if --no-tea flag is set
 		if config.CommonOptions.NoTea {
 			deployWithoutTea(bndlClient)
@@ -94,6 +115,35 @@ var deployCmd = &cobra.Command{
 	},
 }
 
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback [BUNDLE_NAME]",
+	Short: lang.CmdBundleRollbackShort,
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		bundleCfg.RollbackOpts.BundleName = args[0]
+		configureZarf()
+
+		// load uds-config if it exists, the rollback is deployed with the same variables as a deploy
+		if v.ConfigFileUsed() != "" {
+			if err := loadViperConfig(); err != nil {
+				message.Fatalf(err, "Failed to load uds-config: %s", err.Error())
+				return
+			}
+		}
+		bndlClient := bundle.NewOrDie(&bundleCfg)
+		defer bndlClient.ClearPaths()
+
+		// create an empty program and kill it, this makes Program.Send a no-op
+		deploy.Program = tea.NewProgram(nil)
+		deploy.Program.Kill()
+
+		if err := bndlClient.Rollback(); err != nil {
+			bndlClient.ClearPaths()
+			message.Fatalf(err, "Failed to roll back bundle: %s", err.Error())
+		}
+	},
+}
+
 var inspectCmd = &cobra.Command{
 	Use:     "inspect [BUNDLE_TARBALL|OCI_REF]",
 	Aliases: []string{"i"},
@@ -103,6 +153,16 @@ var inspectCmd = &cobra.Command{
 		if cmd.Flag("extract").Value.String() == "true" && cmd.Flag("sbom").Value.String() == "false" {
 			message.Fatal(nil, "cannot use 'extract' flag without 'sbom' flag")
 		}
+		listImages, showSignature := cmd.Flag("list-images").Value.String() == "true", cmd.Flag("show-signature").Value.String() == "true"
+		if cmd.Flag("json").Value.String() == "true" && !listImages && !showSignature {
+			message.Fatal(nil, "cannot use 'json' flag without 'list-images' or 'show-signature' flag")
+		}
+		if listImages && showSignature {
+			message.Fatal(nil, "cannot use 'list-images' flag with 'show-signature' flag")
+		}
+		if cmd.Flag("extract-layer").Changed && (listImages || showSignature) {
+			message.Fatal(nil, "cannot use 'extract-layer' flag with 'list-images' or 'show-signature' flag")
+		}
 	},
 	Run: func(_ *cobra.Command, args []string) {
 		bundleCfg.InspectOpts.Source = chooseBundle(args)
@@ -118,6 +178,87 @@ var inspectCmd = &cobra.Command{
 	},
 }
 
+var diffCmd = &cobra.Command{
+	Use:   "diff [BUNDLE_TARBALL|OCI_REF] [BUNDLE_TARBALL|OCI_REF]",
+	Short: lang.CmdBundleDiffShort,
+	Args:  cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		bundleCfg.DiffOpts.From = args[0]
+		bundleCfg.DiffOpts.To = args[1]
+		configureZarf()
+
+		bndlClient := bundle.NewOrDie(&bundleCfg)
+		defer bndlClient.ClearPaths()
+
+		if err := bndlClient.Diff(); err != nil {
+			bndlClient.ClearPaths()
+			message.Fatalf(err, "Failed to diff bundles: %s", err.Error())
+		}
+	},
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [OCI_REF]",
+	Short: lang.CmdBundleVerifyShort,
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		bundleCfg.VerifyOpts.Source = args[0]
+		configureZarf()
+
+		bndlClient := bundle.NewOrDie(&bundleCfg)
+		defer bndlClient.ClearPaths()
+
+		if err := bndlClient.Verify(); err != nil {
+			bndlClient.ClearPaths()
+			// each class of failure has its own exit code so scripts can branch on it
+			var verifyErr *bundle.VerifyError
+			if errors.As(err, &verifyErr) {
+				message.WarnErrf(err, "Failed to verify bundle: %s", err.Error())
+				os.Exit(verifyErr.ExitCode)
+			}
+			message.Fatalf(err, "Failed to verify bundle: %s", err.Error())
+		}
+	},
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [BUNDLE_YAML|DIRECTORY]",
+	Short: lang.CmdBundleLintShort,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		bundleCfg.LintOpts.Source = config.BundleYAML
+		if len(args) > 0 {
+			bundleCfg.LintOpts.Source = args[0]
+		}
+
+		bndlClient := bundle.NewOrDie(&bundleCfg)
+		defer bndlClient.ClearPaths()
+
+		if err := bndlClient.Lint(); err != nil {
+			bndlClient.ClearPaths()
+			message.Fatalf(err, "Failed to lint bundle: %s", err.Error())
+		}
+	},
+}
+
+var versionsCmd = &cobra.Command{
+	Use:   "versions [OCI_REPOSITORY]",
+	Short: lang.CmdBundleVersionsShort,
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		bundleCfg.VersionsOpts.Source = args[0]
+		configureZarf()
+
+		bndlClient := bundle.NewOrDie(&bundleCfg)
+		defer bndlClient.ClearPaths()
+
+		if err := bndlClient.Versions(); err != nil {
+			bndlClient.ClearPaths()
+			message.Fatalf(err, "Failed to list bundle versions: %s", err.Error())
+		}
+	},
+}
+
 var removeCmd = &cobra.Command{
 	Use:     "remove [BUNDLE_TARBALL|OCI_REF]",
 	Aliases: []string{"r"},
@@ -161,6 +302,60 @@ var publishCmd = &cobra.Command{
 	},
 }
 
+var copyCmd = &cobra.Command{
+	Use:   "copy [OCI_REF] [OCI_REF]",
+	Short: lang.CmdCopyShort,
+	Args:  cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		bundleCfg.CopyOpts.Source = args[0]
+		bundleCfg.CopyOpts.Destination = args[1]
+		configureZarf()
+		bndlClient := bundle.NewOrDie(&bundleCfg)
+		defer bndlClient.ClearPaths()
+
+		if err := bndlClient.Copy(); err != nil {
+			bndlClient.ClearPaths()
+			message.Fatalf(err, "Failed to copy bundle: %s", err.Error())
+		}
+	},
+}
+
+var retagCmd = &cobra.Command{
+	Use:   "retag [OCI_REF] [TAG]",
+	Short: lang.CmdBundleRetagShort,
+	Args:  cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		bundleCfg.RetagOpts.Source = args[0]
+		bundleCfg.RetagOpts.Tag = args[1]
+		configureZarf()
+		bndlClient := bundle.NewOrDie(&bundleCfg)
+		defer bndlClient.ClearPaths()
+
+		if err := bndlClient.Retag(); err != nil {
+			bndlClient.ClearPaths()
+			message.Fatalf(err, "Failed to retag bundle: %s", err.Error())
+		}
+	},
+}
+
+var resignCmd = &cobra.Command{
+	Use:   "resign [OCI_REF]",
+	Short: lang.CmdBundleResignShort,
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		bundleCfg.ResignOpts.Source = args[0]
+		configureZarf()
+
+		bndlClient := bundle.NewOrDie(&bundleCfg)
+		defer bndlClient.ClearPaths()
+
+		if err := bndlClient.Resign(); err != nil {
+			bndlClient.ClearPaths()
+			message.Fatalf(err, "Failed to resign bundle: %s", err.Error())
+		}
+	},
+}
+
 var pullCmd = &cobra.Command{
 	Use:     "pull [OCI_REF]",
 	Aliases: []string{"p"},
@@ -246,23 +441,105 @@ func init() {
 	// create cmd flags
 	rootCmd.AddCommand(createCmd)
 	createCmd.Flags().BoolVarP(&config.CommonOptions.Confirm, "confirm", "c", false, lang.CmdBundleRemoveFlagConfirm)
-	createCmd.Flags().StringVarP(&bundleCfg.CreateOpts.Output, "output", "o", v.GetString(V_BNDL_CREATE_OUTPUT), lang.CmdBundleCreateFlagOutput)
+	createCmd.Flags().StringVarP(&bundleCfg.CreateOpts.BundleFile, "file", "f", "", lang.CmdBundleCreateFlagFile)
+	createCmd.Flags().StringSliceVarP(&bundleCfg.CreateOpts.Outputs, "output", "o", v.GetStringSlice(V_BNDL_CREATE_OUTPUT), lang.CmdBundleCreateFlagOutput)
 	createCmd.Flags().StringVarP(&bundleCfg.CreateOpts.SigningKeyPath, "signing-key", "k", v.GetString(V_BNDL_CREATE_SIGNING_KEY), lang.CmdBundleCreateFlagSigningKey)
 	createCmd.Flags().StringVarP(&bundleCfg.CreateOpts.SigningKeyPassword, "signing-key-password", "p", v.GetString(V_BNDL_CREATE_SIGNING_KEY_PASSWORD), lang.CmdBundleCreateFlagSigningKeyPassword)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.VerifySignatureKey, "verify-signature-key", v.GetString(V_BNDL_CREATE_VERIFY_SIGNATURE_KEY), lang.CmdBundleCreateFlagVerifySignatureKey)
+	createCmd.Flags().IntVar(&bundleCfg.CreateOpts.MaxConcurrency, "max-concurrency", v.GetInt(V_BNDL_CREATE_MAX_CONCURRENCY), lang.CmdBundleCreateFlagMaxConcurrency)
+	createCmd.Flags().IntVar(&bundleCfg.CreateOpts.LayerConcurrency, "layer-concurrency", v.GetInt(V_BNDL_CREATE_LAYER_CONCURRENCY), lang.CmdBundleCreateFlagLayerConcurrency)
+	createCmd.Flags().IntVar(&bundleCfg.CreateOpts.ConcurrencyLimit, "concurrency-limit", v.GetInt(V_BNDL_CREATE_CONCURRENCY_LIMIT), lang.CmdBundleCreateFlagConcurrencyLimit)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.DryRun, "dry-run", false, lang.CmdBundleCreateFlagDryRun)
+	createCmd.Flags().StringSliceVar(&bundleCfg.CreateOpts.VerifySourceKeys, "verify-source-keys", []string{}, lang.CmdBundleCreateFlagVerifySourceKeys)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.OutputFormat, "output-format", "", lang.CmdBundleCreateFlagOutputFormat)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.OutputDigestOnly, "output-digest-only", false, lang.CmdBundleCreateFlagOutputDigestOnly)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.SignatureReferrer, "signature-referrer", false, lang.CmdBundleCreateFlagSignatureReferrer)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.DetachedSignature, "detached-signature", false, lang.CmdBundleCreateFlagDetachedSignature)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.SBOMFormat, "sbom-format", "", lang.CmdBundleCreateFlagSBOMFormat)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.SBOMReferrers, "sbom-referrers", false, lang.CmdBundleCreateFlagSBOMReferrers)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.SignKeyless, "sign-with-cosign-keyless", false, lang.CmdBundleCreateFlagSignKeyless)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.Platform, "platform", "", lang.CmdBundleCreateFlagPlatform)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.PlatformFromPackage, "platform-from-package", false, lang.CmdBundleCreateFlagPlatformFromPackage)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.SrcCreds, "src-creds", v.GetString(V_BNDL_CREATE_SRC_CREDS), lang.CmdBundleCreateFlagSrcCreds)
+	createCmd.Flags().StringSliceVar(&bundleCfg.CreateOpts.SourceMirrors, "source-mirrors", v.GetStringSlice(V_BNDL_CREATE_SOURCE_MIRRORS), lang.CmdBundleCreateFlagSourceMirrors)
+	createCmd.Flags().StringToStringVar(&bundleCfg.CreateOpts.RegistryOverrides, "registry-override", v.GetStringMapString(V_BNDL_CREATE_REGISTRY_OVERRIDES), lang.CmdBundleCreateFlagRegistryOverride)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.DstCreds, "dst-creds", v.GetString(V_BNDL_CREATE_DST_CREDS), lang.CmdBundleCreateFlagDstCreds)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.RequireSignature, "require-signature", v.GetBool(V_BNDL_CREATE_REQUIRE_SIGNATURE), lang.CmdBundleCreateFlagRequireSignature)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.RequireDigests, "require-digests", v.GetBool(V_BNDL_CREATE_REQUIRE_DIGESTS), lang.CmdBundleCreateFlagRequireDigests)
+	createCmd.Flags().StringSliceVar(&bundleCfg.CreateOpts.AllowedMediaTypes, "allowed-media-types", v.GetStringSlice(V_BNDL_CREATE_ALLOWED_MEDIA_TYPES), lang.CmdBundleCreateFlagAllowedMediaTypes)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.VulnDenyList, "vuln-deny-list", v.GetString(V_BNDL_CREATE_VULN_DENY_LIST), lang.CmdBundleCreateFlagVulnDenyList)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.FailOnVuln, "fail-on-vuln", v.GetBool(V_BNDL_CREATE_FAIL_ON_VULN), lang.CmdBundleCreateFlagFailOnVuln)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.UncompressedSize, "uncompressed-size", v.GetString(V_BNDL_CREATE_UNCOMPRESSED_SIZE), lang.CmdBundleCreateFlagUncompressedSize)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.PruneOldVersions, "prune-old-versions", v.GetBool(V_BNDL_CREATE_PRUNE_OLD_VERSIONS), lang.CmdBundleCreateFlagPruneOldVersions)
+	createCmd.Flags().IntVar(&bundleCfg.CreateOpts.PruneKeep, "keep", v.GetInt(V_BNDL_CREATE_KEEP), lang.CmdBundleCreateFlagKeep)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.AllowDuplicateNames, "allow-duplicate-names", false, lang.CmdBundleCreateFlagAllowDuplicateNames)
+	createCmd.Flags().BoolVarP(&bundleCfg.CreateOpts.Quiet, "quiet", "q", v.GetBool(V_BNDL_CREATE_QUIET), lang.CmdBundleCreateFlagQuiet)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.Provenance, "provenance", false, lang.CmdBundleCreateFlagProvenance)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.NoSignaturePrompt, "no-signature-prompt", false, lang.CmdBundleCreateFlagNoSignaturePrompt)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.NoCache, "no-cache", false, lang.CmdBundleCreateFlagNoCache)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.MetadataMediaType, "metadata-media-type", v.GetString(V_BNDL_CREATE_METADATA_MEDIA_TYPE), lang.CmdBundleCreateFlagMetadataMediaType)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.ConfigMediaType, "config-media-type", v.GetString(V_BNDL_CREATE_CONFIG_MEDIA_TYPE), lang.CmdBundleCreateFlagConfigMediaType)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.Force, "force", false, lang.CmdBundleCreateFlagForce)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.Registry, "registry", "", lang.CmdBundleCreateFlagRegistry)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.DigestTag, "digest-tag", "", lang.CmdBundleCreateFlagDigestTag)
+	createCmd.Flags().BoolVar(&bundleCfg.CreateOpts.CleanupOnFailure, "cleanup-on-failure", false, lang.CmdBundleCreateFlagCleanupOnFailure)
+	createCmd.Flags().IntVar(&bundleCfg.CreateOpts.CompressionLevel, "compression-level", 0, lang.CmdBundleCreateFlagCompressionLevel)
+	createCmd.Flags().DurationVar(&bundleCfg.CreateOpts.Timeout, "timeout", 0, lang.CmdBundleCreateFlagTimeout)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.MetricsFile, "metrics-file", "", lang.CmdBundleCreateFlagMetricsFile)
+	createCmd.Flags().StringVar(&bundleCfg.CreateOpts.DigestFile, "digest-file", "", lang.CmdBundleCreateFlagDigestFile)
 
 	// deploy cmd flags
 	rootCmd.AddCommand(deployCmd)
 	deployCmd.Flags().StringToStringVar(&bundleCfg.DeployOpts.SetVariables, "set", nil, lang.CmdBundleDeployFlagSet)
 	deployCmd.Flags().BoolVarP(&config.CommonOptions.Confirm, "confirm", "c", false, lang.CmdBundleDeployFlagConfirm)
 	deployCmd.Flags().StringArrayVarP(&bundleCfg.DeployOpts.Packages, "packages", "p", []string{}, lang.CmdBundleDeployFlagPackages)
+	deployCmd.Flags().StringSliceVar(&bundleCfg.DeployOpts.SkipPackages, "skip-packages", []string{}, lang.CmdBundleDeployFlagSkipPackages)
 	deployCmd.Flags().BoolVarP(&bundleCfg.DeployOpts.Resume, "resume", "r", false, lang.CmdBundleDeployFlagResume)
+	deployCmd.Flags().StringToStringVar(&bundleCfg.DeployOpts.VarsFiles, "vars-file", nil, lang.CmdBundleDeployFlagVarsFile)
 	deployCmd.Flags().IntVar(&bundleCfg.DeployOpts.Retries, "retries", 3, lang.CmdBundleDeployFlagRetries)
+	deployCmd.Flags().BoolVar(&bundleCfg.DeployOpts.DryRun, "dry-run", false, lang.CmdBundleDeployFlagDryRun)
+	deployCmd.Flags().BoolVar(&bundleCfg.DeployOpts.JSON, "json", false, lang.CmdBundleDeployFlagJSON)
+
+	// rollback cmd flags
+	rootCmd.AddCommand(rollbackCmd)
+	rollbackCmd.Flags().BoolVarP(&config.CommonOptions.Confirm, "confirm", "c", false, lang.CmdBundleRollbackFlagConfirm)
+	rollbackCmd.Flags().StringVarP(&bundleCfg.DeployOpts.PublicKeyPath, "key", "k", "", lang.CmdBundleRollbackFlagKey)
+	rollbackCmd.Flags().IntVar(&bundleCfg.DeployOpts.Retries, "retries", 3, lang.CmdBundleDeployFlagRetries)
 
 	// inspect cmd flags
 	rootCmd.AddCommand(inspectCmd)
 	inspectCmd.Flags().BoolVarP(&bundleCfg.InspectOpts.IncludeSBOM, "sbom", "s", false, lang.CmdPackageInspectFlagSBOM)
 	inspectCmd.Flags().BoolVarP(&bundleCfg.InspectOpts.ExtractSBOM, "extract", "e", false, lang.CmdPackageInspectFlagExtractSBOM)
 	inspectCmd.Flags().StringVarP(&bundleCfg.InspectOpts.PublicKeyPath, "key", "k", v.GetString(V_BNDL_INSPECT_KEY), lang.CmdBundleInspectFlagKey)
+	inspectCmd.Flags().BoolVar(&bundleCfg.InspectOpts.ListImages, "list-images", false, lang.CmdBundleInspectFlagListImages)
+	inspectCmd.Flags().BoolVar(&bundleCfg.InspectOpts.ShowSignature, "show-signature", false, lang.CmdBundleInspectFlagShowSignature)
+	inspectCmd.Flags().BoolVar(&bundleCfg.InspectOpts.JSON, "json", false, lang.CmdBundleInspectFlagJSON)
+	inspectCmd.Flags().StringArrayVar(&bundleCfg.InspectOpts.ExtractLayers, "extract-layer", nil, lang.CmdBundleInspectFlagExtractLayer)
+	inspectCmd.Flags().BoolVar(&bundleCfg.InspectOpts.VerifyAll, "verify-all", false, lang.CmdBundleInspectFlagVerifyAll)
+	inspectCmd.Flags().StringSliceVar(&bundleCfg.InspectOpts.PublicKeyPaths, "keys", v.GetStringSlice(V_BNDL_INSPECT_KEYS), lang.CmdBundleInspectFlagKeys)
+
+	// diff cmd flags
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().BoolVar(&bundleCfg.DiffOpts.JSON, "json", false, lang.CmdBundleDiffFlagJSON)
+
+	// lint cmd
+	rootCmd.AddCommand(lintCmd)
+
+	// versions cmd flags
+	rootCmd.AddCommand(versionsCmd)
+	versionsCmd.Flags().BoolVar(&bundleCfg.VersionsOpts.JSON, "json", false, lang.CmdBundleVersionsFlagJSON)
+
+	// verify cmd flags
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().StringVarP(&bundleCfg.VerifyOpts.PublicKeyPath, "key", "k", v.GetString(V_BNDL_VERIFY_KEY), lang.CmdBundleVerifyFlagKey)
+
+	// resign cmd flags
+	rootCmd.AddCommand(resignCmd)
+	resignCmd.Flags().StringVar(&bundleCfg.ResignOpts.SigningKeyPath, "signing-key", v.GetString(V_BNDL_RESIGN_SIGNING_KEY), lang.CmdBundleResignFlagSigningKey)
+	resignCmd.Flags().StringVar(&bundleCfg.ResignOpts.SigningKeyPassword, "signing-key-password", v.GetString(V_BNDL_RESIGN_SIGNING_KEY_PASSWORD), lang.CmdBundleResignFlagSigningKeyPassword)
+	resignCmd.Flags().BoolVar(&bundleCfg.ResignOpts.SignKeyless, "sign-with-cosign-keyless", false, lang.CmdBundleResignFlagSignKeyless)
+	resignCmd.Flags().StringVarP(&bundleCfg.ResignOpts.PublicKeyPath, "key", "k", v.GetString(V_BNDL_RESIGN_KEY), lang.CmdBundleResignFlagKey)
+	resignCmd.Flags().StringVar(&bundleCfg.ResignOpts.SignaturePath, "signature", "", lang.CmdBundleResignFlagSignature)
 
 	// remove cmd flags
 	rootCmd.AddCommand(removeCmd)
@@ -274,10 +551,19 @@ func init() {
 	// publish cmd flags
 	rootCmd.AddCommand(publishCmd)
 
+	// copy cmd flags
+	rootCmd.AddCommand(copyCmd)
+
+	// retag cmd flags
+	rootCmd.AddCommand(retagCmd)
+	retagCmd.Flags().BoolVar(&bundleCfg.RetagOpts.Force, "force", false, lang.CmdBundleRetagFlagForce)
+
 	// pull cmd flags
 	rootCmd.AddCommand(pullCmd)
 	pullCmd.Flags().StringVarP(&bundleCfg.PullOpts.OutputDirectory, "output", "o", v.GetString(V_BNDL_PULL_OUTPUT), lang.CmdBundlePullFlagOutput)
 	pullCmd.Flags().StringVarP(&bundleCfg.PullOpts.PublicKeyPath, "key", "k", v.GetString(V_BNDL_PULL_KEY), lang.CmdBundlePullFlagKey)
+	pullCmd.Flags().StringArrayVarP(&bundleCfg.PullOpts.Packages, "packages", "p", []string{}, lang.CmdBundlePullFlagPackages)
+	pullCmd.Flags().StringVar(&bundleCfg.PullOpts.Since, "since", "", lang.CmdBundlePullFlagSince)
 
 	// logs cmd
 	rootCmd.AddCommand(logsCmd)
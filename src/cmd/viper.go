@@ -17,23 +17,55 @@ import (
 
 const (
 	// Root config keys
-	V_LOG_LEVEL            = "options.log_level"
-	V_ARCHITECTURE         = "options.architecture"
-	V_NO_LOG_FILE          = "options.no_log_file"
-	V_NO_PROGRESS          = "options.no_progress"
-	V_UDS_CACHE            = "options.uds_cache"
-	V_TMP_DIR              = "options.tmp_dir"
-	V_INSECURE             = "options.insecure"
-	V_BNDL_OCI_CONCURRENCY = "options.oci_concurrency"
-	V_NO_TEA               = "options.no_tea"
+	V_LOG_LEVEL                = "options.log_level"
+	V_ARCHITECTURE             = "options.architecture"
+	V_OS                       = "options.os"
+	V_NO_LOG_FILE              = "options.no_log_file"
+	V_NO_PROGRESS              = "options.no_progress"
+	V_UDS_CACHE                = "options.uds_cache"
+	V_TMP_DIR                  = "options.tmp_dir"
+	V_INSECURE                 = "options.insecure"
+	V_INSECURE_SKIP_TLS_VERIFY = "options.insecure_skip_tls_verify"
+	V_BNDL_OCI_CONCURRENCY     = "options.oci_concurrency"
+	V_OCI_RETRIES              = "options.oci_retries"
+	V_NO_TEA                   = "options.no_tea"
+	V_CA_CERT                  = "options.ca_cert"
 
 	// Bundle create config keys
 	V_BNDL_CREATE_OUTPUT               = "create.output"
 	V_BNDL_CREATE_SIGNING_KEY          = "create.signing-key"
+	V_BNDL_CREATE_VERIFY_SIGNATURE_KEY = "create.verify-signature-key"
 	V_BNDL_CREATE_SIGNING_KEY_PASSWORD = "create.signing-key-password"
+	V_BNDL_CREATE_MAX_CONCURRENCY      = "create.max-concurrency"
+	V_BNDL_CREATE_LAYER_CONCURRENCY    = "create.layer-concurrency"
+	V_BNDL_CREATE_CONCURRENCY_LIMIT    = "create.concurrency-limit"
+	V_BNDL_CREATE_SRC_CREDS            = "create.src-creds"
+	V_BNDL_CREATE_DST_CREDS            = "create.dst-creds"
+	V_BNDL_CREATE_REQUIRE_SIGNATURE    = "create.require-signature"
+	V_BNDL_CREATE_REQUIRE_DIGESTS      = "create.require-digests"
+	V_BNDL_CREATE_ALLOWED_MEDIA_TYPES  = "create.allowed-media-types"
+	V_BNDL_CREATE_VULN_DENY_LIST       = "create.vuln-deny-list"
+	V_BNDL_CREATE_FAIL_ON_VULN         = "create.fail-on-vuln"
+	V_BNDL_CREATE_UNCOMPRESSED_SIZE    = "create.uncompressed-size"
+	V_BNDL_CREATE_PRUNE_OLD_VERSIONS   = "create.prune-old-versions"
+	V_BNDL_CREATE_KEEP                 = "create.keep"
+	V_BNDL_CREATE_SOURCE_MIRRORS       = "create.source-mirrors"
+	V_BNDL_CREATE_REGISTRY_OVERRIDES   = "create.registry-overrides"
+	V_BNDL_CREATE_QUIET                = "create.quiet"
+	V_BNDL_CREATE_METADATA_MEDIA_TYPE  = "create.metadata-media-type"
+	V_BNDL_CREATE_CONFIG_MEDIA_TYPE    = "create.config-media-type"
 
 	// Bundle inspect config keys
-	V_BNDL_INSPECT_KEY = "bundle.inspect.key"
+	V_BNDL_INSPECT_KEY  = "bundle.inspect.key"
+	V_BNDL_INSPECT_KEYS = "bundle.inspect.keys"
+
+	// Bundle verify config keys
+	V_BNDL_VERIFY_KEY = "bundle.verify.key"
+
+	// Bundle resign config keys
+	V_BNDL_RESIGN_SIGNING_KEY          = "bundle.resign.signing-key"
+	V_BNDL_RESIGN_SIGNING_KEY_PASSWORD = "bundle.resign.signing-key-password"
+	V_BNDL_RESIGN_KEY                  = "bundle.resign.key"
 
 	// Bundle pull config keys
 	V_BNDL_PULL_OUTPUT = "bundle.pull.output"
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/types"
 	zarfTypes "github.com/defenseunicorns/zarf/src/types"
 )
@@ -34,9 +35,80 @@ const (
 	// BundleSBOM is the name of the untarred folder containing the bundle's SBOM
 	BundleSBOM = "bundle-sboms"
 
+	// BundleSBOMJSON is the name of the optional bundle-level SBOM describing the bundle's packages
+	BundleSBOMJSON = "bundle.sbom.json"
+
 	// BundleYAMLSignature is the name of the bundle's metadata signature file
 	BundleYAMLSignature = "uds-bundle.yaml.sig"
 
+	// BundleArtifactType is the artifact type of a bundle's root manifest, registries use it to categorize the bundle
+	BundleArtifactType = "application/vnd.uds.bundle.v1+json"
+
+	// BundleSignatureArtifactType is the artifact type of a bundle signature attached with the OCI referrers API
+	BundleSignatureArtifactType = "application/vnd.uds.bundle.signature"
+
+	// PackageSBOMsArtifactType is the artifact type of a Zarf pkg's sboms.tar attached to a bundle with the OCI
+	// referrers API
+	PackageSBOMsArtifactType = "application/vnd.uds.package.sboms.v1.tar"
+
+	// PackageNameAnnotation is the referrer manifest annotation holding the name of the Zarf pkg the referrer describes
+	PackageNameAnnotation = "dev.uds.package.name"
+
+	// BundleYAMLCertificate is the name of the Fulcio certificate of a keyless bundle signature when it's loaded
+	BundleYAMLCertificate = "uds-bundle.yaml.pem"
+
+	// BundleSignatureCertificateAnnotation is the signature layer annotation holding the PEM encoded Fulcio certificate
+	// of a keyless bundle signature
+	BundleSignatureCertificateAnnotation = "dev.uds.bundle.signature.certificate"
+
+	// BundleSignatureRekorLogIndexAnnotation is the signature layer annotation holding the index of a keyless bundle
+	// signature's Rekor entry
+	BundleSignatureRekorLogIndexAnnotation = "dev.uds.bundle.signature.rekor.logIndex"
+
+	// BundleSignatureRekorLogIDAnnotation is the signature layer annotation holding the ID of the Rekor log a keyless
+	// bundle signature was recorded in
+	BundleSignatureRekorLogIDAnnotation = "dev.uds.bundle.signature.rekor.logID"
+
+	// BundleSignatureDigestAnnotation is the root manifest annotation holding the digest of a bundle signature that's
+	// pushed as a blob instead of as a layer of the root manifest
+	BundleSignatureDigestAnnotation = "dev.uds.bundle.signature.digest"
+
+	// BundlePackagesAnnotation is the root manifest annotation holding a JSON array of the name, ref and manifest digest
+	// of each Zarf pkg in the bundle
+	BundlePackagesAnnotation = "dev.uds.bundle.packages"
+
+	// BundleUncompressedSizeAnnotation is the root manifest annotation holding the total uncompressed size in bytes of
+	// the layers of the bundle's Zarf pkgs
+	BundleUncompressedSizeAnnotation = "dev.uds.bundle.uncompressed.size"
+
+	// BundleUncompressedSizeEstimatedAnnotation is the root manifest annotation set to true when the uncompressed size
+	// was estimated from the compressed size of the image layers instead of counted
+	BundleUncompressedSizeEstimatedAnnotation = "dev.uds.bundle.uncompressed.size.estimated"
+
+	// BundleExtraFileAnnotation is the layer annotation marking one of the extra files listed in the bundle's extraFiles,
+	// the layer's title is the file's path
+	BundleExtraFileAnnotation = "dev.uds.bundle.extra-file"
+
+	// DeployRecordPrefix is the prefix of the secret in the Zarf namespace that records a bundle's deploys
+	DeployRecordPrefix = "uds-deploy-"
+
+	// DeployRecordKey is the key of the deploy record secret holding the bundle's latest deploy
+	DeployRecordKey = "deploy"
+
+	// RollbackRecordKey is the key of the deploy record secret holding the deploy that preceded the latest one, it's
+	// what uds rollback redeploys
+	RollbackRecordKey = "rollback"
+
+	// CompressedLayerMediaType is the media type of a Zarf pkg layer that was compressed with zstd when the bundle was
+	// created, it's decompressed back to the original layer when the pkg is pulled
+	CompressedLayerMediaType = "application/vnd.uds.layer.v1.blob+zstd"
+
+	// LayerUncompressedDigestAnnotation is the compressed layer annotation holding the digest of the original layer
+	LayerUncompressedDigestAnnotation = "dev.uds.layer.uncompressed.digest"
+
+	// LayerUncompressedSizeAnnotation is the compressed layer annotation holding the size of the original layer
+	LayerUncompressedSizeAnnotation = "dev.uds.layer.uncompressed.size"
+
 	// PublicKeyFile is the name of the public key file
 	PublicKeyFile = "public.key"
 
@@ -49,11 +121,24 @@ const (
 	// UDSCacheLayers is the directory in the cache containing cached bundle layers
 	UDSCacheLayers = "layers"
 
+	// UDSCacheManifests is the directory in the cache containing cached Zarf pkg root manifests
+	UDSCacheManifests = "manifests"
+
 	// EnvVarPrefix is the prefix for environment variables to override bundle helm variables
 	EnvVarPrefix = "UDS_"
 
+	// EnvVarBundleVarPrefix is the prefix for environment variables that override the bundle's variable defaults, they
+	// take precedence over the other UDS_ environment variables
+	EnvVarBundleVarPrefix = "UDS_VAR_"
+
 	// CachedLogs is a file containing cached logs
 	CachedLogs = "recent-logs"
+
+	// BundleConfigMediaType is the default media type of the bundle's manifest config
+	BundleConfigMediaType = "application/vnd.uds.bundle.config.v1+json"
+
+	// PlatformAll creates a multi-arch bundle for every arch in BundlePlatforms
+	PlatformAll = "all"
 )
 
 var (
@@ -66,6 +151,9 @@ var (
 	// CLIArch is the computer architecture of the device executing the CLI commands
 	CLIArch string
 
+	// CLIOS is the OS of the platform UDS bundles are created for and fetched with
+	CLIOS string
+
 	// SkipLogFile is a flag to skip logging to a file
 	SkipLogFile bool
 
@@ -97,9 +185,24 @@ func GetArch(archs ...string) string {
 	return runtime.GOARCH
 }
 
+// GetOS returns the OS of a bundle's platform based on a priority list with options for overriding, bundles are
+// multi-OS by default since their Zarf packages are
+func GetOS(oses ...string) string {
+	priority := append([]string{CLIOS}, oses...)
+	for _, os := range priority {
+		if os != "" {
+			return os
+		}
+	}
+	return oci.MultiOS
+}
+
 var (
 	// BundleAlwaysPull is a list of paths that will always be pulled from the remote repository.
-	BundleAlwaysPull = []string{BundleYAML, BundleYAMLSignature}
+	BundleAlwaysPull = []string{BundleYAML, BundleYAMLSignature, BundleSBOMJSON}
+
+	// BundlePlatforms are the archs a multi-arch bundle is created for
+	BundlePlatforms = []string{"amd64", "arm64"}
 )
 
 // DefaultZarfInitOptions set these in the case of deploying a Zarf init pkg
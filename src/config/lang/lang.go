@@ -6,16 +6,20 @@ package lang
 
 const (
 	// root UDS-CLI cmds
-	RootCmdShort              = "CLI for UDS Bundles"
-	RootCmdFlagSkipLogFile    = "Disable log file creation"
-	RootCmdFlagNoProgress     = "Disable fancy UI progress bars, spinners, logos, etc"
-	RootCmdFlagCachePath      = "Specify the location of the Zarf cache directory"
-	RootCmdFlagTempDir        = "Specify the temporary directory to use for intermediate files"
-	RootCmdFlagInsecure       = "Allow access to insecure registries and disable other recommended security enforcements such as package checksum and signature validation. This flag should only be used if you have a specific reason and accept the reduced security posture."
-	RootCmdFlagLogLevel       = "Log level when running UDS-CLI. Valid options are: warn, info, debug, trace"
-	RootCmdErrInvalidLogLevel = "Invalid log level. Valid options are: warn, info, debug, trace."
-	RootCmdFlagArch           = "Architecture for UDS bundles and Zarf packages"
-	RootCmdNoTea              = "Don't use the BubbleTea TUI"
+	RootCmdShort                     = "CLI for UDS Bundles"
+	RootCmdFlagSkipLogFile           = "Disable log file creation"
+	RootCmdFlagNoProgress            = "Disable fancy UI progress bars, spinners, logos, etc"
+	RootCmdFlagCachePath             = "Specify the location of the Zarf cache directory"
+	RootCmdFlagTempDir               = "Specify the temporary directory to use for intermediate files"
+	RootCmdFlagInsecureSkipTLSVerify = "Skip verifying the TLS certificates of OCI registries while still requiring HTTPS, unlike --insecure plain HTTP is not allowed"
+	RootCmdFlagInsecure              = "Allow access to insecure registries and disable other recommended security enforcements such as package checksum and signature validation. This flag should only be used if you have a specific reason and accept the reduced security posture."
+	RootCmdFlagLogLevel              = "Log level when running UDS-CLI. Valid options are: warn, info, debug, trace"
+	RootCmdErrInvalidLogLevel        = "Invalid log level. Valid options are: warn, info, debug, trace."
+	RootCmdFlagArch                  = "Architecture for UDS bundles and Zarf packages"
+	RootCmdFlagOS                    = "OS of the platform UDS bundles are created for and fetched with, defaults to multi"
+	RootCmdNoTea                     = "Don't use the BubbleTea TUI"
+	RootCmdFlagCACert                = "Path to a PEM encoded CA certificate to trust in addition to the system roots when connecting to OCI registries, e.g. the CA of a TLS intercepting proxy"
+	RootCmdErrCACert                 = "Unable to trust the CA certificate"
 
 	// logs
 	CmdBundleLogsShort = "View most recent UDS CLI logs"
@@ -23,28 +27,112 @@ const (
 	// bundle
 	CmdBundleShort           = "Commands for creating, deploying, removing, pulling, and inspecting bundles"
 	CmdBundleFlagConcurrency = "Number of concurrent layer operations to perform when interacting with a remote bundle."
+	CmdBundleFlagRetries     = "Number of attempts to make when pushing to a remote bundle fails with a rate limit, server or network error."
 
 	// bundle create
 	CmdBundleCreateShort = "Create a bundle from a given directory or the current directory"
 	//CmdBundleCreateFlagConfirm            = "Confirm bundle creation without prompting"
-	CmdBundleCreateFlagOutput             = "Specify the output (an oci:// URL) for the created bundle"
-	CmdBundleCreateFlagSigningKey         = "Path to private key file for signing bundles"
-	CmdBundleCreateFlagSigningKeyPassword = "Password to the private key file used for signing bundles"
+	CmdBundleCreateFlagFile                = "Path to the bundle's definition, relative to the directory, instead of its uds-bundle.yaml. Use - to read it from stdin, which requires --confirm"
+	CmdBundleCreateFlagOutput              = "Specify the output (an oci:// URL) for the created bundle, repeat the flag to push the bundle to multiple registries. A local output ending in / is written as an OCI image layout directory"
+	CmdBundleCreateFlagSigningKey          = "Path to private key file for signing bundles"
+	CmdBundleCreateFlagVerifySignatureKey  = "Path to a public key file the bundle's signature is verified with against the exact uds-bundle.yaml being pushed, before anything is pushed"
+	CmdBundleCreateFlagSigningKeyPassword  = "Password to the private key file used for signing bundles"
+	CmdBundleCreateFlagMaxConcurrency      = "Maximum number of Zarf packages to push at the same time when creating a bundle in a remote registry"
+	CmdBundleCreateFlagLayerConcurrency    = "Number of each Zarf package's layers to push at the same time when creating a bundle in a remote registry, independent of --max-concurrency. Defaults to --oci-concurrency for layers streamed from another registry and 1 for the others"
+	CmdBundleCreateFlagConcurrencyLimit    = "Maximum number of OCI operations running at the same time across fetching and pushing the packages when creating a bundle in a remote registry, 1 makes the create fully serial. Defaults to no limit beyond the other concurrency flags"
+	CmdBundleCreateFlagDryRun              = "Resolve the packages and print the layers that would be pushed to the remote registry without pushing them"
+	CmdBundleCreateFlagVerifySourceKeys    = "Paths to public keys used to verify the signature of each Zarf package before it is pushed to the remote bundle"
+	CmdBundleCreateFlagOutputFormat        = "Format of the result written to stdout when creating a bundle in an OCI registry, either json or digest"
+	CmdBundleCreateFlagOutputDigestOnly    = "Only write the digest of the bundle's root manifest to stdout when creating a bundle in an OCI registry, everything else is written to stderr (same as --output-format digest)"
+	CmdBundleCreateFlagSBOMReferrers       = "Attach the bundle SBOM (with --sbom-format) and each package's SBOMs with the OCI referrers API when the destination registry supports it, instead of only as layers of the bundle"
+	CmdBundleCreateFlagSignatureReferrer   = "Attach the bundle signature with the OCI referrers API when the destination registry supports it, instead of as a layer of the bundle"
+	CmdBundleCreateFlagDetachedSignature   = "Push the bundle signature as a blob referenced by an annotation on the bundle's root manifest, instead of as a layer of the bundle"
+	CmdBundleCreateFlagSBOMFormat          = "Include a bundle-level SBOM describing the bundle's packages in the given format (spdx or cyclonedx)"
+	CmdBundleCreateFlagSignKeyless         = "Sign the bundle with a short-lived Fulcio certificate for your OIDC identity and record the signature in Rekor, instead of with a private key"
+	CmdBundleCreateFlagPlatform            = "Create a multi-arch bundle with a root manifest for each of amd64 and arm64 under a single OCI index by passing 'all', only supported when creating a bundle in an OCI registry"
+	CmdBundleCreateFlagPlatformFromPackage = "Create the bundle for the architecture of its first package instead of the CLI's, every other package must be available for that architecture"
+	CmdBundleCreateFlagSrcCreds            = "Credentials (username:password) for the registries the bundle's packages are pulled from, overriding the docker config"
+	CmdBundleCreateFlagDstCreds            = "Credentials (username:password) for the registries the bundle is pushed to, overriding the docker config"
+	CmdBundleCreateFlagRequireSignature    = "Fail before anything is pushed if the bundle isn't signed with --signing-key or --sign-with-cosign-keyless"
+	CmdBundleCreateFlagRequireDigests      = "Fail if any remote package's ref is a mutable tag instead of a @sha256: digest, so re-running the create always bundles the same packages"
+	CmdBundleCreateFlagSourceMirrors       = "Registry hosts to fetch the packages from, in order, when fetching a package from its own registry fails"
+	CmdBundleCreateFlagRegistryOverride    = "Rewrite the image references in each package from one registry to another when creating a bundle in a remote registry, e.g. --registry-override docker.io=registry.internal:5000/mirror. The old registry can include a path and the longest match wins"
+	CmdBundleCreateFlagAllowedMediaTypes   = "Media types the destination registry accepts, the create fails before pushing anything if a package has a layer with another media type (all media types are allowed by default)"
+	CmdBundleCreateFlagVulnDenyList        = "Path to a deny list of image digests (one per line with an optional reason, or a JSON array of {\"digest\", \"reason\"} from a scanner), the create warns about each package image whose manifest or layers are on it"
+	CmdBundleCreateFlagFailOnVuln          = "Fail the create before pushing anything if a package image is on the --vuln-deny-list instead of warning about it"
+	CmdBundleCreateFlagUncompressedSize    = "Record the total uncompressed size of the packages' layers on the bundle's manifest when creating a bundle in an OCI registry, either \"exact\" (decompresses every compressed image layer) or \"estimate\" (estimated from the compressed sizes)"
+	CmdBundleCreateFlagPruneOldVersions    = "After publishing, delete the bundle versions in each destination beyond the newest --keep (by semver), the version being published is always kept. Add --dry-run to list them instead"
+	CmdBundleCreateFlagKeep                = "Number of versions kept by --prune-old-versions, including the version being published"
+	CmdBundleCreateFlagQuiet               = "Only write warnings, errors and the --output-format result, suppressing the bundle definition (with --confirm), progress and the inspect/deploy/pull hints"
+	CmdBundleCreateFlagAllowDuplicateNames = "Allow more than one package in the bundle to have the same name, deploying or removing a single package by name is then ambiguous"
+	CmdBundleCreateFlagProvenance          = "Record the CLI version, build time, git commit of the bundle definition and the digest of each package in the bundle's build data and manifest config. SOURCE_DATE_EPOCH pins the build time and leaves the user and machine out of the build data for reproducible bundles"
+	CmdBundleCreateFlagNoSignaturePrompt   = "Confirm that the bundle is intentionally unsigned, skipping the prompt to create it without a signature"
+	CmdBundleCreateFlagNoCache             = "Always fetch the root manifest of each Zarf package instead of reusing the manifest cached from a previous create"
+	CmdBundleCreateFlagMetadataMediaType   = "Media type of the bundle's YAML and signature layers, defaults to the Zarf layer media type"
+	CmdBundleCreateFlagConfigMediaType     = "Media type of the bundle's manifest config, defaults to application/vnd.uds.bundle.config.v1+json"
+	CmdBundleCreateFlagForce               = "Overwrite a bundle that was already pushed to the registry with the same name, version and architecture"
+	CmdBundleCreateFlagRegistry            = "Registry (and optional namespace, e.g. ghcr.io/defenseunicorns) to push the bundle to as <registry>/<name>:<version>, instead of --output"
+	CmdBundleCreateFlagDigestTag           = "Also tag the bundle's root manifest with its digest in each registry (full or short), for an immutable reference to the bundle's contents"
+	CmdBundleCreateFlagCleanupOnFailure    = "If the create fails, delete the blobs it pushed to each registry the bundle wasn't published to, listing the ones the registry doesn't allow deleting"
+	CmdBundleCreateFlagTimeout             = "Abort the create if it takes longer than this duration (e.g. 30m), layers already pushed are cleaned up with --cleanup-on-failure. 0 never times out"
+	CmdBundleCreateFlagCompressionLevel    = "Compress the packages' component tarballs with zstd at this level (1-22) as they're pushed, trading CPU for a smaller bundle. This changes their digests from the source packages'. 0 pushes them as is"
+	CmdBundleCreateFlagDigestFile          = "Write the digest of the bundle's root manifest (e.g. sha256:...) to this file once it's pushed to an OCI registry, e.g. for a GitOps controller to pin"
+	CmdBundleCreateFlagMetricsFile         = "Write the duration and size of each push phase and package to this file in the Prometheus text format when creating a bundle in an OCI registry"
 
 	// bundle deploy
-	CmdBundleDeployShort        = "Deploy a bundle from a local tarball or oci:// URL"
-	CmdBundleDeployFlagConfirm  = "Confirms bundle deployment without prompting. ONLY use with bundles you trust. Skips prompts to review SBOM, configure variables, select optional components and review potential breaking changes."
-	CmdBundleDeployFlagPackages = "Specify which zarf packages you would like to deploy from the bundle. By default all zarf packages in the bundle are deployed."
-	CmdBundleDeployFlagResume   = "Only deploys packages from the bundle which haven't already been deployed"
-	CmdBundleDeployFlagSet      = "Specify deployment variables to set on the command line (KEY=value)"
-	CmdBundleDeployFlagRetries  = "Specify the number of retries for package deployments (applies to all pkgs in a bundle)"
+	CmdBundleDeployShort            = "Deploy a bundle from a local tarball or oci:// URL"
+	CmdBundleDeployFlagConfirm      = "Confirms bundle deployment without prompting. ONLY use with bundles you trust. Skips prompts to review SBOM, configure variables, select optional components and review potential breaking changes."
+	CmdBundleDeployFlagPackages     = "Specify which zarf packages you would like to deploy from the bundle. By default all zarf packages in the bundle are deployed."
+	CmdBundleDeployFlagResume       = "Only deploys packages from the bundle which haven't already been deployed"
+	CmdBundleDeployFlagSkipPackages = "Comma-separated list of zarf packages in the bundle that won't be deployed, a package can't be skipped if a deployed package imports its variables"
+	CmdBundleDeployFlagSet          = "Specify deployment variables to set on the command line (KEY=value), or Helm values of a package's component (PACKAGE.COMPONENT[.CHART].PATH=value)"
+	CmdBundleDeployFlagVarsFile     = "Specify a YAML file of deployment variables for a zarf package in the bundle (PKG_NAME=path), can be repeated for each package"
+	CmdBundleDeployFlagRetries      = "Specify the number of retries for package deployments (applies to all pkgs in a bundle)"
+	CmdBundleDeployFlagDryRun       = "Print the packages that would be deployed in order, with their variables, chart overrides, Helm releases and namespaces, without deploying anything"
+	CmdBundleDeployFlagJSON         = "Print the deploy plan as JSON, requires --dry-run"
+
+	// bundle rollback
+	CmdBundleRollbackShort       = "Redeploy the packages a bundle had deployed before its latest deploy"
+	CmdBundleRollbackFlagConfirm = "Confirms the rollback without prompting"
+	CmdBundleRollbackFlagKey     = "Path to a public key file that will be used to validate the signature of the bundle being rolled back to"
 
 	// bundle inspect
-	CmdBundleInspectShort            = "Display the metadata of a bundle"
-	CmdBundleInspectFlagKey          = "Path to a public key file that will be used to validate a signed bundle"
+	CmdBundleInspectShort             = "Display the metadata of a bundle"
+	CmdBundleInspectFlagKey           = "Path to a public key file that will be used to validate a signed bundle"
+	CmdBundleInspectFlagListImages    = "List the container images of every package in the bundle instead of the bundle's metadata"
+	CmdBundleInspectFlagShowSignature = "Show who signed the bundle and how instead of the bundle's metadata"
+	CmdBundleInspectFlagExtractLayer  = "Write the bundle layer with the given title to a file instead of showing the bundle, as <title>=<path> (e.g. bundle.sbom.json=./sbom.json). Works for the bundle's YAML, signature, SBOM and extra files, and can be repeated"
+	CmdBundleInspectFlagJSON          = "Write the list of images, the signature or the signature checks to stdout as JSON, only used with --list-images, --show-signature or --verify-all"
+	CmdBundleInspectFlagVerifyAll     = "Verify the bundle's signature and the signature of each of its packages, printing whether each one verified instead of the bundle's metadata"
+	CmdBundleInspectFlagKeys          = "Paths to public key files used by --verify-all, each signature is verified if it matches any of them or the --key"
+
+	// bundle diff
+	CmdBundleDiffShort               = "Compare the packages of two bundles and show which were added, removed or changed"
+	CmdBundleDiffFlagJSON            = "Write the differences to stdout as JSON"
 	CmdPackageInspectFlagSBOM        = "Create a tarball of SBOMs contained in the bundle"
 	CmdPackageInspectFlagExtractSBOM = "Create a folder of SBOMs contained in the bundle"
 
+	// bundle lint
+	CmdBundleLintShort = "Statically check a uds-bundle.yaml for errors and likely mistakes without contacting any registry"
+
+	// bundle versions
+	CmdBundleVersionsShort    = "List the bundles published in an OCI repository with the version and digest of each tag"
+	CmdBundleVersionsFlagJSON = "Write the list of versions to stdout as JSON"
+
+	// bundle verify
+	CmdBundleVerifyShort   = "Verify that every layer of a published bundle exists in the registry and that its signature is valid"
+	CmdBundleVerifyFlagKey = "Path to a public key file that will be used to validate the bundle's signature"
+
+	// bundle resign
+	CmdBundleRetagShort                   = "Add a tag to a published bundle in the same repository without pushing it again"
+	CmdBundleRetagFlagForce               = "Move the tag if it already points at a different bundle (or replace the bundle for this arch in its index)"
+	CmdBundleResignShort                  = "Replace the signature of a published bundle without pushing its packages again"
+	CmdBundleResignFlagSigningKey         = "Path to the new private key file used to sign the bundle"
+	CmdBundleResignFlagSigningKeyPassword = "Password to the new private key file used to sign the bundle"
+	CmdBundleResignFlagSignKeyless        = "Sign the bundle with a short-lived Fulcio certificate for your OIDC identity and record the signature in Rekor, instead of with a private key"
+	CmdBundleResignFlagKey                = "Path to the current public key file, the bundle's current signature is validated with it before the bundle is resigned"
+	CmdBundleResignFlagSignature          = "Path to a signature of the bundle's uds-bundle.yaml made by an external signer (e.g. an HSM or KMS), it's attached as is instead of signing the bundle"
+
 	// bundle remove
 	CmdBundleRemoveShort        = "Remove a bundle that has been deployed already"
 	CmdBundleRemoveFlagConfirm  = "REQUIRED. Confirm the removal action to prevent accidental deletions"
@@ -53,10 +141,15 @@ const (
 	// bundle publish
 	CmdPublishShort = "Publish a bundle from the local file system to a remote registry"
 
+	// bundle copy
+	CmdCopyShort = "Copy a published bundle from one registry to another without pulling it to the local file system"
+
 	// bundle pull
-	CmdBundlePullShort      = "Pull a bundle from a remote registry and save to the local file system"
-	CmdBundlePullFlagOutput = "Specify the output directory for the pulled bundle"
-	CmdBundlePullFlagKey    = "Path to a public key file that will be used to validate a signed bundle"
+	CmdBundlePullShort        = "Pull a bundle from a remote registry and save to the local file system"
+	CmdBundlePullFlagOutput   = "Specify the output directory for the pulled bundle"
+	CmdBundlePullFlagKey      = "Path to a public key file that will be used to validate a signed bundle"
+	CmdBundlePullFlagPackages = "Specify which zarf packages you would like to pull from the bundle. By default all zarf packages in the bundle are pulled."
+	CmdBundlePullFlagSince    = "Only pull the layers that aren't in this prior bundle in an OCI registry, producing a delta of the two bundles"
 
 	// cmd viper setup
 	CmdViperErrLoadingConfigFile = "failed to load config file: %s"
@@ -10,12 +10,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers"
 	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/bundler"
 	"github.com/defenseunicorns/uds-cli/src/pkg/bundler/fetcher"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	"github.com/defenseunicorns/uds-cli/src/types"
@@ -25,6 +27,8 @@ import (
 	"github.com/defenseunicorns/zarf/src/pkg/zoci"
 	zarfTypes "github.com/defenseunicorns/zarf/src/types"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote/auth"
 )
 
 // Bundle handles bundler operations
@@ -77,8 +81,25 @@ func (b *Bundle) ClearPaths() {
 	_ = os.RemoveAll(b.tmp)
 }
 
+// validatePackageNames returns an error for the first pkg whose name is already used by an earlier pkg in the bundle,
+// pkgs are deployed, removed and selected with --packages by name so duplicates are ambiguous
+func validatePackageNames(packages []types.Package) error {
+	seen := make(map[string]int, len(packages))
+	for i, pkg := range packages {
+		// missing names are reported when each pkg is validated
+		if pkg.Name == "" {
+			continue
+		}
+		if first, ok := seen[pkg.Name]; ok {
+			return fmt.Errorf("%s .packages[%d] and .packages[%d] are both named %s", config.BundleYAML, first, i, pkg.Name)
+		}
+		seen[pkg.Name] = i
+	}
+	return nil
+}
+
 // ValidateBundleResources validates the bundle's metadata and package references
-func (b *Bundle) ValidateBundleResources(spinner *message.Spinner) error {
+func (b *Bundle) ValidateBundleResources(ctx context.Context, spinner *message.Spinner) error {
 	bundle := &b.bundle
 	if bundle.Metadata.Architecture == "" {
 		// ValidateBundle was erroneously called before CalculateBuildInfo
@@ -100,10 +121,26 @@ func (b *Bundle) ValidateBundleResources(spinner *message.Spinner) error {
 		return fmt.Errorf("%s is missing required list: packages", config.BundleYAML)
 	}
 
+	if err := validatePackageNames(bundle.Packages); err != nil {
+		if !b.cfg.CreateOpts.AllowDuplicateNames {
+			return fmt.Errorf("%w, use --allow-duplicate-names to bundle them anyway", err)
+		}
+		message.Warnf("%s, bundling them anyway because --allow-duplicate-names is set", err)
+	}
+
 	if err := validateBundleVars(bundle.Packages); err != nil {
 		return fmt.Errorf("error validating bundle vars: %s", err)
 	}
 
+	if _, err := orderPackages(bundle.Packages); err != nil {
+		return err
+	}
+
+	srcCredential, dstCredential, err := b.registryCredentials()
+	if err != nil {
+		return err
+	}
+
 	// validate access to packages as well as components referenced in the package
 	for idx, pkg := range bundle.Packages {
 
@@ -133,40 +170,48 @@ func (b *Bundle) ValidateBundleResources(spinner *message.Spinner) error {
 				url = fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref)
 			}
 
-			platform := ocispec.Platform{
-				Architecture: config.GetArch(),
-				OS:           oci.MultiOS,
-			}
-			remote, err := zoci.NewRemote(url, platform)
+			remote, err := zoci.NewRemote(url, utils.GetPkgPlatform(pkg), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 			if err != nil {
 				return err
 			}
+			utils.WithCredential(remote.OrasRemote, srcCredential)
 			if err := remote.Repo().Reference.ValidateReferenceAsDigest(); err != nil {
-				manifestDesc, err := remote.ResolveRoot(context.TODO())
+				manifestDesc, err := remote.ResolveRoot(ctx)
 				if err != nil {
 					return err
 				}
+				if b.cfg.CreateOpts.RequireDigests {
+					return mutableRefError(pkg, manifestDesc)
+				}
 				// todo: don't do this here, a "validate" fn shouldn't be modifying the bundle
 				bundle.Packages[idx].Ref = pkg.Ref + "@sha256:" + manifestDesc.Digest.Encoded()
 			}
+			if err := b.checkSelfReference(ctx, bundle.Packages[idx], dstCredential); err != nil {
+				return err
+			}
 		} else {
-			// atm we don't support outputting a bundle with local pkgs outputting to OCI
-			if utils.IsRegistryURL(b.cfg.CreateOpts.Output) {
-				return fmt.Errorf("detected local Zarf package: %s, outputting to an OCI registry is not supported when using local Zarf packages", pkg.Name)
+			// local pkgs are staged in the OCI registry before they're pushed, so their digests aren't known until then
+			if slices.ContainsFunc(b.cfg.CreateOpts.Outputs, utils.IsRegistryURL) {
+				if b.cfg.CreateOpts.SigningKeyPath != "" || b.cfg.CreateOpts.SignKeyless {
+					return fmt.Errorf("detected local Zarf package: %s, a bundle with local Zarf packages can't be signed when it's created in an OCI registry", pkg.Name)
+				}
+				if b.cfg.CreateOpts.DryRun {
+					return fmt.Errorf("detected local Zarf package: %s, a dry run isn't supported for a bundle with local Zarf packages", pkg.Name)
+				}
 			}
 			path := getPkgPath(pkg, bundle.Metadata.Architecture, b.cfg.CreateOpts.SourceDirectory)
 			bundle.Packages[idx].Path = path
 		}
 
 		// grab the Zarf pkg metadata
-		f, err := fetcher.NewPkgFetcher(pkg, fetcher.Config{
+		f, err := fetcher.NewPkgFetcher(ctx, pkg, fetcher.Config{
 			PkgIter: idx, Bundle: bundle,
 		})
 		if err != nil {
 			return err
 		}
 		// For local pkgs, this will throw an error if the zarf package name in the bundle doesn't match the actual zarf package name
-		zarfYAML, err = f.GetPkgMetadata()
+		zarfYAML, err = f.GetPkgMetadata(ctx)
 		if err != nil {
 			return err
 		}
@@ -183,7 +228,11 @@ func (b *Bundle) ValidateBundleResources(spinner *message.Spinner) error {
 		}
 
 		if len(pkg.OptionalComponents) > 0 {
-			// validate the optional components exist in the package and support the bundle's target architecture
+			pkgArch := bundle.Metadata.Architecture
+			if pkg.Arch != "" {
+				pkgArch = pkg.Arch
+			}
+			// validate the optional components exist in the package and support the pkg's target architecture
 			for _, component := range pkg.OptionalComponents {
 				c := helpers.Find(zarfYAML.Components, func(c zarfTypes.ZarfComponent) bool {
 					return c.Name == component
@@ -192,9 +241,9 @@ func (b *Bundle) ValidateBundleResources(spinner *message.Spinner) error {
 				if c.Name == "" {
 					return fmt.Errorf("%s .packages[%s].components[%s] does not exist in upstream: %s", config.BundleYAML, pkg.Repository, component, url)
 				}
-				// make sure the component supports the bundle's target architecture
-				if c.Only.Cluster.Architecture != "" && c.Only.Cluster.Architecture != bundle.Metadata.Architecture {
-					return fmt.Errorf("%s .packages[%s].components[%s] does not support architecture: %s", config.BundleYAML, pkg.Repository, component, bundle.Metadata.Architecture)
+				// make sure the component supports the pkg's target architecture
+				if c.Only.Cluster.Architecture != "" && c.Only.Cluster.Architecture != pkgArch {
+					return fmt.Errorf("%s .packages[%s].components[%s] does not support architecture: %s", config.BundleYAML, pkg.Repository, component, pkgArch)
 				}
 			}
 		}
@@ -208,9 +257,75 @@ func (b *Bundle) ValidateBundleResources(spinner *message.Spinner) error {
 	return nil
 }
 
+// checkSelfReference returns an error if a remote Zarf pkg is the bundle being created, i.e. its resolved reference is
+// the bundle's own reference in one of the registry outputs, otherwise the bundle would contain itself
+func (b *Bundle) checkSelfReference(ctx context.Context, pkg types.Package, dstCredential auth.Credential) error {
+	for _, output := range b.cfg.CreateOpts.Outputs {
+		if !utils.IsRegistryURL(output) {
+			continue
+		}
+		bundleRef, err := bundler.BundleReference(output, &b.bundle.Metadata)
+		if err != nil {
+			// an invalid output is reported when the bundle is pushed
+			continue
+		}
+		if !inBundleRepository(pkg, bundleRef) {
+			continue
+		}
+		if err := selfReferenceError(pkg, bundleRef, ""); err != nil {
+			return err
+		}
+		// the pkg can also be pinned to the digest that's currently published at the bundle's tag
+		var publishedDigest string
+		remote, err := zoci.NewRemote(bundleRef.String(), utils.GetPkgPlatform(pkg), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+		if err != nil {
+			return err
+		}
+		utils.WithCredential(remote.OrasRemote, dstCredential)
+		if desc, err := remote.ResolveRoot(ctx); err == nil {
+			publishedDigest = desc.Digest.String()
+		} else {
+			message.Debugf("Unable to resolve %s to check if package %s references the bundle: %s", bundleRef, pkg.Name, err)
+		}
+		if err := selfReferenceError(pkg, bundleRef, publishedDigest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inBundleRepository returns true if a remote Zarf pkg is in the same repository as the bundle
+func inBundleRepository(pkg types.Package, bundleRef registry.Reference) bool {
+	pkgRef, err := registry.ParseReference(fmt.Sprintf("%s:%s", strings.TrimPrefix(pkg.Repository, helpers.OCIURLPrefix), pkg.Ref))
+	if err != nil {
+		return false
+	}
+	return pkgRef.Registry == bundleRef.Registry && pkgRef.Repository == bundleRef.Repository
+}
+
+// selfReferenceError returns an error if a remote Zarf pkg's tag is the bundle's tag or its digest is the digest that's
+// published at the bundle's tag, publishedDigest is empty if nothing is published there yet
+func selfReferenceError(pkg types.Package, bundleRef registry.Reference, publishedDigest string) error {
+	if !inBundleRepository(pkg, bundleRef) {
+		return nil
+	}
+	tag, digest, _ := strings.Cut(pkg.Ref, "@")
+	if tag == bundleRef.Reference {
+		return fmt.Errorf("zarf pkg %s references the bundle being created (%s), a bundle can't contain itself", pkg.Name, bundleRef)
+	}
+	if publishedDigest != "" && digest == publishedDigest {
+		return fmt.Errorf("zarf pkg %s references %s, which is the bundle published at %s, a bundle can't contain itself", pkg.Name, publishedDigest, bundleRef)
+	}
+	return nil
+}
+
 func getPkgPath(pkg types.Package, arch string, srcDir string) string {
 	var fullPkgName string
 	var path string
+	// the pkg's arch takes precedence over the bundle's
+	if pkg.Arch != "" {
+		arch = pkg.Arch
+	}
 	// Set path relative to the source directory if not absolute
 	if !filepath.IsAbs(pkg.Path) {
 		pkg.Path = filepath.Join(srcDir, pkg.Path)
@@ -232,19 +347,32 @@ func getPkgPath(pkg types.Package, arch string, srcDir string) string {
 
 // CalculateBuildInfo calculates the build info for the bundle
 func (b *Bundle) CalculateBuildInfo() error {
-	now := time.Now()
-	b.bundle.Build.User = os.Getenv("USER")
-
-	hostname, err := os.Hostname()
+	now, err := buildTime()
 	if err != nil {
 		return err
 	}
-	b.bundle.Build.Terminal = hostname
+	b.bundle.Build.User = ""
+	b.bundle.Build.Terminal = ""
+	if !isBuildPinned() {
+		b.bundle.Build.User = os.Getenv("USER")
+		hostname, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+		b.bundle.Build.Terminal = hostname
+	}
 
 	// --architecture flag > metadata.arch > build.arch > runtime.GOARCH (default)
 	b.bundle.Build.Architecture = config.GetArch(b.bundle.Metadata.Architecture, b.bundle.Build.Architecture)
 	b.bundle.Metadata.Architecture = b.bundle.Build.Architecture
 
+	// --os flag > metadata.os > multi (default), the default isn't written so the YAML of multi-OS bundles is unchanged
+	if bundleOS := config.GetOS(b.bundle.Metadata.OS); bundleOS != oci.MultiOS {
+		b.bundle.Metadata.OS = bundleOS
+	} else {
+		b.bundle.Metadata.OS = ""
+	}
+
 	b.bundle.Build.Timestamp = now.Format(time.RFC1123Z)
 
 	b.bundle.Build.Version = config.CLIVersion
@@ -252,8 +380,45 @@ func (b *Bundle) CalculateBuildInfo() error {
 	return nil
 }
 
-// ValidateBundleSignature validates the bundle signature
-func ValidateBundleSignature(bundleYAMLPath, signaturePath, publicKeyPath string) error {
+// isBundleMetadataLayer returns true if a root manifest layer is one of the bundle's own files rather than a Zarf image manifest
+func isBundleMetadataLayer(layer ocispec.Descriptor) bool {
+	switch layer.Annotations[ocispec.AnnotationTitle] {
+	case config.BundleYAML, config.BundleYAMLSignature, config.BundleSBOMJSON:
+		return true
+	}
+	_, extraFile := layer.Annotations[config.BundleExtraFileAnnotation]
+	return extraFile
+}
+
+// metadataPaths returns the titles of the root manifest layers that are loaded with the bundle's metadata, the
+// bundle's extra files are loaded with its YAML so they're kept when the bundle is pulled
+func metadataPaths(rootManifest *oci.Manifest) []string {
+	paths := slices.Clone(config.BundleAlwaysPull)
+	for _, layer := range rootManifest.Layers {
+		if _, ok := layer.Annotations[config.BundleExtraFileAnnotation]; ok {
+			paths = append(paths, layer.Annotations[ocispec.AnnotationTitle])
+		}
+	}
+	return paths
+}
+
+// writeSignatureCertificate writes the Fulcio certificate of a keyless bundle signature to dir, returning the
+// certificate's path or an empty string if the signature isn't keyless
+func writeSignatureCertificate(signatureDesc ocispec.Descriptor, dir string) (string, error) {
+	cert, ok := signatureDesc.Annotations[config.BundleSignatureCertificateAnnotation]
+	if !ok {
+		return "", nil
+	}
+	certPath := filepath.Join(dir, config.BundleYAMLCertificate)
+	if err := os.WriteFile(certPath, []byte(cert), 0600); err != nil {
+		return "", err
+	}
+	return certPath, nil
+}
+
+// ValidateBundleSignature validates the bundle signature, a keyless signature is verified against its Fulcio
+// certificate when no public key is provided
+func ValidateBundleSignature(bundleYAMLPath, signaturePath, certificatePath, publicKeyPath string) error {
 	if helpers.InvalidPath(bundleYAMLPath) {
 		return fmt.Errorf("path for %s at %s does not exist", config.BundleYAML, bundleYAMLPath)
 	}
@@ -265,6 +430,10 @@ func ValidateBundleSignature(bundleYAMLPath, signaturePath, publicKeyPath string
 	if helpers.InvalidPath(signaturePath) && !helpers.InvalidPath(publicKeyPath) {
 		return fmt.Errorf("package is not signed, but a public key was provided")
 	}
+	// The package is signed keylessly, and no public key was provided
+	if !helpers.InvalidPath(signaturePath) && !helpers.InvalidPath(certificatePath) && helpers.InvalidPath(publicKeyPath) {
+		return utils.CosignVerifyBlobKeyless(bundleYAMLPath, signaturePath, certificatePath)
+	}
 	// The package is signed, but no public key was provided
 	if !helpers.InvalidPath(signaturePath) && helpers.InvalidPath(publicKeyPath) {
 		return fmt.Errorf("package is signed, but no public key was provided")
@@ -344,3 +513,29 @@ func validateBundleVars(packages []types.Package) error {
 	}
 	return nil
 }
+
+// mutableRefError returns the error for a remote Zarf pkg referenced by a tag when digests are required, suggesting
+// the digest the tag currently resolves to so the pkg can be pinned
+func mutableRefError(pkg types.Package, resolved ocispec.Descriptor) error {
+	return fmt.Errorf("zarf pkg %s references %s:%s by a mutable tag and --require-digests is set, pin it with ref: %s@%s",
+		pkg.Name, pkg.Repository, pkg.Ref, pkg.Ref, resolved.Digest)
+}
+
+// selectPackages returns the bundle's Zarf pkgs named in the --packages flag in the order they appear in the bundle,
+// or all of the bundle's Zarf pkgs if none were specified
+func selectPackages(packages []types.Package, specified []string) ([]types.Package, error) {
+	if len(specified) == 0 {
+		return packages, nil
+	}
+	userSpecifiedPackages := strings.Split(strings.ReplaceAll(specified[0], " ", ""), ",")
+	var selected []types.Package
+	for _, pkg := range packages {
+		if slices.Contains(userSpecifiedPackages, pkg.Name) {
+			selected = append(selected, pkg)
+		}
+	}
+	if len(userSpecifiedPackages) != len(selected) {
+		return nil, fmt.Errorf("invalid zarf packages specified by --packages")
+	}
+	return selected, nil
+}
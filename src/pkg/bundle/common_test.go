@@ -1,11 +1,16 @@
 package bundle
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/defenseunicorns/uds-cli/src/config"
 	"github.com/defenseunicorns/uds-cli/src/types"
 	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry"
 )
 
 func Test_validateBundleVars(t *testing.T) {
@@ -203,6 +208,15 @@ func Test_getPkgPath(t *testing.T) {
 			},
 			want: "/fake/zarf-package-nginx-fake64-0.0.1.tar.zst",
 		},
+		{
+			name: "package arch override",
+			args: args{
+				pkg:    types.Package{Name: "nginx", Ref: "0.0.1", Path: "fake", Arch: "other64"},
+				arch:   "fake64",
+				srcDir: "/mock/source",
+			},
+			want: "/mock/source/fake/zarf-package-nginx-other64-0.0.1.tar.zst",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -211,3 +225,128 @@ func Test_getPkgPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_writeSignatureCertificate(t *testing.T) {
+	dir := t.TempDir()
+
+	// a signature created with a signing key has no certificate
+	certPath, err := writeSignatureCertificate(ocispec.Descriptor{Annotations: map[string]string{ocispec.AnnotationTitle: config.BundleYAMLSignature}}, dir)
+	require.NoError(t, err)
+	require.Empty(t, certPath)
+
+	cert := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	certPath, err = writeSignatureCertificate(ocispec.Descriptor{Annotations: map[string]string{
+		ocispec.AnnotationTitle:                     config.BundleYAMLSignature,
+		config.BundleSignatureCertificateAnnotation: cert,
+	}}, dir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, config.BundleYAMLCertificate), certPath)
+	b, err := os.ReadFile(certPath)
+	require.NoError(t, err)
+	require.Equal(t, cert, string(b))
+}
+
+func Test_mutableRefError(t *testing.T) {
+	pkg := types.Package{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/uds-cli/podinfo", Ref: "0.0.1"}
+	resolved := ocispec.Descriptor{Digest: "sha256:0cf2d3bbd8e6ad2a7a1bbbf4f2a7e3a7e7f0ab2fbd6e3f2a1d9d7b7e2d2f4c1a"}
+	err := mutableRefError(pkg, resolved)
+	require.EqualError(t, err, "zarf pkg podinfo references ghcr.io/defenseunicorns/uds-cli/podinfo:0.0.1 by a mutable tag and --require-digests is set, pin it with ref: 0.0.1@sha256:0cf2d3bbd8e6ad2a7a1bbbf4f2a7e3a7e7f0ab2fbd6e3f2a1d9d7b7e2d2f4c1a")
+}
+
+func Test_validatePackageNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		packages []types.Package
+		wantErr  string
+	}{
+		{name: "unique", packages: []types.Package{{Name: "init"}, {Name: "podinfo"}}},
+		{name: "missing names", packages: []types.Package{{}, {}}},
+		{
+			name:     "duplicate",
+			packages: []types.Package{{Name: "init"}, {Name: "podinfo"}, {Name: "nginx"}, {Name: "podinfo"}},
+			wantErr:  "uds-bundle.yaml .packages[1] and .packages[3] are both named podinfo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePackageNames(tt.packages)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_selectPackages(t *testing.T) {
+	packages := []types.Package{{Name: "init"}, {Name: "podinfo"}, {Name: "nginx"}}
+	tests := []struct {
+		name      string
+		specified []string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "none specified", specified: nil, want: []string{"init", "podinfo", "nginx"}},
+		{name: "bundle order", specified: []string{"nginx, init"}, want: []string{"init", "nginx"}},
+		{name: "unknown package", specified: []string{"init,unknown"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectPackages(packages, tt.specified)
+			if tt.wantErr {
+				require.EqualError(t, err, "invalid zarf packages specified by --packages")
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, pkg := range selected {
+				names = append(names, pkg.Name)
+			}
+			require.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func Test_selfReferenceError(t *testing.T) {
+	bundleRef, err := registry.ParseReference("ghcr.io/defenseunicorns/dev/example:0.0.1")
+	require.NoError(t, err)
+	published := "sha256:1d7c2ae7d2c9bca2e5a1ef1fa4b6b8ce2e2b8d7a7a0ec3f0d1d8c7f1d7a6a8b9"
+	tests := []struct {
+		name            string
+		pkg             types.Package
+		publishedDigest string
+		wantErr         string
+	}{
+		{
+			name: "OtherRepository",
+			pkg:  types.Package{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/dev/podinfo", Ref: "0.0.1@" + published},
+		},
+		{
+			name:            "SameRepositoryOtherTag",
+			pkg:             types.Package{Name: "example", Repository: "ghcr.io/defenseunicorns/dev/example", Ref: "1.0.0@sha256:2d7c2ae7d2c9bca2e5a1ef1fa4b6b8ce2e2b8d7a7a0ec3f0d1d8c7f1d7a6a8b9"},
+			publishedDigest: published,
+		},
+		{
+			name:    "BundleTag",
+			pkg:     types.Package{Name: "example", Repository: "oci://ghcr.io/defenseunicorns/dev/example", Ref: "0.0.1@" + published},
+			wantErr: "zarf pkg example references the bundle being created (ghcr.io/defenseunicorns/dev/example:0.0.1), a bundle can't contain itself",
+		},
+		{
+			name:            "PublishedDigest",
+			pkg:             types.Package{Name: "example", Repository: "ghcr.io/defenseunicorns/dev/example", Ref: "latest@" + published},
+			publishedDigest: published,
+			wantErr:         "zarf pkg example references " + published + ", which is the bundle published at ghcr.io/defenseunicorns/dev/example:0.0.1, a bundle can't contain itself",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := selfReferenceError(tt.pkg, bundleRef, tt.publishedDigest)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
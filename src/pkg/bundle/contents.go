@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	goyaml "github.com/goccy/go-yaml"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// BundleContents is a published bundle's metadata and the layers of each of its Zarf pkgs
+type BundleContents struct {
+	// Reference is the reference the bundle was fetched from
+	Reference string
+	// RootManifest is the bundle's root manifest for the CLI's arch
+	RootManifest *oci.Manifest
+	// Bundle is the bundle's YAML
+	Bundle *types.UDSBundle
+	// Packages are the bundle's Zarf pkgs in the same order as Bundle.Packages
+	Packages []PackageContents
+}
+
+// PackageContents is a Zarf pkg in a published bundle
+type PackageContents struct {
+	// Name is the pkg's name in the bundle's YAML
+	Name string
+	// Manifest is the desc of the pkg's manifest in the bundle's root manifest
+	Manifest ocispec.Descriptor
+	// Config and Layers are the config and layers of the pkg's manifest
+	Config ocispec.Descriptor
+	Layers []ocispec.Descriptor
+}
+
+// InspectRemote fetches a published bundle's root manifest, YAML and the manifest of each of its Zarf pkgs without
+// writing anything to disk, the registry is authenticated with the docker config
+func InspectRemote(ref string) (*BundleContents, error) {
+	ctx := context.TODO()
+	ref = utils.EnsureOCIPrefix(ref)
+	platform := utils.BundlePlatform(ctx, ref)
+	remote, err := zoci.NewRemote(ref, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return nil, err
+	}
+	contents, err := inspectRemote(ctx, remote.OrasRemote)
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect %s: %w", ref, err)
+	}
+	contents.Reference = ref
+	return contents, nil
+}
+
+// inspectRemote reads a bundle's contents from a remote, the bundle's Zarf pkg manifests are the root manifest's
+// layers that aren't the bundle's own metadata, in the same order as the pkgs in the bundle's YAML
+func inspectRemote(ctx context.Context, remote *oci.OrasRemote) (*BundleContents, error) {
+	root, err := remote.FetchRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bundleYAMLDesc := root.Locate(config.BundleYAML)
+	if oci.IsEmptyDescriptor(bundleYAMLDesc) {
+		return nil, fmt.Errorf("the root manifest doesn't have a %s layer, it isn't a UDS bundle", config.BundleYAML)
+	}
+	bundleYAML, err := remote.FetchLayer(ctx, bundleYAMLDesc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s: %w", config.BundleYAML, err)
+	}
+	var bundle types.UDSBundle
+	if err := goyaml.Unmarshal(bundleYAML, &bundle); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", config.BundleYAML, err)
+	}
+
+	var pkgManifestDescs []ocispec.Descriptor
+	for _, layer := range root.Layers {
+		if !isBundleMetadataLayer(layer) {
+			pkgManifestDescs = append(pkgManifestDescs, layer)
+		}
+	}
+	if len(pkgManifestDescs) != len(bundle.Packages) {
+		return nil, fmt.Errorf("%s lists %d packages but the root manifest has %d package manifests", config.BundleYAML, len(bundle.Packages), len(pkgManifestDescs))
+	}
+
+	contents := BundleContents{RootManifest: root, Bundle: &bundle}
+	for i, pkg := range bundle.Packages {
+		pkgManifest, err := remote.FetchManifest(ctx, pkgManifestDescs[i])
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch the manifest of package %s: %w", pkg.Name, err)
+		}
+		contents.Packages = append(contents.Packages, PackageContents{
+			Name:     pkg.Name,
+			Manifest: pkgManifestDescs[i],
+			Config:   pkgManifest.Config,
+			Layers:   pkgManifest.Layers,
+		})
+	}
+	return &contents, nil
+}
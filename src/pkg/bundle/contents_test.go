@@ -0,0 +1,105 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	goyaml "github.com/goccy/go-yaml"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+// newTestBundleRemote serves a bundle whose root manifest has the given layers, blobs are served by digest
+func newTestBundleRemote(t *testing.T, layers []ocispec.Descriptor, blobs map[string][]byte) *oci.OrasRemote {
+	rootManifest := ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: ocispec.DescriptorEmptyJSON, Layers: layers}
+	rootManifest.SchemaVersion = 2
+	rootManifestBytes, err := json.Marshal(rootManifest)
+	require.NoError(t, err)
+	rootManifestDigest := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, rootManifestBytes).Digest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var b []byte
+		mediaType := "application/octet-stream"
+		switch r.URL.Path {
+		case "/v2/dev/bundle/manifests/0.0.1", "/v2/dev/bundle/manifests/" + rootManifestDigest.String():
+			b, mediaType = rootManifestBytes, ocispec.MediaTypeImageManifest
+		default:
+			b = blobs[strings.TrimPrefix(r.URL.Path, "/v2/dev/bundle/blobs/")]
+		}
+		if b == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		w.Header().Set("Docker-Content-Digest", content.NewDescriptorFromBytes(mediaType, b).Digest.String())
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ref := strings.TrimPrefix(server.URL, "http://") + "/dev/bundle:0.0.1"
+	remote, err := zoci.NewRemote(ref, ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	return remote.OrasRemote
+}
+
+func Test_inspectRemote(t *testing.T) {
+	bundle := types.UDSBundle{
+		Metadata: types.UDSMetadata{Name: "example", Version: "0.0.1"},
+		Packages: []types.Package{{Name: "podinfo"}, {Name: "nginx"}},
+	}
+	bundleYAML, err := goyaml.Marshal(bundle)
+	require.NoError(t, err)
+	bundleYAMLDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, bundleYAML)
+	bundleYAMLDesc.Annotations = map[string]string{ocispec.AnnotationTitle: config.BundleYAML}
+
+	blobs := map[string][]byte{bundleYAMLDesc.Digest.String(): bundleYAML}
+	var pkgManifestDescs []ocispec.Descriptor
+	for _, pkg := range bundle.Packages {
+		pkgLayer := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte(pkg.Name))
+		pkgManifest, err := json.Marshal(ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON, Layers: []ocispec.Descriptor{pkgLayer}})
+		require.NoError(t, err)
+		desc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, pkgManifest)
+		blobs[desc.Digest.String()] = pkgManifest
+		pkgManifestDescs = append(pkgManifestDescs, desc)
+	}
+
+	t.Run("Contents", func(t *testing.T) {
+		remote := newTestBundleRemote(t, append(append([]ocispec.Descriptor{}, pkgManifestDescs...), bundleYAMLDesc), blobs)
+		contents, err := inspectRemote(context.Background(), remote)
+		require.NoError(t, err)
+		require.Equal(t, "example", contents.Bundle.Metadata.Name)
+		require.Len(t, contents.RootManifest.Layers, 3)
+		require.Len(t, contents.Packages, 2)
+		for i, pkg := range contents.Packages {
+			require.Equal(t, bundle.Packages[i].Name, pkg.Name)
+			require.Equal(t, pkgManifestDescs[i].Digest, pkg.Manifest.Digest)
+			require.Equal(t, ocispec.DescriptorEmptyJSON.Digest, pkg.Config.Digest)
+			require.Len(t, pkg.Layers, 1)
+		}
+	})
+
+	t.Run("MissingBundleYAML", func(t *testing.T) {
+		remote := newTestBundleRemote(t, pkgManifestDescs, blobs)
+		_, err := inspectRemote(context.Background(), remote)
+		require.EqualError(t, err, "the root manifest doesn't have a uds-bundle.yaml layer, it isn't a UDS bundle")
+	})
+
+	t.Run("PackageCountMismatch", func(t *testing.T) {
+		remote := newTestBundleRemote(t, []ocispec.Descriptor{pkgManifestDescs[0], bundleYAMLDesc}, blobs)
+		_, err := inspectRemote(context.Background(), remote)
+		require.EqualError(t, err, "uds-bundle.yaml lists 2 packages but the root manifest has 1 package manifests")
+	})
+}
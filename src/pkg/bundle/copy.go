@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	zarfConfig "github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+)
+
+// Copy copies a published bundle from one registry to another without staging it locally. Every arch in the bundle's
+// index and the referrers of each of its root manifests are copied, blobs are streamed between the registries (or
+// mounted within a registry) and manifests are pushed as they were fetched so the bundle's digests and signature don't change
+func (b *Bundle) Copy() error {
+	ctx := context.TODO()
+	src, dst, err := copyReferences(b.cfg.CopyOpts.Source, b.cfg.CopyOpts.Destination)
+	if err != nil {
+		return err
+	}
+
+	platform := ocispec.Platform{
+		Architecture: config.GetArch(),
+		OS:           config.GetOS(),
+	}
+	srcRemote, err := zoci.NewRemote(src.String(), platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return err
+	}
+	dstRemote, err := zoci.NewRemote(dst.String(), platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return err
+	}
+	srcRepo, dstRepo := srcRemote.Repo(), dstRemote.Repo()
+
+	// resolve the tag instead of the root manifest of this arch, the whole index is copied
+	root, err := srcRepo.Resolve(ctx, src.Reference)
+	if err != nil {
+		return fmt.Errorf("unable to resolve %s: %w", src, err)
+	}
+
+	spinner := message.NewProgressSpinner("Copying %s to %s", src, dst)
+	defer spinner.Stop()
+	copyOpts := bundleCopyGraphOpts(src, dst, srcRepo.Blobs())
+	copyOpts.PostCopy = func(_ context.Context, desc ocispec.Descriptor) error {
+		spinner.Updatef("Copied %s (%s)", desc.Digest.Encoded(), zarfUtils.ByteFormat(float64(desc.Size), 2))
+		return nil
+	}
+	if err := oras.CopyGraph(ctx, srcRepo, dstRepo, root, copyOpts); err != nil {
+		return fmt.Errorf("unable to copy %s to %s: %w", src, dst, err)
+	}
+
+	// signatures and SBOMs attached with the referrers API point at a root manifest, they aren't in its graph
+	rootManifests := []ocispec.Descriptor{root}
+	if root.MediaType == ocispec.MediaTypeImageIndex {
+		indexBytes, err := content.FetchAll(ctx, srcRepo, root)
+		if err != nil {
+			return err
+		}
+		var index ocispec.Index
+		if err := json.Unmarshal(indexBytes, &index); err != nil {
+			return err
+		}
+		rootManifests = index.Manifests
+	}
+	for _, rootManifest := range rootManifests {
+		err := srcRepo.Referrers(ctx, rootManifest, "", func(referrers []ocispec.Descriptor) error {
+			for _, referrer := range referrers {
+				spinner.Updatef("Copying the %s referrer %s", referrer.ArtifactType, referrer.Digest.Encoded())
+				if err := oras.CopyGraph(ctx, srcRepo, dstRepo, referrer, copyOpts); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("unable to copy the referrers of %s: %w", rootManifest.Digest, err)
+		}
+	}
+
+	if _, err := dst.Digest(); err != nil {
+		if err := dstRepo.Tag(ctx, root, dst.Reference); err != nil {
+			return fmt.Errorf("unable to tag %s: %w", dst, err)
+		}
+	}
+	spinner.Successf("Copied %s to %s (%s)", src, dst, root.Digest)
+	return nil
+}
+
+// copyReferences parses the source and destination of a copy, a destination without a tag or digest gets the source's
+func copyReferences(source, destination string) (registry.Reference, registry.Reference, error) {
+	src, err := registry.ParseReference(strings.TrimPrefix(utils.EnsureOCIPrefix(source), helpers.OCIURLPrefix))
+	if err != nil {
+		return registry.Reference{}, registry.Reference{}, fmt.Errorf("invalid source %q: %w", source, err)
+	}
+	if src.Reference == "" {
+		return registry.Reference{}, registry.Reference{}, fmt.Errorf("invalid source %q, it must include the bundle's tag or digest", source)
+	}
+	dst, err := registry.ParseReference(strings.TrimPrefix(utils.EnsureOCIPrefix(destination), helpers.OCIURLPrefix))
+	if err != nil {
+		return registry.Reference{}, registry.Reference{}, fmt.Errorf("invalid destination %q: %w", destination, err)
+	}
+	if dst.Reference == "" {
+		dst.Reference = src.Reference
+	}
+	// the bundle's digest doesn't change, so the destination can only be a digest if it's the source's
+	if _, err := dst.Digest(); err == nil && dst.Reference != src.Reference {
+		return registry.Reference{}, registry.Reference{}, fmt.Errorf("invalid destination %q, a copied bundle keeps its digest so the destination must be a tag", destination)
+	}
+	if src == dst {
+		return registry.Reference{}, registry.Reference{}, fmt.Errorf("the source and destination are both %s", src)
+	}
+	return src, dst, nil
+}
+
+// bundleCopyGraphOpts returns the options to copy a bundle's graph from src to dst, blobs are mounted instead of
+// streamed if they're in the same registry
+func bundleCopyGraphOpts(src, dst registry.Reference, srcBlobs blobResolver) oras.CopyGraphOptions {
+	opts := oras.CopyGraphOptions{
+		Concurrency: zarfConfig.CommonOptions.OCIConcurrency,
+		FindSuccessors: func(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+			return bundleSuccessors(ctx, fetcher, srcBlobs, desc)
+		},
+	}
+	if src.Registry == dst.Registry && src.Repository != dst.Repository {
+		opts.MountFrom = func(_ context.Context, _ ocispec.Descriptor) ([]string, error) {
+			return []string{src.Repository}, nil
+		}
+	}
+	return opts
+}
+
+// bundleSuccessors finds the successors of a node in a bundle's graph. A root manifest's Zarf pkg manifests are layers
+// without a title, so they're walked like manifests, and a detached signature is referenced by an annotation instead of
+// a layer so it's resolved from the source's blobs
+func bundleSuccessors(ctx context.Context, fetcher content.Fetcher, blobs blobResolver, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	_, hasTitleAnnotation := desc.Annotations[ocispec.AnnotationTitle]
+	isPkgManifest := desc.MediaType == zoci.ZarfLayerMediaTypeBlob && !hasTitleAnnotation
+	if desc.MediaType != ocispec.MediaTypeImageManifest && !isPkgManifest {
+		return content.Successors(ctx, fetcher, desc)
+	}
+
+	b, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		if isPkgManifest {
+			// an untitled blob that isn't a manifest, e.g. the config of an older bundle, has no successors
+			return nil, nil
+		}
+		return nil, err
+	}
+	var nodes []ocispec.Descriptor
+	if manifest.Subject != nil {
+		nodes = append(nodes, *manifest.Subject)
+	}
+	nodes = append(nodes, manifest.Config)
+	nodes = append(nodes, manifest.Layers...)
+	if sigDigest, ok := manifest.Annotations[config.BundleSignatureDigestAnnotation]; ok {
+		signatureDesc, err := blobs.Resolve(ctx, sigDigest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve the detached signature %s: %w", sigDigest, err)
+		}
+		nodes = append(nodes, signatureDesc)
+	}
+
+	var successors []ocispec.Descriptor
+	for _, node := range nodes {
+		if node.Digest != "" {
+			successors = append(successors, node)
+		}
+	}
+	return successors, nil
+}
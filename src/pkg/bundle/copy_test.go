@@ -0,0 +1,100 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry"
+)
+
+func Test_copyReferences(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		destination string
+		wantDst     string
+		wantErr     string
+	}{
+		{name: "Tag", source: "oci://ghcr.io/defenseunicorns/dev/example:0.0.1", destination: "oci://registry.internal/bundles/example:stable", wantDst: "registry.internal/bundles/example:stable"},
+		{name: "SourceTag", source: "ghcr.io/defenseunicorns/dev/example:0.0.1", destination: "registry.internal/bundles/example", wantDst: "registry.internal/bundles/example:0.0.1"},
+		{name: "SourceDigest", source: "oci://ghcr.io/defenseunicorns/dev/example@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", destination: "oci://registry.internal/bundles/example", wantDst: "registry.internal/bundles/example@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{name: "NoSourceTag", source: "oci://ghcr.io/defenseunicorns/dev/example", destination: "oci://registry.internal/bundles/example", wantErr: "it must include the bundle's tag or digest"},
+		{name: "DifferentDigest", source: "oci://ghcr.io/defenseunicorns/dev/example:0.0.1", destination: "oci://registry.internal/bundles/example@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", wantErr: "a copied bundle keeps its digest so the destination must be a tag"},
+		{name: "Same", source: "oci://ghcr.io/defenseunicorns/dev/example:0.0.1", destination: "oci://ghcr.io/defenseunicorns/dev/example", wantErr: "the source and destination are both ghcr.io/defenseunicorns/dev/example:0.0.1"},
+		{name: "InvalidDestination", source: "oci://ghcr.io/defenseunicorns/dev/example:0.0.1", destination: "oci://registry.internal/Bundles", wantErr: `invalid destination "oci://registry.internal/Bundles"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, dst, err := copyReferences(tt.source, tt.destination)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDst, dst.String())
+		})
+	}
+}
+
+func Test_bundleCopyGraph(t *testing.T) {
+	ctx := context.Background()
+	src := memory.New()
+	push := func(mediaType string, b []byte) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes(mediaType, b)
+		require.NoError(t, src.Push(ctx, desc, bytes.NewReader(b)))
+		return desc
+	}
+	pushJSON := func(mediaType string, v any) ocispec.Descriptor {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return push(mediaType, b)
+	}
+
+	// a Zarf pkg's manifest is a layer of the root manifest, it's pushed as a blob
+	pkgLayer := push(zoci.ZarfLayerMediaTypeBlob, []byte("zarf.yaml"))
+	pkgLayer.Annotations = map[string]string{ocispec.AnnotationTitle: "zarf.yaml"}
+	pkgConfig := push(ocispec.MediaTypeImageConfig, []byte("{}"))
+	pkgManifest := pushJSON(zoci.ZarfLayerMediaTypeBlob, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: pkgConfig, Layers: []ocispec.Descriptor{pkgLayer}})
+
+	bundleYAML := push(zoci.ZarfLayerMediaTypeBlob, []byte("kind: UDSBundle"))
+	bundleYAML.Annotations = map[string]string{ocispec.AnnotationTitle: config.BundleYAML}
+	signature := push(zoci.ZarfLayerMediaTypeBlob, []byte("signature"))
+	rootConfig := push(config.BundleConfigMediaType, []byte(`{"name":"example"}`))
+	rootManifest := pushJSON(ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Config:      rootConfig,
+		Layers:      []ocispec.Descriptor{pkgManifest, bundleYAML},
+		Annotations: map[string]string{config.BundleSignatureDigestAnnotation: signature.Digest.String()},
+	})
+	index := pushJSON(ocispec.MediaTypeImageIndex, ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{rootManifest}})
+
+	srcRef := registry.Reference{Registry: "ghcr.io", Repository: "defenseunicorns/dev/example", Reference: "0.0.1"}
+	dstRef := registry.Reference{Registry: "registry.internal", Repository: "bundles/example", Reference: "0.0.1"}
+	dst := memory.New()
+	opts := bundleCopyGraphOpts(srcRef, dstRef, fakeBlobs{signature.Digest.String(): signature})
+	require.Nil(t, opts.MountFrom)
+	require.NoError(t, oras.CopyGraph(ctx, src, dst, index, opts))
+
+	// every node is copied as is, including the pkg's layers and the detached signature
+	for _, desc := range []ocispec.Descriptor{index, rootManifest, rootConfig, bundleYAML, signature, pkgManifest, pkgConfig, pkgLayer} {
+		exists, err := dst.Exists(ctx, desc)
+		require.NoError(t, err)
+		require.True(t, exists, "%s wasn't copied", desc.Digest)
+	}
+
+	// blobs are mounted within the same registry
+	opts = bundleCopyGraphOpts(srcRef, registry.Reference{Registry: "ghcr.io", Repository: "defenseunicorns/prod/example"}, fakeBlobs{})
+	mountFrom, err := opts.MountFrom(ctx, pkgLayer)
+	require.NoError(t, err)
+	require.Equal(t, []string{"defenseunicorns/dev/example"}, mountFrom)
+}
@@ -5,30 +5,46 @@
 package bundle
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/defenseunicorns/uds-cli/src/config"
 	"github.com/defenseunicorns/uds-cli/src/pkg/bundler"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	zarfConfig "github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/pkg/interactive"
 	"github.com/defenseunicorns/zarf/src/pkg/message"
-	"github.com/defenseunicorns/zarf/src/pkg/utils"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	goyaml "github.com/goccy/go-yaml"
 	"github.com/pterm/pterm"
+	"oras.land/oras-go/v2/registry/remote/auth"
 )
 
-// Create creates a bundle
-func (b *Bundle) Create() error {
+// BundleFileStdin is the --file that reads the bundle's definition from stdin
+const BundleFileStdin = "-"
+
+// Create creates a bundle, canceling ctx (e.g. on Ctrl-C) aborts any in-flight transfers
+func (b *Bundle) Create(ctx context.Context) error {
 
 	// read the bundle's metadata into memory
-	if err := utils.ReadYaml(filepath.Join(b.cfg.CreateOpts.SourceDirectory, b.cfg.CreateOpts.BundleFile), &b.bundle); err != nil {
+	if b.cfg.CreateOpts.BundleFile == BundleFileStdin && !config.CommonOptions.Confirm {
+		return fmt.Errorf("reading the %s from stdin requires --confirm, stdin can't also be used to confirm the create", config.BundleYAML)
+	}
+	src, err := readBundleDefinition(b.cfg.CreateOpts.SourceDirectory, b.cfg.CreateOpts.BundleFile, os.Stdin)
+	if err != nil {
 		return err
 	}
-
-	// confirm creation
-	if ok := b.confirmBundleCreation(); !ok {
-		return fmt.Errorf("bundle creation cancelled")
+	if err := goyaml.Unmarshal(src, &b.bundle); err != nil {
+		return fmt.Errorf("unable to parse the %s: %w", config.BundleYAML, err)
 	}
 
 	// make the bundle's build information
@@ -36,58 +52,336 @@ func (b *Bundle) Create() error {
 		return err
 	}
 
+	// validate the bundle's metadata before making any network calls
+	if err := ValidateBundleMetadata(&b.bundle, src); err != nil {
+		return fmt.Errorf("invalid %s:\n%w", config.BundleYAML, err)
+	}
+	// unknown keys are ignored, they're only reported in case they're typos
+	for _, err := range newMetadataValidator(src).unknownFields() {
+		message.Warn(err.Error())
+	}
+
+	// compose the output from the bundle's metadata when only a registry is given
+	if b.cfg.CreateOpts.Registry != "" {
+		if len(b.cfg.CreateOpts.Outputs) > 0 {
+			return fmt.Errorf("cannot use both --registry and --output, --registry composes the output from the bundle's metadata")
+		}
+		output, err := bundler.RegistryOutput(b.cfg.CreateOpts.Registry, &b.bundle.Metadata)
+		if err != nil {
+			return err
+		}
+		b.cfg.CreateOpts.Outputs = []string{output}
+	}
+
+	if b.cfg.CreateOpts.SignKeyless && b.cfg.CreateOpts.SigningKeyPath != "" {
+		return fmt.Errorf("cannot sign a bundle with both a signing key and keyless signing")
+	}
+	if b.cfg.CreateOpts.RequireSignature && b.cfg.CreateOpts.NoSignaturePrompt {
+		return fmt.Errorf("cannot both require a signature and confirm an unsigned bundle")
+	}
+	if b.cfg.CreateOpts.OutputDigestOnly {
+		if b.cfg.CreateOpts.OutputFormat != "" {
+			return fmt.Errorf("cannot use both --output-digest-only and --output-format, --output-digest-only is the %s output format", bundler.OutputFormatDigest)
+		}
+		b.cfg.CreateOpts.OutputFormat = bundler.OutputFormatDigest
+	}
+	if err := b.validatePlatform(); err != nil {
+		return err
+	}
+	if _, _, err := b.registryCredentials(); err != nil {
+		return err
+	}
+	if b.cfg.CreateOpts.PlatformFromPackage {
+		defer func(arch string) { config.CLIArch = arch }(config.CLIArch)
+		if err := b.platformFromPackage(ctx); err != nil {
+			return err
+		}
+	}
+	if err := validateExtraFiles(b.bundle.ExtraFiles, b.cfg.CreateOpts.SourceDirectory); err != nil {
+		return err
+	}
+
+	// confirm creation
+	if ok := b.confirmBundleCreation(); !ok {
+		return fmt.Errorf("bundle creation cancelled")
+	}
+	if ok := b.confirmUnsignedBundle(); !ok {
+		return fmt.Errorf("bundle creation cancelled")
+	}
+
 	// populate Zarf config
 	zarfConfig.CommonOptions.Insecure = config.CommonOptions.Insecure
 
+	// the timeout starts once the create is confirmed so it only covers the create itself
+	if timeout := b.cfg.CreateOpts.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := b.createPlatforms(ctx, b.createBundle); err != nil {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return fmt.Errorf("bundle creation timed out after %s: %w", b.cfg.CreateOpts.Timeout, err)
+		case errors.Is(ctx.Err(), context.Canceled):
+			return fmt.Errorf("bundle creation was canceled: %w", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// readBundleDefinition reads the bundle's definition from bundleFile, a relative path is in the source directory. A
+// bundleFile of BundleFileStdin is read from stdin instead so a generated definition doesn't have to be written to disk
+func readBundleDefinition(sourceDir, bundleFile string, stdin io.Reader) ([]byte, error) {
+	if bundleFile != BundleFileStdin {
+		if !filepath.IsAbs(bundleFile) {
+			bundleFile = filepath.Join(sourceDir, bundleFile)
+		}
+		return os.ReadFile(bundleFile)
+	}
+	src, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the %s from stdin: %w", config.BundleYAML, err)
+	}
+	if len(bytes.TrimSpace(src)) == 0 {
+		return nil, fmt.Errorf("no %s was read from stdin", config.BundleYAML)
+	}
+	return src, nil
+}
+
+// createPlatforms creates the bundle for the CLI's arch with create, or once per arch for --platform all
+func (b *Bundle) createPlatforms(ctx context.Context, create func(context.Context) error) error {
+	if b.cfg.CreateOpts.Platform != config.PlatformAll {
+		return create(ctx)
+	}
+
+	// create the bundle once per arch, each push adds the arch's root manifest to the bundle's index; the bundle's and
+	// its packages' platforms are resolved from config.GetArch() so the CLI arch is pinned to each arch in turn
+	defer func(arch string) { config.CLIArch = arch }(config.CLIArch)
+	// a create pins the pkgs' refs and paths for its arch, e.g. a remote pkg's ref to its arch's manifest and a local
+	// pkg's path to its arch's tarball, so each arch starts from the pkgs and outputs as they were defined
+	pkgs, outputs := b.bundle.Packages, b.cfg.CreateOpts.Outputs
+	defer func() { b.bundle.Packages, b.cfg.CreateOpts.Outputs = pkgs, outputs }()
+	for _, arch := range config.BundlePlatforms {
+		config.CLIArch = arch
+		b.bundle.Packages, b.cfg.CreateOpts.Outputs = slices.Clone(pkgs), slices.Clone(outputs)
+		if err := b.CalculateBuildInfo(); err != nil {
+			return err
+		}
+		if !b.cfg.CreateOpts.Quiet {
+			message.HeaderInfof("📦 %s BUNDLE", strings.ToUpper(arch))
+		}
+		if err := create(ctx); err != nil {
+			return fmt.Errorf("unable to create the %s bundle: %w", arch, err)
+		}
+	}
+	return nil
+}
+
+// createBundle validates, signs and creates the bundle for the bundle's architecture
+func (b *Bundle) createBundle(ctx context.Context) error {
 	validateSpinner := message.NewProgressSpinner("Validating bundle")
 
 	defer validateSpinner.Stop()
 
 	// validate bundle / verify access to all repositories
-	if err := b.ValidateBundleResources(validateSpinner); err != nil {
+	if err := b.ValidateBundleResources(ctx, validateSpinner); err != nil {
 		return err
 	}
 
 	validateSpinner.Successf("Bundle Validated")
 	pterm.Print()
 
-	// sign the bundle if a signing key was provided
-	if b.cfg.CreateOpts.SigningKeyPath != "" {
-		// write the bundle to disk so we can sign it
-		bundlePath := filepath.Join(b.tmp, config.BundleYAML)
-		if err := utils.WriteYaml(bundlePath, &b.bundle, 0600); err != nil {
+	// record how the bundle was produced, it's part of the signed YAML and the root manifest's config
+	if b.cfg.CreateOpts.Provenance {
+		provenance, err := b.buildProvenance()
+		if err != nil {
 			return err
 		}
+		b.bundle.Build.Provenance = provenance
+	}
 
+	// sign the bundle if a signing key was provided or keyless signing was requested
+	signature, sigAnnotations, err := b.signBundle()
+	if err != nil {
+		return err
+	}
+
+	srcCredential, dstCredential, err := b.registryCredentials()
+	if err != nil {
+		return err
+	}
+
+	extraFiles, err := readExtraFiles(b.bundle.ExtraFiles, b.cfg.CreateOpts.SourceDirectory)
+	if err != nil {
+		return err
+	}
+
+	var vulnScanner bundler.VulnScanner
+	if b.cfg.CreateOpts.VulnDenyList != "" {
+		if vulnScanner, err = bundler.LoadDenyList(b.cfg.CreateOpts.VulnDenyList); err != nil {
+			return err
+		}
+	} else if b.cfg.CreateOpts.FailOnVuln {
+		return fmt.Errorf("--fail-on-vuln requires a --vuln-deny-list to check the package images against")
+	}
+
+	pruneKeep := 0
+	if b.cfg.CreateOpts.PruneOldVersions {
+		if b.cfg.CreateOpts.PruneKeep < 1 {
+			return fmt.Errorf("--prune-old-versions requires --keep with the number of versions to keep, including the one being published")
+		}
+		pruneKeep = b.cfg.CreateOpts.PruneKeep
+	} else if b.cfg.CreateOpts.PruneKeep != 0 {
+		return fmt.Errorf("--keep is only used with --prune-old-versions")
+	}
+
+	opts := bundler.Options{
+		Bundle:               &b.bundle,
+		Outputs:              b.cfg.CreateOpts.Outputs,
+		TmpDir:               config.CommonOptions.TempDirectory,
+		SourceDir:            b.cfg.CreateOpts.SourceDirectory,
+		MaxConcurrency:       b.cfg.CreateOpts.MaxConcurrency,
+		DryRun:               b.cfg.CreateOpts.DryRun,
+		VerifySourceKeys:     b.cfg.CreateOpts.VerifySourceKeys,
+		OutputFormat:         b.cfg.CreateOpts.OutputFormat,
+		SignatureReferrer:    b.cfg.CreateOpts.SignatureReferrer,
+		DetachedSignature:    b.cfg.CreateOpts.DetachedSignature,
+		SBOMFormat:           b.cfg.CreateOpts.SBOMFormat,
+		SBOMReferrers:        b.cfg.CreateOpts.SBOMReferrers,
+		Signature:            signature,
+		SignatureAnnotations: sigAnnotations,
+		VerifySignatureKey:   b.cfg.CreateOpts.VerifySignatureKey,
+		SrcCredential:        srcCredential,
+		SourceMirrors:        b.cfg.CreateOpts.SourceMirrors,
+		RegistryOverrides:    b.cfg.CreateOpts.RegistryOverrides,
+		DstCredential:        dstCredential,
+		RequireSignature:     b.cfg.CreateOpts.RequireSignature,
+		NoCache:              b.cfg.CreateOpts.NoCache,
+		MetadataMediaType:    b.cfg.CreateOpts.MetadataMediaType,
+		ConfigMediaType:      b.cfg.CreateOpts.ConfigMediaType,
+		Force:                b.cfg.CreateOpts.Force,
+		MetricsFile:          b.cfg.CreateOpts.MetricsFile,
+		DigestFile:           b.cfg.CreateOpts.DigestFile,
+		ExtraFiles:           extraFiles,
+		DigestTag:            b.cfg.CreateOpts.DigestTag,
+		CleanupOnFailure:     b.cfg.CreateOpts.CleanupOnFailure,
+		CompressionLevel:     b.cfg.CreateOpts.CompressionLevel,
+		LayerConcurrency:     b.cfg.CreateOpts.LayerConcurrency,
+		ConcurrencyLimit:     b.cfg.CreateOpts.ConcurrencyLimit,
+		AllowedMediaTypes:    b.cfg.CreateOpts.AllowedMediaTypes,
+		VulnScanner:          vulnScanner,
+		FailOnVuln:           b.cfg.CreateOpts.FailOnVuln,
+		UncompressedSize:     b.cfg.CreateOpts.UncompressedSize,
+		PruneKeep:            pruneKeep,
+		Quiet:                b.cfg.CreateOpts.Quiet,
+	}
+	bundlerClient := bundler.NewBundler(&opts)
+	return bundlerClient.Create(ctx)
+}
+
+// validatePlatform validates the --platform flag, a multi-arch bundle can only be created in an OCI registry since it's
+// stored as an OCI index
+func (b *Bundle) validatePlatform() error {
+	opts := b.cfg.CreateOpts
+	if opts.Platform == "" {
+		return nil
+	}
+	if opts.Platform != config.PlatformAll {
+		return fmt.Errorf("unsupported platform %q, the only supported platform is %q", opts.Platform, config.PlatformAll)
+	}
+	if len(opts.Outputs) == 0 || slices.ContainsFunc(opts.Outputs, func(output string) bool { return !utils.IsRegistryURL(output) }) {
+		return fmt.Errorf("the %s platform is only supported when creating a bundle in an OCI registry", config.PlatformAll)
+	}
+	if opts.OutputFormat != "" {
+		// each arch is created separately and would write its own result
+		return fmt.Errorf("the %s output format isn't supported with the %s platform", opts.OutputFormat, config.PlatformAll)
+	}
+	if opts.MetricsFile != "" {
+		// each arch would overwrite the previous arch's metrics
+		return fmt.Errorf("a metrics file isn't supported with the %s platform", config.PlatformAll)
+	}
+	if opts.DigestFile != "" {
+		// each arch has its own root manifest
+		return fmt.Errorf("a digest file isn't supported with the %s platform", config.PlatformAll)
+	}
+	return nil
+}
+
+// registryCredentials parses the --src-creds and --dst-creds flags, empty credentials fall back to the docker config
+func (b *Bundle) registryCredentials() (auth.Credential, auth.Credential, error) {
+	srcCredential, err := utils.ParseCredential(b.cfg.CreateOpts.SrcCreds)
+	if err != nil {
+		return auth.EmptyCredential, auth.EmptyCredential, fmt.Errorf("invalid --src-creds: %w", err)
+	}
+	dstCredential, err := utils.ParseCredential(b.cfg.CreateOpts.DstCreds)
+	if err != nil {
+		return auth.EmptyCredential, auth.EmptyCredential, fmt.Errorf("invalid --dst-creds: %w", err)
+	}
+	return srcCredential, dstCredential, nil
+}
+
+// signBundle signs the bundle's YAML with the signing key or keylessly, returning the signature and the annotations
+// to add to the signature layer. It returns a nil signature if the bundle isn't being signed
+func (b *Bundle) signBundle() ([]byte, map[string]string, error) {
+	if b.cfg.CreateOpts.SigningKeyPath == "" && !b.cfg.CreateOpts.SignKeyless {
+		return nil, nil, nil
+	}
+
+	// write the bundle to disk so we can sign it
+	bundlePath := filepath.Join(b.tmp, config.BundleYAML)
+	if err := zarfUtils.WriteYaml(bundlePath, &b.bundle, 0600); err != nil {
+		return nil, nil, err
+	}
+	return b.signBundleYAML(bundlePath, b.cfg.CreateOpts.SigningKeyPath, b.cfg.CreateOpts.SigningKeyPassword, b.cfg.CreateOpts.SignKeyless)
+}
+
+// signBundleYAML signs the bundle YAML at bundlePath with the signing key or keylessly, returning the signature and
+// the annotations to add to the signature layer
+func (b *Bundle) signBundleYAML(bundlePath, signingKeyPath, signingKeyPassword string, keyless bool) ([]byte, map[string]string, error) {
+	signaturePath := filepath.Join(b.tmp, config.BundleYAMLSignature)
+
+	var sigAnnotations map[string]string
+	if keyless {
+		signed, err := utils.CosignSignBlobKeyless(bundlePath, signaturePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		sigAnnotations = map[string]string{
+			config.BundleSignatureCertificateAnnotation:   string(signed.Certificate),
+			config.BundleSignatureRekorLogIndexAnnotation: strconv.FormatInt(signed.RekorLogIndex, 10),
+			config.BundleSignatureRekorLogIDAnnotation:    signed.RekorLogID,
+		}
+	} else {
 		getSigCreatePassword := func(_ bool) ([]byte, error) {
-			if b.cfg.CreateOpts.SigningKeyPassword != "" {
-				return []byte(b.cfg.CreateOpts.SigningKeyPassword), nil
+			if signingKeyPassword != "" {
+				return []byte(signingKeyPassword), nil
 			}
 			return interactive.PromptSigPassword()
 		}
 		// sign the bundle
-		signaturePath := filepath.Join(b.tmp, config.BundleYAMLSignature)
-		_, err := utils.CosignSignBlob(bundlePath, signaturePath, b.cfg.CreateOpts.SigningKeyPath, getSigCreatePassword)
-		if err != nil {
-			return err
+		if _, err := zarfUtils.CosignSignBlob(bundlePath, signaturePath, signingKeyPath, getSigCreatePassword); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	opts := bundler.Options{
-		Bundle:    &b.bundle,
-		Output:    b.cfg.CreateOpts.Output,
-		TmpDstDir: b.tmp,
-		SourceDir: b.cfg.CreateOpts.SourceDirectory,
+	// the signature is written base64 encoded, which is how it's stored in the bundle
+	signature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return nil, nil, err
 	}
-	bundlerClient := bundler.NewBundler(&opts)
-	return bundlerClient.Create()
+	return signature, sigAnnotations, nil
 }
 
 // confirmBundleCreation prompts the user to confirm bundle creation
 func (b *Bundle) confirmBundleCreation() (confirm bool) {
+	// the definition is printed to review before confirming, a quiet create that's already confirmed skips it
+	if b.cfg.CreateOpts.Quiet && config.CommonOptions.Confirm {
+		return true
+	}
 
 	message.HeaderInfof("🎁 BUNDLE DEFINITION")
-	utils.ColorPrintYAML(b.bundle, nil, false)
+	zarfUtils.ColorPrintYAML(b.bundle, nil, false)
 
 	message.HorizontalRule()
 	pterm.Println()
@@ -107,3 +401,25 @@ func (b *Bundle) confirmBundleCreation() (confirm bool) {
 	}
 	return true
 }
+
+// confirmUnsignedBundle warns that the bundle won't be signed and prompts the user to confirm it, unless the bundle is
+// being signed, a signature is required (the create fails instead) or the unsigned bundle was confirmed with a flag
+func (b *Bundle) confirmUnsignedBundle() (confirm bool) {
+	opts := b.cfg.CreateOpts
+	if opts.SigningKeyPath != "" || opts.SignKeyless || opts.RequireSignature || opts.NoSignaturePrompt {
+		return true
+	}
+
+	message.Warn("This bundle won't be signed, use --signing-key or --sign-with-cosign-keyless to sign it or --no-signature-prompt to confirm it's intentionally unsigned")
+	if config.CommonOptions.Confirm {
+		return true
+	}
+
+	prompt := &survey.Confirm{
+		Message: "Create this bundle without a signature?",
+	}
+	if err := survey.AskOne(prompt, &confirm); err != nil || !confirm {
+		return false
+	}
+	return true
+}
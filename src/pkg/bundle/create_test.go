@@ -0,0 +1,103 @@
+package bundle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readBundleDefinition(t *testing.T) {
+	dir := t.TempDir()
+	definition := "kind: UDSBundle\nmetadata:\n  name: test\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, config.BundleYAML), []byte(definition), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("other"), 0600))
+
+	tests := []struct {
+		name       string
+		bundleFile string
+		stdin      string
+		expected   string
+		err        string
+	}{
+		{
+			name:       "file in the source directory",
+			bundleFile: config.BundleYAML,
+			expected:   definition,
+		},
+		{
+			name:       "absolute path",
+			bundleFile: filepath.Join(dir, "other.yaml"),
+			expected:   "other",
+		},
+		{
+			name:       "stdin",
+			bundleFile: BundleFileStdin,
+			stdin:      definition,
+			expected:   definition,
+		},
+		{
+			name:       "empty stdin",
+			bundleFile: BundleFileStdin,
+			stdin:      " \n",
+			err:        "no uds-bundle.yaml was read from stdin",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := readBundleDefinition(dir, tt.bundleFile, strings.NewReader(tt.stdin))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(src))
+		})
+	}
+}
+
+func Test_createPlatforms(t *testing.T) {
+	b := Bundle{
+		cfg: &types.BundleConfig{CreateOpts: types.BundleCreateOptions{
+			Platform:        config.PlatformAll,
+			SourceDirectory: "src",
+			Outputs:         []string{"localhost:5000/dev"},
+			Quiet:           true,
+		}},
+		bundle: types.UDSBundle{
+			Metadata: types.UDSMetadata{Name: "test", Version: "0.0.1"},
+			Packages: []types.Package{
+				{Name: "local", Path: "packages", Ref: "0.0.1"},
+				{Name: "remote", Repository: "ghcr.io/defenseunicorns/packages/podinfo", Ref: "0.0.1"},
+			},
+		},
+	}
+
+	// each arch pins the pkgs like a create does: the local pkg's path to its arch's tarball and ref to the digest
+	// it's staged with, and the remote pkg's ref to its arch's manifest
+	type created struct{ arch, localPath, localRef, remoteRef, output string }
+	var creates []created
+	create := func(context.Context) error {
+		arch := config.GetArch()
+		pkgs := b.bundle.Packages
+		pkgs[0].Path = getPkgPath(pkgs[0], arch, b.cfg.CreateOpts.SourceDirectory)
+		pkgs[0].Ref += "@sha256:staged-" + arch
+		pkgs[1].Ref += "@sha256:" + arch
+		b.cfg.CreateOpts.Outputs[0] = "oci://" + b.cfg.CreateOpts.Outputs[0]
+		creates = append(creates, created{arch, pkgs[0].Path, pkgs[0].Ref, pkgs[1].Ref, b.cfg.CreateOpts.Outputs[0]})
+		return nil
+	}
+	require.NoError(t, b.createPlatforms(context.Background(), create))
+
+	require.Equal(t, []created{
+		{"amd64", "src/packages/zarf-package-local-amd64-0.0.1.tar.zst", "0.0.1@sha256:staged-amd64", "0.0.1@sha256:amd64", "oci://localhost:5000/dev"},
+		{"arm64", "src/packages/zarf-package-local-arm64-0.0.1.tar.zst", "0.0.1@sha256:staged-arm64", "0.0.1@sha256:arm64", "oci://localhost:5000/dev"},
+	}, creates)
+	require.Equal(t, "packages", b.bundle.Packages[0].Path)
+	require.Equal(t, []string{"localhost:5000/dev"}, b.cfg.CreateOpts.Outputs)
+}
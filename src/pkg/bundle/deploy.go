@@ -39,25 +39,90 @@ var templatedVarRegex = regexp.MustCompile(`\${([^}]+)}`)
 func (b *Bundle) Deploy() error {
 	resume := b.cfg.DeployOpts.Resume
 
+	if err := b.loadVarsFiles(); err != nil {
+		return err
+	}
+	if b.hasSetValues() {
+		// the components of the pkgs are needed to check the Helm values set with --set
+		zarfYAMLs, err := b.planZarfYAMLs(b.cfg.DeployOpts.Source)
+		if err != nil {
+			return err
+		}
+		if err := b.loadSetValues(zarfYAMLs); err != nil {
+			return err
+		}
+	}
+
+	packages, err := orderPackages(b.bundle.Packages)
+	if err != nil {
+		return err
+	}
 	// Check if --packages flag is set and zarf packages have been specified
-	var packagesToDeploy []types.Package
-	if len(b.cfg.DeployOpts.Packages) != 0 {
-		userSpecifiedPackages := strings.Split(strings.ReplaceAll(b.cfg.DeployOpts.Packages[0], " ", ""), ",")
+	packagesToDeploy, err := selectPackages(packages, b.cfg.DeployOpts.Packages)
+	if err != nil {
+		return err
+	}
+	packagesToDeploy, skipped, err := skipPackages(packagesToDeploy, b.cfg.DeployOpts.SkipPackages)
+	if err != nil {
+		return err
+	}
+	if len(skipped) > 0 {
+		message.Infof("Skipping %s", strings.Join(packageNames(skipped), ", "))
+	}
 
-		for _, pkg := range b.bundle.Packages {
-			if slices.Contains(userSpecifiedPackages, pkg.Name) {
-				packagesToDeploy = append(packagesToDeploy, pkg)
+	// record the deploy in the cluster so the bundle can be rolled back to what was deployed before it
+	recorder := b.startDeployRecord(packagesToDeploy)
+	err = deployPackages(packagesToDeploy, resume, b)
+	recorder.finish(err)
+	if err != nil {
+		return err
+	}
+	if len(skipped) > 0 {
+		message.Successf("Deployed %s, skipped %s", strings.Join(packageNames(packagesToDeploy), ", "), strings.Join(packageNames(skipped), ", "))
+	}
+	return nil
+}
+
+// skipPackages removes the Zarf pkgs named in the --skip-packages flag from the pkgs being deployed and returns them
+// separately. A pkg can't be skipped if a pkg that's deployed dependsOn it or imports one of its variables
+func skipPackages(packages []types.Package, specified []string) ([]types.Package, []types.Package, error) {
+	if len(specified) == 0 {
+		return packages, nil, nil
+	}
+	userSkippedPackages := strings.Split(strings.ReplaceAll(strings.Join(specified, ","), " ", ""), ",")
+	var deployed, skipped []types.Package
+	for _, pkg := range packages {
+		if slices.Contains(userSkippedPackages, pkg.Name) {
+			skipped = append(skipped, pkg)
+		} else {
+			deployed = append(deployed, pkg)
+		}
+	}
+	if len(userSkippedPackages) != len(skipped) {
+		return nil, nil, fmt.Errorf("invalid zarf packages specified by --skip-packages")
+	}
+	for _, pkg := range deployed {
+		for _, dep := range pkg.DependsOn {
+			if slices.Contains(userSkippedPackages, dep) {
+				return nil, nil, fmt.Errorf("unable to skip %s, %s depends on it", dep, pkg.Name)
 			}
 		}
-
-		// Check if invalid packages were specified
-		if len(userSpecifiedPackages) != len(packagesToDeploy) {
-			return fmt.Errorf("invalid zarf packages specified by --packages")
+		for _, imp := range pkg.Imports {
+			if slices.Contains(userSkippedPackages, imp.Package) {
+				return nil, nil, fmt.Errorf("unable to skip %s, %s imports its %s variable", imp.Package, pkg.Name, imp.Name)
+			}
 		}
-		return deployPackages(packagesToDeploy, resume, b)
 	}
+	return deployed, skipped, nil
+}
 
-	return deployPackages(b.bundle.Packages, resume, b)
+// packageNames returns the names of the Zarf pkgs
+func packageNames(packages []types.Package) []string {
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+	return names
 }
 
 func deployPackages(packages []types.Package, resume bool, b *Bundle) error {
@@ -160,49 +225,70 @@ func deployPackages(packages []types.Package, resume bool, b *Bundle) error {
 	return nil
 }
 
-// loadVariables loads and sets precedence for config-level and imported variables
+// loadVarsFiles reads the variables file given for each Zarf pkg with --vars-file into DeployOpts.FileVariables, the
+// pkgs must be in the bundle so a typo in a pkg name doesn't silently drop its variables
+func (b *Bundle) loadVarsFiles() error {
+	if len(b.cfg.DeployOpts.VarsFiles) == 0 {
+		return nil
+	}
+	fileVars := make(map[string]map[string]interface{}, len(b.cfg.DeployOpts.VarsFiles))
+	for pkgName, path := range b.cfg.DeployOpts.VarsFiles {
+		if !slices.ContainsFunc(b.bundle.Packages, func(pkg types.Package) bool { return pkg.Name == pkgName }) {
+			return fmt.Errorf("invalid --vars-file %s=%s, the bundle doesn't have a zarf pkg named %s", pkgName, path, pkgName)
+		}
+		var vars map[string]interface{}
+		if err := utils.ReadYaml(path, &vars); err != nil {
+			return fmt.Errorf("unable to read the variables file %s for zarf pkg %s: %w", path, pkgName, err)
+		}
+		// ensure the vars are uppercase like the ones read from uds-config.yaml
+		pkgVars := make(map[string]interface{}, len(vars))
+		for name, val := range vars {
+			pkgVars[strings.ToUpper(name)] = val
+		}
+		fileVars[pkgName] = pkgVars
+	}
+	b.cfg.DeployOpts.FileVariables = fileVars
+	return nil
+}
+
+// loadVariables loads and sets precedence for bundle-level, config-level and imported variables
 func (b *Bundle) loadVariables(pkg types.Package, bundleExportedVars map[string]map[string]string) map[string]string {
 	pkgVars := make(map[string]string)
 
+	// Set variables in order or precedence (least specific to most specific)
+	// bundle vars (the defaults in the bundle's variables)
+	for name, val := range b.bundleVariables() {
+		pkgVars[name] = variableString(val)
+	}
 	// load all exported variables
 	for _, exportedVarMap := range bundleExportedVars {
 		for varName, varValue := range exportedVarMap {
 			pkgVars[strings.ToUpper(varName)] = varValue
 		}
 	}
-
-	// Set variables in order or precedence (least specific to most specific)
 	// imported vars
 	for _, imp := range pkg.Imports {
 		pkgVars[strings.ToUpper(imp.Name)] = bundleExportedVars[imp.Package][imp.Name]
 	}
 	// shared vars
 	for name, val := range b.cfg.DeployOpts.SharedVariables {
-		pkgVars[strings.ToUpper(name)] = fmt.Sprint(val)
+		pkgVars[strings.ToUpper(name)] = variableString(val)
 	}
 	// config vars
 	for name, val := range b.cfg.DeployOpts.Variables[pkg.Name] {
-		pkgVars[strings.ToUpper(name)] = fmt.Sprint(val)
-	}
-	// env vars (vars that start with UDS_)
-	for _, envVar := range os.Environ() {
-		if strings.HasPrefix(envVar, config.EnvVarPrefix) {
-			parts := strings.Split(envVar, "=")
-			pkgVars[strings.ToUpper(strings.TrimPrefix(parts[0], config.EnvVarPrefix))] = parts[1]
-		}
-	}
-	// set vars (vars set with --set flag)
-	for name, val := range b.cfg.DeployOpts.SetVariables {
-		// Check if setting package specific variable (ex. packageName.variableName)
-		splitName := strings.Split(name, string("."))
-		if len(splitName) == 2 {
-			packageName, variableName := splitName[0], splitName[1]
-			if packageName == pkg.Name {
-				pkgVars[strings.ToUpper(variableName)] = fmt.Sprint(val)
-			}
-		} else {
-			pkgVars[strings.ToUpper(name)] = fmt.Sprint(val)
-		}
+		pkgVars[strings.ToUpper(name)] = variableString(val)
+	}
+	// vars file vars (vars read from the pkg's --vars-file)
+	for name, val := range b.cfg.DeployOpts.FileVariables[pkg.Name] {
+		pkgVars[strings.ToUpper(name)] = variableString(val)
+	}
+	// env vars (vars that start with UDS_, UDS_VAR_ takes precedence)
+	for name, val := range envVariables() {
+		pkgVars[name] = val
+	}
+	// set vars (vars set with --set flag, packageName.variableName takes precedence)
+	for name, val := range b.setVariables(pkg.Name) {
+		pkgVars[name] = val
 	}
 	return pkgVars
 }
@@ -252,6 +338,15 @@ func (b *Bundle) loadChartOverrides(pkg types.Package, pkgVars map[string]string
 			b.processOverrideNamespaces(nsOverrides, chartCopy.Namespace, componentName, chartName)
 		}
 	}
+	// the Helm values set with --set take precedence over the bundle's overrides
+	for componentName, component := range b.cfg.DeployOpts.SetValues[pkg.Name] {
+		for chartName, chartValues := range component {
+			chartValues := chartValues
+			if err := b.processOverrideValues(&overrideMap, &chartValues, componentName, chartName, nil); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
 
 	processed := make(PkgOverrideMap)
 
@@ -312,7 +407,7 @@ func (b *Bundle) PreDeployValidation() (string, string, string, error) {
 	}
 
 	// validate the sig (if present)
-	if err := ValidateBundleSignature(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], b.cfg.DeployOpts.PublicKeyPath); err != nil {
+	if err := ValidateBundleSignature(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], loaded[config.BundleYAMLCertificate], b.cfg.DeployOpts.PublicKeyPath); err != nil {
 		return "", "", "", err
 	}
 
@@ -357,41 +452,47 @@ func (b *Bundle) processOverrideValues(overrideMap *map[string]map[string]*value
 
 // processOverrideVariables processes bundle variables overrides and adds them to the override map
 func (b *Bundle) processOverrideVariables(overrideMap *map[string]map[string]*values.Options, pkgName string, variables *[]types.BundleChartVariable, componentName string, chartName string) error {
+	setVars := b.setVariables(pkgName)
+	envVars := envVariables()
+	bundleVars := b.bundleVariables()
 	for _, v := range *variables {
 		var overrideVal interface{}
 		// Ensuring variable name is upper case since comparisons are being done against upper case env and config variables
 		v.Name = strings.ToUpper(v.Name)
 
 		// check for override in --set vars
-		for k, val := range b.cfg.DeployOpts.SetVariables {
-			if strings.Contains(k, ".") {
-				// check for <pkg>.<var> syntax was used in --set and use uppercase for a non-case-sensitive comparison
-				setVal := strings.Split(k, ".")
-				if setVal[0] == pkgName && strings.ToUpper(setVal[1]) == v.Name {
-					overrideVal = val
-				}
-			} else if strings.ToUpper(k) == v.Name {
-				overrideVal = val
-			}
+		if setOverride, exists := setVars[v.Name]; exists {
+			overrideVal = setOverride
 		}
 
 		// check for override in env vars if not in --set
-		if envVarOverride, exists := os.LookupEnv(strings.ToUpper(config.EnvVarPrefix + v.Name)); overrideVal == nil && exists {
+		if envVarOverride, exists := envVars[v.Name]; overrideVal == nil && exists {
 			overrideVal = envVarOverride
 		}
 
-		// if not in --set or an env var, use the following precedence: configFile, sharedConfig, default
+		// if not in --set or an env var, use the following precedence: varsFile, configFile, sharedConfig, default, bundle default
+		bundleDefault, existsInBundle := bundleVars[v.Name]
 		if overrideVal == nil {
-			if configFileOverride, existsInConfig := b.cfg.DeployOpts.Variables[pkgName][v.Name]; existsInConfig {
+			if varsFileOverride, existsInVarsFile := b.cfg.DeployOpts.FileVariables[pkgName][v.Name]; existsInVarsFile {
+				overrideVal = varsFileOverride
+			} else if configFileOverride, existsInConfig := b.cfg.DeployOpts.Variables[pkgName][v.Name]; existsInConfig {
 				overrideVal = configFileOverride
 			} else if sharedConfigOverride, existsInSharedConfig := b.cfg.DeployOpts.SharedVariables[v.Name]; existsInSharedConfig {
 				overrideVal = sharedConfigOverride
 			} else if v.Default != nil {
 				overrideVal = v.Default
+			} else if existsInBundle {
+				overrideVal = bundleDefault
 			} else {
 				continue
 			}
 		}
+		if existsInBundle {
+			var err error
+			if overrideVal, err = coerceVariable(v.Name, overrideVal, bundleDefault); err != nil {
+				return err
+			}
+		}
 
 		// Add the override to the map, or return an error if the path is invalid
 		if err := addOverrideValue(*overrideMap, componentName, chartName, v.Path, overrideVal, nil); err != nil {
@@ -452,7 +553,7 @@ func addOverrideValue(overrides map[string]map[string]*values.Options, component
 			value = setTemplatedVariables(templatedVariable, pkgVars)
 		}
 		// handle default case of simple values like strings and numbers
-		helmVal := fmt.Sprintf("%s=%v", valuePath, value)
+		helmVal := fmt.Sprintf("%s=%s", valuePath, variableString(value))
 		overrides[component][chart].Values = append(overrides[component][chart].Values, helmVal)
 	}
 	return nil
@@ -2,6 +2,7 @@ package bundle
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -242,6 +243,60 @@ func TestLoadVariablesPrecedence(t *testing.T) {
 	}
 }
 
+func TestLoadVarsFiles(t *testing.T) {
+	tmp := t.TempDir()
+	varsFile := filepath.Join(tmp, "vars.yaml")
+	require.NoError(t, os.WriteFile(varsFile, []byte("foo: set from vars file\nreplicas: 3\n"), 0600))
+
+	testCases := []struct {
+		name         string
+		varsFiles    map[string]string
+		expectedVars map[string]map[string]interface{}
+		expectedErr  string
+	}{
+		{
+			name:      "pkg vars file",
+			varsFiles: map[string]string{"fooPkg": varsFile},
+			expectedVars: map[string]map[string]interface{}{
+				"fooPkg": {"FOO": "set from vars file", "REPLICAS": uint64(3)},
+			},
+		},
+		{
+			name:        "pkg not in bundle",
+			varsFiles:   map[string]string{"bazPkg": varsFile},
+			expectedErr: "invalid --vars-file bazPkg=" + varsFile + ", the bundle doesn't have a zarf pkg named bazPkg",
+		},
+		{
+			name:        "missing vars file",
+			varsFiles:   map[string]string{"fooPkg": filepath.Join(tmp, "missing.yaml")},
+			expectedErr: "unable to read the variables file " + filepath.Join(tmp, "missing.yaml") + " for zarf pkg fooPkg",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := Bundle{
+				cfg:    &types.BundleConfig{DeployOpts: types.BundleDeployOptions{VarsFiles: tc.varsFiles}},
+				bundle: types.UDSBundle{Packages: []types.Package{{Name: "fooPkg"}, {Name: "barPkg"}}},
+			}
+			err := b.loadVarsFiles()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVars, b.cfg.DeployOpts.FileVariables)
+
+			// vars file vars take precedence over uds-config.yaml vars for their pkg only
+			b.cfg.DeployOpts.Variables = map[string]map[string]interface{}{
+				"fooPkg": {"FOO": "set from variables key in uds-config.yaml"},
+			}
+			os.Unsetenv("UDS_FOO")
+			require.Equal(t, "set from vars file", b.loadVariables(types.Package{Name: "fooPkg"}, nil)["FOO"])
+			require.NotContains(t, b.loadVariables(types.Package{Name: "barPkg"}, nil), "FOO")
+		})
+	}
+}
+
 func TestHelmOverrideVariablePrecedence(t *testing.T) {
 	// args for b.processOverrideVariables fn
 	type args struct {
@@ -430,3 +485,42 @@ func TestHelmOverrideVariablePrecedence(t *testing.T) {
 		})
 	}
 }
+
+func Test_skipPackages(t *testing.T) {
+	packages := []types.Package{
+		{Name: "init"},
+		{Name: "podinfo", Exports: []types.BundleVariableExport{{Name: "DOMAIN"}}},
+		{Name: "nginx", Imports: []types.BundleVariableImport{{Name: "DOMAIN", Package: "podinfo"}}},
+		{Name: "tests", DependsOn: []string{"init"}},
+	}
+	tests := []struct {
+		name         string
+		specified    []string
+		wantDeployed []string
+		wantSkipped  []string
+		wantErr      string
+	}{
+		{name: "none skipped", specified: nil, wantDeployed: []string{"init", "podinfo", "nginx", "tests"}},
+		{name: "skipped", specified: []string{"tests", " nginx"}, wantDeployed: []string{"init", "podinfo"}, wantSkipped: []string{"nginx", "tests"}},
+		{name: "dependency and dependent skipped", specified: []string{"podinfo,nginx"}, wantDeployed: []string{"init", "tests"}, wantSkipped: []string{"podinfo", "nginx"}},
+		{name: "unknown package", specified: []string{"unknown"}, wantErr: "invalid zarf packages specified by --skip-packages"},
+		{name: "dependency skipped", specified: []string{"podinfo"}, wantErr: "unable to skip podinfo, nginx imports its DOMAIN variable"},
+		{name: "dependsOn skipped", specified: []string{"init"}, wantErr: "unable to skip init, tests depends on it"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployed, skipped, err := skipPackages(packages, tt.specified)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDeployed, packageNames(deployed))
+			if tt.wantSkipped == nil {
+				require.Empty(t, skipped)
+				return
+			}
+			require.Equal(t, tt.wantSkipped, packageNames(skipped))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/utils"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// PackageAdded is a Zarf pkg that's only in the bundle being compared to
+	PackageAdded = "added"
+	// PackageRemoved is a Zarf pkg that's only in the bundle being compared from
+	PackageRemoved = "removed"
+	// PackageChanged is a Zarf pkg whose ref or digest changed between the bundles
+	PackageChanged = "changed"
+)
+
+// BundleDiff is the difference between the Zarf pkgs of two bundles
+type BundleDiff struct {
+	From        string        `json:"from"`
+	FromVersion string        `json:"fromVersion"`
+	To          string        `json:"to"`
+	ToVersion   string        `json:"toVersion"`
+	Packages    []PackageDiff `json:"packages"`
+}
+
+// PackageDiff is a Zarf pkg that was added, removed or changed between two bundles
+type PackageDiff struct {
+	Name   string `json:"name"`
+	Change string `json:"change"`
+	// FromRef and ToRef are the pkg's ref in each bundle, empty if the pkg isn't in that bundle
+	FromRef string `json:"fromRef,omitempty"`
+	ToRef   string `json:"toRef,omitempty"`
+	// FromDigest and ToDigest are the digest of the pkg's manifest in each bundle
+	FromDigest string `json:"fromDigest,omitempty"`
+	ToDigest   string `json:"toDigest,omitempty"`
+}
+
+// diffSide is a bundle's Zarf pkgs along with the digest of each pkg's manifest in the bundle
+type diffSide struct {
+	bundle     types.UDSBundle
+	pkgDigests map[string]string
+}
+
+// Diff compares the Zarf pkgs of two bundles and shows which were added, removed or changed
+func (b *Bundle) Diff() error {
+	from, err := b.loadDiffSide(b.cfg.DiffOpts.From, "from")
+	if err != nil {
+		return err
+	}
+	to, err := b.loadDiffSide(b.cfg.DiffOpts.To, "to")
+	if err != nil {
+		return err
+	}
+
+	diff := BundleDiff{
+		From:        b.cfg.DiffOpts.From,
+		FromVersion: from.bundle.Metadata.Version,
+		To:          b.cfg.DiffOpts.To,
+		ToVersion:   to.bundle.Metadata.Version,
+		Packages:    diffPackages(from, to),
+	}
+
+	if b.cfg.DiffOpts.JSON {
+		output, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(output) + "\n")
+		return nil
+	}
+
+	message.Infof("Comparing %s (%s) to %s (%s)", diff.From, diff.FromVersion, diff.To, diff.ToVersion)
+	if len(diff.Packages) == 0 {
+		message.Successf("No package changes between the bundles")
+		return nil
+	}
+	var rows [][]string
+	for _, pkg := range diff.Packages {
+		rows = append(rows, []string{pkg.Name, pkg.Change, diffColumn(pkg.FromRef, pkg.FromDigest), diffColumn(pkg.ToRef, pkg.ToDigest)})
+	}
+	message.Table([]string{"Package", "Change", "From", "To"}, rows)
+	return nil
+}
+
+// loadDiffSide loads a bundle's metadata and root manifest into its own directory, the bundles can have the same
+// metadata file names
+func (b *Bundle) loadDiffSide(source string, dir string) (diffSide, error) {
+	source, err := CheckOCISourcePath(source)
+	if err != nil {
+		return diffSide{}, err
+	}
+	dst := filepath.Join(b.tmp, dir)
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return diffSide{}, err
+	}
+	provider, err := NewBundleProvider(source, dst)
+	if err != nil {
+		return diffSide{}, err
+	}
+	loaded, err := provider.LoadBundleMetadata()
+	if err != nil {
+		return diffSide{}, err
+	}
+	side := diffSide{pkgDigests: make(map[string]string)}
+	if err := utils.ReadYaml(loaded[config.BundleYAML], &side.bundle); err != nil {
+		return diffSide{}, err
+	}
+	rootManifest, err := provider.getBundleManifest()
+	if err != nil {
+		return diffSide{}, err
+	}
+	if err := side.setPkgDigests(rootManifest.Layers); err != nil {
+		return diffSide{}, fmt.Errorf("unable to read the packages of %s: %w", source, err)
+	}
+	return side, nil
+}
+
+// setPkgDigests maps each Zarf pkg to the digest of its manifest, the pkg manifests are the root manifest's
+// non-metadata layers in the same order as the bundle's packages
+func (s *diffSide) setPkgDigests(layers []ocispec.Descriptor) error {
+	var pkgLayers []ocispec.Descriptor
+	for _, layer := range layers {
+		if !isBundleMetadataLayer(layer) {
+			pkgLayers = append(pkgLayers, layer)
+		}
+	}
+	if len(pkgLayers) != len(s.bundle.Packages) {
+		return fmt.Errorf("the root manifest has %d package layers but %s has %d packages", len(pkgLayers), config.BundleYAML, len(s.bundle.Packages))
+	}
+	for i, pkg := range s.bundle.Packages {
+		s.pkgDigests[pkg.Name] = pkgLayers[i].Digest.String()
+	}
+	return nil
+}
+
+// diffPackages compares the Zarf pkgs of two bundles by name, the changed and removed pkgs are listed in the order of
+// the bundle being compared from, followed by the added pkgs
+func diffPackages(from, to diffSide) []PackageDiff {
+	toPkgs := make(map[string]types.Package)
+	for _, pkg := range to.bundle.Packages {
+		toPkgs[pkg.Name] = pkg
+	}
+	fromPkgs := make(map[string]bool)
+
+	var diffs []PackageDiff
+	for _, fromPkg := range from.bundle.Packages {
+		fromPkgs[fromPkg.Name] = true
+		toPkg, ok := toPkgs[fromPkg.Name]
+		if !ok {
+			diffs = append(diffs, PackageDiff{Name: fromPkg.Name, Change: PackageRemoved, FromRef: fromPkg.Ref, FromDigest: from.pkgDigests[fromPkg.Name]})
+			continue
+		}
+		fromDigest, toDigest := from.pkgDigests[fromPkg.Name], to.pkgDigests[toPkg.Name]
+		if fromPkg.Ref != toPkg.Ref || fromDigest != toDigest {
+			diffs = append(diffs, PackageDiff{
+				Name:       fromPkg.Name,
+				Change:     PackageChanged,
+				FromRef:    fromPkg.Ref,
+				ToRef:      toPkg.Ref,
+				FromDigest: fromDigest,
+				ToDigest:   toDigest,
+			})
+		}
+	}
+	for _, toPkg := range to.bundle.Packages {
+		if !fromPkgs[toPkg.Name] {
+			diffs = append(diffs, PackageDiff{Name: toPkg.Name, Change: PackageAdded, ToRef: toPkg.Ref, ToDigest: to.pkgDigests[toPkg.Name]})
+		}
+	}
+	return diffs
+}
+
+// diffColumn formats a pkg's ref and digest for the diff table
+func diffColumn(ref string, digest string) string {
+	if ref == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", ref, digest)
+}
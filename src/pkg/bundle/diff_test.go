@@ -0,0 +1,53 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_diffPackages(t *testing.T) {
+	pkgLayer := func(name string) ocispec.Descriptor {
+		return content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte(name))
+	}
+	yamlLayer := ocispec.Descriptor{Annotations: map[string]string{ocispec.AnnotationTitle: config.BundleYAML}}
+	side := func(layers []ocispec.Descriptor, pkgs ...types.Package) diffSide {
+		s := diffSide{bundle: types.UDSBundle{Packages: pkgs}, pkgDigests: make(map[string]string)}
+		require.NoError(t, s.setPkgDigests(layers))
+		return s
+	}
+
+	from := side([]ocispec.Descriptor{pkgLayer("init"), pkgLayer("podinfo"), pkgLayer("nginx"), yamlLayer},
+		types.Package{Name: "init", Ref: "v0.32.6"},
+		types.Package{Name: "podinfo", Ref: "0.0.1"},
+		types.Package{Name: "nginx", Ref: "0.0.1"},
+	)
+	to := side([]ocispec.Descriptor{pkgLayer("init"), pkgLayer("podinfo v2"), pkgLayer("nginx v2"), pkgLayer("prometheus"), yamlLayer},
+		types.Package{Name: "init", Ref: "v0.32.6"},
+		types.Package{Name: "podinfo", Ref: "0.0.2"},
+		// same ref, different content
+		types.Package{Name: "nginx", Ref: "0.0.1"},
+		types.Package{Name: "prometheus", Ref: "0.0.1"},
+	)
+	diffs := diffPackages(from, to)
+	require.Equal(t, []PackageDiff{
+		{Name: "podinfo", Change: PackageChanged, FromRef: "0.0.1", ToRef: "0.0.2", FromDigest: pkgLayer("podinfo").Digest.String(), ToDigest: pkgLayer("podinfo v2").Digest.String()},
+		{Name: "nginx", Change: PackageChanged, FromRef: "0.0.1", ToRef: "0.0.1", FromDigest: pkgLayer("nginx").Digest.String(), ToDigest: pkgLayer("nginx v2").Digest.String()},
+		{Name: "prometheus", Change: PackageAdded, ToRef: "0.0.1", ToDigest: pkgLayer("prometheus").Digest.String()},
+	}, diffs)
+
+	// swapping the sides reports the added pkg as removed
+	diffs = diffPackages(to, from)
+	require.Len(t, diffs, 3)
+	require.Equal(t, PackageDiff{Name: "prometheus", Change: PackageRemoved, FromRef: "0.0.1", FromDigest: pkgLayer("prometheus").Digest.String()}, diffs[2])
+
+	require.Empty(t, diffPackages(from, from))
+
+	// the pkg layers must line up with the bundle's packages
+	mismatched := diffSide{bundle: types.UDSBundle{Packages: []types.Package{{Name: "init"}}}, pkgDigests: make(map[string]string)}
+	require.Error(t, mismatched.setPkgDigests([]ocispec.Descriptor{yamlLayer}))
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/bundler"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+)
+
+// maxExtraFileSize bounds each of the bundle's extra files, they're meant for small documents like a LICENSE or a
+// deploy README and are read into memory to be pushed
+const maxExtraFileSize = 10 * 1024 * 1024
+
+// validateExtraFiles checks that each of the bundle's extra files exists in the source dir, is a regular file and
+// isn't larger than maxExtraFileSize
+func validateExtraFiles(paths []string, srcDir string) error {
+	for i, path := range paths {
+		info, err := os.Stat(filepath.Join(srcDir, filepath.FromSlash(path)))
+		if err != nil {
+			return fmt.Errorf("%s .extraFiles[%d] %s can't be bundled: %w", config.BundleYAML, i, path, err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s .extraFiles[%d] %s isn't a regular file, only files can be bundled", config.BundleYAML, i, path)
+		}
+		if info.Size() > maxExtraFileSize {
+			return fmt.Errorf("%s .extraFiles[%d] %s is larger than %s, the most an extra file can be", config.BundleYAML, i, path,
+				zarfUtils.ByteFormat(maxExtraFileSize, 2))
+		}
+	}
+	return nil
+}
+
+// readExtraFiles reads the bundle's extra files from the source dir, they must have been validated with
+// validateExtraFiles
+func readExtraFiles(paths []string, srcDir string) ([]bundler.ExtraFile, error) {
+	var files []bundler.ExtraFile
+	for _, path := range paths {
+		contents, err := os.ReadFile(filepath.Join(srcDir, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read extra file %s: %w", path, err)
+		}
+		files = append(files, bundler.ExtraFile{Path: path, Contents: contents})
+	}
+	return files, nil
+}
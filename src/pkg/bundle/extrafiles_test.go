@@ -0,0 +1,63 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/pkg/bundler"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateExtraFiles(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "LICENSE"), []byte("Apache-2.0"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "docs"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "docs", "README.md"), []byte("# deploy"), 0600))
+	large := filepath.Join(srcDir, "large.bin")
+	require.NoError(t, os.WriteFile(large, nil, 0600))
+	require.NoError(t, os.Truncate(large, maxExtraFileSize+1))
+
+	testCases := []struct {
+		name        string
+		paths       []string
+		expectedErr string
+	}{
+		{
+			name:  "files",
+			paths: []string{"LICENSE", "docs/README.md"},
+		},
+		{
+			name:        "missing file",
+			paths:       []string{"LICENSE", "NOTICE"},
+			expectedErr: "uds-bundle.yaml .extraFiles[1] NOTICE can't be bundled",
+		},
+		{
+			name:        "directory",
+			paths:       []string{"docs"},
+			expectedErr: "uds-bundle.yaml .extraFiles[0] docs isn't a regular file",
+		},
+		{
+			name:        "too large",
+			paths:       []string{"large.bin"},
+			expectedErr: "uds-bundle.yaml .extraFiles[0] large.bin is larger than 10.49 MBs, the most an extra file can be",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateExtraFiles(tc.paths, srcDir)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	files, err := readExtraFiles([]string{"LICENSE", "docs/README.md"}, srcDir)
+	require.NoError(t, err)
+	require.Equal(t, []bundler.ExtraFile{
+		{Path: "LICENSE", Contents: []byte("Apache-2.0")},
+		{Path: "docs/README.md", Contents: []byte("# deploy")},
+	}, files)
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	goyaml "github.com/goccy/go-yaml"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// listImages prints the container images declared by the components of every Zarf pkg in a published bundle, each
+// pkg's zarf.yaml is fetched from the registry so the pkgs' images aren't pulled
+func (b *Bundle) listImages(source string) error {
+	if !helpers.IsOCIURL(source) {
+		return fmt.Errorf("--list-images only supports bundles in an OCI registry, %s is not an OCI reference", source)
+	}
+	ctx := context.TODO()
+	platform := utils.BundlePlatform(ctx, source)
+	remote, err := zoci.NewRemote(source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return err
+	}
+	contents, err := inspectRemote(ctx, remote.OrasRemote)
+	if err != nil {
+		return fmt.Errorf("unable to inspect %s: %w", source, err)
+	}
+
+	var pkgs []zarfTypes.ZarfPackage
+	for _, pkgContents := range contents.Packages {
+		pkg, err := fetchZarfYAML(ctx, remote.OrasRemote, pkgContents)
+		if err != nil {
+			return err
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	images := bundleImages(pkgs)
+
+	if b.cfg.InspectOpts.JSON {
+		output, err := json.MarshalIndent(images, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(output) + "\n")
+		return nil
+	}
+	for _, image := range images {
+		fmt.Println(image)
+	}
+	return nil
+}
+
+// fetchZarfYAML fetches and parses the zarf.yaml layer of a Zarf pkg in a published bundle
+func fetchZarfYAML(ctx context.Context, remote *oci.OrasRemote, pkgContents PackageContents) (zarfTypes.ZarfPackage, error) {
+	i := slices.IndexFunc(pkgContents.Layers, func(layer ocispec.Descriptor) bool {
+		return layer.Annotations[ocispec.AnnotationTitle] == config.ZarfYAML
+	})
+	if i < 0 {
+		return zarfTypes.ZarfPackage{}, fmt.Errorf("the manifest of package %s doesn't have a %s layer", pkgContents.Name, config.ZarfYAML)
+	}
+	zarfYAML, err := remote.FetchLayer(ctx, pkgContents.Layers[i])
+	if err != nil {
+		return zarfTypes.ZarfPackage{}, fmt.Errorf("unable to fetch the %s of package %s: %w", config.ZarfYAML, pkgContents.Name, err)
+	}
+	var pkg zarfTypes.ZarfPackage
+	if err := goyaml.Unmarshal(zarfYAML, &pkg); err != nil {
+		return zarfTypes.ZarfPackage{}, fmt.Errorf("unable to parse the %s of package %s: %w", config.ZarfYAML, pkgContents.Name, err)
+	}
+	return pkg, nil
+}
+
+// bundleImages returns the images declared by the components of the Zarf pkgs, sorted and without duplicates since
+// pkgs in a bundle commonly share images
+func bundleImages(pkgs []zarfTypes.ZarfPackage) []string {
+	images := []string{}
+	for _, pkg := range pkgs {
+		for _, component := range pkg.Components {
+			images = append(images, component.Images...)
+		}
+	}
+	slices.Sort(images)
+	return slices.Compact(images)
+}
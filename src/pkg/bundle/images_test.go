@@ -0,0 +1,40 @@
+package bundle
+
+import (
+	"testing"
+
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_bundleImages(t *testing.T) {
+	tests := []struct {
+		name string
+		pkgs []zarfTypes.ZarfPackage
+		want []string
+	}{
+		{
+			name: "no images",
+			pkgs: []zarfTypes.ZarfPackage{{Components: []zarfTypes.ZarfComponent{{Name: "manifests"}}}},
+			want: []string{},
+		},
+		{
+			name: "images shared by pkgs are listed once",
+			pkgs: []zarfTypes.ZarfPackage{
+				{Components: []zarfTypes.ZarfComponent{
+					{Name: "podinfo", Images: []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "busybox:1.36"}},
+				}},
+				{Components: []zarfTypes.ZarfComponent{
+					{Name: "nginx", Images: []string{"nginx:1.25"}},
+					{Name: "init", Images: []string{"busybox:1.36"}},
+				}},
+			},
+			want: []string{"busybox:1.36", "ghcr.io/stefanprodan/podinfo:6.4.0", "nginx:1.25"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, bundleImages(tt.pkgs))
+		})
+	}
+}
@@ -5,10 +5,26 @@
 package bundle
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers"
 	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
 	"github.com/defenseunicorns/zarf/src/pkg/utils"
 )
 
+// layerExtract is a bundle layer written to a file by inspect --extract-layer
+type layerExtract struct {
+	title string
+	path  string
+}
+
 // Inspect pulls/unpacks a bundle's metadata and shows it
 func (b *Bundle) Inspect() error {
 
@@ -18,6 +34,10 @@ func (b *Bundle) Inspect() error {
 		return err
 	}
 	b.cfg.InspectOpts.Source = source
+	extracts, err := parseLayerExtracts(b.cfg.InspectOpts.ExtractLayers)
+	if err != nil {
+		return err
+	}
 
 	// create a new provider
 	provider, err := NewBundleProvider(b.cfg.InspectOpts.Source, b.tmp)
@@ -31,11 +51,21 @@ func (b *Bundle) Inspect() error {
 		return err
 	}
 
+	// verify the bundle's signature and the signature of each of its pkgs against every key given
+	if b.cfg.InspectOpts.VerifyAll {
+		return b.verifyAll(b.cfg.InspectOpts.Source, loaded)
+	}
+
 	// validate the sig (if present)
-	if err := ValidateBundleSignature(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], b.cfg.InspectOpts.PublicKeyPath); err != nil {
+	if err := ValidateBundleSignature(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], loaded[config.BundleYAMLCertificate], b.cfg.InspectOpts.PublicKeyPath); err != nil {
 		return err
 	}
 
+	// only the requested layers are written, they were pulled with the bundle's metadata and verified with its signature
+	if len(extracts) > 0 {
+		return extractLayers(loaded, extracts)
+	}
+
 	// pull sbom
 	if b.cfg.InspectOpts.IncludeSBOM {
 		err := provider.CreateBundleSBOM(b.cfg.InspectOpts.ExtractSBOM)
@@ -48,10 +78,103 @@ func (b *Bundle) Inspect() error {
 		return err
 	}
 
-	// show the bundle's metadata
+	if b.cfg.InspectOpts.ShowSignature {
+		return b.showSignature(b.cfg.InspectOpts.Source)
+	}
+	if b.cfg.InspectOpts.ListImages {
+		return b.listImages(b.cfg.InspectOpts.Source)
+	}
+
+	// show the bundle's metadata, its provenance is shown in its own section
+	provenance := b.bundle.Build.Provenance
+	b.bundle.Build.Provenance = nil
 	utils.ColorPrintYAML(b.bundle, nil, false)
+	if provenance != nil {
+		message.Title("Provenance", "how the bundle was produced")
+		utils.ColorPrintYAML(provenance, nil, false)
+	}
+	if len(b.bundle.ExtraFiles) > 0 {
+		message.Title("Extra Files", "the files shipped alongside the bundle's YAML")
+		var rows [][]string
+		for _, path := range b.bundle.ExtraFiles {
+			size := "missing"
+			if info, err := os.Stat(loaded[path]); err == nil {
+				size = utils.ByteFormat(float64(info.Size()), 2)
+			}
+			rows = append(rows, []string{path, size})
+		}
+		message.Table([]string{"File", "Size"}, rows)
+	}
+	if err := printUncompressedSize(provider); err != nil {
+		return err
+	}
 
 	// TODO: showing package metadata?
 	// TODO: could be cool to have an interactive mode that lets you select a package and show its metadata
 	return nil
 }
+
+// parseLayerExtracts parses the <title>=<path> pairs of --extract-layer, a layer without a path is written to the
+// current directory under its title's base name
+func parseLayerExtracts(specs []string) ([]layerExtract, error) {
+	extracts := make([]layerExtract, 0, len(specs))
+	for _, spec := range specs {
+		title, path, _ := strings.Cut(spec, "=")
+		if title == "" {
+			return nil, fmt.Errorf("invalid --extract-layer %q, it must be <title>=<path>", spec)
+		}
+		if path == "" {
+			path = filepath.Base(title)
+		}
+		extracts = append(extracts, layerExtract{title: title, path: path})
+	}
+	return extracts, nil
+}
+
+// extractLayers copies the layers LoadBundleMetadata pulled to the requested paths, every title is checked before
+// anything is written so a typo doesn't leave some of the files behind
+func extractLayers(loaded types.PathMap, extracts []layerExtract) error {
+	for _, extract := range extracts {
+		if _, ok := loaded[extract.title]; !ok || extract.title == config.BundleYAMLCertificate {
+			var titles []string
+			for title := range loaded {
+				if title != config.BundleYAMLCertificate {
+					titles = append(titles, title)
+				}
+			}
+			slices.Sort(titles)
+			return fmt.Errorf("the bundle doesn't have a layer titled %s, its layers are titled: %s", extract.title, strings.Join(titles, ", "))
+		}
+	}
+	for _, extract := range extracts {
+		if err := helpers.CreatePathAndCopy(loaded[extract.title], extract.path); err != nil {
+			return fmt.Errorf("unable to write %s to %s: %w", extract.title, extract.path, err)
+		}
+		message.Successf("Extracted %s to %s", extract.title, extract.path)
+	}
+	return nil
+}
+
+// printUncompressedSize prints the total uncompressed size of the bundle's Zarf pkgs if it was recorded when the
+// bundle was created
+func printUncompressedSize(provider Provider) error {
+	rootManifest, err := provider.getBundleManifest()
+	if err != nil {
+		return err
+	}
+	annotation, ok := rootManifest.Annotations[config.BundleUncompressedSizeAnnotation]
+	if !ok {
+		return nil
+	}
+	size, err := strconv.ParseInt(annotation, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation %q: %w", config.BundleUncompressedSizeAnnotation, annotation, err)
+	}
+	message.Title("Uncompressed Size", "the total size of the bundle's packages once their layers are decompressed")
+	if rootManifest.Annotations[config.BundleUncompressedSizeEstimatedAnnotation] == "true" {
+		message.Infof("~%s (%d bytes, estimated)", utils.ByteFormat(float64(size), 2), size)
+		return nil
+	}
+	message.Infof("%s (%d bytes)", utils.ByteFormat(float64(size), 2), size)
+	return nil
+}
@@ -0,0 +1,44 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseLayerExtracts(t *testing.T) {
+	extracts, err := parseLayerExtracts([]string{"bundle.sbom.json=./out/sbom.json", "docs/README.md"})
+	require.NoError(t, err)
+	require.Equal(t, []layerExtract{
+		{title: "bundle.sbom.json", path: "./out/sbom.json"},
+		{title: "docs/README.md", path: "README.md"},
+	}, extracts)
+
+	_, err = parseLayerExtracts([]string{"=./out.json"})
+	require.EqualError(t, err, `invalid --extract-layer "=./out.json", it must be <title>=<path>`)
+}
+
+func Test_extractLayers(t *testing.T) {
+	src := t.TempDir()
+	loaded := make(types.PathMap)
+	for title, contents := range map[string]string{config.BundleYAML: "kind: UDSBundle", config.BundleYAMLCertificate: "cert", "LICENSE": "Apache-2.0"} {
+		path := filepath.Join(src, filepath.Base(title))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+		loaded[title] = path
+	}
+
+	dst := t.TempDir()
+	require.NoError(t, extractLayers(loaded, []layerExtract{{title: "LICENSE", path: filepath.Join(dst, "out", "LICENSE")}}))
+	b, err := os.ReadFile(filepath.Join(dst, "out", "LICENSE"))
+	require.NoError(t, err)
+	require.Equal(t, "Apache-2.0", string(b))
+
+	// nothing is written if any title is missing, the certificate isn't a layer
+	err = extractLayers(loaded, []layerExtract{{title: config.BundleYAML, path: filepath.Join(dst, "bundle.yaml")}, {title: config.BundleYAMLCertificate, path: filepath.Join(dst, "cert.pem")}})
+	require.EqualError(t, err, "the bundle doesn't have a layer titled uds-bundle.yaml.pem, its layers are titled: LICENSE, uds-bundle.yaml")
+	require.NoFileExists(t, filepath.Join(dst, "bundle.yaml"))
+}
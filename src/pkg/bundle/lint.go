@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	goyaml "github.com/goccy/go-yaml"
+)
+
+// Lint statically checks a bundle definition without making any network calls, printing its errors and warnings
+// grouped by severity. It only fails if the bundle has errors
+func (b *Bundle) Lint() error {
+	bundleFile := b.cfg.LintOpts.Source
+	if info, err := os.Stat(bundleFile); err == nil && info.IsDir() {
+		bundleFile = filepath.Join(bundleFile, config.BundleYAML)
+	}
+	src, err := os.ReadFile(bundleFile)
+	if err != nil {
+		return err
+	}
+	if err := goyaml.Unmarshal(src, &b.bundle); err != nil {
+		return fmt.Errorf("unable to parse %s: %w", bundleFile, err)
+	}
+
+	errs, warns := lintBundle(&b.bundle, src)
+	if err := validateExtraFiles(b.bundle.ExtraFiles, filepath.Dir(bundleFile)); err != nil {
+		errs = append(errs, err)
+	}
+	printLintFindings("Errors", errs)
+	printLintFindings("Warnings", warns)
+	if len(errs) > 0 {
+		return fmt.Errorf("%s has %d error(s) and %d warning(s)", bundleFile, len(errs), len(warns))
+	}
+	if len(warns) > 0 {
+		message.Successf("%s has no errors and %d warning(s)", bundleFile, len(warns))
+		return nil
+	}
+	message.Successf("%s has no errors or warnings", bundleFile)
+	return nil
+}
+
+// lintBundle returns the errors that would fail a create and the warnings about a bundle that's valid but likely
+// isn't what was intended, it uses the same validation as create without resolving the pkgs' references
+func lintBundle(bundle *types.UDSBundle, src []byte) ([]error, []error) {
+	v := newMetadataValidator(src)
+
+	// create defaults a missing architecture, so the rest of the bundle is validated as create would see it
+	validated := *bundle
+	if validated.Metadata.Architecture == "" {
+		validated.Metadata.Architecture = config.GetArch()
+		v.warnf("metadata.architecture", "isn't set, create uses the architecture it's run with (%s)", validated.Metadata.Architecture)
+	}
+	v.validate(&validated)
+	v.errs = append(v.errs, v.unknownFields()...)
+	if err := validatePackageNames(bundle.Packages); err != nil {
+		v.errs = append(v.errs, err)
+	}
+	if err := validateBundleVars(bundle.Packages); err != nil {
+		v.errs = append(v.errs, fmt.Errorf("%s: %w", config.BundleYAML, err))
+	}
+	if _, err := orderPackages(bundle.Packages); err != nil {
+		v.errs = append(v.errs, fmt.Errorf("%s: %w", config.BundleYAML, err))
+	}
+
+	for i, pkg := range bundle.Packages {
+		if pkg.Repository != "" && pkg.Ref != "" && !strings.Contains(pkg.Ref, "@sha256:") {
+			v.warnf(fmt.Sprintf("packages[%d].ref", i), "%s is a mutable tag, pin it with a digest (ref: %s@sha256:<digest>) so every create bundles the same package", pkg.Ref, pkg.Ref)
+		}
+	}
+	return v.errs, v.warns
+}
+
+// printLintFindings prints one severity's findings under a heading, nothing is printed if there aren't any
+func printLintFindings(severity string, findings []error) {
+	if len(findings) == 0 {
+		return
+	}
+	fmt.Printf("%s (%d):\n", severity, len(findings))
+	for _, finding := range findings {
+		fmt.Printf("  %s\n", finding)
+	}
+}
@@ -0,0 +1,128 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lintBundle(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		wantErrs  []string
+		wantWarns []string
+	}{
+		{
+			name: "Clean",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: 0.0.1
+  architecture: amd64
+packages:
+  - name: podinfo
+    repository: ghcr.io/defenseunicorns/uds-cli/podinfo
+    ref: 0.0.1@sha256:b0a7a4ee8e4a5e5b7e5c4b7b8a9e0d1f2c3b4a5d6e7f8091a2b3c4d5e6f70819
+  - name: local
+    path: ../packages
+    ref: 0.0.1
+`,
+		},
+		{
+			name: "UnknownFields",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: 0.0.1
+  architecture: amd64
+  verison: 0.0.2
+packages:
+  - name: local
+    path: ../packages
+    ref: 0.0.1
+    overides:
+      podinfo-component:
+        podinfo:
+          namespace: podinfo
+  - name: helm
+    path: ../packages
+    ref: 0.0.1
+    overrides:
+      podinfo-component:
+        podinfo:
+          namespaec: podinfo
+`,
+			wantErrs: []string{
+				"uds-bundle.yaml:6: metadata.verison is not a known field",
+				"uds-bundle.yaml:11: packages[0].overides is not a known field",
+				"uds-bundle.yaml:21: packages[1].overrides.podinfo-component.podinfo.namespaec is not a known field",
+			},
+		},
+		{
+			name: "DuplicateNamesAndInvalidImports",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: 0.0.1
+  architecture: amd64
+packages:
+  - name: local
+    path: ../packages
+    ref: 0.0.1
+  - name: local
+    path: ../other
+    ref: 0.0.1
+    imports:
+      - name: DOMAIN
+        package: missing
+`,
+			wantErrs: []string{
+				"uds-bundle.yaml .packages[0] and .packages[1] are both named local",
+				"uds-bundle.yaml: import var DOMAIN does not have a matching export",
+			},
+		},
+		{
+			name: "MissingArchitectureAndMutableTag",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: not a version
+packages:
+  - name: podinfo
+    repository: ghcr.io/defenseunicorns/uds-cli/podinfo
+    ref: 0.0.1
+`,
+			wantErrs: []string{
+				`uds-bundle.yaml:4: metadata.version "not a version" is not a valid version, it must be a valid OCI tag`,
+			},
+			wantWarns: []string{
+				"uds-bundle.yaml:3: metadata.architecture isn't set, create uses the architecture it's run with (" + config.GetArch() + ")",
+				"uds-bundle.yaml:8: packages[0].ref 0.0.1 is a mutable tag, pin it with a digest (ref: 0.0.1@sha256:<digest>) so every create bundles the same package",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bundle types.UDSBundle
+			require.NoError(t, goyaml.Unmarshal([]byte(tt.src), &bundle))
+			errs, warns := lintBundle(&bundle, []byte(tt.src))
+			require.Equal(t, tt.wantErrs, errorStrings(errs))
+			require.Equal(t, tt.wantWarns, errorStrings(warns))
+			// linting doesn't default the architecture of the bundle it's given
+			require.Equal(t, bundle.Metadata.Architecture == "", tt.name == "MissingArchitectureAndMutableTag")
+		})
+	}
+}
+
+// errorStrings returns the messages of errs, or nil if there aren't any
+func errorStrings(errs []error) []string {
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return msgs
+}
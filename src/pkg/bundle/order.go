@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+)
+
+// orderPackages returns the bundle's Zarf pkgs in deploy order: each pkg is deployed after the pkgs it dependsOn and the
+// pkgs it imports variables from. Otherwise pkgs keep the order they're listed in, so a bundle without dependsOn
+// deploys as listed. It errors if a pkg depends on a pkg that isn't in the bundle or if pkgs depend on each other
+func orderPackages(packages []types.Package) ([]types.Package, error) {
+	names := make(map[string]bool, len(packages))
+	for _, pkg := range packages {
+		names[pkg.Name] = true
+	}
+	deps := make([][]string, len(packages))
+	for i, pkg := range packages {
+		for _, dep := range pkg.DependsOn {
+			if !names[dep] {
+				return nil, fmt.Errorf("zarf pkg %s depends on %s, which isn't in the bundle", pkg.Name, dep)
+			}
+			deps[i] = append(deps[i], dep)
+		}
+		for _, imp := range pkg.Imports {
+			// imports without a matching export are reported by validateBundleVars
+			if names[imp.Package] {
+				deps[i] = append(deps[i], imp.Package)
+			}
+		}
+	}
+
+	ordered := make([]types.Package, 0, len(packages))
+	deployed := make(map[string]bool, len(packages))
+	done := make([]bool, len(packages))
+	for len(ordered) < len(packages) {
+		// the first listed pkg whose dependencies are all deployed goes next
+		next := -1
+		for i := range packages {
+			if !done[i] && allDeployed(deps[i], deployed) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, dependencyCycle(packages, deps, done)
+		}
+		done[next] = true
+		deployed[packages[next].Name] = true
+		ordered = append(ordered, packages[next])
+	}
+	return ordered, nil
+}
+
+// allDeployed returns true if every dependency is deployed
+func allDeployed(deps []string, deployed map[string]bool) bool {
+	for _, dep := range deps {
+		if !deployed[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// dependencyCycle returns the error for the pkgs orderPackages couldn't order. Each of them depends on another one of
+// them, so following their dependencies from the first one always leads back around to a pkg already visited
+func dependencyCycle(packages []types.Package, deps [][]string, done []bool) error {
+	remaining := make(map[string]int)
+	start := -1
+	for i, pkg := range packages {
+		if done[i] {
+			continue
+		}
+		if _, ok := remaining[pkg.Name]; !ok {
+			remaining[pkg.Name] = i
+		}
+		if start == -1 {
+			start = i
+		}
+	}
+	var path []string
+	visited := make(map[string]int)
+	for i := start; i >= 0; {
+		name := packages[i].Name
+		if first, ok := visited[name]; ok {
+			path = append(path[first:], name)
+			break
+		}
+		visited[name] = len(path)
+		path = append(path, name)
+		next := -1
+		for _, dep := range deps[i] {
+			if j, ok := remaining[dep]; ok {
+				next = j
+				break
+			}
+		}
+		i = next
+	}
+	return fmt.Errorf("zarf pkgs depend on each other: %s", strings.Join(path, " -> "))
+}
@@ -0,0 +1,81 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_orderPackages(t *testing.T) {
+	tests := []struct {
+		name     string
+		packages []types.Package
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "listed order without dependencies",
+			packages: []types.Package{{Name: "init"}, {Name: "podinfo"}, {Name: "nginx"}},
+			want:     []string{"init", "podinfo", "nginx"},
+		},
+		{
+			name: "dependencies are deployed first",
+			packages: []types.Package{
+				{Name: "init"},
+				{Name: "podinfo", DependsOn: []string{"cert-manager"}},
+				{Name: "nginx"},
+				{Name: "cert-manager", DependsOn: []string{"init"}},
+			},
+			want: []string{"init", "nginx", "cert-manager", "podinfo"},
+		},
+		{
+			name: "imports are dependencies",
+			packages: []types.Package{
+				{Name: "init"},
+				{Name: "nginx", Imports: []types.BundleVariableImport{{Name: "DOMAIN", Package: "podinfo"}}},
+				{Name: "podinfo", DependsOn: []string{"init"}, Exports: []types.BundleVariableExport{{Name: "DOMAIN"}}},
+			},
+			want: []string{"init", "podinfo", "nginx"},
+		},
+		{
+			name:     "unknown dependency",
+			packages: []types.Package{{Name: "init"}, {Name: "podinfo", DependsOn: []string{"cert-manger"}}},
+			wantErr:  "zarf pkg podinfo depends on cert-manger, which isn't in the bundle",
+		},
+		{
+			name: "cycle",
+			packages: []types.Package{
+				{Name: "init"},
+				{Name: "podinfo", DependsOn: []string{"nginx"}},
+				{Name: "nginx", DependsOn: []string{"init", "cert-manager"}},
+				{Name: "cert-manager", DependsOn: []string{"podinfo"}},
+			},
+			wantErr: "zarf pkgs depend on each other: podinfo -> nginx -> cert-manager -> podinfo",
+		},
+		{
+			name:     "depends on itself",
+			packages: []types.Package{{Name: "init"}, {Name: "podinfo", DependsOn: []string{"podinfo"}}},
+			wantErr:  "zarf pkgs depend on each other: podinfo -> podinfo",
+		},
+		{
+			name: "cycle through an import",
+			packages: []types.Package{
+				{Name: "podinfo", DependsOn: []string{"nginx"}, Exports: []types.BundleVariableExport{{Name: "DOMAIN"}}},
+				{Name: "nginx", Imports: []types.BundleVariableImport{{Name: "DOMAIN", Package: "podinfo"}}},
+			},
+			wantErr: "zarf pkgs depend on each other: podinfo -> nginx -> podinfo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := orderPackages(tt.packages)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, packageNames(ordered))
+		})
+	}
+}
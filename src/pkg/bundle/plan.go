@@ -0,0 +1,366 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/cluster"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/packager/filters"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	goyaml "github.com/goccy/go-yaml"
+	av3 "github.com/mholt/archiver/v3"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// PlanInstall is a Zarf pkg that isn't deployed to the cluster yet
+	PlanInstall = "install"
+	// PlanUpgrade is a Zarf pkg that's already deployed to the cluster
+	PlanUpgrade = "upgrade"
+)
+
+// sensitiveValue replaces the value of a Zarf pkg's sensitive variables in a deploy plan
+const sensitiveValue = "********"
+
+// DeployPlan is what deploying a bundle would do, resolved without deploying anything
+type DeployPlan struct {
+	Bundle  string `json:"bundle"`
+	Version string `json:"version"`
+	Source  string `json:"source"`
+	// Packages are the Zarf pkgs that would be deployed, in deploy order
+	Packages []PackagePlan `json:"packages"`
+	// Skipped are the Zarf pkgs skipped with --skip-packages, or already deployed when resuming
+	Skipped []string `json:"skipped,omitempty"`
+	// Namespaces are the namespaces the Zarf pkgs' charts and manifests would be deployed to
+	Namespaces []string `json:"namespaces"`
+}
+
+// PackagePlan is a Zarf pkg in a deploy plan
+type PackagePlan struct {
+	Name    string `json:"name"`
+	Ref     string `json:"ref"`
+	Version string `json:"version"`
+	// Action is PlanInstall or PlanUpgrade, depending on whether the pkg is already deployed to the cluster
+	Action string `json:"action"`
+	// DeployedVersion is the version of the pkg that's deployed to the cluster, if any
+	DeployedVersion string `json:"deployedVersion,omitempty"`
+	// Components are the pkg's components that would be deployed
+	Components []string `json:"components"`
+	// Variables are the values of the pkg's variables, sensitive values are masked and values exported by a pkg
+	// deployed earlier are shown as a placeholder
+	Variables map[string]string `json:"variables,omitempty"`
+	Releases  []ReleasePlan     `json:"releases,omitempty"`
+}
+
+// ReleasePlan is a Helm release of a Zarf pkg's chart in a deploy plan
+type ReleasePlan struct {
+	Component string `json:"component"`
+	Chart     string `json:"chart"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Values are the bundle's overrides of the chart's values
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// DryRunDeploy resolves the bundle and prints the Zarf pkgs a deploy would deploy, in order, along with their variables,
+// chart overrides and the Helm releases and namespaces they'd change in the cluster, without deploying anything
+func (b *Bundle) DryRunDeploy() error {
+	if _, _, _, err := b.PreDeployValidation(); err != nil {
+		return err
+	}
+	if err := b.loadVarsFiles(); err != nil {
+		return err
+	}
+	zarfYAMLs, err := b.planZarfYAMLs(b.cfg.DeployOpts.Source)
+	if err != nil {
+		return err
+	}
+	if err := b.loadSetValues(zarfYAMLs); err != nil {
+		return err
+	}
+	plan, err := b.planDeploy(zarfYAMLs, deployedPackageVersions())
+	if err != nil {
+		return err
+	}
+
+	if b.cfg.DeployOpts.JSON {
+		output, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(output) + "\n")
+		return nil
+	}
+	printDeployPlan(plan)
+	return nil
+}
+
+// planDeploy resolves the deploy of the bundle's Zarf pkgs the same way Deploy does, zarfYAMLs are the pkgs' zarf.yaml by
+// name and deployed the versions of the pkgs that are deployed to the cluster
+func (b *Bundle) planDeploy(zarfYAMLs map[string]zarfTypes.ZarfPackage, deployed map[string]string) (*DeployPlan, error) {
+	packages, err := orderPackages(b.bundle.Packages)
+	if err != nil {
+		return nil, err
+	}
+	packages, err = selectPackages(packages, b.cfg.DeployOpts.Packages)
+	if err != nil {
+		return nil, err
+	}
+	packages, skipped, err := skipPackages(packages, b.cfg.DeployOpts.SkipPackages)
+	if err != nil {
+		return nil, err
+	}
+	plan := DeployPlan{
+		Bundle:     b.bundle.Metadata.Name,
+		Version:    b.bundle.Metadata.Version,
+		Source:     b.cfg.DeployOpts.Source,
+		Packages:   []PackagePlan{},
+		Skipped:    packageNames(skipped),
+		Namespaces: []string{},
+	}
+
+	// the values exported by a pkg aren't known until it's deployed
+	exportedVars := make(map[string]map[string]string)
+	for _, pkg := range packages {
+		deployedVersion, isDeployed := deployed[pkg.Name]
+		if b.cfg.DeployOpts.Resume && isDeployed {
+			plan.Skipped = append(plan.Skipped, pkg.Name)
+			continue
+		}
+		zarfYAML, ok := zarfYAMLs[pkg.Name]
+		if !ok {
+			return nil, fmt.Errorf("unable to find the %s of package %s", config.ZarfYAML, pkg.Name)
+		}
+		components, err := filters.ForDeploy(strings.Join(pkg.OptionalComponents, ","), false).Apply(zarfYAML)
+		if err != nil {
+			return nil, fmt.Errorf("unable to select the components of package %s: %w", pkg.Name, err)
+		}
+		pkgVars := b.loadVariables(pkg, exportedVars)
+		valuesOverrides, nsOverrides, err := b.loadChartOverrides(pkg, pkgVars)
+		if err != nil {
+			return nil, err
+		}
+
+		pkgPlan := PackagePlan{
+			Name:            pkg.Name,
+			Ref:             pkg.Ref,
+			Version:         strings.Split(pkg.Ref, "@")[0],
+			Action:          PlanInstall,
+			DeployedVersion: deployedVersion,
+			Components:      []string{},
+			Variables:       make(map[string]string),
+		}
+		if isDeployed {
+			pkgPlan.Action = PlanUpgrade
+		}
+		// only the variables the pkg declares are passed to Zarf
+		for _, v := range zarfYAML.Variables {
+			value, ok := pkgVars[v.Name]
+			if !ok {
+				value = v.Default
+			}
+			if value == "" {
+				continue
+			}
+			if v.Sensitive {
+				value = sensitiveValue
+			}
+			pkgPlan.Variables[v.Name] = value
+		}
+		for _, component := range components {
+			pkgPlan.Components = append(pkgPlan.Components, component.Name)
+			for _, chart := range component.Charts {
+				release := ReleasePlan{Component: component.Name, Chart: chart.Name, Name: chart.ReleaseName, Namespace: chart.Namespace}
+				if release.Name == "" {
+					release.Name = chart.Name
+				}
+				if ns := nsOverrides[component.Name][chart.Name]; ns != "" {
+					release.Namespace = ns
+				}
+				if values := valuesOverrides[component.Name][chart.Name]; len(values) > 0 {
+					release.Values = values
+				}
+				pkgPlan.Releases = append(pkgPlan.Releases, release)
+				plan.Namespaces = append(plan.Namespaces, release.Namespace)
+			}
+			for _, manifest := range component.Manifests {
+				plan.Namespaces = append(plan.Namespaces, manifest.Namespace)
+			}
+		}
+		plan.Packages = append(plan.Packages, pkgPlan)
+
+		pkgExportedVars := make(map[string]string)
+		for _, exp := range pkg.Exports {
+			pkgExportedVars[strings.ToUpper(exp.Name)] = fmt.Sprintf("<exported by %s>", pkg.Name)
+		}
+		exportedVars[pkg.Name] = pkgExportedVars
+	}
+
+	plan.Namespaces = slices.DeleteFunc(plan.Namespaces, func(ns string) bool { return ns == "" })
+	sort.Strings(plan.Namespaces)
+	plan.Namespaces = slices.Compact(plan.Namespaces)
+	return &plan, nil
+}
+
+// printDeployPlan prints a deploy plan as tables, followed by the chart overrides
+func printDeployPlan(plan *DeployPlan) {
+	message.HeaderInfof("📋 DEPLOY PLAN")
+	message.Infof("Deploying %s (%s) from %s would:", plan.Bundle, plan.Version, plan.Source)
+
+	rows := make([][]string, 0, len(plan.Packages))
+	for i, pkg := range plan.Packages {
+		action := pkg.Action
+		if pkg.DeployedVersion != "" {
+			action = fmt.Sprintf("%s from %s", action, pkg.DeployedVersion)
+		}
+		rows = append(rows, []string{fmt.Sprint(i + 1), pkg.Name, pkg.Version, action, strings.Join(pkg.Components, ", ")})
+	}
+	message.Table([]string{"#", "Package", "Version", "Action", "Components"}, rows)
+	if len(plan.Skipped) > 0 {
+		message.Infof("Skip %s", strings.Join(plan.Skipped, ", "))
+	}
+
+	var varRows, releaseRows [][]string
+	for _, pkg := range plan.Packages {
+		names := make([]string, 0, len(pkg.Variables))
+		for name := range pkg.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			varRows = append(varRows, []string{pkg.Name, name, pkg.Variables[name]})
+		}
+		for _, release := range pkg.Releases {
+			overridden := "no"
+			if len(release.Values) > 0 {
+				overridden = "yes"
+			}
+			releaseRows = append(releaseRows, []string{pkg.Name, release.Component, release.Name, release.Namespace, overridden})
+		}
+	}
+	if len(varRows) > 0 {
+		message.Table([]string{"Package", "Variable", "Value"}, varRows)
+	}
+	if len(releaseRows) > 0 {
+		message.Table([]string{"Package", "Component", "Helm Release", "Namespace", "Overrides"}, releaseRows)
+	}
+	if len(plan.Namespaces) > 0 {
+		message.Infof("Namespaces: %s", strings.Join(plan.Namespaces, ", "))
+	}
+	for _, pkg := range plan.Packages {
+		for _, release := range pkg.Releases {
+			if len(release.Values) == 0 {
+				continue
+			}
+			message.Infof("Overrides of Helm release %s in package %s:", release.Name, pkg.Name)
+			zarfUtils.ColorPrintYAML(release.Values, nil, false)
+		}
+	}
+	message.Infof("This was a dry run, nothing was deployed")
+}
+
+// deployedPackageVersions returns the version of each Zarf pkg deployed to the cluster, it's empty if there's no cluster
+func deployedPackageVersions() map[string]string {
+	deployed := make(map[string]string)
+	c, _ := cluster.NewCluster()
+	if c == nil {
+		return deployed
+	}
+	pkgs, _ := c.GetDeployedZarfPackages()
+	for _, pkg := range pkgs {
+		deployed[pkg.Name] = pkg.Data.Metadata.Version
+	}
+	return deployed
+}
+
+// planZarfYAMLs reads the zarf.yaml of each of the bundle's Zarf pkgs without pulling the pkgs, from the registry for a
+// published bundle or from the blobs of a bundle tarball
+func (b *Bundle) planZarfYAMLs(source string) (map[string]zarfTypes.ZarfPackage, error) {
+	zarfYAMLs := make(map[string]zarfTypes.ZarfPackage)
+	if helpers.IsOCIURL(source) {
+		ctx := context.TODO()
+		platform := utils.BundlePlatform(ctx, source)
+		remote, err := zoci.NewRemote(source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+		if err != nil {
+			return nil, err
+		}
+		contents, err := inspectRemote(ctx, remote.OrasRemote)
+		if err != nil {
+			return nil, fmt.Errorf("unable to inspect %s: %w", source, err)
+		}
+		for _, pkgContents := range contents.Packages {
+			if zarfYAMLs[pkgContents.Name], err = fetchZarfYAML(ctx, remote.OrasRemote, pkgContents); err != nil {
+				return nil, err
+			}
+		}
+		return zarfYAMLs, nil
+	}
+
+	provider, err := NewBundleProvider(source, b.tmp)
+	if err != nil {
+		return nil, err
+	}
+	root, err := provider.getBundleManifest()
+	if err != nil {
+		return nil, err
+	}
+	var pkgManifestDescs []ocispec.Descriptor
+	for _, layer := range root.Layers {
+		if !isBundleMetadataLayer(layer) {
+			pkgManifestDescs = append(pkgManifestDescs, layer)
+		}
+	}
+	if len(pkgManifestDescs) != len(b.bundle.Packages) {
+		return nil, fmt.Errorf("%s lists %d packages but the root manifest has %d package manifests", config.BundleYAML, len(b.bundle.Packages), len(pkgManifestDescs))
+	}
+	for i, pkg := range b.bundle.Packages {
+		pkgManifestBytes, err := extractTarballBlob(source, b.tmp, pkgManifestDescs[i])
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the manifest of package %s: %w", pkg.Name, err)
+		}
+		var pkgManifest oci.Manifest
+		if err := json.Unmarshal(pkgManifestBytes, &pkgManifest); err != nil {
+			return nil, fmt.Errorf("unable to read the manifest of package %s: %w", pkg.Name, err)
+		}
+		zarfYAMLDesc := pkgManifest.Locate(config.ZarfYAML)
+		if oci.IsEmptyDescriptor(zarfYAMLDesc) {
+			return nil, fmt.Errorf("the manifest of package %s doesn't have a %s layer", pkg.Name, config.ZarfYAML)
+		}
+		zarfYAML, err := extractTarballBlob(source, b.tmp, zarfYAMLDesc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the %s of package %s: %w", config.ZarfYAML, pkg.Name, err)
+		}
+		var zarfPkg zarfTypes.ZarfPackage
+		if err := goyaml.Unmarshal(zarfYAML, &zarfPkg); err != nil {
+			return nil, fmt.Errorf("unable to parse the %s of package %s: %w", config.ZarfYAML, pkg.Name, err)
+		}
+		zarfYAMLs[pkg.Name] = zarfPkg
+	}
+	return zarfYAMLs, nil
+}
+
+// extractTarballBlob extracts a single blob from a bundle tarball into dst and reads it
+func extractTarballBlob(src, dst string, desc ocispec.Descriptor) ([]byte, error) {
+	pathInTarball := filepath.Join(config.BlobsDir, desc.Digest.Encoded())
+	if err := av3.Extract(src, pathInTarball, dst); err != nil {
+		return nil, fmt.Errorf("failed to extract %s from %s: %w", pathInTarball, src, err)
+	}
+	return os.ReadFile(filepath.Join(dst, pathInTarball))
+}
@@ -0,0 +1,82 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_planDeploy(t *testing.T) {
+	required := true
+	zarfYAMLs := map[string]zarfTypes.ZarfPackage{
+		"database": {
+			Variables: []zarfTypes.ZarfPackageVariable{{Name: "PASSWORD", Default: "changeme", Sensitive: true}, {Name: "PORT", Default: "5432"}},
+			Components: []zarfTypes.ZarfComponent{
+				{Name: "postgres", Required: &required, Charts: []zarfTypes.ZarfChart{{Name: "postgresql", Namespace: "db"}}},
+			},
+		},
+		"podinfo": {
+			Variables: []zarfTypes.ZarfPackageVariable{{Name: "PORT"}, {Name: "REPLICAS"}, {Name: "UNSET"}},
+			Components: []zarfTypes.ZarfComponent{
+				{Name: "podinfo", Required: &required, Charts: []zarfTypes.ZarfChart{{Name: "podinfo", ReleaseName: "app", Namespace: "podinfo"}}},
+				{Name: "tests", Manifests: []zarfTypes.ZarfManifest{{Name: "tests", Namespace: "tests"}}},
+				{Name: "monitoring", Default: true, Manifests: []zarfTypes.ZarfManifest{{Name: "monitors", Namespace: "monitoring"}}},
+			},
+		},
+		"skipped": {},
+	}
+	b := Bundle{
+		bundle: types.UDSBundle{
+			Metadata: types.UDSMetadata{Name: "example", Version: "0.0.1"},
+			Packages: []types.Package{
+				{Name: "database", Ref: "1.0.0@sha256:abc", Exports: []types.BundleVariableExport{{Name: "PORT"}}},
+				{
+					Name: "podinfo", Ref: "6.4.0@sha256:def",
+					Imports: []types.BundleVariableImport{{Name: "PORT", Package: "database"}},
+					Overrides: map[string]map[string]types.BundleChartOverrides{
+						"podinfo": {"podinfo": {Namespace: "apps", Values: []types.BundleChartValue{{Path: "replicaCount", Value: 2}}}},
+					},
+				},
+				{Name: "skipped", Ref: "0.0.1@sha256:123"},
+			},
+		},
+		cfg: &types.BundleConfig{DeployOpts: types.BundleDeployOptions{
+			Source:       "oci://ghcr.io/defenseunicorns/example:0.0.1",
+			SkipPackages: []string{"skipped"},
+			SetVariables: map[string]string{"podinfo.REPLICAS": "3"},
+		}},
+	}
+
+	plan, err := b.planDeploy(zarfYAMLs, map[string]string{"database": "0.9.0"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"skipped"}, plan.Skipped)
+	require.Equal(t, []string{"apps", "db", "monitoring"}, plan.Namespaces)
+	require.Len(t, plan.Packages, 2)
+
+	database := plan.Packages[0]
+	require.Equal(t, "1.0.0", database.Version)
+	require.Equal(t, PlanUpgrade, database.Action)
+	require.Equal(t, "0.9.0", database.DeployedVersion)
+	// sensitive values are masked
+	require.Equal(t, map[string]string{"PASSWORD": sensitiveValue, "PORT": "5432"}, database.Variables)
+
+	podinfo := plan.Packages[1]
+	require.Equal(t, PlanInstall, podinfo.Action)
+	// optional components are only deployed if they're selected or default
+	require.Equal(t, []string{"podinfo", "monitoring"}, podinfo.Components)
+	// an imported value isn't known until the exporting pkg is deployed
+	require.Equal(t, map[string]string{"PORT": "<exported by database>", "REPLICAS": "3"}, podinfo.Variables)
+	require.Equal(t, []ReleasePlan{{
+		Component: "podinfo", Chart: "podinfo", Name: "app", Namespace: "apps",
+		Values: map[string]interface{}{"replicaCount": int64(2)},
+	}}, podinfo.Releases)
+
+	// resuming skips the pkgs that are already deployed
+	b.cfg.DeployOpts.Resume = true
+	plan, err = b.planDeploy(zarfYAMLs, map[string]string{"database": "1.0.0"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"skipped", "database"}, plan.Skipped)
+	require.Len(t, plan.Packages, 1)
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/bundler/fetcher"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// platformFromPackage pins the bundle's architecture to the one its first Zarf pkg is built for, the same way
+// --platform all pins it to each arch, and checks that every other pkg is available for that arch
+func (b *Bundle) platformFromPackage(ctx context.Context) error {
+	if b.cfg.CreateOpts.Platform == config.PlatformAll {
+		return fmt.Errorf("cannot use both --platform-from-package and --platform %s", config.PlatformAll)
+	}
+	if config.CLIArch != "" {
+		return fmt.Errorf("cannot use --platform-from-package with the %s architecture set by --architecture, UDS_ARCHITECTURE or options.architecture", config.CLIArch)
+	}
+	if len(b.bundle.Packages) == 0 {
+		return fmt.Errorf("%s is missing required list: packages", config.BundleYAML)
+	}
+	srcCredential, _, err := b.registryCredentials()
+	if err != nil {
+		return err
+	}
+
+	pkgArchs := make([][]string, len(b.bundle.Packages))
+	for i, pkg := range b.bundle.Packages {
+		if pkgArchs[i], err = packageArchs(ctx, pkg, b.cfg.CreateOpts.SourceDirectory, srcCredential); err != nil {
+			return fmt.Errorf("unable to determine the architecture of package %s: %w", pkg.Name, err)
+		}
+	}
+	arch, err := agreeOnArch(b.bundle.Packages, pkgArchs)
+	if err != nil {
+		return err
+	}
+	if bundleArch := b.bundle.Metadata.Architecture; bundleArch != "" && bundleArch != arch {
+		return fmt.Errorf("the bundle's metadata.architecture is %s but package %s is built for %s", bundleArch, b.bundle.Packages[0].Name, arch)
+	}
+
+	message.Infof("Creating the bundle for %s, the architecture of package %s", arch, b.bundle.Packages[0].Name)
+	config.CLIArch = arch
+	return b.CalculateBuildInfo()
+}
+
+// agreeOnArch returns the arch of the first pkg, pkgArchs are the archs each pkg is available for. Every other pkg must
+// be available for that arch, a nil entry is a pkg whose arch can't be known until its path is resolved for the
+// bundle's arch and is checked when it's read
+func agreeOnArch(pkgs []types.Package, pkgArchs [][]string) (string, error) {
+	first := pkgs[0].Name
+	switch archs := pkgArchs[0]; {
+	case archs == nil:
+		return "", fmt.Errorf("unable to determine the architecture of package %s, set its path to the package tarball or set its arch", first)
+	case len(archs) == 0:
+		return "", fmt.Errorf("package %s doesn't record the architecture it's built for, set its arch", first)
+	case len(archs) > 1:
+		return "", fmt.Errorf("package %s is published for %s, set its arch to choose the bundle's architecture", first, strings.Join(archs, ", "))
+	}
+	arch := pkgArchs[0][0]
+	for i, archs := range pkgArchs[1:] {
+		if archs == nil || slices.Contains(archs, arch) {
+			continue
+		}
+		pkg := pkgs[i+1]
+		if len(archs) == 0 {
+			return "", fmt.Errorf("packages disagree on architecture: package %s is built for %s but package %s doesn't record its architecture", first, arch, pkg.Name)
+		}
+		return "", fmt.Errorf("packages disagree on architecture: package %s is built for %s but package %s is built for %s", first, arch, pkg.Name, strings.Join(archs, ", "))
+	}
+	return arch, nil
+}
+
+// packageArchs returns the archs a Zarf pkg is available for. A pkg's arch in the bundle takes precedence, a remote
+// pkg is available for each arch in its index and a local tarball for the arch it was built for. nil is returned for
+// a local pkg whose path is a directory since the tarball's name depends on the arch
+func packageArchs(ctx context.Context, pkg types.Package, srcDir string, credential auth.Credential) ([]string, error) {
+	if pkg.Arch != "" {
+		return []string{pkg.Arch}, nil
+	}
+	if utils.IsRemotePkg(pkg) {
+		remote, err := zoci.NewRemote(fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref), ocispec.Platform{}, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+		if err != nil {
+			return nil, err
+		}
+		utils.WithCredential(remote.OrasRemote, credential)
+		return remoteArchs(ctx, remote.OrasRemote)
+	}
+
+	if !strings.HasSuffix(pkg.Path, ".tar.zst") {
+		return nil, nil
+	}
+	if !filepath.IsAbs(pkg.Path) {
+		pkg.Path = filepath.Join(srcDir, pkg.Path)
+	}
+	f, err := fetcher.NewPkgFetcher(ctx, pkg, fetcher.Config{Bundle: &types.UDSBundle{Packages: []types.Package{pkg}}})
+	if err != nil {
+		return nil, err
+	}
+	zarfYAML, err := f.GetPkgMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if zarfYAML.Build.Architecture == "" {
+		return []string{}, nil
+	}
+	return []string{zarfYAML.Build.Architecture}, nil
+}
+
+// remoteArchs returns the archs in the index a remote Zarf pkg's reference points at, a reference to a single manifest
+// is available for the arch in its config
+func remoteArchs(ctx context.Context, remote *oci.OrasRemote) ([]string, error) {
+	desc, err := remote.Repo().Resolve(ctx, remote.Repo().Reference.Reference)
+	if err != nil {
+		return nil, err
+	}
+	archs := []string{}
+	if desc.MediaType == ocispec.MediaTypeImageIndex {
+		index, err := oci.FetchUnmarshal[ocispec.Index](ctx, remote.FetchLayer, json.Unmarshal, desc)
+		if err != nil {
+			return nil, err
+		}
+		for _, manifest := range index.Manifests {
+			if manifest.Platform != nil && manifest.Platform.Architecture != "" && !slices.Contains(archs, manifest.Platform.Architecture) {
+				archs = append(archs, manifest.Platform.Architecture)
+			}
+		}
+		return archs, nil
+	}
+	root, err := remote.FetchManifest(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	pkgConfig, err := oci.FetchUnmarshal[oci.ConfigPartial](ctx, remote.FetchLayer, json.Unmarshal, root.Config)
+	if err != nil {
+		return nil, err
+	}
+	if pkgConfig.Architecture != "" {
+		archs = append(archs, pkgConfig.Architecture)
+	}
+	return archs, nil
+}
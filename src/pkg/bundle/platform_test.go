@@ -0,0 +1,119 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_agreeOnArch(t *testing.T) {
+	pkgs := []types.Package{{Name: "podinfo"}, {Name: "nginx"}, {Name: "local"}}
+	tests := []struct {
+		name     string
+		pkgArchs [][]string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "AllAgree",
+			pkgArchs: [][]string{{"arm64"}, {"amd64", "arm64"}, nil},
+			want:     "arm64",
+		},
+		{
+			name:     "Disagree",
+			pkgArchs: [][]string{{"arm64"}, {"amd64"}, nil},
+			wantErr:  "packages disagree on architecture: package podinfo is built for arm64 but package nginx is built for amd64",
+		},
+		{
+			name:     "FirstIsMultiArch",
+			pkgArchs: [][]string{{"amd64", "arm64"}, {"arm64"}, nil},
+			wantErr:  "package podinfo is published for amd64, arm64, set its arch to choose the bundle's architecture",
+		},
+		{
+			name:     "FirstIsLocalDirectory",
+			pkgArchs: [][]string{nil, {"arm64"}, nil},
+			wantErr:  "unable to determine the architecture of package podinfo, set its path to the package tarball or set its arch",
+		},
+		{
+			name:     "OtherWithoutArch",
+			pkgArchs: [][]string{{"arm64"}, {}, nil},
+			wantErr:  "packages disagree on architecture: package podinfo is built for arm64 but package nginx doesn't record its architecture",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arch, err := agreeOnArch(pkgs, tt.pkgArchs)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, arch)
+		})
+	}
+}
+
+func Test_remoteArchs(t *testing.T) {
+	manifests := make(map[string][]byte)
+	mediaTypes := make(map[string]string)
+	add := func(mediaType string, v any) ocispec.Descriptor {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		desc := content.NewDescriptorFromBytes(mediaType, b)
+		manifests[desc.Digest.String()] = b
+		mediaTypes[desc.Digest.String()] = mediaType
+		return desc
+	}
+	pkgConfig := add(zoci.ZarfConfigMediaType, oci.ConfigPartial{Architecture: "arm64"})
+	manifest := ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: pkgConfig, Layers: []ocispec.Descriptor{}}
+	manifest.SchemaVersion = 2
+	manifestDesc := add(ocispec.MediaTypeImageManifest, manifest)
+	amd64, arm64 := manifestDesc, manifestDesc
+	amd64.Platform = &ocispec.Platform{Architecture: "amd64", OS: oci.MultiOS}
+	arm64.Platform = &ocispec.Platform{Architecture: "arm64", OS: oci.MultiOS}
+	index := ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{amd64, arm64, arm64}}
+	index.SchemaVersion = 2
+	tags := map[string]ocispec.Descriptor{
+		"multi":  add(ocispec.MediaTypeImageIndex, index),
+		"single": manifestDesc,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reference := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if desc, ok := tags[reference]; ok {
+			reference = desc.Digest.String()
+		}
+		b, ok := manifests[reference]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", mediaTypes[reference])
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		w.Header().Set("Docker-Content-Digest", reference)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	for tag, want := range map[string][]string{"multi": {"amd64", "arm64"}, "single": {"arm64"}} {
+		remote, err := oci.NewOrasRemote(host+"/dev/podinfo:"+tag, ocispec.Platform{}, oci.WithPlainHTTP(true))
+		require.NoError(t, err)
+		archs, err := remoteArchs(context.Background(), remote)
+		require.NoError(t, err)
+		require.Equal(t, want, archs, tag)
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// sourceDateEpochEnv is the reproducible builds variable that pins the time a build records
+// (https://reproducible-builds.org/docs/source-date-epoch/)
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// buildTime returns the time recorded in the bundle's build data, SOURCE_DATE_EPOCH takes precedence over the current
+// time so rebuilding the same definition records the same time
+func buildTime() (time.Time, error) {
+	epoch, ok := os.LookupEnv(sourceDateEpochEnv)
+	if !ok || epoch == "" {
+		return time.Now(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q, it must be a Unix timestamp: %w", sourceDateEpochEnv, epoch, err)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// isBuildPinned returns true if SOURCE_DATE_EPOCH is set, the build data then leaves out the user and machine that ran
+// the create so rebuilding the same definition with the same CLI produces the same uds-bundle.yaml
+func isBuildPinned() bool {
+	return os.Getenv(sourceDateEpochEnv) != ""
+}
+
+// buildProvenance records how the bundle is being produced, it must be called after ValidateBundleResources has
+// resolved the digest of each remote Zarf pkg. Only inputs to the bundle are recorded (not the user or machine) so the
+// provenance is the same for every create of the same definition when SOURCE_DATE_EPOCH is set
+func (b *Bundle) buildProvenance() (*types.UDSProvenance, error) {
+	now, err := buildTime()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := gitCommit(b.cfg.CreateOpts.SourceDirectory)
+	if err != nil {
+		return nil, err
+	}
+	provenance := types.UDSProvenance{
+		CLIVersion: config.CLIVersion,
+		Timestamp:  now.UTC().Format(time.RFC3339),
+		GitCommit:  commit,
+	}
+	for _, pkg := range b.bundle.Packages {
+		pkgProvenance, err := packageProvenance(pkg, b.cfg.CreateOpts.SourceDirectory)
+		if err != nil {
+			return nil, err
+		}
+		provenance.Packages = append(provenance.Packages, pkgProvenance)
+	}
+	return &provenance, nil
+}
+
+// packageProvenance returns the source of a Zarf pkg, remote pkgs are identified by the manifest digest appended to
+// their ref and local pkgs by the digest of their tarball, whose path is recorded relative to the source dir
+func packageProvenance(pkg types.Package, srcDir string) (types.PackageProvenance, error) {
+	pkgProvenance := types.PackageProvenance{Name: pkg.Name, Repository: pkg.Repository}
+	if pkg.Repository != "" {
+		_, digest, ok := strings.Cut(pkg.Ref, "@")
+		if !ok {
+			return types.PackageProvenance{}, fmt.Errorf("the digest of zarf pkg %s (%s:%s) hasn't been resolved", pkg.Name, pkg.Repository, pkg.Ref)
+		}
+		pkgProvenance.Digest = digest
+		return pkgProvenance, nil
+	}
+	sha, err := helpers.GetSHA256OfFile(pkg.Path)
+	if err != nil {
+		return types.PackageProvenance{}, fmt.Errorf("unable to compute the digest of zarf pkg %s at %s: %w", pkg.Name, pkg.Path, err)
+	}
+	pkgProvenance.Digest = "sha256:" + sha
+	pkgProvenance.Path = pkg.Path
+	if rel, err := filepath.Rel(srcDir, pkg.Path); err == nil {
+		pkgProvenance.Path = filepath.ToSlash(rel)
+	}
+	return pkgProvenance, nil
+}
+
+// gitCommit returns the HEAD commit of the git repository containing dir, or an empty string if dir isn't in a git
+// repository or the repository doesn't have any commits
+func gitCommit(dir string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to open the git repository containing %s: %w", dir, err)
+	}
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read the HEAD commit of the git repository containing %s: %w", dir, err)
+	}
+	return head.Hash().String(), nil
+}
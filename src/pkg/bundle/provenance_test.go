@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildTime(t *testing.T) {
+	t.Setenv(sourceDateEpochEnv, "1704164645")
+	now, err := buildTime()
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), now)
+
+	t.Setenv(sourceDateEpochEnv, "yesterday")
+	_, err = buildTime()
+	require.ErrorContains(t, err, "invalid SOURCE_DATE_EPOCH")
+
+	t.Setenv(sourceDateEpochEnv, "")
+	now, err = buildTime()
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), now, time.Minute)
+}
+
+func Test_CalculateBuildInfoPinned(t *testing.T) {
+	t.Setenv("USER", "builder")
+	b := &Bundle{bundle: types.UDSBundle{Metadata: types.UDSMetadata{Architecture: "amd64"}}}
+	t.Setenv(sourceDateEpochEnv, "")
+	require.NoError(t, b.CalculateBuildInfo())
+	require.Equal(t, "builder", b.bundle.Build.User)
+	require.NotEmpty(t, b.bundle.Build.Terminal)
+
+	// the user and machine would change the bundle's digest between builds of the same definition
+	t.Setenv(sourceDateEpochEnv, "0")
+	require.NoError(t, b.CalculateBuildInfo())
+	require.Empty(t, b.bundle.Build.User)
+	require.Empty(t, b.bundle.Build.Terminal)
+	require.Equal(t, "Thu, 01 Jan 1970 00:00:00 +0000", b.bundle.Build.Timestamp)
+}
+
+func Test_packageProvenance(t *testing.T) {
+	srcDir := t.TempDir()
+	tarball := filepath.Join(srcDir, "zarf-package-local-amd64-0.0.1.tar.zst")
+	require.NoError(t, os.WriteFile(tarball, []byte("pkg"), 0600))
+
+	tests := []struct {
+		name    string
+		pkg     types.Package
+		want    types.PackageProvenance
+		wantErr string
+	}{
+		{
+			name: "remote",
+			pkg:  types.Package{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/podinfo", Ref: "0.0.1@sha256:abc"},
+			want: types.PackageProvenance{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/podinfo", Digest: "sha256:abc"},
+		},
+		{
+			name:    "remote without a resolved digest",
+			pkg:     types.Package{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/podinfo", Ref: "0.0.1"},
+			wantErr: "the digest of zarf pkg podinfo (ghcr.io/defenseunicorns/podinfo:0.0.1) hasn't been resolved",
+		},
+		{
+			name: "local",
+			pkg:  types.Package{Name: "local", Path: tarball, Ref: "0.0.1"},
+			// sha256 of "pkg"
+			want: types.PackageProvenance{Name: "local", Path: "zarf-package-local-amd64-0.0.1.tar.zst", Digest: "sha256:e0ce1a7f3f76d7bfda0ffdae6e0226aa63b3009c4c4a770c321876aecad0a6c1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := packageProvenance(tt.pkg, srcDir)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_gitCommit(t *testing.T) {
+	dir := t.TempDir()
+	commit, err := gitCommit(dir)
+	require.NoError(t, err)
+	require.Empty(t, commit)
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	commit, err = gitCommit(dir)
+	require.NoError(t, err)
+	require.Empty(t, commit)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uds-bundle.yaml"), []byte("kind: UDSBundle"), 0600))
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add("uds-bundle.yaml")
+	require.NoError(t, err)
+	hash, err := worktree.Commit("bundle", &git.CommitOptions{Author: &object.Signature{Name: "uds", Email: "uds@example.com", When: time.Now()}})
+	require.NoError(t, err)
+
+	// the bundle definition can be in a subdirectory of the repository
+	subDir := filepath.Join(dir, "bundles")
+	require.NoError(t, os.Mkdir(subDir, 0700))
+	commit, err = gitCommit(subDir)
+	require.NoError(t, err)
+	require.Equal(t, hash.String(), commit)
+}
@@ -10,11 +10,9 @@ import (
 
 	"github.com/defenseunicorns/pkg/helpers"
 	"github.com/defenseunicorns/pkg/oci"
-	"github.com/defenseunicorns/uds-cli/src/config"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	"github.com/defenseunicorns/uds-cli/src/types"
 	"github.com/defenseunicorns/zarf/src/pkg/zoci"
-	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // Provider is an interface for processing bundles
@@ -52,12 +50,9 @@ func NewBundleProvider(source, destination string) (Provider, error) {
 	ctx := context.TODO()
 	if helpers.IsOCIURL(source) {
 		op := ociProvider{src: source, dst: destination}
-		platform := ocispec.Platform{
-			Architecture: config.GetArch(),
-			OS:           oci.MultiOS,
-		}
+		platform := utils.BundlePlatform(ctx, source)
 		// get remote client
-		remote, err := zoci.NewRemote(source, platform)
+		remote, err := zoci.NewRemote(source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 		if err != nil {
 			return nil, err
 		}
@@ -9,7 +9,6 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/config"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
@@ -52,9 +51,9 @@ func (b *Bundle) Publish() error {
 	bundleTag := b.bundle.Metadata.Version
 	platform := ocispec.Platform{
 		Architecture: config.GetArch(),
-		OS:           oci.MultiOS,
+		OS:           config.GetOS(b.bundle.Metadata.OS),
 	}
-	remote, err := zoci.NewRemote(fmt.Sprintf("%s/%s:%s", ociURL, bundleName, bundleTag), platform)
+	remote, err := zoci.NewRemote(fmt.Sprintf("%s/%s:%s", ociURL, bundleName, bundleTag), platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 	if err != nil {
 		return err
 	}
@@ -12,8 +12,8 @@ import (
 	"path/filepath"
 
 	"github.com/defenseunicorns/pkg/helpers"
-	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	"github.com/defenseunicorns/uds-cli/src/types"
 	zarfConfig "github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/pkg/message"
@@ -44,6 +44,15 @@ func (b *Bundle) Pull() error {
 		return err
 	}
 
+	// only pull the layers that aren't in the bundle given to --since
+	if b.cfg.PullOpts.Since != "" {
+		since, err := b.sinceLayers(ctx)
+		if err != nil {
+			return err
+		}
+		provider.(*ociProvider).since = since
+	}
+
 	// pull the bundle's uds-bundle.yaml and it's Zarf pkgs
 	bundle, loaded, err := provider.LoadBundle(b.cfg.PullOpts, zarfConfig.CommonOptions.OCIConcurrency)
 	if err != nil {
@@ -52,11 +61,8 @@ func (b *Bundle) Pull() error {
 	b.bundle = *bundle
 
 	// create a remote client just to resolve the root descriptor
-	platform := ocispec.Platform{
-		Architecture: config.GetArch(),
-		OS:           oci.MultiOS,
-	}
-	remote, err := zoci.NewRemote(b.cfg.PullOpts.Source, platform)
+	platform := utils.BundlePlatform(ctx, b.cfg.PullOpts.Source)
+	remote, err := zoci.NewRemote(b.cfg.PullOpts.Source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 	if err != nil {
 		return err
 	}
@@ -110,12 +116,10 @@ func (b *Bundle) Pull() error {
 	pathMap[filepath.Join(b.tmp, "index.json")] = "index.json"
 	pathMap[filepath.Join(cacheDir, "oci-layout")] = "oci-layout"
 
-	// re-map the paths to be relative to the cache directory
-	for sha, abs := range loaded {
-		if sha == config.BundleYAML || sha == config.BundleYAMLSignature {
-			sha = filepath.Base(abs)
-		}
-		pathMap[abs] = filepath.Join(config.BlobsDir, sha)
+	// re-map the paths to be relative to the cache directory, the metadata files and extra files are loaded by their
+	// title but stored by their digest
+	for _, abs := range loaded {
+		pathMap[abs] = filepath.Join(config.BlobsDir, filepath.Base(abs))
 	}
 
 	files, err := archiver.FilesFromDisk(nil, pathMap)
@@ -129,6 +133,9 @@ func (b *Bundle) Pull() error {
 	}
 
 	message.Debug("Create tarball saved to", dst)
+	if b.cfg.PullOpts.Since != "" {
+		message.Warnf("%s is a delta of %s, it's missing the layers that are unchanged since that bundle and can't be deployed on its own", dst, b.cfg.PullOpts.Since)
+	}
 
 	return nil
 }
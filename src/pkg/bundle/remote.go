@@ -44,6 +44,8 @@ type ociProvider struct {
 	dst string
 	*oci.OrasRemote
 	rootManifest *oci.Manifest
+	// since is the digests of the layers in the bundle given to pull --since, they're skipped when the bundle is loaded
+	since map[string]bool
 }
 
 func (op *ociProvider) getBundleManifest() (*oci.Manifest, error) {
@@ -60,7 +62,7 @@ func (op *ociProvider) LoadBundleMetadata() (types.PathMap, error) {
 		return nil, err
 	}
 
-	layers, err := op.PullPaths(ctx, filepath.Join(op.dst, config.BlobsDir), config.BundleAlwaysPull)
+	layers, err := op.PullPaths(ctx, filepath.Join(op.dst, config.BlobsDir), metadataPaths(op.rootManifest))
 	if err != nil {
 		return nil, err
 	}
@@ -74,10 +76,65 @@ func (op *ociProvider) LoadBundleMetadata() (types.PathMap, error) {
 			return nil, err
 		}
 		loaded[rel] = absSha
+		if rel == config.BundleYAMLSignature {
+			certPath, err := writeSignatureCertificate(layer, filepath.Join(op.dst, config.BlobsDir))
+			if err != nil {
+				return nil, err
+			}
+			if certPath != "" {
+				loaded[config.BundleYAMLCertificate] = certPath
+			}
+		}
+	}
+	if _, ok := loaded[config.BundleYAMLSignature]; !ok {
+		if err := op.loadDetachedSignature(ctx, loaded); err != nil {
+			return nil, err
+		}
 	}
 	return loaded, nil
 }
 
+// detachedSignatureDesc resolves the signature the root manifest references with an annotation instead of a layer,
+// returning false if the bundle doesn't have a detached signature
+func (op *ociProvider) detachedSignatureDesc(ctx context.Context) (ocispec.Descriptor, bool, error) {
+	sigDigest, ok := op.rootManifest.Annotations[config.BundleSignatureDigestAnnotation]
+	if !ok {
+		return ocispec.Descriptor{}, false, nil
+	}
+	signatureDesc, err := op.Repo().Blobs().Resolve(ctx, sigDigest)
+	if err != nil {
+		return ocispec.Descriptor{}, true, fmt.Errorf("unable to resolve the detached signature %s: %w", sigDigest, err)
+	}
+	// the signature's annotations are on the root manifest since it isn't a layer
+	signatureDesc.Annotations = op.rootManifest.Annotations
+	return signatureDesc, true, nil
+}
+
+// loadDetachedSignature pulls a detached signature into the same place as a signature layer
+func (op *ociProvider) loadDetachedSignature(ctx context.Context, loaded types.PathMap) error {
+	signatureDesc, ok, err := op.detachedSignatureDesc(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	signature, err := op.FetchLayer(ctx, signatureDesc)
+	if err != nil {
+		return err
+	}
+	signaturePath := filepath.Join(op.dst, config.BlobsDir, signatureDesc.Digest.Encoded())
+	if err := os.WriteFile(signaturePath, signature, 0600); err != nil {
+		return err
+	}
+	loaded[config.BundleYAMLSignature] = signaturePath
+	certPath, err := writeSignatureCertificate(signatureDesc, filepath.Join(op.dst, config.BlobsDir))
+	if err != nil {
+		return err
+	}
+	if certPath != "" {
+		loaded[config.BundleYAMLCertificate] = certPath
+	}
+	return nil
+}
+
 // CreateBundleSBOM creates a bundle-level SBOM from the underlying Zarf packages, if the Zarf package contains an SBOM
 func (op *ociProvider) CreateBundleSBOM(extractSBOM bool) error {
 	ctx := context.TODO()
@@ -95,7 +152,21 @@ func (op *ociProvider) CreateBundleSBOM(extractSBOM bool) error {
 
 	// iterate through Zarf image manifests and find the Zarf pkg's sboms.tar
 	for _, layer := range root.Layers {
-		if layer.Annotations[ocispec.AnnotationTitle] == config.BundleYAML {
+		if layer.Annotations[ocispec.AnnotationTitle] == config.BundleSBOMJSON {
+			// the bundle-level SBOM is added to the extracted SBOMs as is
+			sbomBytes, err := op.OrasRemote.FetchLayer(ctx, layer)
+			if err != nil {
+				return err
+			}
+			sbomPath := filepath.Join(op.dst, config.BundleSBOM, config.BundleSBOMJSON)
+			if err := os.WriteFile(sbomPath, sbomBytes, 0600); err != nil {
+				return err
+			}
+			SBOMArtifactPathMap[sbomPath] = config.BundleSBOMJSON
+			containsSBOMs = true
+			continue
+		}
+		if isBundleMetadataLayer(layer) {
 			continue
 		}
 		zarfManifest, err := op.OrasRemote.FetchManifest(ctx, layer)
@@ -156,7 +227,7 @@ func (op *ociProvider) LoadBundle(opts types.BundlePullOptions, _ int) (*types.U
 	}
 
 	// validate the sig (if present) before pulling the whole bundle
-	if err := ValidateBundleSignature(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], opts.PublicKeyPath); err != nil {
+	if err := ValidateBundleSignature(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], loaded[config.BundleYAMLCertificate], opts.PublicKeyPath); err != nil {
 		return nil, nil, err
 	}
 
@@ -169,10 +240,17 @@ func (op *ociProvider) LoadBundle(opts types.BundlePullOptions, _ int) (*types.U
 		return nil, nil, err
 	}
 
+	// only pull the Zarf pkgs specified by --packages, the bundle's metadata and sig are always pulled
+	packagesToPull, err := selectPackages(bundle.Packages, opts.Packages)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// grab root manifest config
 	layersToPull = append(layersToPull, rootManifest.Config)
+	skipped := make(map[string][]ocispec.Descriptor)
 
-	for _, pkg := range bundle.Packages {
+	for _, pkg := range packagesToPull {
 
 		// grab sha of zarf image manifest and pull it down
 		sha := strings.Split(pkg.Ref, "@sha256:")[1] // this is where we use the SHA appended to the Zarf pkg inside the bundle
@@ -193,8 +271,11 @@ func (op *ociProvider) LoadBundle(opts types.BundlePullOptions, _ int) (*types.U
 		layersToPull = append(layersToPull, manifestDesc)
 		progressBar := message.NewProgressBar(int64(len(manifest.Layers)), fmt.Sprintf("Verifying layers in Zarf package: %s", pkg.Name))
 
-		// go through the layers in the zarf image manifest and check if they exist in the remote
-		for _, layer := range manifest.Layers {
+		// go through the layers in the zarf image manifest and check if they exist in the remote, layers that are
+		// already in the bundle given to --since are left out of the pull
+		var pkgLayers []ocispec.Descriptor
+		pkgLayers, skipped[pkg.Name] = excludeSinceLayers(manifest.Layers, op.since)
+		for _, layer := range pkgLayers {
 			ok, err := op.Repo().Blobs().Exists(ctx, layer)
 			progressBar.Add(1)
 			estimatedBytes += layer.Size
@@ -208,6 +289,9 @@ func (op *ociProvider) LoadBundle(opts types.BundlePullOptions, _ int) (*types.U
 		}
 		progressBar.Successf("Verified %s package", pkg.Name)
 	}
+	if op.since != nil {
+		printSkippedLayers(opts.Since, skipped, packageNames(packagesToPull))
+	}
 
 	store, err := ocistore.NewWithContext(ctx, op.dst)
 	if err != nil {
@@ -221,6 +305,20 @@ func (op *ociProvider) LoadBundle(opts types.BundlePullOptions, _ int) (*types.U
 	}
 	layersToPull = append(layersToPull, rootDesc)
 
+	// layers left behind by a previous (possibly interrupted) pull are skipped by oras.Copy() if they still verify
+	verifiedBytes, err := op.removeCorruptLayers(layersToPull)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// add the size of the dst dir to the estimate, otherwise the progress bar will be off because
+	// RenderProgressBarForLocalDirWrite counts the layers that already exist in the dst dir
+	dstDirSize, err := helpers.GetDirSize(op.dst)
+	if err != nil {
+		return nil, nil, err
+	}
+	estimatedBytes = estimatedBytes - verifiedBytes + dstDirSize
+
 	// create copy options for oras.Copy()
 	copyOpts := utils.CreateCopyOpts(layersToPull, config.CommonOptions.OCIConcurrency)
 
@@ -243,6 +341,29 @@ func (op *ociProvider) LoadBundle(opts types.BundlePullOptions, _ int) (*types.U
 	return &bundle, loaded, nil
 }
 
+// removeCorruptLayers checks the layers that already exist in the dst dir, removing any that don't match their
+// descriptor so they get pulled again, and returns the total size of the layers that verified
+func (op *ociProvider) removeCorruptLayers(layers []ocispec.Descriptor) (int64, error) {
+	blobsDir := filepath.Join(op.dst, config.BlobsDir)
+	verifiedBytes := int64(0)
+	for _, layer := range layers {
+		blobPath := filepath.Join(blobsDir, layer.Digest.Encoded())
+		if helpers.InvalidPath(blobPath) {
+			continue
+		}
+		if utils.IsValidBlob(blobsDir, layer) {
+			message.Debugf("Layer %s already pulled, skipping", layer.Digest.Encoded())
+			verifiedBytes += layer.Size
+			continue
+		}
+		message.Debugf("Layer %s is corrupt, pulling it again", layer.Digest.Encoded())
+		if err := os.Remove(blobPath); err != nil {
+			return 0, err
+		}
+	}
+	return verifiedBytes, nil
+}
+
 func (op *ociProvider) PublishBundle(_ types.UDSBundle, _ *oci.OrasRemote) error {
 	// todo: implement moving bundles from one registry to another
 	return fmt.Errorf("moving bundles in between remote registries not yet supported")
@@ -253,13 +374,9 @@ func getOCIValidatedSource(source string) (string, error) {
 	ctx := context.TODO()
 	originalSource := source
 
-	platform := ocispec.Platform{
-		Architecture: config.GetArch(),
-		OS:           oci.MultiOS,
-	}
 	// Check provided repository path
 	sourceWithOCI := utils.EnsureOCIPrefix(source)
-	remote, err := zoci.NewRemote(sourceWithOCI, platform)
+	remote, err := zoci.NewRemote(sourceWithOCI, utils.BundlePlatform(ctx, sourceWithOCI), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 	if err == nil {
 		source = sourceWithOCI
 		_, err = remote.ResolveRoot(ctx)
@@ -269,7 +386,7 @@ func getOCIValidatedSource(source string) (string, error) {
 	if err != nil {
 		// Check in ghcr uds bundle path
 		source = GHCRUDSBundlePath + originalSource
-		remote, err = zoci.NewRemote(source, platform)
+		remote, err = zoci.NewRemote(source, utils.BundlePlatform(ctx, source), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 		if err == nil {
 			_, err = remote.ResolveRoot(ctx)
 		}
@@ -277,7 +394,7 @@ func getOCIValidatedSource(source string) (string, error) {
 			message.Debugf("%s: not found", source)
 			// Check in delivery bundle path
 			source = GHCRDeliveryBundlePath + originalSource
-			remote, err = zoci.NewRemote(source, platform)
+			remote, err = zoci.NewRemote(source, utils.BundlePlatform(ctx, source), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 			if err == nil {
 				_, err = remote.ResolveRoot(ctx)
 			}
@@ -285,7 +402,7 @@ func getOCIValidatedSource(source string) (string, error) {
 				message.Debugf("%s: not found", source)
 				// Check in packages bundle path
 				source = GHCRPackagesPath + originalSource
-				remote, err = zoci.NewRemote(source, platform)
+				remote, err = zoci.NewRemote(source, utils.BundlePlatform(ctx, source), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 				if err == nil {
 					_, err = remote.ResolveRoot(ctx)
 				}
@@ -52,12 +52,18 @@ func (b *Bundle) Remove() error {
 		return err
 	}
 
+	// pkgs are removed in the reverse of their deploy order
+	packages, err := orderPackages(b.bundle.Packages)
+	if err != nil {
+		return err
+	}
+
 	// Check if --packages flag is set and zarf packages have been specified
 	var packagesToRemove []types.Package
 
 	if len(b.cfg.RemoveOpts.Packages) != 0 {
 		userSpecifiedPackages := strings.Split(strings.ReplaceAll(b.cfg.RemoveOpts.Packages[0], " ", ""), ",")
-		for _, pkg := range b.bundle.Packages {
+		for _, pkg := range packages {
 			if slices.Contains(userSpecifiedPackages, pkg.Name) {
 				packagesToRemove = append(packagesToRemove, pkg)
 			}
@@ -69,7 +75,7 @@ func (b *Bundle) Remove() error {
 		}
 		return removePackages(packagesToRemove, b)
 	}
-	return removePackages(b.bundle.Packages, b)
+	return removePackages(packages, b)
 }
 
 func removePackages(packagesToRemove []types.Package, b *Bundle) error {
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/bundler"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+)
+
+// Resign signs a published bundle's YAML with a new key and pushes only the new signature, the bundle's Zarf pkgs
+// stay where they are in the registry
+func (b *Bundle) Resign() error {
+	ctx := context.TODO()
+	opts := b.cfg.ResignOpts
+	if opts.SigningKeyPath == "" && !opts.SignKeyless && opts.SignaturePath == "" {
+		return fmt.Errorf("a signing key, keyless signing or a signature is required to resign a bundle")
+	}
+	if opts.SigningKeyPath != "" && opts.SignKeyless {
+		return fmt.Errorf("cannot sign a bundle with both a signing key and keyless signing")
+	}
+	if opts.SignaturePath != "" && (opts.SigningKeyPath != "" || opts.SignKeyless) {
+		return fmt.Errorf("a signature can't be combined with a signing key or keyless signing, it's attached as is")
+	}
+	source, err := CheckOCISourcePath(opts.Source)
+	if err != nil {
+		return err
+	}
+	if !helpers.IsOCIURL(source) {
+		return fmt.Errorf("resign only supports bundles in an OCI registry, %s is not an OCI reference", source)
+	}
+
+	provider, err := NewBundleProvider(source, b.tmp)
+	if err != nil {
+		return err
+	}
+	loaded, err := provider.LoadBundleMetadata()
+	if err != nil {
+		return err
+	}
+	if err := zarfUtils.ReadYaml(loaded[config.BundleYAML], &b.bundle); err != nil {
+		return err
+	}
+
+	// check the bundle was signed by the old key before vouching for it with the new one
+	if opts.PublicKeyPath != "" {
+		if err := ValidateBundleSignature(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], loaded[config.BundleYAMLCertificate], opts.PublicKeyPath); err != nil {
+			return fmt.Errorf("unable to validate the current signature of %s: %w", source, err)
+		}
+	}
+
+	// sign the YAML as published, re-marshaling it could change its bytes and invalidate the signature
+	var signature []byte
+	var sigAnnotations map[string]string
+	if opts.SignaturePath != "" {
+		// an external signer (e.g. an HSM or KMS) already signed the YAML as published
+		if signature, err = os.ReadFile(opts.SignaturePath); err != nil {
+			return fmt.Errorf("unable to read the signature: %w", err)
+		}
+		if len(bytes.TrimSpace(signature)) == 0 {
+			return fmt.Errorf("the signature %s is empty", opts.SignaturePath)
+		}
+	} else {
+		signature, sigAnnotations, err = b.signBundleYAML(loaded[config.BundleYAML], opts.SigningKeyPath, opts.SigningKeyPassword, opts.SignKeyless)
+		if err != nil {
+			return err
+		}
+	}
+
+	platform := utils.BundlePlatform(ctx, source)
+	remote, err := zoci.NewRemote(source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return err
+	}
+	rootManifestDesc, err := bundler.Resign(ctx, remote, &b.bundle, bundler.ResignOptions{
+		Signature:            signature,
+		SignatureAnnotations: sigAnnotations,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to resign %s: %w", source, err)
+	}
+	message.Successf("Resigned %s, its root manifest is now %s", source, rootManifestDesc.Digest)
+	return nil
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/bundler"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+)
+
+// Retag adds a tag to a published bundle in the same repository, the bundle's blobs and root manifest aren't pushed
+// again
+func (b *Bundle) Retag() error {
+	ctx := context.TODO()
+	opts := b.cfg.RetagOpts
+	source, err := CheckOCISourcePath(opts.Source)
+	if err != nil {
+		return err
+	}
+	if !helpers.IsOCIURL(source) {
+		return fmt.Errorf("retag only supports bundles in an OCI registry, %s is not an OCI reference", source)
+	}
+
+	// the bundle's metadata has the arch its root manifest is indexed under
+	provider, err := NewBundleProvider(source, b.tmp)
+	if err != nil {
+		return err
+	}
+	loaded, err := provider.LoadBundleMetadata()
+	if err != nil {
+		return err
+	}
+	if err := zarfUtils.ReadYaml(loaded[config.BundleYAML], &b.bundle); err != nil {
+		return err
+	}
+
+	platform := utils.BundlePlatform(ctx, source)
+	remote, err := zoci.NewRemote(source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return err
+	}
+	desc, err := bundler.Retag(ctx, remote, &b.bundle, opts.Tag, opts.Force)
+	if err != nil {
+		return fmt.Errorf("unable to retag %s: %w", source, err)
+	}
+	message.Successf("Tagged %s as %s (%s)", source, opts.Tag, desc.Digest)
+	return nil
+}
@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/cluster"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// deployStatusDeploying is the status of a deploy that started but hasn't finished, e.g. because it was interrupted
+	deployStatusDeploying = "deploying"
+	// deployStatusDeployed is the status of a deploy whose Zarf pkgs were all deployed
+	deployStatusDeployed = "deployed"
+	// deployStatusFailed is the status of a deploy that failed to deploy one of its Zarf pkgs
+	deployStatusFailed = "failed"
+)
+
+// deployRecorder records a bundle's deploy in the cluster along with the deploy it can be rolled back to
+type deployRecorder struct {
+	c        *cluster.Cluster
+	deploy   types.BundleDeployRecord
+	rollback *types.BundleDeployRecord
+}
+
+// startDeployRecord captures the deploy the bundle can be rolled back to before any of its Zarf pkgs are deployed and
+// records the deploy that's starting. The deploy isn't recorded if the cluster can't be reached or the previous deploy
+// can't be captured, so the existing records aren't lost
+func (b *Bundle) startDeployRecord(packages []types.Package) *deployRecorder {
+	c, err := cluster.NewCluster()
+	if err != nil {
+		message.Debugf("Not recording the deploy of %s, unable to connect to the cluster: %s", b.bundle.Metadata.Name, err)
+		return nil
+	}
+	rollback, err := captureRollback(c, b.bundle.Metadata.Name)
+	if err != nil {
+		message.Warnf("Unable to capture the previous deploy of %s, this deploy won't be recorded and can't be rolled back: %s", b.bundle.Metadata.Name, err)
+		return nil
+	}
+	if rollback == nil {
+		message.Debugf("%s doesn't have a previous deploy to roll back to", b.bundle.Metadata.Name)
+	}
+
+	r := &deployRecorder{
+		c:        c,
+		deploy:   newDeployRecord(&b.bundle, deployRecordSource(b.cfg.DeployOpts.Source), packages),
+		rollback: rollback,
+	}
+	// the Zarf namespace doesn't exist until the bundle's init pkg is deployed, the record is written again once the
+	// deploy finishes
+	if err := r.write(); err != nil {
+		message.Debugf("Unable to record the start of the deploy of %s: %s", b.bundle.Metadata.Name, err)
+	}
+	return r
+}
+
+// finish records whether the deploy succeeded, a later deploy only captures a successful deploy to roll back to
+func (r *deployRecorder) finish(deployErr error) {
+	if r == nil {
+		return
+	}
+	r.deploy.Status = deployStatusDeployed
+	if deployErr != nil {
+		r.deploy.Status = deployStatusFailed
+	}
+	if err := r.write(); err != nil {
+		message.Warnf("Unable to record the deploy of %s, it can't be rolled back: %s", r.deploy.Name, err)
+	}
+}
+
+// write stores the deploy and its rollback in the bundle's deploy record secret
+func (r *deployRecorder) write() error {
+	secret := r.c.GenerateSecret(cluster.ZarfNamespaceName, deployRecordSecretName(r.deploy.Name), corev1.SecretTypeOpaque)
+	deployData, err := json.Marshal(r.deploy)
+	if err != nil {
+		return err
+	}
+	secret.Data[config.DeployRecordKey] = deployData
+	if r.rollback != nil {
+		rollbackData, err := json.Marshal(r.rollback)
+		if err != nil {
+			return err
+		}
+		secret.Data[config.RollbackRecordKey] = rollbackData
+	}
+	_, err = r.c.CreateOrUpdateSecret(secret)
+	return err
+}
+
+// newDeployRecord returns the record of a deploy of the bundle's pkgs that's starting
+func newDeployRecord(bundle *types.UDSBundle, source string, packages []types.Package) types.BundleDeployRecord {
+	record := types.BundleDeployRecord{
+		Name:      bundle.Metadata.Name,
+		Version:   bundle.Metadata.Version,
+		Source:    source,
+		Status:    deployStatusDeploying,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, pkg := range packages {
+		record.Packages = append(record.Packages, types.DeployRecordPackage{Name: pkg.Name, Ref: pkg.Ref})
+	}
+	return record
+}
+
+// deployRecordSource returns the source a bundle is redeployed from on rollback, a tarball's path is made absolute so
+// the rollback can be run from any directory
+func deployRecordSource(source string) string {
+	if !utils.IsValidTarballPath(source) {
+		return source
+	}
+	if abs, err := filepath.Abs(source); err == nil {
+		return abs
+	}
+	return source
+}
+
+// deployRecordSecretName returns the name of the secret a bundle's deploys are recorded in
+func deployRecordSecretName(bundleName string) string {
+	return config.DeployRecordPrefix + bundleName
+}
+
+// getDeployRecords returns a bundle's latest deploy and the deploy it can be rolled back to, either is nil if it
+// wasn't recorded
+func getDeployRecords(c *cluster.Cluster, bundleName string) (*types.BundleDeployRecord, *types.BundleDeployRecord, error) {
+	secret, err := c.GetSecret(cluster.ZarfNamespaceName, deployRecordSecretName(bundleName))
+	if k8serrors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	deploy, err := unmarshalDeployRecord(secret.Data[config.DeployRecordKey])
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the latest deploy of %s: %w", bundleName, err)
+	}
+	rollback, err := unmarshalDeployRecord(secret.Data[config.RollbackRecordKey])
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the deploy %s can be rolled back to: %w", bundleName, err)
+	}
+	return deploy, rollback, nil
+}
+
+// unmarshalDeployRecord decodes a deploy record, nil is returned if there isn't one
+func unmarshalDeployRecord(data []byte) (*types.BundleDeployRecord, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var record types.BundleDeployRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// captureRollback returns the deploy a bundle can be rolled back to once it's deployed again. That's its latest
+// deploy if it succeeded, limited to the Zarf pkgs that are still deployed and with the versions the cluster has for
+// them; a failed or interrupted deploy isn't a state to roll back to so the deploy before it is kept instead. nil is
+// returned for the bundle's first deploy
+func captureRollback(c *cluster.Cluster, bundleName string) (*types.BundleDeployRecord, error) {
+	latest, rollback, err := getDeployRecords(c, bundleName)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil || latest.Status != deployStatusDeployed {
+		return rollback, nil
+	}
+
+	deployedPackages, errs := c.GetDeployedZarfPackages()
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("unable to get the deployed packages: %w", errors.Join(errs...))
+	}
+	versions := make(map[string]string, len(deployedPackages))
+	for _, pkg := range deployedPackages {
+		versions[pkg.Name] = pkg.Data.Metadata.Version
+	}
+	captured := *latest
+	captured.Packages = nil
+	for _, pkg := range latest.Packages {
+		version, ok := versions[pkg.Name]
+		if !ok {
+			// the pkg was removed since, there's nothing to roll it back to
+			continue
+		}
+		pkg.Version = version
+		captured.Packages = append(captured.Packages, pkg)
+	}
+	if len(captured.Packages) == 0 {
+		return nil, nil
+	}
+	return &captured, nil
+}
+
+// Rollback redeploys the Zarf pkgs of the deploy a bundle can be rolled back to, from the bundle they were deployed
+// from. The rollback is recorded like any deploy, so rolling back again returns to the deploy that was rolled back
+func (b *Bundle) Rollback() error {
+	name := b.cfg.RollbackOpts.BundleName
+	c, err := cluster.NewCluster()
+	if err != nil {
+		return fmt.Errorf("unable to connect to the cluster: %w", err)
+	}
+	_, rollback, err := getDeployRecords(c, name)
+	if err != nil {
+		return err
+	}
+	if rollback == nil {
+		return fmt.Errorf("%s doesn't have a previous deploy to roll back to, it has only been deployed once or wasn't deployed with uds deploy", name)
+	}
+
+	message.Infof("Rolling back %s to version %s from %s", name, rollback.Version, rollback.Source)
+	b.cfg.DeployOpts.Source = rollback.Source
+	if _, _, _, err := b.PreDeployValidation(); err != nil {
+		return err
+	}
+	if err := checkRollbackBundle(&b.bundle, rollback); err != nil {
+		return err
+	}
+	var names []string
+	for _, pkg := range rollback.Packages {
+		names = append(names, pkg.Name)
+	}
+	b.cfg.DeployOpts.Packages = []string{strings.Join(names, ",")}
+	if ok := b.ConfirmBundleDeploy(); !ok {
+		return fmt.Errorf("bundle rollback cancelled")
+	}
+	return b.Deploy()
+}
+
+// checkRollbackBundle returns an error if the bundle at the rollback's source isn't the one that was deployed, e.g.
+// because its tag was moved to a newer bundle since
+func checkRollbackBundle(bundle *types.UDSBundle, rollback *types.BundleDeployRecord) error {
+	if bundle.Metadata.Name != rollback.Name {
+		return fmt.Errorf("the bundle at %s is %s, not %s", rollback.Source, bundle.Metadata.Name, rollback.Name)
+	}
+	for _, recorded := range rollback.Packages {
+		i := slices.IndexFunc(bundle.Packages, func(pkg types.Package) bool { return pkg.Name == recorded.Name })
+		if i < 0 || bundle.Packages[i].Ref != recorded.Ref {
+			return fmt.Errorf("the bundle at %s has changed since it was deployed, package %s was deployed from %s", rollback.Source, recorded.Name, recorded.Ref)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/cluster"
+	"github.com/defenseunicorns/zarf/src/pkg/k8s"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_deployRecords(t *testing.T) {
+	c := &cluster.Cluster{K8s: &k8s.K8s{Clientset: fake.NewSimpleClientset()}}
+	// deployZarfPkg records a Zarf pkg the way Zarf does once it's deployed
+	deployZarfPkg := func(name, version string) {
+		data, err := json.Marshal(zarfTypes.DeployedPackage{Name: name, Data: zarfTypes.ZarfPackage{Metadata: zarfTypes.ZarfMetadata{Name: name, Version: version}}})
+		require.NoError(t, err)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "zarf-package-" + name, Namespace: cluster.ZarfNamespaceName, Labels: map[string]string{cluster.ZarfPackageInfoLabel: name}},
+			Data:       map[string][]byte{"data": data},
+		}
+		_, err = c.CreateOrUpdateSecret(secret)
+		require.NoError(t, err)
+	}
+	bundleVersion := func(version string) *types.UDSBundle {
+		return &types.UDSBundle{
+			Metadata: types.UDSMetadata{Name: "example", Version: version},
+			Packages: []types.Package{
+				{Name: "podinfo", Ref: version + "@sha256:" + version},
+				{Name: "nginx", Ref: version + "@sha256:" + version},
+			},
+		}
+	}
+	deployBundle := func(bundle *types.UDSBundle, deployErr error) {
+		rollback, err := captureRollback(c, bundle.Metadata.Name)
+		require.NoError(t, err)
+		r := &deployRecorder{c: c, deploy: newDeployRecord(bundle, "oci://ghcr.io/org/example:"+bundle.Metadata.Version, bundle.Packages), rollback: rollback}
+		require.NoError(t, r.write())
+		if deployErr == nil {
+			for _, pkg := range bundle.Packages {
+				deployZarfPkg(pkg.Name, bundle.Metadata.Version)
+			}
+		}
+		r.finish(deployErr)
+	}
+
+	// the first deploy has nothing to roll back to
+	deploy, rollback, err := getDeployRecords(c, "example")
+	require.NoError(t, err)
+	require.Nil(t, deploy)
+	require.Nil(t, rollback)
+	deployBundle(bundleVersion("0.0.1"), nil)
+	deploy, rollback, err = getDeployRecords(c, "example")
+	require.NoError(t, err)
+	require.Equal(t, deployStatusDeployed, deploy.Status)
+	require.Equal(t, "0.0.1", deploy.Version)
+	require.Nil(t, rollback)
+
+	// the next deploy can be rolled back to the versions the cluster had deployed
+	deployBundle(bundleVersion("0.0.2"), nil)
+	_, rollback, err = getDeployRecords(c, "example")
+	require.NoError(t, err)
+	require.Equal(t, "0.0.1", rollback.Version)
+	require.Equal(t, "oci://ghcr.io/org/example:0.0.1", rollback.Source)
+	require.Equal(t, []types.DeployRecordPackage{
+		{Name: "podinfo", Ref: "0.0.1@sha256:0.0.1", Version: "0.0.1"},
+		{Name: "nginx", Ref: "0.0.1@sha256:0.0.1", Version: "0.0.1"},
+	}, rollback.Packages)
+
+	// a failed deploy isn't rolled back to, the last successful deploy is still the rollback of the next one
+	deployBundle(bundleVersion("0.0.3"), errors.New("failed"))
+	deploy, rollback, err = getDeployRecords(c, "example")
+	require.NoError(t, err)
+	require.Equal(t, deployStatusFailed, deploy.Status)
+	require.Equal(t, "0.0.2", rollback.Version)
+	rollback, err = captureRollback(c, "example")
+	require.NoError(t, err)
+	require.Equal(t, "0.0.2", rollback.Version)
+
+	// pkgs that were removed since aren't rolled back
+	deployBundle(bundleVersion("0.0.3"), nil)
+	require.NoError(t, c.Clientset.CoreV1().Secrets(cluster.ZarfNamespaceName).Delete(context.Background(), "zarf-package-nginx", metav1.DeleteOptions{}))
+	rollback, err = captureRollback(c, "example")
+	require.NoError(t, err)
+	require.Equal(t, "0.0.3", rollback.Version)
+	require.Equal(t, []types.DeployRecordPackage{{Name: "podinfo", Ref: "0.0.3@sha256:0.0.3", Version: "0.0.3"}}, rollback.Packages)
+}
+
+func Test_checkRollbackBundle(t *testing.T) {
+	rollback := &types.BundleDeployRecord{
+		Name:     "example",
+		Source:   "oci://ghcr.io/org/example:0.0.1",
+		Packages: []types.DeployRecordPackage{{Name: "podinfo", Ref: "0.0.1@sha256:abc"}},
+	}
+	bundle := &types.UDSBundle{
+		Metadata: types.UDSMetadata{Name: "example"},
+		Packages: []types.Package{{Name: "podinfo", Ref: "0.0.1@sha256:abc"}, {Name: "nginx", Ref: "0.0.1@sha256:def"}},
+	}
+	require.NoError(t, checkRollbackBundle(bundle, rollback))
+
+	bundle.Packages[0].Ref = "0.0.1@sha256:123"
+	require.EqualError(t, checkRollbackBundle(bundle, rollback),
+		"the bundle at oci://ghcr.io/org/example:0.0.1 has changed since it was deployed, package podinfo was deployed from 0.0.1@sha256:abc")
+
+	bundle.Metadata.Name = "other"
+	require.EqualError(t, checkRollbackBundle(bundle, rollback), "the bundle at oci://ghcr.io/org/example:0.0.1 is other, not example")
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+)
+
+// isSetValue returns whether a --set key is a Helm value of a pkg's component (ex. packageName.componentName.path)
+// rather than a variable (ex. variableName or packageName.variableName)
+func isSetValue(name string) bool {
+	return strings.Count(name, ".") >= 2
+}
+
+// hasSetValues returns whether any Helm values were set with --set
+func (b *Bundle) hasSetValues() bool {
+	return slices.ContainsFunc(setKeys(b.cfg.DeployOpts.SetVariables), isSetValue)
+}
+
+// loadSetValues maps the Helm values set with --set to the chart of the component they're set for into
+// DeployOpts.SetValues, zarfYAMLs are the pkgs' zarf.yaml by name. The pkg and component must exist so a typo doesn't
+// silently drop a value. The chart can be left out of the key if the component only has one
+// (ex. packageName.componentName.path), otherwise it follows the component (ex. packageName.componentName.chartName.path)
+func (b *Bundle) loadSetValues(zarfYAMLs map[string]zarfTypes.ZarfPackage) error {
+	setValues := make(map[string]map[string]map[string][]types.BundleChartValue)
+	for _, name := range setKeys(b.cfg.DeployOpts.SetVariables) {
+		if !isSetValue(name) {
+			continue
+		}
+		parts := strings.SplitN(name, ".", 3)
+		pkgName, componentName, path := parts[0], parts[1], parts[2]
+		if !slices.ContainsFunc(b.bundle.Packages, func(pkg types.Package) bool { return pkg.Name == pkgName }) {
+			return fmt.Errorf("invalid --set %s, the bundle doesn't have a zarf pkg named %s", name, pkgName)
+		}
+		zarfYAML, ok := zarfYAMLs[pkgName]
+		if !ok {
+			return fmt.Errorf("invalid --set %s, unable to find the components of zarf pkg %s", name, pkgName)
+		}
+		i := slices.IndexFunc(zarfYAML.Components, func(c zarfTypes.ZarfComponent) bool { return c.Name == componentName })
+		if i < 0 {
+			return fmt.Errorf("invalid --set %s, zarf pkg %s doesn't have a component named %s", name, pkgName, componentName)
+		}
+		chartName, path, err := setValueChart(zarfYAML.Components[i], path)
+		if err != nil {
+			return fmt.Errorf("invalid --set %s, %w", name, err)
+		}
+
+		if _, ok := setValues[pkgName]; !ok {
+			setValues[pkgName] = make(map[string]map[string][]types.BundleChartValue)
+		}
+		if _, ok := setValues[pkgName][componentName]; !ok {
+			setValues[pkgName][componentName] = make(map[string][]types.BundleChartValue)
+		}
+		value := types.BundleChartValue{Path: path, Value: b.cfg.DeployOpts.SetVariables[name]}
+		setValues[pkgName][componentName][chartName] = append(setValues[pkgName][componentName][chartName], value)
+	}
+	b.cfg.DeployOpts.SetValues = setValues
+	return nil
+}
+
+// setValueChart returns the chart of the component a Helm value set with --set is for along with the value's path in
+// the chart. A path starting with the name of one of the component's charts is for that chart
+func setValueChart(component zarfTypes.ZarfComponent, path string) (string, string, error) {
+	if chartName, chartPath, ok := strings.Cut(path, "."); ok {
+		if slices.ContainsFunc(component.Charts, func(c zarfTypes.ZarfChart) bool { return c.Name == chartName }) {
+			return chartName, chartPath, nil
+		}
+	}
+	switch len(component.Charts) {
+	case 0:
+		return "", "", fmt.Errorf("component %s doesn't have any Helm charts", component.Name)
+	case 1:
+		return component.Charts[0].Name, path, nil
+	}
+	chartNames := make([]string, len(component.Charts))
+	for i, chart := range component.Charts {
+		chartNames[i] = chart.Name
+	}
+	return "", "", fmt.Errorf("component %s has several Helm charts (%s), include the chart after the component", component.Name, strings.Join(chartNames, ", "))
+}
+
+// setKeys returns the keys set with --set, sorted so they're applied in the same order on every deploy
+func setKeys(set map[string]string) []string {
+	keys := make([]string, 0, len(set))
+	for name := range set {
+		keys = append(keys, name)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,115 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadSetValues(t *testing.T) {
+	zarfYAMLs := map[string]zarfTypes.ZarfPackage{
+		"podinfo": {Components: []zarfTypes.ZarfComponent{
+			{Name: "podinfo", Charts: []zarfTypes.ZarfChart{{Name: "podinfo"}}},
+			{Name: "monitoring", Charts: []zarfTypes.ZarfChart{{Name: "grafana"}, {Name: "prometheus"}}},
+			{Name: "images"},
+		}},
+		"nginx": {Components: []zarfTypes.ZarfComponent{
+			{Name: "nginx", Charts: []zarfTypes.ZarfChart{{Name: "nginx"}}},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		set      map[string]string
+		expected map[string]map[string]map[string][]types.BundleChartValue
+		err      string
+	}{
+		{
+			name: "the chart of a component with one chart can be left out",
+			set:  map[string]string{"podinfo.podinfo.replicaCount": "2", "podinfo.podinfo.ui.color": "blue"},
+			expected: map[string]map[string]map[string][]types.BundleChartValue{
+				"podinfo": {"podinfo": {"podinfo": {{Path: "replicaCount", Value: "2"}, {Path: "ui.color", Value: "blue"}}}},
+			},
+		},
+		{
+			name: "the chart can follow the component",
+			set:  map[string]string{"podinfo.monitoring.grafana.replicas": "3", "nginx.nginx.nginx.replicaCount": "1"},
+			expected: map[string]map[string]map[string][]types.BundleChartValue{
+				"podinfo": {"monitoring": {"grafana": {{Path: "replicas", Value: "3"}}}},
+				"nginx":   {"nginx": {"nginx": {{Path: "replicaCount", Value: "1"}}}},
+			},
+		},
+		{
+			name:     "variables are ignored",
+			set:      map[string]string{"DOMAIN": "uds.dev", "podinfo.UI_COLOR": "green"},
+			expected: map[string]map[string]map[string][]types.BundleChartValue{},
+		},
+		{
+			name: "unknown pkg",
+			set:  map[string]string{"podnfo.podinfo.replicaCount": "2"},
+			err:  "invalid --set podnfo.podinfo.replicaCount, the bundle doesn't have a zarf pkg named podnfo",
+		},
+		{
+			name: "unknown component",
+			set:  map[string]string{"podinfo.podnfo.replicaCount": "2"},
+			err:  "invalid --set podinfo.podnfo.replicaCount, zarf pkg podinfo doesn't have a component named podnfo",
+		},
+		{
+			name: "component without charts",
+			set:  map[string]string{"podinfo.images.replicaCount": "2"},
+			err:  "invalid --set podinfo.images.replicaCount, component images doesn't have any Helm charts",
+		},
+		{
+			name: "component with several charts",
+			set:  map[string]string{"podinfo.monitoring.replicas": "2"},
+			err:  "invalid --set podinfo.monitoring.replicas, component monitoring has several Helm charts (grafana, prometheus), include the chart after the component",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Bundle{
+				bundle: types.UDSBundle{Packages: []types.Package{{Name: "podinfo"}, {Name: "nginx"}}},
+				cfg:    &types.BundleConfig{DeployOpts: types.BundleDeployOptions{SetVariables: tt.set}},
+			}
+			err := b.loadSetValues(zarfYAMLs)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, b.cfg.DeployOpts.SetValues)
+		})
+	}
+}
+
+func Test_setValuesOverrideBundleValues(t *testing.T) {
+	b := Bundle{
+		bundle: types.UDSBundle{Packages: []types.Package{{Name: "podinfo"}}},
+		cfg: &types.BundleConfig{DeployOpts: types.BundleDeployOptions{
+			SetVariables: map[string]string{"podinfo.podinfo.replicaCount": "3", "podinfo.podinfo.ui.color": "blue", "UI_MESSAGE": "hi"},
+		}},
+	}
+	zarfYAMLs := map[string]zarfTypes.ZarfPackage{
+		"podinfo": {Components: []zarfTypes.ZarfComponent{{Name: "podinfo", Charts: []zarfTypes.ZarfChart{{Name: "podinfo"}}}}},
+	}
+	require.NoError(t, b.loadSetValues(zarfYAMLs))
+
+	pkg := types.Package{
+		Name: "podinfo",
+		Overrides: map[string]map[string]types.BundleChartOverrides{
+			"podinfo": {"podinfo": {Values: []types.BundleChartValue{{Path: "replicaCount", Value: 2}, {Path: "ui.logo", Value: "logo.png"}}}},
+		},
+	}
+	pkgVars := b.loadVariables(pkg, nil)
+	// Helm values aren't variables
+	require.Equal(t, map[string]string{"UI_MESSAGE": "hi"}, b.setVariables(pkg.Name))
+
+	overrides, _, err := b.loadChartOverrides(pkg, pkgVars)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"replicaCount": int64(3),
+		"ui":           map[string]interface{}{"color": "blue", "logo": "logo.png"},
+	}, overrides["podinfo"]["podinfo"])
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+)
+
+const (
+	// signatureLocationLayer is a signature pushed as a layer of the root manifest
+	signatureLocationLayer = "layer"
+	// signatureLocationDetached is a signature pushed as a blob the root manifest references with an annotation
+	signatureLocationDetached = "detached"
+)
+
+// signatureInfo is the metadata of a published bundle's signature
+type signatureInfo struct {
+	Signed        bool   `json:"signed"`
+	Location      string `json:"location,omitempty"`
+	Digest        string `json:"digest,omitempty"`
+	Keyless       bool   `json:"keyless,omitempty"`
+	Algorithm     string `json:"algorithm,omitempty"`
+	Identity      string `json:"identity,omitempty"`
+	Issuer        string `json:"issuer,omitempty"`
+	RekorLogIndex string `json:"rekorLogIndex,omitempty"`
+	RekorLogID    string `json:"rekorLogID,omitempty"`
+}
+
+// showSignature prints the metadata of a published bundle's signature, only the root manifest and the signature are
+// fetched from the registry
+func (b *Bundle) showSignature(source string) error {
+	if !helpers.IsOCIURL(source) {
+		return fmt.Errorf("--show-signature only supports bundles in an OCI registry, %s is not an OCI reference", source)
+	}
+	ctx := context.TODO()
+	platform := utils.BundlePlatform(ctx, source)
+	remote, err := zoci.NewRemote(source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return err
+	}
+	info, err := fetchSignatureInfo(ctx, remote.OrasRemote)
+	if err != nil {
+		return fmt.Errorf("unable to read the signature of %s: %w", source, err)
+	}
+
+	if b.cfg.InspectOpts.JSON {
+		output, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(output) + "\n")
+		return nil
+	}
+	if !info.Signed {
+		message.Warnf("%s is unsigned, it doesn't have a %s", source, config.BundleYAMLSignature)
+		return nil
+	}
+	message.Title("Signature", "who signed the bundle and how")
+	zarfUtils.ColorPrintYAML(info, nil, false)
+	return nil
+}
+
+// fetchSignatureInfo finds the signature the root manifest references, either as a layer or detached, and describes it
+func fetchSignatureInfo(ctx context.Context, remote *oci.OrasRemote) (signatureInfo, error) {
+	root, err := remote.FetchRoot(ctx)
+	if err != nil {
+		return signatureInfo{}, err
+	}
+	location := signatureLocationLayer
+	signatureDesc := root.Locate(config.BundleYAMLSignature)
+	if oci.IsEmptyDescriptor(signatureDesc) {
+		sigDigest, ok := root.Annotations[config.BundleSignatureDigestAnnotation]
+		if !ok {
+			return signatureInfo{Signed: false}, nil
+		}
+		location = signatureLocationDetached
+		signatureDesc, err = remote.Repo().Blobs().Resolve(ctx, sigDigest)
+		if err != nil {
+			return signatureInfo{}, fmt.Errorf("unable to resolve the detached signature %s: %w", sigDigest, err)
+		}
+		// the signature's annotations are on the root manifest since it isn't a layer
+		signatureDesc.Annotations = root.Annotations
+	}
+	signature, err := remote.FetchLayer(ctx, signatureDesc)
+	if err != nil {
+		return signatureInfo{}, err
+	}
+	info, err := describeSignature(signature, signatureDesc.Annotations)
+	if err != nil {
+		return signatureInfo{}, err
+	}
+	info.Location = location
+	info.Digest = signatureDesc.Digest.String()
+	return info, nil
+}
+
+// describeSignature decodes a signature's annotations, a keyless signature's signer is read from its Fulcio
+// certificate while a signature made with a key doesn't record who signed it so only its algorithm is reported
+func describeSignature(signature []byte, annotations map[string]string) (signatureInfo, error) {
+	info := signatureInfo{Signed: true}
+	cert, keyless := annotations[config.BundleSignatureCertificateAnnotation]
+	if !keyless {
+		info.Algorithm = signatureAlgorithm(signature)
+		return info, nil
+	}
+	signer, err := utils.ParseCertificateSigner([]byte(cert))
+	if err != nil {
+		return signatureInfo{}, fmt.Errorf("unable to parse the signature's certificate: %w", err)
+	}
+	info.Keyless = true
+	info.Algorithm = signer.Algorithm
+	info.Identity = signer.Identity
+	info.Issuer = signer.Issuer
+	info.RekorLogIndex = annotations[config.BundleSignatureRekorLogIndexAnnotation]
+	info.RekorLogID = annotations[config.BundleSignatureRekorLogIDAnnotation]
+	return info, nil
+}
+
+// signatureAlgorithm infers the algorithm of a base64 encoded cosign signature from its encoding, ECDSA signatures are
+// ASN.1 encoded and Ed25519 signatures are always 64 bytes
+func signatureAlgorithm(signature []byte) string {
+	raw, err := base64.StdEncoding.DecodeString(string(signature))
+	if err != nil {
+		return "unknown"
+	}
+	var ecdsaSignature struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(raw, &ecdsaSignature); err == nil && len(rest) == 0 {
+		return "ECDSA"
+	}
+	if len(raw) == 64 {
+		return "Ed25519"
+	}
+	return "unknown"
+}
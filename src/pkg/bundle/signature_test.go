@@ -0,0 +1,101 @@
+package bundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_describeSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte("uds-bundle.yaml"))
+	ecdsaSignature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	// a Fulcio-like certificate with the signer's email and OIDC issuer
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(1),
+		Subject:        pkix.Name{},
+		NotBefore:      time.Now(),
+		NotAfter:       time.Now().Add(10 * time.Minute),
+		EmailAddresses: []string{"signer@example.com"},
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}, Value: []byte("https://accounts.example.com")},
+		},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	testCases := []struct {
+		name        string
+		signature   []byte
+		annotations map[string]string
+		expected    signatureInfo
+		expectedErr string
+	}{
+		{
+			name:      "ECDSA key",
+			signature: []byte(base64.StdEncoding.EncodeToString(ecdsaSignature)),
+			expected:  signatureInfo{Signed: true, Algorithm: "ECDSA"},
+		},
+		{
+			name:      "Ed25519 key",
+			signature: []byte(base64.StdEncoding.EncodeToString(make([]byte, 64))),
+			expected:  signatureInfo{Signed: true, Algorithm: "Ed25519"},
+		},
+		{
+			name:      "unknown algorithm",
+			signature: []byte("not base64!"),
+			expected:  signatureInfo{Signed: true, Algorithm: "unknown"},
+		},
+		{
+			name:      "keyless",
+			signature: []byte(base64.StdEncoding.EncodeToString(ecdsaSignature)),
+			annotations: map[string]string{
+				config.BundleSignatureCertificateAnnotation:   string(certPEM),
+				config.BundleSignatureRekorLogIndexAnnotation: "42",
+				config.BundleSignatureRekorLogIDAnnotation:    "c0d23d6ad406973f",
+			},
+			expected: signatureInfo{
+				Signed:        true,
+				Keyless:       true,
+				Algorithm:     "ECDSA",
+				Identity:      "signer@example.com",
+				Issuer:        "https://accounts.example.com",
+				RekorLogIndex: "42",
+				RekorLogID:    "c0d23d6ad406973f",
+			},
+		},
+		{
+			name:        "invalid certificate",
+			signature:   []byte(base64.StdEncoding.EncodeToString(ecdsaSignature)),
+			annotations: map[string]string{config.BundleSignatureCertificateAnnotation: "not a certificate"},
+			expectedErr: "unable to parse the signature's certificate",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := describeSignature(tc.signature, tc.annotations)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, info)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// sinceLayers returns the digests of every layer in the bundle given to --since: its root manifest's config and
+// layers, which include the manifests of its Zarf pkgs, and the layers of each of its Zarf pkgs
+func (b *Bundle) sinceLayers(ctx context.Context) (map[string]bool, error) {
+	source, err := CheckOCISourcePath(b.cfg.PullOpts.Since)
+	if err != nil {
+		return nil, err
+	}
+	if !helpers.IsOCIURL(source) {
+		return nil, fmt.Errorf("invalid --since %s, it must be a bundle in an OCI registry", b.cfg.PullOpts.Since)
+	}
+	dst := filepath.Join(b.tmp, "since")
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return nil, err
+	}
+	provider, err := NewBundleProvider(source, dst)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the bundle %s: %w", source, err)
+	}
+	op := provider.(*ociProvider)
+
+	digests := map[string]bool{op.rootManifest.Config.Digest.String(): true}
+	for _, layer := range op.rootManifest.Layers {
+		digests[layer.Digest.String()] = true
+		if isBundleMetadataLayer(layer) {
+			continue
+		}
+		manifest, err := op.FetchManifest(ctx, layer)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the package manifest %s of %s: %w", layer.Digest, source, err)
+		}
+		digests[manifest.Config.Digest.String()] = true
+		for _, pkgLayer := range manifest.Layers {
+			digests[pkgLayer.Digest.String()] = true
+		}
+	}
+	return digests, nil
+}
+
+// excludeSinceLayers splits a Zarf pkg's layers into the layers to pull and the layers skipped because they're
+// already in the bundle given to --since, every layer is pulled if since is nil
+func excludeSinceLayers(layers []ocispec.Descriptor, since map[string]bool) (pull []ocispec.Descriptor, skipped []ocispec.Descriptor) {
+	for _, layer := range layers {
+		if since[layer.Digest.String()] {
+			skipped = append(skipped, layer)
+			continue
+		}
+		pull = append(pull, layer)
+	}
+	return pull, skipped
+}
+
+// printSkippedLayers lists the layers that were left out of a pull with --since since they're unchanged
+func printSkippedLayers(since string, skipped map[string][]ocispec.Descriptor, pkgNames []string) {
+	var rows [][]string
+	var skippedBytes int64
+	for _, name := range pkgNames {
+		for _, layer := range skipped[name] {
+			title := layer.Annotations[ocispec.AnnotationTitle]
+			if title == "" {
+				title = "-"
+			}
+			rows = append(rows, []string{name, title, layer.Digest.String(), zarfUtils.ByteFormat(float64(layer.Size), 2)})
+			skippedBytes += layer.Size
+		}
+	}
+	if len(rows) == 0 {
+		message.Infof("No layers are unchanged since %s, every layer will be pulled", since)
+		return
+	}
+	message.Infof("Skipping %d layers (%s) that are unchanged since %s", len(rows), zarfUtils.ByteFormat(float64(skippedBytes), 2), since)
+	message.Table([]string{"Package", "Layer", "Digest", "Size"}, rows)
+}
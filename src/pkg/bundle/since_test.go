@@ -0,0 +1,26 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_excludeSinceLayers(t *testing.T) {
+	unchanged := ocispec.Descriptor{Digest: digest.FromString("unchanged"), Size: 9}
+	changed := ocispec.Descriptor{Digest: digest.FromString("changed"), Size: 7}
+	added := ocispec.Descriptor{Digest: digest.FromString("added"), Size: 5}
+	layers := []ocispec.Descriptor{unchanged, changed, added}
+
+	// without --since every layer is pulled
+	pull, skipped := excludeSinceLayers(layers, nil)
+	require.Equal(t, layers, pull)
+	require.Empty(t, skipped)
+
+	since := map[string]bool{unchanged.Digest.String(): true, digest.FromString("removed").String(): true}
+	pull, skipped = excludeSinceLayers(layers, since)
+	require.Equal(t, []ocispec.Descriptor{changed, added}, pull)
+	require.Equal(t, []ocispec.Descriptor{unchanged}, skipped)
+}
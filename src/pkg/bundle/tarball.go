@@ -51,11 +51,24 @@ func (tp *tarballBundleProvider) CreateBundleSBOM(extractSBOM bool) error {
 	containsSBOMs := false
 
 	for _, layer := range rootManifest.Layers {
+		layerFilePath := filepath.Join(config.BlobsDir, layer.Digest.Encoded())
+		if layer.Annotations[ocispec.AnnotationTitle] == config.BundleSBOMJSON {
+			// the bundle-level SBOM is added to the extracted SBOMs as is
+			if err := av3.Extract(tp.src, layerFilePath, tp.dst); err != nil {
+				return fmt.Errorf("failed to extract %s from %s: %w", config.BundleSBOMJSON, tp.src, err)
+			}
+			sbomPath := filepath.Join(tp.dst, config.BundleSBOM, config.BundleSBOMJSON)
+			if err := os.Rename(filepath.Join(tp.dst, layerFilePath), sbomPath); err != nil {
+				return err
+			}
+			SBOMArtifactPathMap[sbomPath] = config.BundleSBOMJSON
+			containsSBOMs = true
+			continue
+		}
 		// get Zarf image manifests from bundle manifest
-		if layer.Annotations[ocispec.AnnotationTitle] == config.BundleYAML {
+		if isBundleMetadataLayer(layer) {
 			continue
 		}
-		layerFilePath := filepath.Join(config.BlobsDir, layer.Digest.Encoded())
 		if err := av3.Extract(tp.src, layerFilePath, tp.dst); err != nil {
 			return fmt.Errorf("failed to extract %s from %s: %w", layer.Digest.Encoded(), tp.src, err)
 		}
@@ -196,7 +209,7 @@ func (tp *tarballBundleProvider) LoadBundleMetadata() (types.PathMap, error) {
 	if err != nil {
 		return nil, err
 	}
-	pathsToExtract := config.BundleAlwaysPull
+	pathsToExtract := metadataPaths(bundleRootManifest)
 
 	loaded := make(types.PathMap)
 
@@ -206,6 +219,15 @@ func (tp *tarballBundleProvider) LoadBundleMetadata() (types.PathMap, error) {
 			pathInTarball := filepath.Join(config.BlobsDir, layer.Digest.Encoded())
 			abs := filepath.Join(tp.dst, pathInTarball)
 			loaded[path] = abs
+			if path == config.BundleYAMLSignature {
+				certPath, err := writeSignatureCertificate(layer, tp.dst)
+				if err != nil {
+					return nil, err
+				}
+				if certPath != "" {
+					loaded[config.BundleYAMLCertificate] = certPath
+				}
+			}
 			if !helpers.InvalidPath(abs) && helpers.SHAsMatch(abs, layer.Digest.Encoded()) == nil {
 				continue
 			}
@@ -263,8 +285,8 @@ func (tp *tarballBundleProvider) PublishBundle(bundle types.UDSBundle, remote *o
 	// push bundle layers to remote
 	for _, manifestDesc := range bundleRootManifest.Layers {
 		layersToPush = append(layersToPush, manifestDesc)
-		if manifestDesc.Annotations[ocispec.AnnotationTitle] == config.BundleYAML {
-			continue // uds-bundle.yaml doesn't have layers
+		if isBundleMetadataLayer(manifestDesc) {
+			continue // uds-bundle.yaml, its signature and the bundle SBOM don't have layers
 		}
 		layers, estimatedPkgSize, err := tp.getZarfLayers(store, manifestDesc)
 		estimatedBytes += estimatedPkgSize
@@ -290,7 +312,7 @@ func (tp *tarballBundleProvider) PublishBundle(bundle types.UDSBundle, remote *o
 	ref := bundle.Metadata.Version
 
 	// check for existing index
-	index, err := utils.GetIndex(remote, ref)
+	index, err := utils.GetIndex(tp.ctx, remote, ref)
 	if err != nil {
 		return err
 	}
@@ -318,7 +340,7 @@ func (tp *tarballBundleProvider) PublishBundle(bundle types.UDSBundle, remote *o
 	}
 
 	// create or update, then push index.json
-	err = utils.UpdateIndex(index, remote, &bundle, tp.bundleRootDesc)
+	err = utils.UpdateIndex(tp.ctx, index, remote, &bundle, tp.bundleRootDesc)
 	if err != nil {
 		return err
 	}
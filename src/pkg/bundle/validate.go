@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"oras.land/oras-go/v2/registry"
+)
+
+// versionPattern matches a valid OCI tag, the bundle's version is used as the tag of the bundle's reference
+var versionPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+// variablePattern matches a valid variable name, variables are uppercased when they're loaded like Zarf's variables
+var variablePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// metadataValidator collects every validation error in a bundle, annotated with the line of the offending field
+type metadataValidator struct {
+	file  *ast.File
+	errs  []error
+	warns []error
+}
+
+// newMetadataValidator parses src for line context, src is the raw YAML the bundle was read from and can be nil
+func newMetadataValidator(src []byte) *metadataValidator {
+	v := metadataValidator{}
+	if len(src) > 0 {
+		// the bundle was already unmarshalled successfully, so a parse error only means there's no line context
+		v.file, _ = parser.ParseBytes(src, 0)
+	}
+	return &v
+}
+
+// ValidateBundleMetadata validates the bundle's metadata and package entries without making any network calls,
+// returning all of the validation errors at once. src is the raw YAML the bundle was read from and is used to
+// point each error at a line, it can be nil
+func ValidateBundleMetadata(bundle *types.UDSBundle, src []byte) error {
+	v := newMetadataValidator(src)
+	v.validate(bundle)
+	return errors.Join(v.errs...)
+}
+
+// validate records an error for every invalid field of the bundle's metadata and package entries
+func (v *metadataValidator) validate(bundle *types.UDSBundle) {
+
+	if bundle.Metadata.Name == "" {
+		v.addf("metadata.name", "is required")
+	}
+	if bundle.Metadata.Version == "" {
+		v.addf("metadata.version", "is required")
+	} else if !versionPattern.MatchString(bundle.Metadata.Version) {
+		v.addf("metadata.version", "%q is not a valid version, it must be a valid OCI tag", bundle.Metadata.Version)
+	}
+	if bundle.Metadata.Architecture == "" {
+		v.addf("metadata.architecture", "is required")
+	}
+
+	if len(bundle.Packages) == 0 {
+		v.addf("packages", "must contain at least one package")
+	}
+	for i, pkg := range bundle.Packages {
+		field := fmt.Sprintf("packages[%d]", i)
+		if pkg.Name == "" {
+			v.addf(field+".name", "is required")
+		}
+		if pkg.Ref == "" {
+			v.addf(field+".ref", "is required")
+		}
+		for j, pattern := range pkg.ExcludeImages {
+			if _, err := path.Match(pattern, ""); err != nil {
+				v.addf(fmt.Sprintf("%s.excludeImages[%d]", field, j), "%q is not a valid pattern: %s", pattern, err)
+			}
+		}
+		switch {
+		case pkg.Repository == "" && pkg.Path == "":
+			v.addf(field, "must have either a repository or a path")
+		case pkg.Repository != "" && pkg.Path != "":
+			v.addf(field, "cannot have both a repository and a path")
+		case pkg.Repository != "" && pkg.Ref != "":
+			url := fmt.Sprintf("%s:%s", strings.TrimPrefix(pkg.Repository, helpers.OCIURLPrefix), pkg.Ref)
+			if _, err := registry.ParseReference(url); err != nil {
+				v.addf(field+".ref", "%s is not a valid reference: %s", url, err)
+			}
+		}
+	}
+
+	reserved := []string{config.BundleYAML, config.BundleYAMLSignature, config.BundleYAMLCertificate, config.BundleSBOMJSON}
+	seen := make(map[string]int, len(bundle.ExtraFiles))
+	for i, file := range bundle.ExtraFiles {
+		field := fmt.Sprintf("extraFiles[%d]", i)
+		switch {
+		case file == "":
+			v.addf(field, "is required")
+		case filepath.IsAbs(file) || file != filepath.ToSlash(filepath.Clean(file)) || file == ".." || strings.HasPrefix(file, "../"):
+			// the path is the layer's title, which is the file's name when the bundle's metadata is pulled
+			v.addf(field, "%q must be a clean relative path inside the bundle's directory, e.g. docs/README.md", file)
+		case slices.Contains(reserved, file):
+			v.addf(field, "%q is reserved for the bundle's own files", file)
+		default:
+			if first, ok := seen[file]; ok {
+				v.addf(field, "%q is already listed in extraFiles[%d]", file, first)
+			}
+			seen[file] = i
+		}
+	}
+
+	names := make([]string, 0, len(bundle.Variables))
+	for name := range bundle.Variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	declared := make(map[string]string, len(names))
+	for _, name := range names {
+		field := "variables." + name
+		if !variablePattern.MatchString(name) {
+			v.addf(field, "is not a valid variable name, it must only contain letters, numbers and underscores")
+		}
+		if first, ok := declared[strings.ToUpper(name)]; ok {
+			v.addf(field, "is already declared as %s, variable names are case-insensitive", first)
+		}
+		declared[strings.ToUpper(name)] = name
+		switch bundle.Variables[name].(type) {
+		case string, bool, int, int64, uint64, float64:
+		default:
+			v.addf(field, "must be a string, boolean or number")
+		}
+	}
+}
+
+// addf records a validation error for a field, e.g. packages[0].ref
+func (v *metadataValidator) addf(field string, format string, a ...any) {
+	v.errs = append(v.errs, v.errorf(field, format, a...))
+}
+
+// warnf records a warning for a field that's valid but likely a mistake
+func (v *metadataValidator) warnf(field string, format string, a ...any) {
+	v.warns = append(v.warns, v.errorf(field, format, a...))
+}
+
+// errorf formats a message about a field, prefixed with the field's line if it's known
+func (v *metadataValidator) errorf(field string, format string, a ...any) error {
+	msg := fmt.Sprintf(format, a...)
+	if line := v.line(field); line > 0 {
+		return fmt.Errorf("%s:%d: %s %s", config.BundleYAML, line, field, msg)
+	}
+	return fmt.Errorf("%s: %s %s", config.BundleYAML, field, msg)
+}
+
+// unknownFields returns an error for every key in the bundle's YAML that isn't a field of the bundle, these keys are
+// ignored when the bundle is read so they're usually typos, e.g. overides instead of overrides
+func (v *metadataValidator) unknownFields() []error {
+	if v.file == nil || len(v.file.Docs) == 0 {
+		return nil
+	}
+	var errs []error
+	walkUnknownFields(v.file.Docs[0].Body, reflect.TypeOf(types.UDSBundle{}), "", &errs)
+	return errs
+}
+
+// walkUnknownFields compares the keys of a YAML node with the JSON tags of the type it's read into
+func walkUnknownFields(node ast.Node, t reflect.Type, field string, errs *[]error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for _, kv := range mappingValues(node) {
+			key := kv.Key.GetToken()
+			child := strings.TrimPrefix(field+"."+key.Value, ".")
+			structField, ok := fieldByJSONName(t, key.Value)
+			if !ok {
+				*errs = append(*errs, fmt.Errorf("%s:%d: %s is not a known field", config.BundleYAML, key.Position.Line, child))
+				continue
+			}
+			walkUnknownFields(kv.Value, structField.Type, child, errs)
+		}
+	case reflect.Map:
+		for _, kv := range mappingValues(node) {
+			walkUnknownFields(kv.Value, t.Elem(), strings.TrimPrefix(field+"."+kv.Key.GetToken().Value, "."), errs)
+		}
+	case reflect.Slice:
+		if seq, ok := node.(*ast.SequenceNode); ok {
+			for i, item := range seq.Values {
+				walkUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", field, i), errs)
+			}
+		}
+	}
+}
+
+// mappingValues returns the key-value pairs of a mapping node, a mapping with a single key is parsed as just the pair
+func mappingValues(node ast.Node) []*ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	case *ast.AnchorNode:
+		return mappingValues(n.Value)
+	}
+	return nil
+}
+
+// fieldByJSONName returns the struct field whose JSON tag has the given name, which is how the bundle's YAML is read
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// line returns the line of a field in the bundle's YAML, falling back to the closest parent for missing fields
+func (v *metadataValidator) line(field string) int {
+	if v.file == nil {
+		return 0
+	}
+	for field != "" {
+		if path, err := goyaml.PathString("$." + field); err == nil {
+			if node, err := path.FilterFile(v.file); err == nil && node != nil {
+				return node.GetToken().Position.Line
+			}
+		}
+		idx := strings.LastIndexAny(field, ".[")
+		if idx < 0 {
+			break
+		}
+		field = field[:idx]
+	}
+	return 0
+}
@@ -0,0 +1,152 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateBundleMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		wantErrs []string
+	}{
+		{
+			name: "Valid",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: 0.0.1
+  architecture: amd64
+packages:
+  - name: podinfo
+    repository: ghcr.io/defenseunicorns/uds-cli/podinfo
+    ref: 0.0.1
+  - name: local
+    path: ../packages
+    ref: 0.0.1
+`,
+		},
+		{
+			name: "AllErrorsAtOnce",
+			src: `kind: UDSBundle
+metadata:
+  version: not a version
+  architecture: amd64
+packages:
+  - name: podinfo
+    repository: ghcr.io/defenseunicorns/uds-cli/podinfo
+    ref: "0.0.1!"
+  - repository: ghcr.io/defenseunicorns/uds-cli/nginx
+    path: ../packages
+    ref: 0.0.1
+  - name: missing-ref
+    repository: ghcr.io/defenseunicorns/uds-cli/nginx
+`,
+			wantErrs: []string{
+				"uds-bundle.yaml:3: metadata.name is required",
+				`uds-bundle.yaml:3: metadata.version "not a version" is not a valid version, it must be a valid OCI tag`,
+				"uds-bundle.yaml:8: packages[0].ref ghcr.io/defenseunicorns/uds-cli/podinfo:0.0.1! is not a valid reference",
+				"uds-bundle.yaml:9: packages[1].name is required",
+				"uds-bundle.yaml:9: packages[1] cannot have both a repository and a path",
+				"uds-bundle.yaml:12: packages[2].ref is required",
+			},
+		},
+		{
+			name: "InvalidExcludeImagesPattern",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: 0.0.1
+  architecture: amd64
+packages:
+  - name: podinfo
+    repository: ghcr.io/defenseunicorns/uds-cli/podinfo
+    ref: 0.0.1
+    excludeImages:
+      - ghcr.io/stefanprodan/podinfo:*
+      - ghcr.io/[test
+`,
+			wantErrs: []string{`uds-bundle.yaml:12: packages[0].excludeImages[1] "ghcr.io/[test" is not a valid pattern: syntax error in pattern`},
+		},
+		{
+			name: "NoPackages",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: 0.0.1
+  architecture: amd64
+`,
+			wantErrs: []string{"uds-bundle.yaml: packages must contain at least one package"},
+		},
+		{
+			name: "InvalidExtraFiles",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: 0.0.1
+  architecture: amd64
+extraFiles:
+  - LICENSE
+  - ../README.md
+  - /etc/passwd
+  - uds-bundle.yaml
+  - LICENSE
+packages:
+  - name: podinfo
+    repository: ghcr.io/defenseunicorns/uds-cli/podinfo
+    ref: 0.0.1
+`,
+			wantErrs: []string{
+				`uds-bundle.yaml:8: extraFiles[1] "../README.md" must be a clean relative path inside the bundle's directory`,
+				`uds-bundle.yaml:9: extraFiles[2] "/etc/passwd" must be a clean relative path inside the bundle's directory`,
+				`uds-bundle.yaml:10: extraFiles[3] "uds-bundle.yaml" is reserved for the bundle's own files`,
+				`uds-bundle.yaml:11: extraFiles[4] "LICENSE" is already listed in extraFiles[0]`,
+			},
+		},
+		{
+			name: "InvalidVariables",
+			src: `kind: UDSBundle
+metadata:
+  name: example
+  version: 0.0.1
+  architecture: amd64
+variables:
+  DOMAIN: uds.dev
+  REPLICAS: 2
+  DEBUG: false
+  domain: other.dev
+  BAD-NAME: foo
+  LIST: [a, b]
+packages:
+  - name: podinfo
+    repository: ghcr.io/defenseunicorns/uds-cli/podinfo
+    ref: 0.0.1
+`,
+			wantErrs: []string{
+				"uds-bundle.yaml:11: variables.BAD-NAME is not a valid variable name",
+				"uds-bundle.yaml:12: variables.LIST must be a string, boolean or number",
+				"uds-bundle.yaml:10: variables.domain is already declared as DOMAIN, variable names are case-insensitive",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bundle types.UDSBundle
+			require.NoError(t, goyaml.Unmarshal([]byte(tt.src), &bundle))
+			err := ValidateBundleMetadata(&bundle, []byte(tt.src))
+			if len(tt.wantErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, wantErr := range tt.wantErrs {
+				require.Contains(t, err.Error(), wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+)
+
+// bundleVariables returns the variable defaults declared in the bundle's variables, keyed by their uppercase name like
+// the variables read from uds-config.yaml
+func (b *Bundle) bundleVariables() map[string]interface{} {
+	vars := make(map[string]interface{}, len(b.bundle.Variables))
+	for name, val := range b.bundle.Variables {
+		vars[strings.ToUpper(name)] = val
+	}
+	return vars
+}
+
+// envVariables returns the variables set in the environment, a UDS_VAR_ variable takes precedence over a UDS_ one
+// of the same name
+func envVariables() map[string]string {
+	vars := make(map[string]string)
+	var bundleVars []string
+	for _, envVar := range os.Environ() {
+		name, val, _ := strings.Cut(envVar, "=")
+		if strings.HasPrefix(name, config.EnvVarBundleVarPrefix) {
+			bundleVars = append(bundleVars, envVar)
+		}
+		if strings.HasPrefix(name, config.EnvVarPrefix) {
+			vars[strings.ToUpper(strings.TrimPrefix(name, config.EnvVarPrefix))] = val
+		}
+	}
+	for _, envVar := range bundleVars {
+		name, val, _ := strings.Cut(envVar, "=")
+		vars[strings.ToUpper(strings.TrimPrefix(name, config.EnvVarBundleVarPrefix))] = val
+	}
+	return vars
+}
+
+// setVariables returns the variables set with --set for a Zarf pkg, keyed by their uppercase name. A variable scoped
+// to the pkg (ex. packageName.variableName) takes precedence over an unscoped one of the same name
+func (b *Bundle) setVariables(pkgName string) map[string]string {
+	vars := make(map[string]string)
+	scoped := make(map[string]string)
+	for name, val := range b.cfg.DeployOpts.SetVariables {
+		if isSetValue(name) {
+			// a Helm value of one of the pkg's components, see loadSetValues
+			continue
+		}
+		if packageName, variableName, ok := strings.Cut(name, "."); ok {
+			if packageName == pkgName {
+				scoped[strings.ToUpper(variableName)] = val
+			}
+		} else {
+			vars[strings.ToUpper(name)] = val
+		}
+	}
+	for name, val := range scoped {
+		vars[name] = val
+	}
+	return vars
+}
+
+// variableString formats a variable's value the way it's written in YAML, e.g. a whole number read as a float is
+// formatted without a decimal point or exponent
+func variableString(val interface{}) string {
+	switch v := val.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// coerceVariable converts a variable set as a string, with --set or an env var, to the type of the bundle's default
+// for it so a boolean or number default stays a boolean or number when it's overridden
+func coerceVariable(name string, val interface{}, like interface{}) (interface{}, error) {
+	s, ok := val.(string)
+	if !ok {
+		return val, nil
+	}
+	var err error
+	switch like.(type) {
+	case bool:
+		if val, err = strconv.ParseBool(s); err == nil {
+			return val, nil
+		}
+		return nil, fmt.Errorf("invalid value %q for variable %s, its default is a boolean", s, name)
+	case int, int64, uint64:
+		if val, err = strconv.ParseInt(s, 10, 64); err == nil {
+			return val, nil
+		}
+		return nil, fmt.Errorf("invalid value %q for variable %s, its default is a whole number", s, name)
+	case float64:
+		if val, err = strconv.ParseFloat(s, 64); err == nil {
+			return val, nil
+		}
+		return nil, fmt.Errorf("invalid value %q for variable %s, its default is a number", s, name)
+	}
+	return s, nil
+}
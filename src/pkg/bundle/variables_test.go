@@ -0,0 +1,64 @@
+package bundle
+
+import (
+	"os"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/cli/values"
+)
+
+func Test_bundleVariablePrecedence(t *testing.T) {
+	os.Unsetenv("UDS_FOO")
+	t.Setenv("UDS_DOMAIN", "set using UDS_ env var")
+	t.Setenv("UDS_VAR_DOMAIN", "set using UDS_VAR_ env var")
+	t.Setenv("UDS_VAR_replicas", "4")
+	b := Bundle{
+		bundle: types.UDSBundle{
+			Variables: map[string]interface{}{
+				"domain":   "uds.dev",
+				"REPLICAS": uint64(2),
+				"DEBUG":    false,
+				"RATIO":    0.5,
+				"LIMIT":    float64(1000000),
+				"FOO":      "bundle default",
+			},
+		},
+		cfg: &types.BundleConfig{DeployOpts: types.BundleDeployOptions{
+			SetVariables: map[string]string{"fooPkg.debug": "true", "DEBUG": "false", "ratio": "0.75"},
+		}},
+	}
+
+	pkgVars := b.loadVariables(types.Package{Name: "fooPkg"}, nil)
+	require.Equal(t, "set using UDS_VAR_ env var", pkgVars["DOMAIN"])
+	require.Equal(t, "4", pkgVars["REPLICAS"])
+	// a --set scoped to the pkg takes precedence over an unscoped one
+	require.Equal(t, "true", pkgVars["DEBUG"])
+	require.Equal(t, "0.75", pkgVars["RATIO"])
+	require.Equal(t, "1000000", pkgVars["LIMIT"])
+	require.Equal(t, "bundle default", pkgVars["FOO"])
+
+	// a pkg's config vars take precedence over the bundle's defaults
+	b.cfg.DeployOpts.Variables = map[string]map[string]interface{}{"fooPkg": {"FOO": "set from variables key in uds-config.yaml"}}
+	pkgVars = b.loadVariables(types.Package{Name: "fooPkg"}, nil)
+	require.Equal(t, "set from variables key in uds-config.yaml", pkgVars["FOO"])
+
+	// chart variables are coerced to the type of the bundle's default
+	overrideMap := map[string]map[string]*values.Options{}
+	variables := []types.BundleChartVariable{
+		{Name: "replicas", Path: "replicaCount"},
+		{Name: "debug", Path: "debug"},
+		{Name: "ratio", Path: "ratio"},
+		{Name: "domain", Path: "domain"},
+		{Name: "limit", Path: "limit"},
+	}
+	require.NoError(t, b.processOverrideVariables(&overrideMap, "fooPkg", &variables, "component", "chart"))
+	require.Equal(t, []string{"replicaCount=4", "debug=true", "ratio=0.75", "domain=set using UDS_VAR_ env var", "limit=1000000"}, overrideMap["component"]["chart"].Values)
+
+	// an override that doesn't match the type of the bundle's default is an error
+	t.Setenv("UDS_VAR_REPLICAS", "many")
+	overrideMap = map[string]map[string]*values.Options{}
+	err := b.processOverrideVariables(&overrideMap, "fooPkg", &variables, "component", "chart")
+	require.EqualError(t, err, `invalid value "many" for variable REPLICAS, its default is a whole number`)
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// The exit codes of uds verify for each class of failure, scripts branch on them so they must not change. When checks
+// of more than one class fail the highest code is returned, the classes are ordered from the most fundamental failure
+const (
+	// VerifyExitMissingSignature is returned if a key was provided to verify the bundle with but it isn't signed
+	VerifyExitMissingSignature = 2
+	// VerifyExitSignatureMismatch is returned if the bundle's signature doesn't verify against its YAML
+	VerifyExitSignatureMismatch = 3
+	// VerifyExitCorruptLayer is returned if a layer is missing from the registry or doesn't match its digest and size
+	VerifyExitCorruptLayer = 4
+	// VerifyExitUnreachable is returned if the registry couldn't be reached
+	VerifyExitUnreachable = 5
+)
+
+// VerifyError is returned by Verify when the bundle fails verification, ExitCode is the class of the failure
+type VerifyError struct {
+	ExitCode int
+	Err      error
+}
+
+func (e *VerifyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
+
+// blobResolver resolves a blob in a registry by its digest, registry.BlobStore satisfies it
+type blobResolver interface {
+	Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error)
+}
+
+// layerCheck is the result of verifying a single blob of a published bundle
+type layerCheck struct {
+	// pkg is the Zarf pkg the blob belongs to, empty for the bundle's own layers
+	pkg   string
+	title string
+	desc  ocispec.Descriptor
+	err   error
+	// exitCode is the class of the check's failure, see VerifyExitMissingSignature etc.
+	exitCode int
+}
+
+// fail records the check's failure, a layer that couldn't be checked because the registry was unreachable isn't
+// reported as missing
+func (c *layerCheck) fail(err error, exitCode int) {
+	c.err = err
+	c.exitCode = exitCode
+	if err != nil && exitCode == VerifyExitCorruptLayer && isUnreachable(err) {
+		c.exitCode = VerifyExitUnreachable
+	}
+}
+
+// Verify checks that every blob referenced by a published bundle exists in the registry with the expected digest and
+// size, and validates the bundle's signature
+func (b *Bundle) Verify() error {
+	ctx := context.TODO()
+	source, err := CheckOCISourcePath(b.cfg.VerifyOpts.Source)
+	if err != nil {
+		return err
+	}
+	if !helpers.IsOCIURL(source) {
+		return fmt.Errorf("verify only supports bundles in an OCI registry, %s is not an OCI reference", source)
+	}
+	provider, err := NewBundleProvider(source, b.tmp)
+	if isUnreachable(err) {
+		return &VerifyError{ExitCode: VerifyExitUnreachable, Err: err}
+	}
+	if err != nil {
+		return err
+	}
+	op := provider.(*ociProvider)
+
+	spinner := message.NewProgressSpinner("Verifying the layers of %s", source)
+	defer spinner.Stop()
+	checks := op.verifyLayers(ctx, spinner)
+	spinner.Stop()
+
+	// a detached signature isn't a layer of the root manifest, it's verified as a blob the same way
+	signatureDesc := op.rootManifest.Locate(config.BundleYAMLSignature)
+	if detachedDesc, ok, err := op.detachedSignatureDesc(ctx); ok {
+		signatureDesc = detachedDesc
+		detachedCheck := layerCheck{title: "detached " + config.BundleYAMLSignature, desc: detachedDesc}
+		detachedCheck.fail(err, VerifyExitCorruptLayer)
+		checks = append(checks, detachedCheck)
+	}
+
+	// an unsigned bundle only fails verification if a key was provided to verify it with
+	if !oci.IsEmptyDescriptor(signatureDesc) || b.cfg.VerifyOpts.PublicKeyPath != "" {
+		checks = append(checks, op.verifySignature(checks, signatureDesc, b.cfg.VerifyOpts.PublicKeyPath))
+	} else {
+		message.Warnf("%s isn't signed, only its layers were verified", source)
+	}
+
+	var rows [][]string
+	var failed, exitCode int
+	for _, check := range checks {
+		result := "pass"
+		if check.err != nil {
+			result = "fail: " + check.err.Error()
+			failed++
+			exitCode = max(exitCode, check.exitCode)
+		}
+		rows = append(rows, []string{check.pkg, check.title, zarfUtils.ByteFormat(float64(check.desc.Size), 2), result})
+	}
+	message.Table([]string{"Package", "Layer", "Size", "Result"}, rows)
+
+	if failed > 0 {
+		return &VerifyError{ExitCode: exitCode, Err: fmt.Errorf("%d of %d checks failed for %s", failed, len(rows), source)}
+	}
+	message.Successf("Verified %s, all %d checks passed", source, len(rows))
+	return nil
+}
+
+// verifyLayers verifies the bundle's root manifest layers and config, then the layers and config of every Zarf pkg
+// manifest in the bundle, each blob is only verified once
+func (op *ociProvider) verifyLayers(ctx context.Context, spinner *message.Spinner) []layerCheck {
+	blobs := op.Repo().Blobs()
+	verified := make(map[string]bool)
+	var checks []layerCheck
+	check := func(pkg string, title string, desc ocispec.Descriptor) error {
+		if desc.Digest == "" || verified[desc.Digest.String()] {
+			return nil
+		}
+		verified[desc.Digest.String()] = true
+		spinner.Updatef("Verifying %s", desc.Digest)
+		err := verifyBlob(ctx, blobs, desc)
+		blobCheck := layerCheck{pkg: pkg, title: title, desc: desc}
+		blobCheck.fail(err, VerifyExitCorruptLayer)
+		checks = append(checks, blobCheck)
+		return err
+	}
+
+	root := op.rootManifest
+	_ = check("", "config", root.Config)
+	for _, layer := range root.Layers {
+		if isBundleMetadataLayer(layer) {
+			_ = check("", layer.Annotations[ocispec.AnnotationTitle], layer)
+			continue
+		}
+		if err := check("", "zarf pkg manifest", layer); err != nil {
+			continue
+		}
+		zarfManifest, err := op.FetchManifest(ctx, layer)
+		if err != nil {
+			checks[len(checks)-1].fail(fmt.Errorf("unable to fetch the manifest: %w", err), VerifyExitCorruptLayer)
+			continue
+		}
+		pkgName := zarfManifest.Annotations[ocispec.AnnotationTitle]
+		if pkgName == "" {
+			pkgName = layer.Digest.Encoded()
+		}
+		checks[len(checks)-1].pkg = pkgName
+		_ = check(pkgName, "config", zarfManifest.Config)
+		for _, pkgLayer := range zarfManifest.Layers {
+			_ = check(pkgName, layerTitle(pkgLayer), pkgLayer)
+		}
+	}
+	return checks
+}
+
+// verifySignature validates the bundle's signature, the signature can't be checked if the bundle's metadata layers
+// failed verification
+func (op *ociProvider) verifySignature(checks []layerCheck, signatureDesc ocispec.Descriptor, publicKeyPath string) layerCheck {
+	signatureCheck := layerCheck{title: "signature", desc: signatureDesc}
+	if slices.ContainsFunc(checks, func(check layerCheck) bool { return check.err != nil && isBundleMetadataLayer(check.desc) }) {
+		signatureCheck.fail(fmt.Errorf("the bundle's metadata failed verification"), VerifyExitCorruptLayer)
+		return signatureCheck
+	}
+	loaded, err := op.LoadBundleMetadata()
+	if err != nil {
+		signatureCheck.fail(err, VerifyExitCorruptLayer)
+		return signatureCheck
+	}
+	if loaded[config.BundleYAMLSignature] == "" {
+		signatureCheck.fail(fmt.Errorf("the bundle isn't signed, but a public key was provided"), VerifyExitMissingSignature)
+		return signatureCheck
+	}
+	err = ValidateBundleSignature(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], loaded[config.BundleYAMLCertificate], publicKeyPath)
+	signatureCheck.fail(err, VerifyExitSignatureMismatch)
+	return signatureCheck
+}
+
+// verifyBlob checks that a blob exists in the registry and matches the digest and size of its desc
+func verifyBlob(ctx context.Context, blobs blobResolver, desc ocispec.Descriptor) error {
+	pushedDesc, err := blobs.Resolve(ctx, desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("unable to resolve the blob: %w", err)
+	}
+	if pushedDesc.Digest != desc.Digest || pushedDesc.Size != desc.Size {
+		return fmt.Errorf("expected %s (%d bytes), got %s (%d bytes)", desc.Digest, desc.Size, pushedDesc.Digest, pushedDesc.Size)
+	}
+	return nil
+}
+
+// isUnreachable returns true if an OCI operation failed because the registry couldn't be reached, e.g. it couldn't be
+// resolved or refused the connection
+func isUnreachable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// layerTitle returns the title annotation of a layer, falling back to its digest
+func layerTitle(layer ocispec.Descriptor) string {
+	if title := layer.Annotations[ocispec.AnnotationTitle]; title != "" {
+		return title
+	}
+	return layer.Digest.String()
+}
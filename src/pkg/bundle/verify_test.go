@@ -0,0 +1,97 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// fakeBlobs resolves the blobs of a fake registry by digest
+type fakeBlobs map[string]ocispec.Descriptor
+
+func (f fakeBlobs) Resolve(_ context.Context, reference string) (ocispec.Descriptor, error) {
+	desc, ok := f[reference]
+	if !ok {
+		return ocispec.Descriptor{}, errdef.ErrNotFound
+	}
+	return desc, nil
+}
+
+func Test_verifyBlob(t *testing.T) {
+	ctx := context.Background()
+	layer := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("layer"))
+	missing := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("missing"))
+	truncated := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("truncated"))
+	blobs := fakeBlobs{
+		layer.Digest.String():     layer,
+		truncated.Digest.String(): {Digest: truncated.Digest, Size: truncated.Size - 1},
+	}
+
+	require.NoError(t, verifyBlob(ctx, blobs, layer))
+	require.ErrorIs(t, verifyBlob(ctx, blobs, missing), errdef.ErrNotFound)
+	require.ErrorContains(t, verifyBlob(ctx, blobs, truncated), "expected")
+}
+
+func Test_layerTitle(t *testing.T) {
+	layer := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("layer"))
+	require.Equal(t, layer.Digest.String(), layerTitle(layer))
+	layer.Annotations = map[string]string{ocispec.AnnotationTitle: "zarf.yaml"}
+	require.Equal(t, "zarf.yaml", layerTitle(layer))
+}
+
+func Test_layerCheckFail(t *testing.T) {
+	unreachable := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	testCases := []struct {
+		name             string
+		err              error
+		exitCode         int
+		expectedExitCode int
+	}{
+		{
+			name:             "missing layer",
+			err:              errdef.ErrNotFound,
+			exitCode:         VerifyExitCorruptLayer,
+			expectedExitCode: VerifyExitCorruptLayer,
+		},
+		{
+			name:             "unreachable registry",
+			err:              fmt.Errorf("unable to resolve the blob: %w", unreachable),
+			exitCode:         VerifyExitCorruptLayer,
+			expectedExitCode: VerifyExitUnreachable,
+		},
+		{
+			name:             "signature mismatch",
+			err:              errors.New("invalid signature when validating ASN.1 encoded signature"),
+			exitCode:         VerifyExitSignatureMismatch,
+			expectedExitCode: VerifyExitSignatureMismatch,
+		},
+		{
+			name:             "missing signature",
+			err:              errors.New("the bundle isn't signed, but a public key was provided"),
+			exitCode:         VerifyExitMissingSignature,
+			expectedExitCode: VerifyExitMissingSignature,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var check layerCheck
+			check.fail(tc.err, tc.exitCode)
+			require.Equal(t, tc.err, check.err)
+			require.Equal(t, tc.expectedExitCode, check.exitCode)
+		})
+	}
+
+	// the exit codes are part of uds verify's interface
+	require.Equal(t, []int{2, 3, 4, 5}, []int{VerifyExitMissingSignature, VerifyExitSignatureMismatch, VerifyExitCorruptLayer, VerifyExitUnreachable})
+	verifyErr := &VerifyError{ExitCode: VerifyExitCorruptLayer, Err: errdef.ErrNotFound}
+	var target *VerifyError
+	require.ErrorAs(t, fmt.Errorf("wrapped: %w", verifyErr), &target)
+	require.ErrorIs(t, verifyErr, errdef.ErrNotFound)
+}
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/layout"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// layerFetcher fetches a layer of a published bundle, oci.OrasRemote satisfies it
+type layerFetcher interface {
+	FetchLayer(ctx context.Context, desc ocispec.Descriptor) ([]byte, error)
+}
+
+// signatureCheck is the result of verifying the signature of a bundle or one of its Zarf pkgs
+type signatureCheck struct {
+	Name     string `json:"name"`
+	Ref      string `json:"ref"`
+	Signed   bool   `json:"signed"`
+	Verified bool   `json:"verified"`
+	Key      string `json:"key,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// verifyAll verifies the signature of a published bundle and the signature of each of its Zarf pkgs against the keys
+// given to --key and --keys, printing the result of each. Only each pkg's zarf.yaml and signature are fetched
+func (b *Bundle) verifyAll(source string, loaded types.PathMap) error {
+	if !helpers.IsOCIURL(source) {
+		return fmt.Errorf("--verify-all only supports bundles in an OCI registry, %s is not an OCI reference", source)
+	}
+	keys := b.cfg.InspectOpts.PublicKeyPaths
+	if b.cfg.InspectOpts.PublicKeyPath != "" {
+		keys = append([]string{b.cfg.InspectOpts.PublicKeyPath}, keys...)
+	}
+	if len(keys) == 0 && loaded[config.BundleYAMLCertificate] == "" {
+		return fmt.Errorf("--verify-all requires the public keys to verify the signatures with, pass them with --keys")
+	}
+	if err := zarfUtils.ReadYaml(loaded[config.BundleYAML], &b.bundle); err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	platform := utils.BundlePlatform(ctx, source)
+	remote, err := zoci.NewRemote(source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return err
+	}
+	contents, err := inspectRemote(ctx, remote.OrasRemote)
+	if err != nil {
+		return fmt.Errorf("unable to inspect %s: %w", source, err)
+	}
+
+	spinner := message.NewProgressSpinner("Verifying the signatures of %s", source)
+	defer spinner.Stop()
+	checks := []signatureCheck{verifyBundleSignatureWithKeys(b.bundle.Metadata.Name, source, loaded, keys)}
+	for _, pkgContents := range contents.Packages {
+		spinner.Updatef("Verifying the signature of package %s", pkgContents.Name)
+		dir := filepath.Join(b.tmp, "signatures", pkgContents.Name)
+		checks = append(checks, verifyPackageSignature(ctx, remote.OrasRemote, pkgContents, b.packageRef(pkgContents.Name), dir, keys))
+	}
+	spinner.Stop()
+
+	if b.cfg.InspectOpts.JSON {
+		output, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(output) + "\n")
+	} else {
+		printSignatureChecks(checks)
+	}
+	return signatureChecksError(checks)
+}
+
+// packageRef returns where a Zarf pkg in the bundle was sourced from
+func (b *Bundle) packageRef(name string) string {
+	i := slices.IndexFunc(b.bundle.Packages, func(pkg types.Package) bool { return pkg.Name == name })
+	if i < 0 {
+		return ""
+	}
+	pkg := b.bundle.Packages[i]
+	if pkg.Repository == "" {
+		return pkg.Path
+	}
+	return fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref)
+}
+
+// verifyBundleSignatureWithKeys verifies the bundle's signature against each key until one matches, a keyless
+// signature is verified against its Fulcio certificate instead
+func verifyBundleSignatureWithKeys(name, source string, loaded types.PathMap, keys []string) signatureCheck {
+	check := signatureCheck{Name: name, Ref: source, Signed: loaded[config.BundleYAMLSignature] != ""}
+	if !check.Signed {
+		check.Error = fmt.Sprintf("the bundle doesn't have a %s", config.BundleYAMLSignature)
+		return check
+	}
+	if certPath := loaded[config.BundleYAMLCertificate]; certPath != "" {
+		if err := utils.CosignVerifyBlobKeyless(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], certPath); err != nil {
+			check.Error = err.Error()
+			return check
+		}
+		check.Verified = true
+		check.Key = "keyless"
+		return check
+	}
+	check.Key, check.Verified, check.Error = verifyWithKeys(loaded[config.BundleYAML], loaded[config.BundleYAMLSignature], keys)
+	return check
+}
+
+// verifyPackageSignature fetches a Zarf pkg's zarf.yaml and signature to dir and verifies the signature against each
+// key until one matches
+func verifyPackageSignature(ctx context.Context, remote layerFetcher, pkgContents PackageContents, ref, dir string, keys []string) signatureCheck {
+	check := signatureCheck{Name: pkgContents.Name, Ref: ref}
+	titled := func(title string) (ocispec.Descriptor, bool) {
+		i := slices.IndexFunc(pkgContents.Layers, func(layer ocispec.Descriptor) bool {
+			return layer.Annotations[ocispec.AnnotationTitle] == title
+		})
+		if i < 0 {
+			return ocispec.Descriptor{}, false
+		}
+		return pkgContents.Layers[i], true
+	}
+	zarfYAMLDesc, ok := titled(config.ZarfYAML)
+	if !ok {
+		check.Error = fmt.Sprintf("the package doesn't have a %s", config.ZarfYAML)
+		return check
+	}
+	signatureDesc, ok := titled(layout.Signature)
+	if !ok {
+		check.Error = fmt.Sprintf("the package doesn't have a %s", layout.Signature)
+		return check
+	}
+	check.Signed = true
+
+	if err := helpers.CreateDirectory(dir, 0700); err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	paths := make(map[string]string, 2)
+	for _, desc := range []ocispec.Descriptor{zarfYAMLDesc, signatureDesc} {
+		b, err := remote.FetchLayer(ctx, desc)
+		if err != nil {
+			check.Error = fmt.Sprintf("unable to fetch the %s: %s", desc.Annotations[ocispec.AnnotationTitle], err)
+			return check
+		}
+		path := filepath.Join(dir, desc.Annotations[ocispec.AnnotationTitle])
+		if err := os.WriteFile(path, b, 0600); err != nil {
+			check.Error = err.Error()
+			return check
+		}
+		paths[desc.Annotations[ocispec.AnnotationTitle]] = path
+	}
+	check.Key, check.Verified, check.Error = verifyWithKeys(paths[config.ZarfYAML], paths[layout.Signature], keys)
+	return check
+}
+
+// verifyWithKeys verifies a signature against each key until one matches, returning the key that matched or the error
+// of the last key that didn't
+func verifyWithKeys(blobPath, signaturePath string, keys []string) (string, bool, string) {
+	if len(keys) == 0 {
+		return "", false, "no public key was provided to verify the signature with"
+	}
+	var err error
+	for _, key := range keys {
+		if err = zarfUtils.CosignVerifyBlob(blobPath, signaturePath, key); err == nil {
+			return key, true, ""
+		}
+		message.Debugf("Signature %s doesn't match key %s: %s", signaturePath, key, err)
+	}
+	return "", false, fmt.Sprintf("the signature doesn't match any of the keys: %s", err)
+}
+
+// printSignatureChecks prints a table of the signature checks, the bundle's check is first
+func printSignatureChecks(checks []signatureCheck) {
+	var rows [][]string
+	for _, check := range checks {
+		signed := "no"
+		if check.Signed {
+			signed = "yes"
+		}
+		result := "verified with " + check.Key
+		if !check.Verified {
+			result = "failed: " + check.Error
+		}
+		rows = append(rows, []string{check.Name, check.Ref, signed, result})
+	}
+	message.Title("Signatures", "the bundle's signature and the signature of each of its packages")
+	message.Table([]string{"Name", "Ref", "Signed", "Result"}, rows)
+}
+
+// signatureChecksError returns an error naming the bundle or Zarf pkgs whose signatures didn't verify
+func signatureChecksError(checks []signatureCheck) error {
+	var failed []string
+	for _, check := range checks {
+		if !check.Verified {
+			failed = append(failed, check.Name)
+		}
+	}
+	if len(failed) == 0 {
+		message.Successf("Verified %d signatures", len(checks))
+		return nil
+	}
+	return fmt.Errorf("%d of %d signatures didn't verify: %s", len(failed), len(checks), strings.Join(failed, ", "))
+}
@@ -0,0 +1,84 @@
+package bundle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/zarf/src/pkg/layout"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// fakeLayers fetches the layers of a fake registry by digest
+type fakeLayers map[string][]byte
+
+func (f fakeLayers) FetchLayer(_ context.Context, desc ocispec.Descriptor) ([]byte, error) {
+	b, ok := f[desc.Digest.String()]
+	if !ok {
+		return nil, errdef.ErrNotFound
+	}
+	return b, nil
+}
+
+func Test_verifyPackageSignature(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	password := func(bool) ([]byte, error) { return []byte("password"), nil }
+	writeKeys := func(name string) (string, string) {
+		keys, err := cosign.GenerateKeyPair(password)
+		require.NoError(t, err)
+		privateKeyPath := filepath.Join(dir, name+".key")
+		publicKeyPath := filepath.Join(dir, name+".pub")
+		require.NoError(t, os.WriteFile(privateKeyPath, keys.PrivateBytes, 0600))
+		require.NoError(t, os.WriteFile(publicKeyPath, keys.PublicBytes, 0600))
+		return privateKeyPath, publicKeyPath
+	}
+	signingKey, publicKey := writeKeys("signer")
+	_, otherKey := writeKeys("other")
+
+	zarfYAML := []byte("kind: ZarfPackageConfig\nmetadata:\n  name: podinfo\n")
+	zarfYAMLPath := filepath.Join(dir, config.ZarfYAML)
+	require.NoError(t, os.WriteFile(zarfYAMLPath, zarfYAML, 0600))
+	signature, err := zarfUtils.CosignSignBlob(zarfYAMLPath, filepath.Join(dir, layout.Signature), signingKey, password)
+	require.NoError(t, err)
+
+	layer := func(title string, b []byte) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, b)
+		desc.Annotations = map[string]string{ocispec.AnnotationTitle: title}
+		return desc
+	}
+	zarfYAMLDesc := layer(config.ZarfYAML, zarfYAML)
+	signatureDesc := layer(layout.Signature, signature)
+	remote := fakeLayers{zarfYAMLDesc.Digest.String(): zarfYAML, signatureDesc.Digest.String(): signature}
+	signed := PackageContents{Name: "podinfo", Layers: []ocispec.Descriptor{zarfYAMLDesc, signatureDesc}}
+
+	// the signature is verified if it matches any of the keys
+	check := verifyPackageSignature(ctx, remote, signed, "ghcr.io/defenseunicorns/podinfo:6.4.0", filepath.Join(dir, "verified"), []string{otherKey, publicKey})
+	require.Equal(t, signatureCheck{Name: "podinfo", Ref: "ghcr.io/defenseunicorns/podinfo:6.4.0", Signed: true, Verified: true, Key: publicKey}, check)
+
+	check = verifyPackageSignature(ctx, remote, signed, "", filepath.Join(dir, "mismatch"), []string{otherKey})
+	require.True(t, check.Signed)
+	require.False(t, check.Verified)
+	require.Contains(t, check.Error, "the signature doesn't match any of the keys")
+
+	unsigned := PackageContents{Name: "unsigned", Layers: []ocispec.Descriptor{zarfYAMLDesc}}
+	check = verifyPackageSignature(ctx, remote, unsigned, "", filepath.Join(dir, "unsigned"), []string{publicKey})
+	require.Equal(t, signatureCheck{Name: "unsigned", Error: "the package doesn't have a zarf.yaml.sig"}, check)
+
+	check = verifyPackageSignature(ctx, fakeLayers{}, signed, "", filepath.Join(dir, "missing"), []string{publicKey})
+	require.False(t, check.Verified)
+	require.Contains(t, check.Error, "unable to fetch the zarf.yaml")
+
+	err = signatureChecksError([]signatureCheck{{Name: "example", Verified: true}, {Name: "podinfo", Verified: true}})
+	require.NoError(t, err)
+	err = signatureChecksError([]signatureCheck{{Name: "example", Verified: true}, {Name: "podinfo"}, {Name: "unsigned"}})
+	require.EqualError(t, err, "2 of 3 signatures didn't verify: podinfo, unsigned")
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundle contains functions for interacting with, managing and deploying UDS packages
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	goyaml "github.com/goccy/go-yaml"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry"
+)
+
+// bundleVersion is a bundle root manifest a tag of the bundle's repository points at, either directly or through the
+// tag's index
+type bundleVersion struct {
+	Tag          string `json:"tag"`
+	Version      string `json:"version"`
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Digest       string `json:"digest"`
+}
+
+// Versions lists the bundles published in an OCI repository, for each tag the version in the bundle's YAML and the
+// digest of its root manifest are shown for every platform in the tag's index
+func (b *Bundle) Versions() error {
+	source := b.cfg.VersionsOpts.Source
+	if !helpers.IsOCIURL(source) {
+		return fmt.Errorf("versions only supports bundles in an OCI registry, %s is not an OCI reference", source)
+	}
+	ref, err := registry.ParseReference(strings.TrimPrefix(source, helpers.OCIURLPrefix))
+	if err != nil {
+		return fmt.Errorf("failed to parse OCI reference %q: %w", source, err)
+	}
+	if ref.Reference != "" {
+		return fmt.Errorf("%s includes a tag or digest, pass the bundle's repository, e.g. oci://ghcr.io/<org>/<bundle name>", source)
+	}
+	platform := ocispec.Platform{
+		Architecture: config.GetArch(),
+		OS:           config.GetOS(),
+	}
+	remote, err := zoci.NewRemote(source, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return err
+	}
+	versions, err := listVersions(context.TODO(), remote.OrasRemote)
+	if err != nil {
+		return fmt.Errorf("unable to list the versions of %s: %w", source, err)
+	}
+
+	if b.cfg.VersionsOpts.JSON {
+		output, err := json.MarshalIndent(versions, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(output) + "\n")
+		return nil
+	}
+	if len(versions) == 0 {
+		message.Warnf("%s doesn't have any bundles", source)
+		return nil
+	}
+	rows := make([][]string, 0, len(versions))
+	for _, v := range versions {
+		rows = append(rows, []string{v.Tag, v.Version, v.OS + "/" + v.Architecture, v.Digest})
+	}
+	message.Table([]string{"Tag", "Version", "Platform", "Digest"}, rows)
+	return nil
+}
+
+// listVersions resolves each of the repository's tags, a tag's index is expanded into its root manifests while a tag
+// that points at a root manifest (e.g. a digest tag) is listed as is. Manifests without a bundle YAML, such as the
+// referrers indexes of registries without the referrers API, aren't bundles and are skipped
+func listVersions(ctx context.Context, remote *oci.OrasRemote) ([]bundleVersion, error) {
+	var tags []string
+	err := remote.Repo().Tags(ctx, "", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tags: %w", err)
+	}
+
+	// digest tags point at the same root manifests as the version tags, each bundle YAML is only fetched once
+	bundles := make(map[string]*types.UDSBundle)
+	var versions []bundleVersion
+	for _, tag := range tags {
+		desc, err := remote.Repo().Resolve(ctx, tag)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve %s: %w", tag, err)
+		}
+		rootManifestDescs := []ocispec.Descriptor{desc}
+		if desc.MediaType == ocispec.MediaTypeImageIndex {
+			index, err := oci.FetchUnmarshal[ocispec.Index](ctx, remote.FetchLayer, json.Unmarshal, desc)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch the index of %s: %w", tag, err)
+			}
+			rootManifestDescs = index.Manifests
+		}
+		for _, rootManifestDesc := range rootManifestDescs {
+			digest := rootManifestDesc.Digest.String()
+			bundle, ok := bundles[digest]
+			if !ok {
+				bundle, err = fetchBundleYAML(ctx, remote, rootManifestDesc)
+				if err != nil {
+					return nil, fmt.Errorf("unable to read the bundle at %s@%s: %w", tag, digest, err)
+				}
+				bundles[digest] = bundle
+			}
+			if bundle == nil {
+				message.Debugf("Skipping %s@%s, it isn't a UDS bundle", tag, digest)
+				continue
+			}
+			versions = append(versions, bundleVersion{
+				Tag:          tag,
+				Version:      bundle.Metadata.Version,
+				Architecture: bundle.Metadata.Architecture,
+				OS:           config.GetOS(bundle.Metadata.OS),
+				Digest:       digest,
+			})
+		}
+	}
+	return versions, nil
+}
+
+// fetchBundleYAML fetches the bundle YAML of a root manifest, nil is returned if the manifest doesn't have one
+func fetchBundleYAML(ctx context.Context, remote *oci.OrasRemote, rootManifestDesc ocispec.Descriptor) (*types.UDSBundle, error) {
+	if rootManifestDesc.MediaType != ocispec.MediaTypeImageManifest {
+		return nil, nil
+	}
+	root, err := remote.FetchManifest(ctx, rootManifestDesc)
+	if err != nil {
+		return nil, err
+	}
+	bundleYAMLDesc := root.Locate(config.BundleYAML)
+	if oci.IsEmptyDescriptor(bundleYAMLDesc) {
+		return nil, nil
+	}
+	bundleYAML, err := remote.FetchLayer(ctx, bundleYAMLDesc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s: %w", config.BundleYAML, err)
+	}
+	var bundle types.UDSBundle
+	if err := goyaml.Unmarshal(bundleYAML, &bundle); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", config.BundleYAML, err)
+	}
+	return &bundle, nil
+}
@@ -0,0 +1,112 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	goyaml "github.com/goccy/go-yaml"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_listVersions(t *testing.T) {
+	manifests := make(map[string][]byte)
+	blobs := make(map[string][]byte)
+	mediaTypes := make(map[string]string)
+	addManifest := func(mediaType string, v any) ocispec.Descriptor {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		desc := content.NewDescriptorFromBytes(mediaType, b)
+		manifests[desc.Digest.String()] = b
+		mediaTypes[desc.Digest.String()] = mediaType
+		return desc
+	}
+	addBundle := func(version, arch, os string) ocispec.Descriptor {
+		bundleYAML, err := goyaml.Marshal(types.UDSBundle{Metadata: types.UDSMetadata{Name: "example", Version: version, Architecture: arch, OS: os}})
+		require.NoError(t, err)
+		layer := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, bundleYAML)
+		layer.Annotations = map[string]string{ocispec.AnnotationTitle: config.BundleYAML}
+		blobs[layer.Digest.String()] = bundleYAML
+		rootManifest := ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: ocispec.DescriptorEmptyJSON, Layers: []ocispec.Descriptor{layer}}
+		rootManifest.SchemaVersion = 2
+		desc := addManifest(ocispec.MediaTypeImageManifest, rootManifest)
+		desc.Platform = &ocispec.Platform{Architecture: arch, OS: config.GetOS(os)}
+		return desc
+	}
+	addIndex := func(descs ...ocispec.Descriptor) ocispec.Descriptor {
+		index := ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: descs}
+		index.SchemaVersion = 2
+		return addManifest(ocispec.MediaTypeImageIndex, index)
+	}
+
+	amd64 := addBundle("0.0.1", "amd64", "")
+	arm64 := addBundle("0.0.1", "arm64", "")
+	windows := addBundle("0.0.2", "amd64", "windows")
+	// a referrers index a registry without the referrers API tags with the subject's digest
+	referrer := ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: ocispec.DescriptorEmptyJSON, Layers: []ocispec.Descriptor{ocispec.DescriptorEmptyJSON}}
+	referrer.SchemaVersion = 2
+	tags := map[string]ocispec.Descriptor{
+		"0.0.1":                            addIndex(amd64, arm64),
+		"0.0.2":                            addIndex(windows),
+		"sha256-" + amd64.Digest.Encoded(): amd64,
+		"sha256-" + arm64.Digest.Encoded(): addIndex(addManifest(ocispec.MediaTypeImageManifest, referrer)),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/dev/bundle/tags/list" {
+			var names []string
+			for tag := range tags {
+				names = append(names, tag)
+			}
+			slices.Sort(names)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "dev/bundle", "tags": names})
+			return
+		}
+		var b []byte
+		mediaType := "application/octet-stream"
+		if reference, ok := strings.CutPrefix(r.URL.Path, "/v2/dev/bundle/manifests/"); ok {
+			if desc, ok := tags[reference]; ok {
+				reference = desc.Digest.String()
+			}
+			b, mediaType = manifests[reference], mediaTypes[reference]
+		} else {
+			b = blobs[strings.TrimPrefix(r.URL.Path, "/v2/dev/bundle/blobs/")]
+		}
+		if b == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		w.Header().Set("Docker-Content-Digest", content.NewDescriptorFromBytes(mediaType, b).Digest.String())
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	}))
+	defer server.Close()
+
+	remote, err := oci.NewOrasRemote(strings.TrimPrefix(server.URL, "http://")+"/dev/bundle", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	versions, err := listVersions(context.Background(), remote)
+	require.NoError(t, err)
+
+	// the registry lists tags in lexical order, the referrers index's tag isn't a bundle so it's skipped
+	require.Equal(t, []bundleVersion{
+		{Tag: "0.0.1", Version: "0.0.1", Architecture: "amd64", OS: oci.MultiOS, Digest: amd64.Digest.String()},
+		{Tag: "0.0.1", Version: "0.0.1", Architecture: "arm64", OS: oci.MultiOS, Digest: arm64.Digest.String()},
+		{Tag: "0.0.2", Version: "0.0.2", Architecture: "amd64", OS: "windows", Digest: windows.Digest.String()},
+		{Tag: "sha256-" + amd64.Digest.Encoded(), Version: "0.0.1", Architecture: "amd64", OS: oci.MultiOS, Digest: amd64.Digest.String()},
+	}, versions)
+}
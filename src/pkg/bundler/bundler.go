@@ -5,16 +5,84 @@
 package bundler
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/defenseunicorns/uds-cli/src/pkg/bundler/pusher"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	// OutputFormatJSON writes a machine-readable result of creating a remote bundle to stdout
+	OutputFormatJSON = "json"
+	// OutputFormatDigest only writes the digest of the remote bundle's root manifest to stdout, followed by a newline
+	OutputFormatDigest = "digest"
+)
+
+const (
+	// DigestTagFull also tags the bundle's root manifest with its full digest, e.g. sha256-<hex>
+	DigestTagFull = "full"
+	// DigestTagShort also tags the bundle's root manifest with the first 12 characters of its digest
+	DigestTagShort = "short"
 )
 
 // Bundler is used for bundling packages
 type Bundler struct {
-	bundle    *types.UDSBundle
-	output    string
-	tmpDstDir string
-	sourceDir string
+	bundle            *types.UDSBundle
+	outputs           []string
+	tmpDir            string
+	tmpDstDir         string
+	sourceDir         string
+	maxConcurrency    int
+	dryRun            bool
+	verifySourceKeys  []string
+	outputFormat      string
+	signatureReferrer bool
+	detachedSignature bool
+	sbomFormat        string
+	sbomReferrers     bool
+	signature         []byte
+	sigAnnotations    map[string]string
+	srcCredential     auth.Credential
+	dstCredential     auth.Credential
+	requireSig        bool
+	noCache           bool
+	force             bool
+	metadataMediaType string
+	configMediaType   string
+	progressFn        pusher.ProgressFn
+	packagePushedFn   pusher.PackagePushedFn
+	logger            *slog.Logger
+	metricsFile       string
+	digestTag         string
+	cleanupOnFailure  bool
+	compressionLevel  int
+	allowedMediaTypes []string
+	vulnScanner       VulnScanner
+	failOnVuln        bool
+	uncompressedSize  string
+	pruneKeep         int
+	quiet             bool
+	layerConcurrency  int
+	concurrencyLimit  int
+	transformBundle   BundleTransformFn
+	verifySigKey      string
+	sourceMirrors     []string
+	registryOverrides map[string]string
+	digestFile        string
+	extraFiles        []ExtraFile
+	// rootManifestDesc is the desc of the root manifest pushed by the last create in an OCI registry
+	rootManifestDesc ocispec.Descriptor
+	// bundleYAMLDesc is the desc of the YAML layer pushed by the last create in an OCI registry
+	bundleYAMLDesc ocispec.Descriptor
 }
 
 // Pusher is the interface for pushing bundles
@@ -23,37 +91,400 @@ type Pusher interface {
 
 // Options are the options for creating a bundler
 type Options struct {
-	Bundle    *types.UDSBundle
-	Output    string
-	TmpDstDir string
-	SourceDir string
+	Bundle  *types.UDSBundle
+	Outputs []string
+	// TmpDir is the dir the create makes its own tmp dir under, e.g. a dir on a large volume, it's removed once the
+	// create returns; the OS's tmp dir is used if it's empty
+	TmpDir            string
+	SourceDir         string
+	MaxConcurrency    int
+	DryRun            bool
+	VerifySourceKeys  []string
+	OutputFormat      string
+	SignatureReferrer bool
+	// DetachedSignature references the bundle's signature with a root manifest annotation instead of a layer
+	DetachedSignature bool
+	SBOMFormat        string
+	// SBOMReferrers attaches the bundle-level SBOM and each Zarf pkg's SBOMs with the OCI referrers API when every
+	// destination supports it, it's only used when creating a bundle in an OCI registry
+	SBOMReferrers bool
+	// Signature is the signature of the bundle's YAML, if the bundle was signed
+	Signature []byte
+	// SignatureAnnotations are added to the bundle's signature layer, e.g. the certificate of a keyless signature
+	SignatureAnnotations map[string]string
+	// SrcCredential and DstCredential authenticate to the source and destination registries, the docker config is
+	// used if they're empty
+	SrcCredential auth.Credential
+	DstCredential auth.Credential
+	// RequireSignature fails the create before anything is pushed if the bundle isn't signed
+	RequireSignature bool
+	// NoCache fetches each Zarf pkg's root manifest instead of reusing the one cached on disk
+	NoCache bool
+	// Force overwrites the root manifest an existing index has for the bundle's arch
+	Force bool
+	// MetadataMediaType is the media type of the bundle's YAML and signature layers, defaults to the Zarf layer media type
+	MetadataMediaType string
+	// ConfigMediaType is the media type of the bundle's manifest config, defaults to config.BundleConfigMediaType
+	ConfigMediaType string
+	// ProgressFn is called as layers are pushed, it's only used when creating a bundle in an OCI registry
+	ProgressFn pusher.ProgressFn
+	// PackagePushedFn is called as each Zarf pkg finishes pushing with its manifest desc and the bytes pushed, it's only
+	// used when creating a bundle in an OCI registry
+	PackagePushedFn pusher.PackagePushedFn
+	// Logger receives structured events (pkg names, digests, bytes and durations) as the bundle is pushed, it's only used
+	// when creating a bundle in an OCI registry and the events are dropped if it's nil
+	Logger *slog.Logger
+	// MetricsFile is the path the push durations are written to in the Prometheus text format, it's only used when
+	// creating a bundle in an OCI registry
+	MetricsFile string
+	// DigestTag also tags the root manifest with its digest (DigestTagFull or DigestTagShort), it's only used when
+	// creating a bundle in an OCI registry
+	DigestTag string
+	// CleanupOnFailure deletes the blobs a failed create pushed, it's only used when creating a bundle in an OCI registry
+	CleanupOnFailure bool
+	// CompressionLevel is the zstd level the Zarf pkgs' component tarballs are compressed at, 0 pushes them as is; it's
+	// only used when creating a bundle in an OCI registry
+	CompressionLevel int
+	// AllowedMediaTypes are the only media types the Zarf pkgs' layers may have, the create fails before anything is
+	// pushed if a layer has another media type; it's only used when creating a bundle in an OCI registry
+	AllowedMediaTypes []string
+	// VulnScanner reports the images of the Zarf pkgs with known vulnerabilities before anything is pushed, the
+	// findings are warned about unless FailOnVuln is set; it's only used when creating a bundle in an OCI registry
+	VulnScanner VulnScanner
+	// FailOnVuln fails the create if the VulnScanner reports any images
+	FailOnVuln bool
+	// UncompressedSize records the total uncompressed size of the Zarf pkgs' layers on the root manifest, computed
+	// exactly (UncompressedSizeExact) or estimated (UncompressedSizeEstimate); it's only used when creating a bundle in
+	// an OCI registry
+	UncompressedSize string
+	// PruneKeep deletes the versions of the bundle beyond the newest PruneKeep (by semver) from each destination once
+	// the bundle is published, nothing is pruned if it's 0; it's only used when creating a bundle in an OCI registry
+	PruneKeep int
+	// Quiet suppresses the headers, progress, success lines and the inspect/deploy/pull hints, only warnings, errors
+	// and the JSON output format are written
+	Quiet bool
+	// LayerConcurrency is the number of each Zarf pkg's layers pushed at the same time, independent of the number of
+	// pkgs pushed at the same time; it's only used when creating a bundle in an OCI registry
+	LayerConcurrency int
+	// ConcurrencyLimit bounds the OCI operations running at the same time across fetching the Zarf pkgs and pushing
+	// them, 0 doesn't limit them beyond the other concurrency settings; it's only used when creating a bundle in an OCI
+	// registry
+	ConcurrencyLimit int
+	// TransformBundle mutates a copy of the bundle before it's pushed as the bundle's YAML layer, e.g. to redact
+	// internal-only fields; it's only used when creating an unsigned bundle in an OCI registry
+	TransformBundle BundleTransformFn
+	// VerifySignatureKey is the path to a public key the bundle's signature is verified with against the exact YAML
+	// being pushed, before anything is pushed
+	VerifySignatureKey string
+	// SourceMirrors are registry hosts the Zarf pkgs are fetched from, in order, if fetching a pkg from its own
+	// registry fails; it's only used when creating a bundle in an OCI registry
+	SourceMirrors []string
+	// RegistryOverrides rewrite the image references in the Zarf pkgs from an old registry (and optional path) to a new
+	// one, e.g. to deploy from an air-gapped mirror; it's only used when creating a bundle in an OCI registry
+	RegistryOverrides map[string]string
+	// DigestFile is the path the digest of the bundle's root manifest is written to once it's pushed, it's only used
+	// when creating a bundle in (or also publishing it to) an OCI registry
+	DigestFile string
+	// ExtraFiles are pushed as layers alongside the bundle's YAML, titled with their path
+	ExtraFiles []ExtraFile
 }
 
 // NewBundler creates a new bundler
 func NewBundler(opts *Options) *Bundler {
 	b := Bundler{
-		bundle:    opts.Bundle,
-		output:    opts.Output,
-		tmpDstDir: opts.TmpDstDir,
-		sourceDir: opts.SourceDir,
+		bundle:            opts.Bundle,
+		outputs:           opts.Outputs,
+		tmpDir:            opts.TmpDir,
+		sourceDir:         opts.SourceDir,
+		maxConcurrency:    opts.MaxConcurrency,
+		dryRun:            opts.DryRun,
+		verifySourceKeys:  opts.VerifySourceKeys,
+		outputFormat:      opts.OutputFormat,
+		signatureReferrer: opts.SignatureReferrer,
+		detachedSignature: opts.DetachedSignature,
+		sbomFormat:        opts.SBOMFormat,
+		sbomReferrers:     opts.SBOMReferrers,
+		signature:         opts.Signature,
+		sigAnnotations:    opts.SignatureAnnotations,
+		srcCredential:     opts.SrcCredential,
+		dstCredential:     opts.DstCredential,
+		requireSig:        opts.RequireSignature,
+		noCache:           opts.NoCache,
+		force:             opts.Force,
+		metadataMediaType: opts.MetadataMediaType,
+		configMediaType:   opts.ConfigMediaType,
+		progressFn:        opts.ProgressFn,
+		packagePushedFn:   opts.PackagePushedFn,
+		logger:            opts.Logger,
+		metricsFile:       opts.MetricsFile,
+		digestTag:         opts.DigestTag,
+		cleanupOnFailure:  opts.CleanupOnFailure,
+		compressionLevel:  opts.CompressionLevel,
+		allowedMediaTypes: opts.AllowedMediaTypes,
+		vulnScanner:       opts.VulnScanner,
+		failOnVuln:        opts.FailOnVuln,
+		uncompressedSize:  opts.UncompressedSize,
+		pruneKeep:         opts.PruneKeep,
+		quiet:             opts.Quiet,
+		layerConcurrency:  opts.LayerConcurrency,
+		concurrencyLimit:  opts.ConcurrencyLimit,
+		transformBundle:   opts.TransformBundle,
+		verifySigKey:      opts.VerifySignatureKey,
+		sourceMirrors:     opts.SourceMirrors,
+		registryOverrides: opts.RegistryOverrides,
+		digestFile:        opts.DigestFile,
+		extraFiles:        opts.ExtraFiles,
 	}
 	return &b
 }
 
-// Create creates a bundle
-func (b *Bundler) Create() error {
-	if utils.IsRegistryURL(b.output) {
-		remoteBundle := NewRemoteBundle(&RemoteBundleOpts{Bundle: b.bundle, Output: b.output})
-		err := remoteBundle.create(nil)
+// Create creates a bundle, canceling ctx aborts any in-flight transfers
+func (b *Bundler) Create(ctx context.Context) error {
+	if b.outputFormat != "" && b.outputFormat != OutputFormatJSON && b.outputFormat != OutputFormatDigest {
+		return fmt.Errorf("unsupported output format %q, supported formats are %q and %q", b.outputFormat, OutputFormatJSON, OutputFormatDigest)
+	}
+	if b.sbomFormat != "" && b.sbomFormat != SBOMFormatSPDX && b.sbomFormat != SBOMFormatCycloneDX {
+		return fmt.Errorf("unsupported SBOM format %q, supported formats are %q and %q", b.sbomFormat, SBOMFormatSPDX, SBOMFormatCycloneDX)
+	}
+	if b.digestTag != "" && b.digestTag != DigestTagFull && b.digestTag != DigestTagShort {
+		return fmt.Errorf("unsupported digest tag %q, supported digest tags are %q and %q", b.digestTag, DigestTagFull, DigestTagShort)
+	}
+	if err := validateMetadataMediaType(b.metadataMediaType); err != nil {
+		return err
+	}
+	if err := validateConfigMediaType(b.configMediaType); err != nil {
+		return err
+	}
+	if err := validateUncompressedSize(b.uncompressedSize); err != nil {
+		return err
+	}
+	if b.detachedSignature && b.signatureReferrer {
+		return fmt.Errorf("a detached signature can't also be attached with the OCI referrers API, choose one")
+	}
+	if err := utils.ValidateCompressionLevel(b.compressionLevel); err != nil {
+		return err
+	}
+	for _, mirror := range b.sourceMirrors {
+		if err := utils.ValidateMirror(mirror); err != nil {
+			return err
+		}
+	}
+	for from, to := range b.registryOverrides {
+		if err := utils.ValidateRegistryOverride(from, to); err != nil {
+			return err
+		}
+	}
+	if b.pruneKeep < 0 {
+		return fmt.Errorf("invalid number of versions to keep %d, it can't be negative", b.pruneKeep)
+	}
+	if b.layerConcurrency < 0 {
+		return fmt.Errorf("invalid layer concurrency %d, it can't be negative", b.layerConcurrency)
+	}
+	if b.concurrencyLimit < 0 {
+		return fmt.Errorf("invalid concurrency limit %d, it can't be negative", b.concurrencyLimit)
+	}
+	if b.metricsFile != "" && b.dryRun {
+		return fmt.Errorf("a metrics file can't be written for a dry run since nothing is pushed")
+	}
+	if b.digestFile != "" && b.dryRun {
+		return fmt.Errorf("a digest file can't be written for a dry run since nothing is pushed")
+	}
+
+	// the create stages the bundle in a tmp dir of its own, which is removed on every return and when a panic unwinds
+	// the create, so nothing is left behind no matter where it fails
+	tmpDstDir, err := makeTmpDir(b.tmpDir)
+	if err != nil {
+		return err
+	}
+	b.tmpDstDir = tmpDstDir
+	defer func() {
+		if err := removeTmpDir(tmpDstDir); err != nil {
+			message.Warnf("Unable to clean up after the create: %s", err)
+		}
+	}()
+
+	if len(b.outputs) > 0 && allRegistryURLs(b.outputs) {
+		remoteBundle := NewRemoteBundle(&RemoteBundleOpts{
+			Bundle:               b.bundle,
+			Outputs:              b.outputs,
+			TmpDstDir:            b.tmpDstDir,
+			MaxConcurrency:       b.maxConcurrency,
+			DryRun:               b.dryRun,
+			VerifySourceKeys:     b.verifySourceKeys,
+			OutputFormat:         b.outputFormat,
+			SignatureReferrer:    b.signatureReferrer,
+			DetachedSignature:    b.detachedSignature,
+			SBOMFormat:           b.sbomFormat,
+			SBOMReferrers:        b.sbomReferrers,
+			SignatureAnnotations: b.sigAnnotations,
+			SrcCredential:        b.srcCredential,
+			DstCredential:        b.dstCredential,
+			RequireSignature:     b.requireSig,
+			NoCache:              b.noCache,
+			Force:                b.force,
+			MetadataMediaType:    b.metadataMediaType,
+			ConfigMediaType:      b.configMediaType,
+			ProgressFn:           b.progressFn,
+			PackagePushedFn:      b.packagePushedFn,
+			Logger:               b.logger,
+			MetricsFile:          b.metricsFile,
+			DigestTag:            b.digestTag,
+			CleanupOnFailure:     b.cleanupOnFailure,
+			CompressionLevel:     b.compressionLevel,
+			AllowedMediaTypes:    b.allowedMediaTypes,
+			VulnScanner:          b.vulnScanner,
+			FailOnVuln:           b.failOnVuln,
+			UncompressedSize:     b.uncompressedSize,
+			PruneKeep:            b.pruneKeep,
+			Quiet:                b.quiet,
+			LayerConcurrency:     b.layerConcurrency,
+			ConcurrencyLimit:     b.concurrencyLimit,
+			TransformBundle:      b.transformBundle,
+			VerifySignatureKey:   b.verifySigKey,
+			SourceMirrors:        b.sourceMirrors,
+			RegistryOverrides:    b.registryOverrides,
+			ExtraFiles:           b.extraFiles,
+		})
+		rootManifestDesc, err := remoteBundle.create(ctx, b.signature)
 		if err != nil {
 			return err
 		}
+		b.rootManifestDesc = rootManifestDesc
+		b.bundleYAMLDesc = remoteBundle.bundleYAMLDesc
 	} else {
-		localBundle := NewLocalBundle(&LocalBundleOpts{Bundle: b.bundle, TmpDstDir: b.tmpDstDir, SourceDir: b.sourceDir, OutputDir: b.output})
-		err := localBundle.create(nil)
+		if b.dryRun {
+			return fmt.Errorf("dry run is only supported when creating a bundle in an OCI registry")
+		}
+		if len(b.verifySourceKeys) > 0 {
+			return fmt.Errorf("verifying source package signatures is only supported when creating a bundle in an OCI registry")
+		}
+		if b.outputFormat != "" {
+			return fmt.Errorf("the %s output format is only supported when creating a bundle in an OCI registry", b.outputFormat)
+		}
+		if b.metricsFile != "" {
+			return fmt.Errorf("a metrics file is only supported when creating a bundle in an OCI registry")
+		}
+		if b.digestTag != "" {
+			return fmt.Errorf("digest tags are only supported when creating a bundle in an OCI registry")
+		}
+		if b.cleanupOnFailure {
+			return fmt.Errorf("cleaning up a failed create is only supported when creating a bundle in an OCI registry")
+		}
+		if b.compressionLevel != 0 {
+			return fmt.Errorf("compressing layers is only supported when creating a bundle in an OCI registry")
+		}
+		if b.layerConcurrency > 0 {
+			return fmt.Errorf("layer concurrency is only supported when creating a bundle in an OCI registry")
+		}
+		if b.concurrencyLimit > 0 {
+			return fmt.Errorf("a concurrency limit is only supported when creating a bundle in an OCI registry")
+		}
+		if len(b.sourceMirrors) > 0 {
+			return fmt.Errorf("source mirrors are only supported when creating a bundle in an OCI registry")
+		}
+		if b.transformBundle != nil {
+			return fmt.Errorf("transforming the bundle YAML is only supported when creating a bundle in an OCI registry")
+		}
+		if b.sbomReferrers {
+			return fmt.Errorf("SBOM referrers are only supported when creating a bundle in an OCI registry")
+		}
+		if len(b.allowedMediaTypes) > 0 {
+			return fmt.Errorf("allowed media types are only supported when creating a bundle in an OCI registry")
+		}
+		if b.vulnScanner != nil {
+			return fmt.Errorf("scanning for vulnerable images is only supported when creating a bundle in an OCI registry")
+		}
+		if b.uncompressedSize != "" {
+			return fmt.Errorf("recording the uncompressed size is only supported when creating a bundle in an OCI registry")
+		}
+		if b.pruneKeep > 0 {
+			return fmt.Errorf("pruning old versions is only supported when creating a bundle in an OCI registry")
+		}
+		if len(b.registryOverrides) > 0 {
+			return fmt.Errorf("registry overrides are only supported when creating a bundle in an OCI registry")
+		}
+		if slices.ContainsFunc(b.bundle.Packages, func(pkg types.Package) bool { return len(pkg.ExcludeImages) > 0 }) {
+			return fmt.Errorf("excluding images is only supported when creating a bundle in an OCI registry")
+		}
+		if b.detachedSignature {
+			return fmt.Errorf("detached signatures are only supported when creating a bundle in an OCI registry")
+		}
+		// a local bundle can also be published to OCI registries, the layers fetched into the tarball are pushed from
+		// disk so the source pkgs are only fetched once and every output has the same root manifest
+		var localOutputs, registryOutputs []string
+		for _, output := range b.outputs {
+			if utils.IsRegistryURL(output) {
+				registryOutputs = append(registryOutputs, output)
+			} else {
+				localOutputs = append(localOutputs, output)
+			}
+		}
+		if b.dstCredential != auth.EmptyCredential && len(registryOutputs) == 0 {
+			return fmt.Errorf("destination registry credentials are only supported when creating a bundle in an OCI registry")
+		}
+		if b.digestFile != "" && len(registryOutputs) == 0 {
+			return fmt.Errorf("a digest file is only supported when creating a bundle in an OCI registry")
+		}
+		if len(localOutputs) > 1 {
+			return fmt.Errorf("multiple outputs are only supported when creating a bundle in an OCI registry")
+		}
+		outputDir := ""
+		if len(localOutputs) == 1 {
+			outputDir = localOutputs[0]
+		}
+		localBundle := NewLocalBundle(&LocalBundleOpts{Bundle: b.bundle, TmpDstDir: b.tmpDstDir, SourceDir: b.sourceDir, OutputDir: outputDir, SBOMFormat: b.sbomFormat, SignatureAnnotations: b.sigAnnotations, SrcCredential: b.srcCredential, RequireSignature: b.requireSig, NoCache: b.noCache, MetadataMediaType: b.metadataMediaType, ConfigMediaType: b.configMediaType, Quiet: b.quiet, VerifySignatureKey: b.verifySigKey, ExtraFiles: b.extraFiles, OCILayout: isOCILayout(outputDir)})
+		rootManifestDesc, err := localBundle.create(ctx, b.signature)
 		if err != nil {
 			return err
 		}
+		if len(registryOutputs) > 0 {
+			if err := localBundle.publish(ctx, rootManifestDesc, registryOutputs, b.dstCredential, b.force); err != nil {
+				return err
+			}
+			b.rootManifestDesc = rootManifestDesc
+		}
+	}
+	return b.writeDigestFile()
+}
+
+// writeDigestFile writes the digest of the pushed root manifest to the digest file, if there is one, so e.g. a GitOps
+// controller can pin the bundle; the file only contains the digest, without a trailing newline
+func (b *Bundler) writeDigestFile() error {
+	if b.digestFile == "" {
+		return nil
+	}
+	if err := os.WriteFile(b.digestFile, []byte(b.rootManifestDesc.Digest.String()), 0600); err != nil {
+		return fmt.Errorf("unable to write the digest file: %w", err)
 	}
 	return nil
 }
+
+// RootManifestDesc returns the desc of the bundle's root manifest pushed by Create, it's empty unless the bundle was
+// created in (or also published to) an OCI registry without a dry run
+func (b *Bundler) RootManifestDesc() ocispec.Descriptor {
+	return b.rootManifestDesc
+}
+
+// BundleYAMLDesc returns the desc of the bundle's YAML layer pushed by Create, its content is what the bundle's
+// signature signs. To sign with an external signer (e.g. an HSM or KMS), create the bundle unsigned, sign the YAML
+// (its digest is the SHA-256 a signer that signs digests signs) and attach the signature with AttachSignature. It's
+// empty unless the bundle was created in an OCI registry without a dry run
+func (b *Bundler) BundleYAMLDesc() ocispec.Descriptor {
+	return b.bundleYAMLDesc
+}
+
+// allRegistryURLs returns true if every output is an OCI registry URL
+func allRegistryURLs(outputs []string) bool {
+	for _, output := range outputs {
+		if !utils.IsRegistryURL(output) {
+			return false
+		}
+	}
+	return true
+}
+
+// isOCILayout returns true if a local output ends with a path separator, e.g. ./oci-layout/, the bundle is written to
+// it as an OCI image layout directory instead of a tarball
+func isOCILayout(output string) bool {
+	return output != "" && (strings.HasSuffix(output, "/") || strings.HasSuffix(output, string(os.PathSeparator)))
+}
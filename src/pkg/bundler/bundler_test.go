@@ -0,0 +1,534 @@
+package bundler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/bundler/pusher"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+	ocistore "oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+func Test_CreateOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []string
+		wantErr string
+	}{
+		{
+			name:    "RegistryAndMultipleLocalDirectories",
+			outputs: []string{"oci://ghcr.io/defenseunicorns/dev", "local/path", "other/path"},
+			wantErr: "multiple outputs are only supported when creating a bundle in an OCI registry",
+		},
+		{
+			// a local bundle that's also published is created locally first, so it's validated as a local bundle
+			name:    "RegistryAndLocalDirectory",
+			outputs: []string{"oci://ghcr.io/defenseunicorns/dev", "local/path"},
+			wantErr: "architecture is required for bundling",
+		},
+		{
+			name:    "MultipleLocalDirectories",
+			outputs: []string{"local/path", "other/path"},
+			wantErr: "multiple outputs are only supported when creating a bundle in an OCI registry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: tt.outputs})
+			require.EqualError(t, b.Create(context.Background()), tt.wantErr)
+		})
+	}
+}
+
+func Test_CreateOutputFormat(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputs      []string
+		outputFormat string
+		wantErr      string
+	}{
+		{
+			name:         "UnsupportedFormat",
+			outputs:      []string{"oci://ghcr.io/defenseunicorns/dev"},
+			outputFormat: "yaml",
+			wantErr:      `unsupported output format "yaml", supported formats are "json" and "digest"`,
+		},
+		{
+			name:         "LocalDirectory",
+			outputs:      []string{"local/path"},
+			outputFormat: OutputFormatJSON,
+			wantErr:      "the json output format is only supported when creating a bundle in an OCI registry",
+		},
+		{
+			name:         "LocalDirectoryDigest",
+			outputs:      []string{"local/path"},
+			outputFormat: OutputFormatDigest,
+			wantErr:      "the digest output format is only supported when creating a bundle in an OCI registry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: tt.outputs, OutputFormat: tt.outputFormat})
+			require.EqualError(t, b.Create(context.Background()), tt.wantErr)
+		})
+	}
+}
+
+func Test_CreateDetachedSignature(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, DetachedSignature: true, SignatureReferrer: true})
+	require.EqualError(t, b.Create(context.Background()), "a detached signature can't also be attached with the OCI referrers API, choose one")
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, DetachedSignature: true})
+	require.EqualError(t, b.Create(context.Background()), "detached signatures are only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateMetricsFile(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, DryRun: true, MetricsFile: "metrics.prom"})
+	require.EqualError(t, b.Create(context.Background()), "a metrics file can't be written for a dry run since nothing is pushed")
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, MetricsFile: "metrics.prom"})
+	require.EqualError(t, b.Create(context.Background()), "a metrics file is only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateDigestTag(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, DigestTag: "long"})
+	require.EqualError(t, b.Create(context.Background()), `unsupported digest tag "long", supported digest tags are "full" and "short"`)
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, DigestTag: DigestTagShort})
+	require.EqualError(t, b.Create(context.Background()), "digest tags are only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateVulnScanner(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, VulnScanner: DenyList{}})
+	require.EqualError(t, b.Create(context.Background()), "scanning for vulnerable images is only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateUncompressedSize(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, UncompressedSize: "approximate"})
+	require.EqualError(t, b.Create(context.Background()), `unsupported uncompressed size "approximate", supported modes are "exact" and "estimate"`)
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, UncompressedSize: UncompressedSizeEstimate})
+	require.EqualError(t, b.Create(context.Background()), "recording the uncompressed size is only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateCleanupOnFailure(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, CleanupOnFailure: true})
+	require.EqualError(t, b.Create(context.Background()), "cleaning up a failed create is only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateCompressionLevel(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, CompressionLevel: 30})
+	require.EqualError(t, b.Create(context.Background()), "invalid compression level 30, it must be between 1 and 22")
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, CompressionLevel: 19})
+	require.EqualError(t, b.Create(context.Background()), "compressing layers is only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateLayerConcurrency(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, LayerConcurrency: -1})
+	require.EqualError(t, b.Create(context.Background()), "invalid layer concurrency -1, it can't be negative")
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, LayerConcurrency: 4})
+	require.EqualError(t, b.Create(context.Background()), "layer concurrency is only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateConcurrencyLimit(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, ConcurrencyLimit: -1})
+	require.EqualError(t, b.Create(context.Background()), "invalid concurrency limit -1, it can't be negative")
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, ConcurrencyLimit: 1})
+	require.EqualError(t, b.Create(context.Background()), "a concurrency limit is only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateRegistryOverrides(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, RegistryOverrides: map[string]string{"docker.io": ""}})
+	require.ErrorContains(t, b.Create(context.Background()), "invalid registry override docker.io=")
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, RegistryOverrides: map[string]string{"docker.io": "registry.internal"}})
+	require.EqualError(t, b.Create(context.Background()), "registry overrides are only supported when creating a bundle in an OCI registry")
+}
+
+func Test_CreateTransformBundle(t *testing.T) {
+	transform := func(*types.UDSBundle) error { return nil }
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, TransformBundle: transform})
+	require.EqualError(t, b.Create(context.Background()), "transforming the bundle YAML is only supported when creating a bundle in an OCI registry")
+
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Architecture: config.GetArch()}}
+	b = NewBundler(&Options{Bundle: bundle, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, TransformBundle: transform, Signature: []byte("signature")})
+	require.EqualError(t, b.Create(context.Background()), "a transformed uds-bundle.yaml can't be signed, the signature wouldn't match the pushed YAML")
+}
+
+func Test_CreateSourceMirrors(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, SourceMirrors: []string{"mirror.example.com/packages"}})
+	require.EqualError(t, b.Create(context.Background()), `invalid registry mirror "mirror.example.com/packages", it must be a registry host, e.g. mirror.example.com:5000`)
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, SourceMirrors: []string{"mirror.example.com"}})
+	require.EqualError(t, b.Create(context.Background()), "source mirrors are only supported when creating a bundle in an OCI registry")
+}
+
+func Test_fetchRootWithMirrors(t *testing.T) {
+	pkgRootManifest := ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: ocispec.DescriptorEmptyJSON}
+	pkgRootManifest.SchemaVersion = 2
+	pkgRootManifestBytes, err := json.Marshal(pkgRootManifest)
+	require.NoError(t, err)
+
+	// newRegistry serves the Zarf pkg's root manifest, or fails every request with the status
+	newRegistry := func(t *testing.T, status int) *zoci.Remote {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if status != http.StatusOK || !strings.HasPrefix(r.URL.Path, "/v2/defenseunicorns/podinfo/manifests/") {
+				w.WriteHeader(status)
+				return
+			}
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Content-Length", strconv.Itoa(len(pkgRootManifestBytes)))
+			w.Header().Set("Docker-Content-Digest", content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, pkgRootManifestBytes).Digest.String())
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(pkgRootManifestBytes)
+			}
+		}))
+		t.Cleanup(server.Close)
+		remote, err := zoci.NewRemote(strings.TrimPrefix(server.URL, "http://")+"/defenseunicorns/podinfo:0.0.1", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+		require.NoError(t, err)
+		return remote
+	}
+	r := &RemoteBundle{noCache: true, log: utils.LoggerOrDiscard(nil)}
+	ctx := context.Background()
+
+	t.Run("primary", func(t *testing.T) {
+		src := newRegistry(t, http.StatusOK)
+		fetchedFrom, root, err := r.fetchRootWithMirrors(ctx, src, []*zoci.Remote{newRegistry(t, http.StatusNotFound)}, "defenseunicorns/podinfo:0.0.1")
+		require.NoError(t, err)
+		require.Same(t, src, fetchedFrom)
+		require.Equal(t, ocispec.DescriptorEmptyJSON.Digest, root.Config.Digest)
+	})
+
+	t.Run("first mirror that has the pkg wins", func(t *testing.T) {
+		mirrors := []*zoci.Remote{newRegistry(t, http.StatusNotFound), newRegistry(t, http.StatusOK), newRegistry(t, http.StatusOK)}
+		fetchedFrom, root, err := r.fetchRootWithMirrors(ctx, newRegistry(t, http.StatusBadRequest), mirrors, "defenseunicorns/podinfo:0.0.1")
+		require.NoError(t, err)
+		require.Same(t, mirrors[1], fetchedFrom)
+		require.Equal(t, ocispec.DescriptorEmptyJSON.Digest, root.Config.Digest)
+	})
+
+	t.Run("every mirror fails", func(t *testing.T) {
+		mirror := newRegistry(t, http.StatusNotFound)
+		_, _, err := r.fetchRootWithMirrors(ctx, newRegistry(t, http.StatusBadRequest), []*zoci.Remote{mirror}, "defenseunicorns/podinfo:0.0.1")
+		require.ErrorContains(t, err, "mirror "+mirror.Repo().Reference.String())
+	})
+}
+
+func Test_CreateConfigMediaType(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, ConfigMediaType: ocispec.MediaTypeImageIndex})
+	require.EqualError(t, b.Create(context.Background()), "the config media type can't be the manifest media type "+ocispec.MediaTypeImageIndex)
+}
+
+func Test_needsConfigMediaTypeCheck(t *testing.T) {
+	require.False(t, needsConfigMediaTypeCheck(config.BundleConfigMediaType))
+	require.False(t, needsConfigMediaTypeCheck(zoci.ZarfLayerMediaTypeBlob))
+	require.True(t, needsConfigMediaTypeCheck("application/vnd.acme.bundle.config.v1+json"))
+}
+
+func Test_checkConfigMediaType(t *testing.T) {
+	const mediaType = "application/vnd.acme.bundle.config.v1+json"
+	ctx := context.Background()
+	configDigest := content.NewDescriptorFromBytes(mediaType, ocispec.DescriptorEmptyJSON.Data).Digest.String()
+	// manifestStatus is the status a manifest push is rejected with, failDeletes fails the manifest deletes while it's set
+	setup := func(t *testing.T, manifestStatus int, failDeletes *bool) (*memoryRegistry, *zoci.Remote) {
+		registry := newMemoryRegistry()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			isManifest := strings.HasPrefix(r.URL.Path, "/v2/dev/bundle/manifests/")
+			switch {
+			case isManifest && r.Method == http.MethodPut && manifestStatus != 0:
+				w.WriteHeader(manifestStatus)
+			case isManifest && r.Method == http.MethodDelete && failDeletes != nil && *failDeletes:
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				registry.ServeHTTP(w, r)
+			}
+		}))
+		t.Cleanup(server.Close)
+		bundleRemote, err := zoci.NewRemote(strings.TrimPrefix(server.URL, "http://")+"/dev/bundle:0.0.1", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+		require.NoError(t, err)
+		return registry, bundleRemote
+	}
+
+	t.Run("the manifest and config that checked the media type are deleted", func(t *testing.T) {
+		registry, bundleRemote := setup(t, 0, nil)
+		require.NoError(t, checkConfigMediaType(ctx, bundleRemote, mediaType, pusher.NewPushedBlobs()))
+		require.Empty(t, registry.manifests)
+		require.Empty(t, registry.blobs)
+	})
+
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnsupportedMediaType} {
+		t.Run(fmt.Sprintf("a %d means the media type isn't accepted", status), func(t *testing.T) {
+			registry, bundleRemote := setup(t, status, nil)
+			err := checkConfigMediaType(ctx, bundleRemote, mediaType, pusher.NewPushedBlobs())
+			require.ErrorContains(t, err, "doesn't accept the config media type "+mediaType+", set a different one with --config-media-type")
+			require.Empty(t, registry.blobs)
+		})
+	}
+
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		t.Run(fmt.Sprintf("a %d isn't reported as a rejected media type", status), func(t *testing.T) {
+			_, bundleRemote := setup(t, status, nil)
+			err := checkConfigMediaType(ctx, bundleRemote, mediaType, pusher.NewPushedBlobs())
+			require.ErrorContains(t, err, "unable to push the manifest that checks the config media type")
+			require.NotContains(t, err.Error(), "doesn't accept")
+			var errResp *errcode.ErrorResponse
+			require.ErrorAs(t, err, &errResp)
+			require.Equal(t, status, errResp.StatusCode)
+		})
+	}
+
+	t.Run("a manifest that can't be deleted is left to the cleanup", func(t *testing.T) {
+		failDeletes := true
+		registry, bundleRemote := setup(t, 0, &failDeletes)
+		pushedBlobs := pusher.NewPushedBlobs()
+		require.NoError(t, checkConfigMediaType(ctx, bundleRemote, mediaType, pushedBlobs))
+		require.Len(t, registry.manifests, 1)
+		require.NotContains(t, registry.blobs, configDigest)
+
+		// the cleanup of a failed create deletes it
+		failDeletes = false
+		require.Empty(t, pushedBlobs.Delete(ctx))
+		require.Empty(t, registry.manifests)
+	})
+}
+
+func Test_CreateDigestFile(t *testing.T) {
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"oci://ghcr.io/defenseunicorns/dev"}, DryRun: true, DigestFile: "digest"})
+	require.EqualError(t, b.Create(context.Background()), "a digest file can't be written for a dry run since nothing is pushed")
+
+	b = NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, DigestFile: "digest"})
+	require.EqualError(t, b.Create(context.Background()), "a digest file is only supported when creating a bundle in an OCI registry")
+
+	path := filepath.Join(t.TempDir(), "digest")
+	b = NewBundler(&Options{DigestFile: path})
+	b.rootManifestDesc = content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("root manifest"))
+	require.NoError(t, b.writeDigestFile())
+	digest, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, b.rootManifestDesc.Digest.String(), string(digest))
+}
+
+func Test_CreateCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bundle := &types.UDSBundle{
+		Metadata: types.UDSMetadata{Name: "test", Version: "0.0.1", Architecture: config.GetArch()},
+		Build:    types.UDSBuildData{Architecture: config.GetArch()},
+		Packages: []types.Package{{Name: "podinfo", Repository: "localhost:888/podinfo", Ref: "0.0.1"}},
+	}
+	b := NewBundler(&Options{Bundle: bundle, Outputs: []string{"oci://localhost:888/dev"}, NoCache: true})
+	require.ErrorIs(t, b.Create(ctx), context.Canceled)
+
+	// a local bundle fetches its pkgs with the create's context too
+	b = NewBundler(&Options{Bundle: bundle, Outputs: []string{t.TempDir()}, NoCache: true})
+	require.ErrorIs(t, b.Create(ctx), context.Canceled)
+}
+
+func Test_CreateTmpDir(t *testing.T) {
+	// the base is created if it doesn't exist yet
+	base := filepath.Join(t.TempDir(), "tmp")
+	requireEmpty := func() {
+		entries, err := os.ReadDir(base)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	}
+
+	b := NewBundler(&Options{Bundle: &types.UDSBundle{}, Outputs: []string{"local/path"}, TmpDir: base})
+	require.EqualError(t, b.Create(context.Background()), "architecture is required for bundling")
+	require.NotEmpty(t, b.tmpDstDir)
+	require.Equal(t, base, filepath.Dir(b.tmpDstDir))
+	requireEmpty()
+
+	// the tmp dir is removed when a panic unwinds the create, a nil bundle panics once the tmp dir is made
+	b = NewBundler(&Options{Outputs: []string{"local/path"}, TmpDir: base})
+	require.Panics(t, func() { _ = b.Create(context.Background()) })
+	requireEmpty()
+}
+
+func Test_removeTmpDir(t *testing.T) {
+	dir, err := makeTmpDir(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "blobs", "sha256", "layer"), []byte("layer"), 0600))
+	require.NoError(t, removeTmpDir(dir))
+	require.NoDirExists(t, dir)
+}
+
+func Test_addDetachedSignatureAnnotations(t *testing.T) {
+	signatureDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("signature"))
+	rootManifest := ocispec.Manifest{Annotations: map[string]string{ocispec.AnnotationDescription: "bundle"}}
+	addDetachedSignatureAnnotations(&rootManifest, signatureDesc, map[string]string{config.BundleSignatureCertificateAnnotation: "cert"})
+	require.Equal(t, map[string]string{
+		ocispec.AnnotationDescription:               "bundle",
+		config.BundleSignatureCertificateAnnotation: "cert",
+		config.BundleSignatureDigestAnnotation:      signatureDesc.Digest.String(),
+	}, rootManifest.Annotations)
+	require.Empty(t, rootManifest.Layers)
+}
+
+func Test_resignedRootManifest(t *testing.T) {
+	pkgManifest := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("pkg"))
+	bundleYAML := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("bundle"))
+	bundleYAML.Annotations = map[string]string{ocispec.AnnotationTitle: config.BundleYAML}
+	oldSignature := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("old"))
+	oldSignature.Annotations = signatureLayerAnnotations(map[string]string{config.BundleSignatureCertificateAnnotation: "cert"})
+	newSignature := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("new"))
+	signed := newSignature
+	signed.Annotations = signatureLayerAnnotations(nil)
+
+	t.Run("replaces the signature layer", func(t *testing.T) {
+		root := ocispec.Manifest{Layers: []ocispec.Descriptor{pkgManifest, bundleYAML, oldSignature}}
+		resigned := resignedRootManifest(root, newSignature, nil)
+		require.Equal(t, []ocispec.Descriptor{pkgManifest, bundleYAML, signed}, resigned.Layers)
+		require.Equal(t, oldSignature, root.Layers[2])
+	})
+	t.Run("signs an unsigned bundle after its YAML", func(t *testing.T) {
+		sbom := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("sbom"))
+		sbom.Annotations = map[string]string{ocispec.AnnotationTitle: config.BundleSBOMJSON}
+		root := ocispec.Manifest{Layers: []ocispec.Descriptor{pkgManifest, bundleYAML, sbom}}
+		resigned := resignedRootManifest(root, newSignature, nil)
+		require.Equal(t, []ocispec.Descriptor{pkgManifest, bundleYAML, signed, sbom}, resigned.Layers)
+	})
+	t.Run("keeps a detached signature detached", func(t *testing.T) {
+		root := ocispec.Manifest{
+			Layers: []ocispec.Descriptor{pkgManifest, bundleYAML},
+			Annotations: map[string]string{
+				ocispec.AnnotationDescription:                 "bundle",
+				config.BundleSignatureDigestAnnotation:        oldSignature.Digest.String(),
+				config.BundleSignatureCertificateAnnotation:   "cert",
+				config.BundleSignatureRekorLogIndexAnnotation: "1",
+			},
+		}
+		resigned := resignedRootManifest(root, newSignature, nil)
+		require.Equal(t, root.Layers, resigned.Layers)
+		require.Equal(t, map[string]string{
+			ocispec.AnnotationDescription:          "bundle",
+			config.BundleSignatureDigestAnnotation: newSignature.Digest.String(),
+		}, resigned.Annotations)
+		require.Equal(t, oldSignature.Digest.String(), root.Annotations[config.BundleSignatureDigestAnnotation])
+	})
+}
+
+func Test_AttachSignature(t *testing.T) {
+	ctx := context.Background()
+	registry := newMemoryRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	rootManifestDesc, _ := registry.pushVersion(t, "0.0.1")
+	remote, err := zoci.NewRemote(strings.TrimPrefix(server.URL, "http://")+"/dev/bundle:0.0.1", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Name: "bundle", Version: "0.0.1", Architecture: "amd64"}}
+
+	_, err = AttachSignature(ctx, remote, bundle, rootManifestDesc, ResignOptions{})
+	require.EqualError(t, err, "a signature is required to attach it to a bundle")
+
+	// the bundle was re-created after the root manifest was pushed
+	other := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("other"))
+	_, err = AttachSignature(ctx, remote, bundle, other, ResignOptions{Signature: []byte("signature")})
+	require.ErrorContains(t, err, "no longer references the root manifest "+other.Digest.String())
+
+	signedDesc, err := AttachSignature(ctx, remote, bundle, rootManifestDesc, ResignOptions{Signature: []byte("signature")})
+	require.NoError(t, err)
+	require.NotEqual(t, rootManifestDesc.Digest, signedDesc.Digest)
+	index, err := utils.GetIndex(ctx, remote.OrasRemote, remote.Repo().Reference.String())
+	require.NoError(t, err)
+	require.Len(t, index.Manifests, 1)
+	require.Equal(t, signedDesc.Digest, index.Manifests[0].Digest)
+	signed, err := remote.FetchManifest(ctx, signedDesc)
+	require.NoError(t, err)
+	signatureDesc := signed.Locate(config.BundleYAMLSignature)
+	require.Equal(t, content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("signature")).Digest, signatureDesc.Digest)
+	require.Contains(t, registry.blobs, signatureDesc.Digest.String())
+}
+
+func Test_PkgRootKey(t *testing.T) {
+	base := types.Package{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/uds-cli/podinfo", Ref: "0.0.1"}
+	renamed := base
+	renamed.Name = "podinfo-copy"
+	otherRef := base
+	otherRef.Ref = "0.0.2"
+	otherArch := base
+	otherArch.Arch = "riscv64"
+
+	// packages that resolve to the same root manifest share a key regardless of their name in the bundle
+	require.Equal(t, pkgRootKey(base), pkgRootKey(renamed))
+	require.NotEqual(t, pkgRootKey(base), pkgRootKey(otherRef))
+	require.NotEqual(t, pkgRootKey(base), pkgRootKey(otherArch))
+
+	// local packages are keyed by their path
+	local := types.Package{Name: "podinfo", Path: "zarf-package-podinfo-amd64-0.0.1.tar.zst", Ref: "0.0.1"}
+	otherLocal := local
+	otherLocal.Path = "build/zarf-package-podinfo-amd64-0.0.1.tar.zst"
+	require.NotEqual(t, pkgRootKey(local), pkgRootKey(otherLocal))
+}
+
+func Test_writeOCILayout(t *testing.T) {
+	ctx := context.Background()
+	require.True(t, isOCILayout("./oci-layout/"))
+	require.False(t, isOCILayout("./build"))
+	require.False(t, isOCILayout(""))
+
+	tmp := t.TempDir()
+	store, err := ocistore.NewWithContext(ctx, tmp)
+	require.NoError(t, err)
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Name: "example", Version: "0.0.1"}}
+	artifactPathMap := make(types.PathMap)
+	push := func(mediaType string, b []byte) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes(mediaType, b)
+		require.NoError(t, store.Push(ctx, desc, bytes.NewReader(b)))
+		artifactPathMap[filepath.Join(tmp, config.BlobsDir, desc.Digest.Encoded())] = filepath.Join(config.BlobsDir, desc.Digest.Encoded())
+		return desc
+	}
+	bundleYAML := push(zoci.ZarfLayerMediaTypeBlob, []byte("kind: UDSBundle"))
+	manifestConfig := push(config.BundleConfigMediaType, []byte(`{"name":"example"}`))
+	manifestBytes, err := json.Marshal(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: manifestConfig, Layers: []ocispec.Descriptor{bundleYAML}})
+	require.NoError(t, err)
+	rootManifestDesc := push(ocispec.MediaTypeImageManifest, manifestBytes)
+	require.NoError(t, store.Tag(ctx, rootManifestDesc, bundle.Metadata.Version))
+	require.NoError(t, cleanIndexJSON(tmp, rootManifestDesc))
+	artifactPathMap[filepath.Join(tmp, "index.json")] = "index.json"
+	artifactPathMap[filepath.Join(tmp, "oci-layout")] = "oci-layout"
+
+	outputDir := filepath.Join(t.TempDir(), "oci-layout")
+	require.NoError(t, writeOCILayout(bundle, artifactPathMap, outputDir, true))
+
+	// the layout can be read by its version like any OCI layout, e.g. by skopeo copy oci:oci-layout:0.0.1
+	layout, err := ocistore.NewWithContext(ctx, outputDir)
+	require.NoError(t, err)
+	desc, err := layout.Resolve(ctx, bundle.Metadata.Version)
+	require.NoError(t, err)
+	require.Equal(t, rootManifestDesc.Digest, desc.Digest)
+	for _, blob := range []ocispec.Descriptor{rootManifestDesc, manifestConfig, bundleYAML} {
+		exists, err := layout.Exists(ctx, blob)
+		require.NoError(t, err)
+		require.True(t, exists, "%s wasn't written", blob.Digest)
+	}
+	layoutFile, err := os.ReadFile(filepath.Join(outputDir, ocispec.ImageLayoutFile))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"imageLayoutVersion":"1.0.0"}`, string(layoutFile))
+
+	// writing the bundle again to the same layout keeps its blobs
+	require.NoError(t, writeOCILayout(bundle, artifactPathMap, outputDir, true))
+}
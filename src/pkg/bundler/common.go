@@ -5,26 +5,71 @@
 package bundler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/defenseunicorns/pkg/helpers"
 	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	"github.com/defenseunicorns/uds-cli/src/types"
 	"github.com/defenseunicorns/zarf/src/pkg/message"
-	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	goyaml "github.com/goccy/go-yaml"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2/registry"
 )
 
+// reservedAnnotationPrefix is the annotation prefix reserved by the OCI image spec
+const reservedAnnotationPrefix = "org.opencontainers."
+
+const (
+	// dockerManifestMediaType is the media type of a Docker image manifest
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	// dockerManifestListMediaType is the media type of a Docker manifest list
+	dockerManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// BundleTransformFn mutates the bundle before its YAML is pushed, e.g. to redact internal-only fields; it's passed a
+// copy of the bundle so the Zarf pkgs are still pushed from the original
+type BundleTransformFn func(bundle *types.UDSBundle) error
+
+// bundleYAML marshals the bundle's YAML layer, if there's a transform it's applied to a deep copy of the bundle
+func bundleYAML(bundle *types.UDSBundle, transform BundleTransformFn) ([]byte, error) {
+	b, err := goyaml.Marshal(bundle)
+	if err != nil || transform == nil {
+		return b, err
+	}
+	var transformed types.UDSBundle
+	if err := goyaml.Unmarshal(b, &transformed); err != nil {
+		return nil, err
+	}
+	if err := transform(&transformed); err != nil {
+		return nil, fmt.Errorf("unable to transform %s: %w", config.BundleYAML, err)
+	}
+	return goyaml.Marshal(&transformed)
+}
+
 // copied from: https://github.com/defenseunicorns/zarf/blob/main/src/pkg/oci/push.go
-func manifestAnnotationsFromMetadata(metadata *types.UDSMetadata) map[string]string {
+// the bundle's build timestamp is recorded as the created annotation, it's taken from SOURCE_DATE_EPOCH when that's set
+// so SOURCE_DATE_EPOCH=0 zeroes it
+func manifestAnnotationsFromMetadata(metadata *types.UDSMetadata, build *types.UDSBuildData) map[string]string {
 	annotations := map[string]string{
 		ocispec.AnnotationDescription: metadata.Description,
 	}
 
+	if created, err := time.Parse(time.RFC1123Z, build.Timestamp); err == nil {
+		annotations[ocispec.AnnotationCreated] = created.UTC().Format(time.RFC3339)
+	}
+
 	if url := metadata.URL; url != "" {
 		annotations[ocispec.AnnotationURL] = url
 	}
@@ -41,27 +86,142 @@ func manifestAnnotationsFromMetadata(metadata *types.UDSMetadata) map[string]str
 		annotations[ocispec.AnnotationVendor] = vendor
 	}
 
+	// user-defined annotations take precedence over the ones derived from metadata
+	for key, value := range metadata.Annotations {
+		if strings.HasPrefix(key, reservedAnnotationPrefix) {
+			message.Warnf("Overriding reserved OCI annotation %s with %q", key, value)
+		}
+		annotations[key] = value
+	}
+
 	return annotations
 }
 
-// copied from: https://github.com/defenseunicorns/zarf/blob/main/src/pkg/oci/push.go
-func pushManifestConfigFromMetadata(r *oci.OrasRemote, metadata *types.UDSMetadata, build *types.UDSBuildData) (ocispec.Descriptor, error) {
+// packagesAnnotation returns the JSON array of the bundle's Zarf pkgs recorded on the root manifest so tools can list
+// them without fetching any layers, zarfManifestDescs must be in the same order as the bundle's packages
+func packagesAnnotation(bundle *types.UDSBundle, zarfManifestDescs []ocispec.Descriptor) (string, error) {
+	pkgs := make([]types.BundlePackageDigest, 0, len(bundle.Packages))
+	for i, pkg := range bundle.Packages {
+		pkgs = append(pkgs, types.BundlePackageDigest{Name: pkg.Name, Ref: pkg.Ref, Digest: zarfManifestDescs[i].Digest.String()})
+	}
+	b, err := json.Marshal(pkgs)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ExtraFile is a file pushed as a layer alongside the bundle's YAML, e.g. a LICENSE or a deploy README
+type ExtraFile struct {
+	// Path is the file's path as listed in the bundle's extraFiles, it's the layer's title
+	Path     string
+	Contents []byte
+}
+
+// extraFileAnnotations returns the annotations of an extra file's layer
+func extraFileAnnotations(file ExtraFile) map[string]string {
+	return map[string]string{
+		ocispec.AnnotationTitle:          file.Path,
+		config.BundleExtraFileAnnotation: "true",
+	}
+}
+
+// signatureLayerAnnotations returns the annotations of the bundle's signature layer
+func signatureLayerAnnotations(sigAnnotations map[string]string) map[string]string {
 	annotations := map[string]string{
-		ocispec.AnnotationTitle:       metadata.Name,
-		ocispec.AnnotationDescription: metadata.Description,
+		ocispec.AnnotationTitle: config.BundleYAMLSignature,
 	}
-	manifestConfig := oci.ConfigPartial{
-		Architecture: build.Architecture,
-		OCIVersion:   "1.0.1",
-		Annotations:  annotations,
+	for key, value := range sigAnnotations {
+		annotations[key] = value
 	}
-	manifestConfigDesc, err := utils.ToOCIRemote(manifestConfig, zoci.ZarfLayerMediaTypeBlob, r)
+	return annotations
+}
+
+// copied from: https://github.com/defenseunicorns/zarf/blob/main/src/pkg/oci/push.go
+func pushManifestConfigFromMetadata(ctx context.Context, r *oci.OrasRemote, metadata *types.UDSMetadata, build *types.UDSBuildData, mediaType string) (ocispec.Descriptor, error) {
+	manifestConfig := manifestConfigFromMetadata(metadata, build)
+	var manifestConfigDesc *ocispec.Descriptor
+	err := utils.RetryOCI(ctx, "push manifest config", func() (err error) {
+		manifestConfigDesc, err = utils.ToOCIRemote(ctx, manifestConfig, mediaType, r)
+		return err
+	})
 	if err != nil {
 		return ocispec.Descriptor{}, err
 	}
 	return *manifestConfigDesc, nil
 }
 
+// manifestConfig is the config of the bundle's root manifest, a Zarf pkg config with the bundle's optional provenance
+type manifestConfig struct {
+	oci.ConfigPartial
+	Provenance *types.UDSProvenance `json:"provenance,omitempty"`
+}
+
+// manifestConfigFromMetadata returns the config of the bundle's root manifest
+func manifestConfigFromMetadata(metadata *types.UDSMetadata, build *types.UDSBuildData) manifestConfig {
+	annotations := map[string]string{
+		ocispec.AnnotationTitle:       metadata.Name,
+		ocispec.AnnotationDescription: metadata.Description,
+	}
+	return manifestConfig{
+		ConfigPartial: oci.ConfigPartial{
+			Architecture: build.Architecture,
+			OCIVersion:   "1.0.1",
+			Annotations:  annotations,
+		},
+		Provenance: build.Provenance,
+	}
+}
+
+// checkBundlePlatform returns an error if the arch in the bundle's config doesn't match the platform its root manifest
+// is published under, e.g. metadata.architecture was set to a different arch than the CLI's
+func checkBundlePlatform(build *types.UDSBuildData, platform ocispec.Platform) error {
+	if build.Architecture != platform.Architecture {
+		return fmt.Errorf("the bundle's architecture %s doesn't match the %s platform its root manifest is published for, set the architecture with --architecture", build.Architecture, platform.Architecture)
+	}
+	return nil
+}
+
+// checkPkgPlatform returns an error if the arch in a Zarf pkg's config doesn't match the platform it was fetched for,
+// e.g. the pkg's tag is a single-arch manifest for a different arch instead of an index
+func checkPkgPlatform(pkg types.Package, pkgConfig oci.ConfigPartial) error {
+	return utils.CheckPkgArch(pkg, pkgConfig.Architecture)
+}
+
+// checkMediaTypes returns an error listing every media type of the Zarf pkgs' configs and layers that isn't allowed,
+// with the pkgs that use it; the layers of a pruned pkg are checked instead of the source pkg's since the excluded
+// images aren't pushed. Every media type is allowed if allowed is empty
+func checkMediaTypes(pkgs []types.Package, pkgRootManifests []*oci.Manifest, prunedPkgs []*utils.PrunedPackage, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	var unsupported []string
+	usedBy := make(map[string][]string)
+	for i, pkg := range pkgs {
+		root := pkgRootManifests[i]
+		if i < len(prunedPkgs) && prunedPkgs[i] != nil {
+			root = prunedPkgs[i].Root
+		}
+		for _, desc := range append([]ocispec.Descriptor{root.Config}, root.Layers...) {
+			if desc.Digest == "" || slices.Contains(allowed, desc.MediaType) || slices.Contains(usedBy[desc.MediaType], pkg.Name) {
+				continue
+			}
+			if _, ok := usedBy[desc.MediaType]; !ok {
+				unsupported = append(unsupported, desc.MediaType)
+			}
+			usedBy[desc.MediaType] = append(usedBy[desc.MediaType], pkg.Name)
+		}
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+	details := make([]string, len(unsupported))
+	for i, mediaType := range unsupported {
+		details[i] = fmt.Sprintf("%s (%s)", mediaType, strings.Join(usedBy[mediaType], ", "))
+	}
+	return fmt.Errorf("packages have layers with media types that aren't allowed by --allowed-media-types: %s", strings.Join(details, "; "))
+}
+
 // copied from: https://github.com/defenseunicorns/zarf/blob/main/src/pkg/oci/utils.go
 func referenceFromMetadata(registryLocation string, metadata *types.UDSMetadata) (string, error) {
 	ver := metadata.Version
@@ -83,3 +243,105 @@ func referenceFromMetadata(registryLocation string, metadata *types.UDSMetadata)
 
 	return ref.String(), nil
 }
+
+// BundleReference returns the reference a bundle is pushed to when it's created in a registry output, i.e.
+// <output>/<name>:<version>
+func BundleReference(output string, metadata *types.UDSMetadata) (registry.Reference, error) {
+	ref, err := referenceFromMetadata(utils.EnsureOCIPrefix(output), metadata)
+	if err != nil {
+		return registry.Reference{}, err
+	}
+	return registry.ParseReference(ref)
+}
+
+// RegistryOutput returns the output for creating a bundle in registryLocation, a registry optionally followed by a
+// namespace (e.g. ghcr.io/defenseunicorns), the bundle is pushed to <registryLocation>/<name>:<version>
+func RegistryOutput(registryLocation string, metadata *types.UDSMetadata) (string, error) {
+	location := strings.TrimSuffix(strings.TrimPrefix(registryLocation, helpers.OCIURLPrefix), "/")
+	if location == "" {
+		return "", errors.New("a registry is required")
+	}
+	if strings.Contains(location, "://") {
+		return "", fmt.Errorf("invalid registry %q, the only supported scheme is %s", registryLocation, helpers.OCIURLPrefix)
+	}
+	// the tag and digest come from the bundle's metadata, only the registry's host can contain a colon (its port)
+	if _, path, ok := strings.Cut(location, "/"); ok && strings.ContainsAny(path, ":@") {
+		return "", fmt.Errorf("invalid registry %q, it can't include a tag or digest since the bundle's version is used", registryLocation)
+	}
+
+	output := helpers.OCIURLPrefix + location
+	ref, err := referenceFromMetadata(output, metadata)
+	if err != nil {
+		return "", fmt.Errorf("unable to compose a reference for bundle %s in registry %q: %w", metadata.Name, registryLocation, err)
+	}
+	message.Debugf("Bundle %s will be pushed to %s", metadata.Name, ref)
+	return output, nil
+}
+
+// checkSignature returns an error if a signature is required but the bundle wasn't signed
+func checkSignature(bundle *types.UDSBundle, requireSignature bool, signature []byte) error {
+	if requireSignature && len(signature) == 0 {
+		return fmt.Errorf("bundle %s isn't signed but a signature is required, sign it with --signing-key or --sign-with-cosign-keyless", bundle.Metadata.Name)
+	}
+	return nil
+}
+
+// verifySignature checks the signature against the exact bundle YAML that's pushed with the public key, so a stale
+// signature fails the create instead of the deploy. It's only verified if a public key is provided
+func verifySignature(tmpDir string, bundleYAML []byte, signature []byte, publicKeyPath string) error {
+	if publicKeyPath == "" {
+		return nil
+	}
+	if len(signature) == 0 {
+		return fmt.Errorf("a public key was provided to verify the bundle's signature, but the bundle isn't signed")
+	}
+	dir, err := os.MkdirTemp(tmpDir, "signature-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	bundleYAMLPath := filepath.Join(dir, config.BundleYAML)
+	signaturePath := filepath.Join(dir, config.BundleYAMLSignature)
+	if err := os.WriteFile(bundleYAMLPath, bundleYAML, 0600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(signaturePath, signature, 0600); err != nil {
+		return err
+	}
+	if err := zarfUtils.CosignVerifyBlob(bundleYAMLPath, signaturePath, publicKeyPath); err != nil {
+		return fmt.Errorf("the bundle's signature doesn't match the %s being pushed: %w", config.BundleYAML, err)
+	}
+	return nil
+}
+
+// validateMetadataMediaType validates a custom media type for the bundle's YAML and signature layers, it can't be a
+// manifest or index media type since those layers would then be walked as manifests when the bundle is pulled
+func validateMetadataMediaType(mediaType string) error {
+	if mediaType == "" {
+		return nil
+	}
+	if _, _, err := mime.ParseMediaType(mediaType); err != nil {
+		return fmt.Errorf("invalid metadata media type %q: %w", mediaType, err)
+	}
+	switch mediaType {
+	case ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex, dockerManifestMediaType, dockerManifestListMediaType:
+		return fmt.Errorf("the metadata media type can't be the manifest media type %s", mediaType)
+	}
+	return nil
+}
+
+// validateConfigMediaType validates a custom media type for the bundle's manifest config, it can't be a manifest or
+// index media type since the config would then be walked as a manifest when the bundle is pulled
+func validateConfigMediaType(mediaType string) error {
+	if mediaType == "" {
+		return nil
+	}
+	if _, _, err := mime.ParseMediaType(mediaType); err != nil {
+		return fmt.Errorf("invalid config media type %q: %w", mediaType, err)
+	}
+	switch mediaType {
+	case ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex, dockerManifestMediaType, dockerManifestListMediaType:
+		return fmt.Errorf("the config media type can't be the manifest media type %s", mediaType)
+	}
+	return nil
+}
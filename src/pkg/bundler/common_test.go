@@ -0,0 +1,344 @@
+package bundler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_manifestAnnotationsFromMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata types.UDSMetadata
+		build    types.UDSBuildData
+		want     map[string]string
+	}{
+		{
+			name:     "NoCustomAnnotations",
+			metadata: types.UDSMetadata{Description: "desc", Source: "https://github.com/defenseunicorns/uds-cli"},
+			want: map[string]string{
+				ocispec.AnnotationDescription: "desc",
+				ocispec.AnnotationSource:      "https://github.com/defenseunicorns/uds-cli",
+			},
+		},
+		{
+			name: "CustomAnnotationsAreMerged",
+			metadata: types.UDSMetadata{
+				Description: "desc",
+				Annotations: map[string]string{"com.myorg.team": "unicorns"},
+			},
+			want: map[string]string{
+				ocispec.AnnotationDescription: "desc",
+				"com.myorg.team":              "unicorns",
+			},
+		},
+		{
+			name: "CustomAnnotationsOverrideMetadata",
+			metadata: types.UDSMetadata{
+				Description: "desc",
+				Source:      "https://github.com/defenseunicorns/uds-cli",
+				Annotations: map[string]string{ocispec.AnnotationSource: "https://example.com/mirror"},
+			},
+			want: map[string]string{
+				ocispec.AnnotationDescription: "desc",
+				ocispec.AnnotationSource:      "https://example.com/mirror",
+			},
+		},
+		{
+			name:     "CreatedAndAuthors",
+			metadata: types.UDSMetadata{Description: "desc", Authors: "Doug <hello@defenseunicorns.com>"},
+			build:    types.UDSBuildData{Timestamp: "Tue, 02 Jan 2024 10:04:05 +0700"},
+			want: map[string]string{
+				ocispec.AnnotationDescription: "desc",
+				ocispec.AnnotationAuthors:     "Doug <hello@defenseunicorns.com>",
+				ocispec.AnnotationCreated:     "2024-01-02T03:04:05Z",
+			},
+		},
+		{
+			name:     "ZeroedCreated",
+			metadata: types.UDSMetadata{Description: "desc"},
+			build:    types.UDSBuildData{Timestamp: "Thu, 01 Jan 1970 00:00:00 +0000"},
+			want: map[string]string{
+				ocispec.AnnotationDescription: "desc",
+				ocispec.AnnotationCreated:     "1970-01-01T00:00:00Z",
+			},
+		},
+		{
+			name: "CustomAnnotationsOverrideCreatedAndAuthors",
+			metadata: types.UDSMetadata{
+				Description: "desc",
+				Authors:     "Doug <hello@defenseunicorns.com>",
+				Annotations: map[string]string{ocispec.AnnotationCreated: "2000-01-01T00:00:00Z", ocispec.AnnotationAuthors: "Pepr"},
+			},
+			build: types.UDSBuildData{Timestamp: "Tue, 02 Jan 2024 10:04:05 +0700"},
+			want: map[string]string{
+				ocispec.AnnotationDescription: "desc",
+				ocispec.AnnotationAuthors:     "Pepr",
+				ocispec.AnnotationCreated:     "2000-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, manifestAnnotationsFromMetadata(&tt.metadata, &tt.build))
+		})
+	}
+}
+
+func Test_packagesAnnotation(t *testing.T) {
+	bundle := &types.UDSBundle{Packages: []types.Package{
+		{Name: "podinfo", Ref: "0.0.1@sha256:abc"},
+		{Name: "nginx", Ref: "0.0.2"},
+	}}
+	descs := []ocispec.Descriptor{
+		{Digest: digest.FromString("podinfo")},
+		{Digest: digest.FromString("nginx")},
+	}
+	annotation, err := packagesAnnotation(bundle, descs)
+	require.NoError(t, err)
+	var pkgs []types.BundlePackageDigest
+	require.NoError(t, json.Unmarshal([]byte(annotation), &pkgs))
+	require.Equal(t, []types.BundlePackageDigest{
+		{Name: "podinfo", Ref: "0.0.1@sha256:abc", Digest: digest.FromString("podinfo").String()},
+		{Name: "nginx", Ref: "0.0.2", Digest: digest.FromString("nginx").String()},
+	}, pkgs)
+}
+
+func Test_manifestConfigFromMetadata(t *testing.T) {
+	metadata := types.UDSMetadata{Name: "bundle", Description: "desc"}
+	build := types.UDSBuildData{Architecture: "amd64"}
+
+	// without provenance the config is the same as a Zarf pkg's so existing bundles keep their digests
+	b, err := json.Marshal(manifestConfigFromMetadata(&metadata, &build))
+	require.NoError(t, err)
+	want, err := json.Marshal(oci.ConfigPartial{
+		Architecture: "amd64",
+		OCIVersion:   "1.0.1",
+		Annotations:  map[string]string{ocispec.AnnotationTitle: "bundle", ocispec.AnnotationDescription: "desc"},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, string(want), string(b))
+	require.NotContains(t, string(b), "provenance")
+
+	build.Provenance = &types.UDSProvenance{
+		CLIVersion: "v0.10.0",
+		Timestamp:  "2024-01-02T03:04:05Z",
+		Packages:   []types.PackageProvenance{{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/podinfo", Digest: "sha256:abc"}},
+	}
+	b, err = json.Marshal(manifestConfigFromMetadata(&metadata, &build))
+	require.NoError(t, err)
+	var config struct {
+		Architecture string               `json:"architecture"`
+		Provenance   *types.UDSProvenance `json:"provenance"`
+	}
+	require.NoError(t, json.Unmarshal(b, &config))
+	require.Equal(t, "amd64", config.Architecture)
+	require.Equal(t, build.Provenance, config.Provenance)
+}
+
+func Test_bundleYAML(t *testing.T) {
+	bundle := &types.UDSBundle{
+		Metadata: types.UDSMetadata{Name: "test", Version: "0.0.1", Description: "internal notes"},
+		Packages: []types.Package{{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/podinfo", Ref: "0.0.1"}},
+	}
+	untransformed, err := goyaml.Marshal(bundle)
+	require.NoError(t, err)
+
+	b, err := bundleYAML(bundle, nil)
+	require.NoError(t, err)
+	require.Equal(t, untransformed, b)
+
+	// the transform only changes the pushed YAML, not the bundle the Zarf pkgs are pushed from
+	b, err = bundleYAML(bundle, func(bundle *types.UDSBundle) error {
+		bundle.Metadata.Description = ""
+		bundle.Packages[0].Repository = "registry.internal/podinfo"
+		return nil
+	})
+	require.NoError(t, err)
+	var pushed types.UDSBundle
+	require.NoError(t, goyaml.Unmarshal(b, &pushed))
+	require.Empty(t, pushed.Metadata.Description)
+	require.Equal(t, "registry.internal/podinfo", pushed.Packages[0].Repository)
+	require.Equal(t, "internal notes", bundle.Metadata.Description)
+	require.Equal(t, "ghcr.io/defenseunicorns/podinfo", bundle.Packages[0].Repository)
+
+	_, err = bundleYAML(bundle, func(*types.UDSBundle) error { return errors.New("redaction failed") })
+	require.EqualError(t, err, "unable to transform uds-bundle.yaml: redaction failed")
+}
+
+func Test_verifySignature(t *testing.T) {
+	dir := t.TempDir()
+	password := func(bool) ([]byte, error) { return []byte("password"), nil }
+	keys, err := cosign.GenerateKeyPair(password)
+	require.NoError(t, err)
+	privateKeyPath := filepath.Join(dir, "cosign.key")
+	publicKeyPath := filepath.Join(dir, "cosign.pub")
+	require.NoError(t, os.WriteFile(privateKeyPath, keys.PrivateBytes, 0600))
+	require.NoError(t, os.WriteFile(publicKeyPath, keys.PublicBytes, 0600))
+
+	bundleYAML := []byte("metadata:\n  name: test\n  version: 0.0.1\n")
+	bundleYAMLPath := filepath.Join(dir, config.BundleYAML)
+	require.NoError(t, os.WriteFile(bundleYAMLPath, bundleYAML, 0600))
+	signature, err := zarfUtils.CosignSignBlob(bundleYAMLPath, filepath.Join(dir, config.BundleYAMLSignature), privateKeyPath, password)
+	require.NoError(t, err)
+
+	require.NoError(t, verifySignature(dir, bundleYAML, signature, publicKeyPath))
+	// without a public key the signature isn't verified
+	require.NoError(t, verifySignature(dir, []byte("stale"), signature, ""))
+
+	err = verifySignature(dir, []byte("metadata:\n  name: test\n  version: 0.0.2\n"), signature, publicKeyPath)
+	require.ErrorContains(t, err, "the bundle's signature doesn't match the uds-bundle.yaml being pushed")
+	err = verifySignature(dir, bundleYAML, nil, publicKeyPath)
+	require.EqualError(t, err, "a public key was provided to verify the bundle's signature, but the bundle isn't signed")
+}
+
+func Test_signatureLayerAnnotations(t *testing.T) {
+	require.Equal(t, map[string]string{ocispec.AnnotationTitle: config.BundleYAMLSignature}, signatureLayerAnnotations(nil))
+
+	annotations := signatureLayerAnnotations(map[string]string{config.BundleSignatureRekorLogIndexAnnotation: "42"})
+	require.Equal(t, map[string]string{
+		ocispec.AnnotationTitle:                       config.BundleYAMLSignature,
+		config.BundleSignatureRekorLogIndexAnnotation: "42",
+	}, annotations)
+}
+
+func Test_checkSignature(t *testing.T) {
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Name: "test"}}
+	require.NoError(t, checkSignature(bundle, false, nil))
+	require.NoError(t, checkSignature(bundle, true, []byte("signature")))
+	require.ErrorContains(t, checkSignature(bundle, true, nil), "bundle test isn't signed")
+}
+
+func Test_validateMetadataMediaType(t *testing.T) {
+	require.NoError(t, validateMetadataMediaType(""))
+	require.NoError(t, validateMetadataMediaType("application/vnd.acme.bundle.layer.v1+yaml"))
+	require.Error(t, validateMetadataMediaType("not a media type"))
+	require.Error(t, validateMetadataMediaType(ocispec.MediaTypeImageManifest))
+	require.Error(t, validateMetadataMediaType(dockerManifestListMediaType))
+}
+
+func Test_validateConfigMediaType(t *testing.T) {
+	require.NoError(t, validateConfigMediaType(""))
+	require.NoError(t, validateConfigMediaType("application/vnd.acme.scanner.config.v1+json"))
+	require.Error(t, validateConfigMediaType("not a media type"))
+	require.Error(t, validateConfigMediaType(ocispec.MediaTypeImageManifest))
+	require.Error(t, validateConfigMediaType(ocispec.MediaTypeImageIndex))
+}
+
+func Test_RegistryOutput(t *testing.T) {
+	metadata := &types.UDSMetadata{Name: "example", Version: "0.0.1"}
+	tests := []struct {
+		name     string
+		registry string
+		metadata *types.UDSMetadata
+		want     string
+		wantErr  string
+	}{
+		{name: "Host", registry: "ghcr.io", metadata: metadata, want: "oci://ghcr.io"},
+		{name: "Namespace", registry: "ghcr.io/defenseunicorns/dev/", metadata: metadata, want: "oci://ghcr.io/defenseunicorns/dev"},
+		{name: "SchemeAndPort", registry: "oci://localhost:888/org", metadata: metadata, want: "oci://localhost:888/org"},
+		{name: "Empty", registry: "oci://", metadata: metadata, wantErr: "a registry is required"},
+		{name: "OtherScheme", registry: "https://ghcr.io", metadata: metadata, wantErr: `invalid registry "https://ghcr.io", the only supported scheme is oci://`},
+		{name: "Tag", registry: "ghcr.io/org:0.0.1", metadata: metadata, wantErr: `invalid registry "ghcr.io/org:0.0.1", it can't include a tag or digest since the bundle's version is used`},
+		{name: "NoVersion", registry: "ghcr.io", metadata: &types.UDSMetadata{Name: "example"}, wantErr: `unable to compose a reference for bundle example in registry "ghcr.io": version is required for publishing`},
+		{name: "InvalidReference", registry: "ghcr.io/Org", metadata: metadata, wantErr: "unable to compose a reference for bundle example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RegistryOutput(tt.registry, tt.metadata)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_checkBundlePlatform(t *testing.T) {
+	platform := ocispec.Platform{Architecture: "amd64", OS: oci.MultiOS}
+	require.NoError(t, checkBundlePlatform(&types.UDSBuildData{Architecture: "amd64"}, platform))
+	require.EqualError(t, checkBundlePlatform(&types.UDSBuildData{Architecture: "arm64"}, platform),
+		"the bundle's architecture arm64 doesn't match the amd64 platform its root manifest is published for, set the architecture with --architecture")
+}
+
+func Test_checkPkgPlatform(t *testing.T) {
+	defer func(arch string) { config.CLIArch = arch }(config.CLIArch)
+	config.CLIArch = "amd64"
+	pkg := types.Package{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/podinfo", Ref: "0.0.1"}
+	tests := []struct {
+		name    string
+		pkg     types.Package
+		config  oci.ConfigPartial
+		wantErr string
+	}{
+		{name: "SameArch", pkg: pkg, config: oci.ConfigPartial{Architecture: "amd64"}},
+		{name: "NoArch", pkg: pkg, config: oci.ConfigPartial{}},
+		{name: "PkgArch", pkg: types.Package{Name: "podinfo", Arch: "arm64"}, config: oci.ConfigPartial{Architecture: "arm64"}},
+		{name: "DifferentArch", pkg: pkg, config: oci.ConfigPartial{Architecture: "arm64"},
+			wantErr: "package podinfo at ghcr.io/defenseunicorns/podinfo:0.0.1 is built for arm64 but the bundle requires amd64"},
+		{name: "LocalPkgDifferentArch", pkg: types.Package{Name: "podinfo", Path: "zarf-package-podinfo-arm64-0.0.1.tar.zst"}, config: oci.ConfigPartial{Architecture: "arm64"},
+			wantErr: "package podinfo at zarf-package-podinfo-arm64-0.0.1.tar.zst is built for arm64 but the bundle requires amd64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPkgPlatform(tt.pkg, tt.config)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_checkMediaTypes(t *testing.T) {
+	layer := func(dgst, mediaType string) ocispec.Descriptor {
+		return ocispec.Descriptor{Digest: digest.Digest(dgst), MediaType: mediaType}
+	}
+	pkgs := []types.Package{{Name: "init"}, {Name: "podinfo"}}
+	roots := []*oci.Manifest{
+		{Manifest: ocispec.Manifest{
+			Config: layer("sha256:a", zoci.ZarfLayerMediaTypeBlob),
+			Layers: []ocispec.Descriptor{layer("sha256:b", zoci.ZarfLayerMediaTypeBlob), layer("sha256:c", "application/vnd.custom.tar")},
+		}},
+		{Manifest: ocispec.Manifest{
+			Config: layer("sha256:d", zoci.ZarfLayerMediaTypeBlob),
+			Layers: []ocispec.Descriptor{
+				layer("sha256:e", "application/vnd.custom.tar"),
+				layer("sha256:f", "application/vnd.custom.tar"),
+				layer("sha256:g", "application/vnd.other.tar"),
+			},
+		}},
+	}
+
+	require.NoError(t, checkMediaTypes(pkgs, roots, nil, nil))
+	require.NoError(t, checkMediaTypes(pkgs, roots, nil, []string{zoci.ZarfLayerMediaTypeBlob, "application/vnd.custom.tar", "application/vnd.other.tar"}))
+	require.EqualError(t, checkMediaTypes(pkgs, roots, nil, []string{zoci.ZarfLayerMediaTypeBlob}),
+		"packages have layers with media types that aren't allowed by --allowed-media-types: application/vnd.custom.tar (init, podinfo); application/vnd.other.tar (podinfo)")
+
+	// the layers excluded from a pruned pkg aren't pushed so they aren't checked
+	pruned := []*utils.PrunedPackage{nil, {Root: &oci.Manifest{Manifest: ocispec.Manifest{
+		Config: layer("sha256:d", zoci.ZarfLayerMediaTypeBlob),
+		Layers: []ocispec.Descriptor{layer("sha256:h", zoci.ZarfLayerMediaTypeBlob)},
+	}}}}
+	require.EqualError(t, checkMediaTypes(pkgs, roots, pruned, []string{zoci.ZarfLayerMediaTypeBlob}),
+		"packages have layers with media types that aren't allowed by --allowed-media-types: application/vnd.custom.tar (init)")
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// LayerEstimate is the estimated size of a layer in the bundle's root manifest
+type LayerEstimate struct {
+	// Title is the name of the Zarf pkg, or the title of the bundle's metadata layers
+	Title string
+	// Digest is the digest of the layer in the bundle's root manifest
+	Digest digest.Digest
+	// Size is the size of the layer, including all of the Zarf pkg layers it references
+	Size int64
+}
+
+// SizeEstimate is the estimated size of a remote bundle
+type SizeEstimate struct {
+	Layers     []LayerEstimate
+	TotalBytes int64
+	// UnknownSizeLayers are the Zarf pkg layers without a size, these aren't counted in TotalBytes
+	UnknownSizeLayers []ocispec.Descriptor
+}
+
+// EstimateSize sums the size of every layer that would be pushed to the bundle without pushing anything
+func (r *RemoteBundle) EstimateSize(ctx context.Context, signature []byte) (*SizeEstimate, error) {
+	for _, pkg := range r.bundle.Packages {
+		if !utils.IsRemotePkg(pkg) {
+			return nil, fmt.Errorf("unable to estimate the size of local package %s, it isn't pushed until it's staged in the destination", pkg.Name)
+		}
+	}
+	srcRemotes, err := r.newSrcRemotes(nil)
+	if err != nil {
+		return nil, err
+	}
+	pkgRootManifests, err := r.fetchRoots(ctx, srcRemotes)
+	if err != nil {
+		return nil, err
+	}
+	prunedPkgs, err := r.pruneImages(ctx, srcRemotes, pkgRootManifests)
+	if err != nil {
+		return nil, err
+	}
+	return r.estimate(ctx, srcRemotes, pkgRootManifests, prunedPkgs, signature)
+}
+
+// estimate sums the sizes of the layers of each Zarf pkg that would be pushed, prunedPkgs are the pkgs with excluded
+// images and are nil for the pkgs without any
+func (r *RemoteBundle) estimate(ctx context.Context, srcRemotes []*zoci.Remote, pkgRootManifests []*oci.Manifest, prunedPkgs []*utils.PrunedPackage, signature []byte) (*SizeEstimate, error) {
+	bundle := r.bundle
+	estimate := SizeEstimate{}
+	estimateSpinner := message.NewProgressSpinner("Estimating size of bundle %s", bundle.Metadata.Name)
+	defer estimateSpinner.Stop()
+
+	for i, pkg := range bundle.Packages {
+		estimateSpinner.Updatef("Fetching %s package layer metadata (package %d of %d)", pkg.Name, i+1, len(bundle.Packages))
+		pkgRootManifest := pkgRootManifests[i]
+		layersToCopy, err := utils.GetZarfLayers(ctx, *srcRemotes[i], pkgRootManifest, pkg.OptionalComponents)
+		if err != nil {
+			return nil, err
+		}
+		var pkgBytes int64
+		if pruned := prunedPkgs[i]; pruned != nil {
+			pkgRootManifest = pruned.Root
+			layersToCopy = pruned.Filter(layersToCopy)
+			for _, blob := range pruned.Blobs {
+				pkgBytes += blob.Desc.Size
+			}
+		}
+		manifestBytes, err := json.Marshal(pkgRootManifest)
+		if err != nil {
+			return nil, err
+		}
+		zarfManifestDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, manifestBytes)
+		pkgBytes += zarfManifestDesc.Size
+		for _, layer := range oci.RemoveDuplicateDescriptors(layersToCopy) {
+			if layer.Digest == "" {
+				continue
+			}
+			if layer.Size == 0 {
+				message.Warnf("Layer %s in package %s has an unknown size, it isn't included in the estimate", layer.Digest, pkg.Name)
+				estimate.UnknownSizeLayers = append(estimate.UnknownSizeLayers, layer)
+				continue
+			}
+			pkgBytes += layer.Size
+		}
+		estimate.add(pkg.Name, zarfManifestDesc.Digest, pkgBytes)
+	}
+
+	bundleYamlBytes, err := bundleYAML(bundle, r.transformBundle)
+	if err != nil {
+		return nil, err
+	}
+	bundleYamlDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, bundleYamlBytes)
+	estimate.add(config.BundleYAML, bundleYamlDesc.Digest, bundleYamlDesc.Size)
+
+	if len(signature) > 0 {
+		signatureDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, signature)
+		estimate.add(config.BundleYAMLSignature, signatureDesc.Digest, signatureDesc.Size)
+	}
+	for _, file := range r.extraFiles {
+		fileDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, file.Contents)
+		estimate.add(file.Path, fileDesc.Digest, fileDesc.Size)
+	}
+
+	if !r.quiet {
+		estimateSpinner.Successf("Estimated size of %d packages", len(bundle.Packages))
+	}
+	return &estimate, nil
+}
+
+// add adds a root manifest layer to the estimate
+func (e *SizeEstimate) add(title string, layerDigest digest.Digest, size int64) {
+	e.Layers = append(e.Layers, LayerEstimate{Title: title, Digest: layerDigest, Size: size})
+	e.TotalBytes += size
+}
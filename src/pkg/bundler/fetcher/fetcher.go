@@ -8,20 +8,19 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/defenseunicorns/pkg/oci"
-	"github.com/defenseunicorns/uds-cli/src/config"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	"github.com/defenseunicorns/uds-cli/src/types"
 	"github.com/defenseunicorns/zarf/src/pkg/zoci"
 	zarfTypes "github.com/defenseunicorns/zarf/src/types"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	ocistore "oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry/remote/auth"
 )
 
 // Fetcher is the interface for fetching packages
 type Fetcher interface {
-	Fetch() ([]ocispec.Descriptor, error)
-	GetPkgMetadata() (zarfTypes.ZarfPackage, error)
+	Fetch(ctx context.Context) ([]ocispec.Descriptor, error)
+	GetPkgMetadata(ctx context.Context) (zarfTypes.ZarfPackage, error)
 }
 
 // Config is the configuration for the fetcher
@@ -32,25 +31,27 @@ type Config struct {
 	NumPkgs            int
 	BundleRootManifest *ocispec.Manifest
 	Bundle             *types.UDSBundle
+	// SrcCredential authenticates to the registries of remote Zarf pkgs, the docker config is used if it's empty
+	SrcCredential auth.Credential
+	// NoCache fetches each Zarf pkg's root manifest instead of reusing the one cached on disk
+	NoCache bool
+	// Quiet suppresses the progress bars and success lines as pkgs are fetched
+	Quiet bool
 }
 
 // NewPkgFetcher creates a fetcher object to pull Zarf pkgs into a local bundle
-func NewPkgFetcher(pkg types.Package, fetcherConfig Config) (Fetcher, error) {
+func NewPkgFetcher(ctx context.Context, pkg types.Package, fetcherConfig Config) (Fetcher, error) {
 	var fetcher Fetcher
 	if utils.IsRemotePkg(pkg) {
-		platform := ocispec.Platform{
-			Architecture: config.GetArch(),
-			OS:           oci.MultiOS,
-		}
 		url := fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref)
-		remote, err := zoci.NewRemote(url, platform)
+		remote, err := zoci.NewRemote(url, utils.GetPkgPlatform(pkg), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("unable to create a remote for package %s (packages[%d]) at %s: %w", pkg.Name, fetcherConfig.PkgIter, url, err)
 		}
-		ctx := context.TODO()
-		pkgRootManifest, err := remote.FetchRoot(ctx)
+		utils.WithCredential(remote.OrasRemote, fetcherConfig.SrcCredential)
+		pkgRootManifest, err := utils.FetchRoot(ctx, remote.OrasRemote, url, !fetcherConfig.NoCache)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("unable to fetch the root manifest of package %s (packages[%d]) at %s: %w", pkg.Name, fetcherConfig.PkgIter, url, err)
 		}
 
 		fetcher = &remoteFetcher{
@@ -35,7 +35,7 @@ type localFetcher struct {
 }
 
 // Fetch fetches a local Zarf pkg and puts it into a local bundle
-func (f *localFetcher) Fetch() ([]ocispec.Descriptor, error) {
+func (f *localFetcher) Fetch(ctx context.Context) ([]ocispec.Descriptor, error) {
 	fetchSpinner := message.NewProgressSpinner("Fetching package %s", f.pkg.Name)
 	defer fetchSpinner.Stop()
 	pkgTmp, err := zarfUtils.MakeTempDir(config.CommonOptions.TempDirectory)
@@ -54,17 +54,22 @@ func (f *localFetcher) Fetch() ([]ocispec.Descriptor, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := utils.CheckPkgArch(f.pkg, zarfPkg.Build.Architecture); err != nil {
+		return nil, err
+	}
 
-	layerDescs, err := f.toBundle(zarfPkg, pkgTmp)
+	layerDescs, err := f.toBundle(ctx, zarfPkg, pkgTmp)
 	if err != nil {
 		return nil, err
 	}
-	fetchSpinner.Successf("Fetched package: %s", f.pkg.Name)
+	if !f.cfg.Quiet {
+		fetchSpinner.Successf("Fetched package: %s", f.pkg.Name)
+	}
 	return layerDescs, nil
 }
 
 // GetPkgMetadata grabs metadata from a local Zarf package's zarf.yaml
-func (f *localFetcher) GetPkgMetadata() (zarfTypes.ZarfPackage, error) {
+func (f *localFetcher) GetPkgMetadata(ctx context.Context) (zarfTypes.ZarfPackage, error) {
 	tmpDir, err := zarfUtils.MakeTempDir(config.CommonOptions.TempDirectory)
 	if err != nil {
 		return zarfTypes.ZarfPackage{}, err
@@ -79,7 +84,7 @@ func (f *localFetcher) GetPkgMetadata() (zarfTypes.ZarfPackage, error) {
 		Compression: av4.Zstd{},
 		Archival:    av4.Tar{},
 	}
-	if err := format.Extract(context.TODO(), zarfTarball, []string{config.ZarfYAML}, func(_ context.Context, fileInArchive av4.File) error {
+	if err := format.Extract(ctx, zarfTarball, []string{config.ZarfYAML}, func(_ context.Context, fileInArchive av4.File) error {
 		// write zarf.yaml to tmp for checking optional components later on
 		dst := filepath.Join(tmpDir, fileInArchive.NameInArchive)
 		outFile, err := os.Create(dst)
@@ -134,9 +139,8 @@ func (f *localFetcher) load() (zarfTypes.ZarfPackage, error) {
 }
 
 // toBundle transfers a Zarf package to a given Bundle
-func (f *localFetcher) toBundle(pkg zarfTypes.ZarfPackage, pkgTmp string) ([]ocispec.Descriptor, error) {
+func (f *localFetcher) toBundle(ctx context.Context, pkg zarfTypes.ZarfPackage, pkgTmp string) ([]ocispec.Descriptor, error) {
 	// todo: only grab components that are required + specified in optionalComponents
-	ctx := context.TODO()
 	src, err := file.New(pkgTmp)
 	if err != nil {
 		return nil, err
@@ -35,11 +35,16 @@ type remoteFetcher struct {
 }
 
 // Fetch fetches a Zarf pkg and puts it into a local bundle
-func (f *remoteFetcher) Fetch() ([]ocispec.Descriptor, error) {
+func (f *remoteFetcher) Fetch(ctx context.Context) ([]ocispec.Descriptor, error) {
 	fetchSpinner := message.NewProgressSpinner("Fetching package %s", f.pkg.Name)
 	defer fetchSpinner.Stop()
 
-	layerDescs, err := f.layersToLocalBundle(fetchSpinner, f.cfg.PkgIter+1, f.cfg.NumPkgs)
+	// the pkg's tag may be a single-arch manifest for a different arch instead of an index, check it before pulling
+	if err := f.checkArch(ctx); err != nil {
+		return nil, err
+	}
+
+	layerDescs, err := f.layersToLocalBundle(ctx, fetchSpinner, f.cfg.PkgIter+1, f.cfg.NumPkgs)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +57,7 @@ func (f *remoteFetcher) Fetch() ([]ocispec.Descriptor, error) {
 			if err != nil {
 				return nil, err
 			}
-			err = utils.FetchLayerAndStore(layerDesc, f.remote.OrasRemote, f.cfg.Store)
+			err = utils.FetchLayerAndStore(ctx, layerDesc, f.remote.OrasRemote, f.cfg.Store)
 			if err != nil {
 				return nil, err
 			}
@@ -62,34 +67,41 @@ func (f *remoteFetcher) Fetch() ([]ocispec.Descriptor, error) {
 
 			// add layer to bundle's root manifest
 			f.cfg.BundleRootManifest.Layers = append(f.cfg.BundleRootManifest.Layers, layerDesc)
-		} else if layerDesc.MediaType == zoci.ZarfConfigMediaType {
-			// read in and unmarshal zarf config
-			jsonData, err := os.ReadFile(filepath.Join(f.cfg.TmpDstDir, config.BlobsDir, layerDesc.Digest.Encoded()))
-			if err != nil {
-				return nil, err
-			}
-			var zarfConfigData oci.ConfigPartial
-			err = json.Unmarshal(jsonData, &zarfConfigData)
-			if err != nil {
-				return nil, err
-			}
 		}
 	}
 
-	fetchSpinner.Successf("Fetched package: %s", f.pkg.Name)
+	if !f.cfg.Quiet {
+		fetchSpinner.Successf("Fetched package: %s", f.pkg.Name)
+	}
 	return layerDescs, nil
 }
 
+// checkArch returns an error if the arch in the Zarf pkg's config isn't the arch the bundle requires of it
+func (f *remoteFetcher) checkArch(ctx context.Context) error {
+	if f.pkgRootManifest.Config.Digest == "" {
+		return nil
+	}
+	b, err := f.remote.FetchLayer(ctx, f.pkgRootManifest.Config)
+	if err != nil {
+		return fmt.Errorf("unable to fetch the config of package %s: %w", f.pkg.Name, err)
+	}
+	var pkgConfig oci.ConfigPartial
+	if err := json.Unmarshal(b, &pkgConfig); err != nil {
+		return fmt.Errorf("unable to parse the config of package %s: %w", f.pkg.Name, err)
+	}
+	return utils.CheckPkgArch(f.pkg, pkgConfig.Architecture)
+}
+
 // LayersToLocalBundle pushes a remote Zarf pkg's layers to a local bundle
-func (f *remoteFetcher) layersToLocalBundle(spinner *message.Spinner, currentPackageIter int, totalPackages int) ([]ocispec.Descriptor, error) {
+func (f *remoteFetcher) layersToLocalBundle(ctx context.Context, spinner *message.Spinner, currentPackageIter int, totalPackages int) ([]ocispec.Descriptor, error) {
 	spinner.Updatef("Fetching %s package layer metadata (package %d of %d)", f.pkg.Name, currentPackageIter, totalPackages)
 	// get only the layers that are required by the components
-	layersToCopy, err := utils.GetZarfLayers(*f.remote, f.pkgRootManifest, f.pkg.OptionalComponents)
+	layersToCopy, err := utils.GetZarfLayers(ctx, *f.remote, f.pkgRootManifest, f.pkg.OptionalComponents)
 	if err != nil {
 		return nil, err
 	}
 	spinner.Stop()
-	layerDescs, err := f.remoteToLocal(layersToCopy)
+	layerDescs, err := f.remoteToLocal(ctx, layersToCopy)
 	if err != nil {
 		return nil, err
 	}
@@ -99,8 +111,7 @@ func (f *remoteFetcher) layersToLocalBundle(spinner *message.Spinner, currentPac
 }
 
 // remoteToLocal copies a remote Zarf pkg to a local OCI store
-func (f *remoteFetcher) remoteToLocal(layersToCopy []ocispec.Descriptor) ([]ocispec.Descriptor, error) {
-	ctx := context.TODO()
+func (f *remoteFetcher) remoteToLocal(ctx context.Context, layersToCopy []ocispec.Descriptor) ([]ocispec.Descriptor, error) {
 	// pull layers from remote and write to OCI artifact dir
 	var descsToBundle []ocispec.Descriptor
 	var layersToPull []ocispec.Descriptor
@@ -140,10 +151,14 @@ func (f *remoteFetcher) remoteToLocal(layersToCopy []ocispec.Descriptor) ([]ocis
 			return nil, err
 		}
 
-		go zarfUtils.RenderProgressBarForLocalDirWrite(f.cfg.TmpDstDir, estimatedBytes+tmpDirSize, doneSaving, fmt.Sprintf("Pulling bundle: %s", f.pkg.Name), fmt.Sprintf("Successfully pulled package: %s", f.pkg.Name))
-		rootPkgDesc, err := oras.Copy(context.TODO(), f.remote.Repo(), f.remote.Repo().Reference.String(), f.cfg.Store, "", copyOpts)
-		doneSaving <- err
-		<-doneSaving
+		if !f.cfg.Quiet {
+			go zarfUtils.RenderProgressBarForLocalDirWrite(f.cfg.TmpDstDir, estimatedBytes+tmpDirSize, doneSaving, fmt.Sprintf("Pulling bundle: %s", f.pkg.Name), fmt.Sprintf("Successfully pulled package: %s", f.pkg.Name))
+		}
+		rootPkgDesc, err := oras.Copy(ctx, f.remote.Repo(), f.remote.Repo().Reference.String(), f.cfg.Store, "", copyOpts)
+		if !f.cfg.Quiet {
+			doneSaving <- err
+			<-doneSaving
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -171,21 +186,18 @@ func (f *remoteFetcher) remoteToLocal(layersToCopy []ocispec.Descriptor) ([]ocis
 	return descsToBundle, nil
 }
 
-func (f *remoteFetcher) GetPkgMetadata() (zarfTypes.ZarfPackage, error) {
-	ctx := context.TODO()
-	platform := ocispec.Platform{
-		Architecture: config.GetArch(),
-		OS:           oci.MultiOS,
-	}
+func (f *remoteFetcher) GetPkgMetadata(ctx context.Context) (zarfTypes.ZarfPackage, error) {
 	url := fmt.Sprintf("%s:%s", f.pkg.Repository, f.pkg.Ref)
-	remote, err := zoci.NewRemote(url, platform)
+	remote, err := zoci.NewRemote(url, utils.GetPkgPlatform(f.pkg), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 	if err != nil {
 		return zarfTypes.ZarfPackage{}, err
 	}
+	utils.WithCredential(remote.OrasRemote, f.cfg.SrcCredential)
 	tmpDir, err := zarfUtils.MakeTempDir(config.CommonOptions.TempDirectory)
 	if err != nil {
 		return zarfTypes.ZarfPackage{}, fmt.Errorf("bundler unable to create temp directory: %w", err)
 	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck
 	if _, err := remote.PullPackageMetadata(ctx, tmpDir); err != nil {
 		return zarfTypes.ZarfPackage{}, err
 	}
@@ -11,9 +11,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers"
-	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/config"
 	"github.com/defenseunicorns/uds-cli/src/pkg/bundler/fetcher"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
@@ -24,8 +24,10 @@ import (
 	"github.com/mholt/archiver/v4"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
 	ocistore "oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry/remote/auth"
 )
 
 // LocalBundleOpts are the options for creating a local bundle
@@ -34,40 +36,107 @@ type LocalBundleOpts struct {
 	TmpDstDir string
 	SourceDir string
 	OutputDir string
+	// SBOMFormat is the format of the bundle-level SBOM to include in the bundle, if any
+	SBOMFormat string
+	// SignatureAnnotations are added to the bundle's signature layer
+	SignatureAnnotations map[string]string
+	// SrcCredential authenticates to the registries of remote Zarf pkgs, the docker config is used if it's empty
+	SrcCredential auth.Credential
+	// RequireSignature fails the create before anything is bundled if the bundle isn't signed
+	RequireSignature bool
+	// NoCache fetches each Zarf pkg's root manifest instead of reusing the one cached on disk
+	NoCache bool
+	// MetadataMediaType is the media type of the bundle's YAML and signature layers, defaults to the Zarf layer media type
+	MetadataMediaType string
+	// ConfigMediaType is the media type of the bundle's manifest config, defaults to config.BundleConfigMediaType
+	ConfigMediaType string
+	// Quiet suppresses the headers, progress and success lines
+	Quiet bool
+	// VerifySignatureKey is the path to a public key the bundle's signature is verified with before it's bundled
+	VerifySignatureKey string
+	// ExtraFiles are bundled as layers alongside the bundle's YAML, titled with their path
+	ExtraFiles []ExtraFile
+	// OCILayout writes the bundle to OutputDir as an OCI image layout directory instead of a tarball
+	OCILayout bool
 }
 
 // LocalBundle enables create ops with local bundles
 type LocalBundle struct {
-	bundle    *types.UDSBundle
-	tmpDstDir string
-	sourceDir string
-	outputDir string
+	bundle            *types.UDSBundle
+	tmpDstDir         string
+	sourceDir         string
+	outputDir         string
+	sbomFormat        string
+	sigAnnotations    map[string]string
+	srcCredential     auth.Credential
+	requireSig        bool
+	noCache           bool
+	metadataMediaType string
+	configMediaType   string
+	quiet             bool
+	verifySigKey      string
+	extraFiles        []ExtraFile
+	ociLayout         bool
+	// layers are the descs of every blob written to the bundle's OCI store, they're copied when the bundle is also
+	// published to an OCI registry
+	layers []ocispec.Descriptor
 }
 
 // NewLocalBundle creates a new local bundle
 func NewLocalBundle(opts *LocalBundleOpts) *LocalBundle {
+	metadataMediaType := opts.MetadataMediaType
+	if metadataMediaType == "" {
+		metadataMediaType = zoci.ZarfLayerMediaTypeBlob
+	}
+	configMediaType := opts.ConfigMediaType
+	if configMediaType == "" {
+		configMediaType = config.BundleConfigMediaType
+	}
 	return &LocalBundle{
-		bundle:    opts.Bundle,
-		tmpDstDir: opts.TmpDstDir,
-		sourceDir: opts.SourceDir,
-		outputDir: opts.OutputDir,
+		bundle:            opts.Bundle,
+		tmpDstDir:         opts.TmpDstDir,
+		sourceDir:         opts.SourceDir,
+		outputDir:         opts.OutputDir,
+		ociLayout:         opts.OCILayout,
+		sbomFormat:        opts.SBOMFormat,
+		sigAnnotations:    opts.SignatureAnnotations,
+		srcCredential:     opts.SrcCredential,
+		requireSig:        opts.RequireSignature,
+		noCache:           opts.NoCache,
+		metadataMediaType: metadataMediaType,
+		configMediaType:   configMediaType,
+		quiet:             opts.Quiet,
+		verifySigKey:      opts.VerifySignatureKey,
+		extraFiles:        opts.ExtraFiles,
 	}
 }
 
-// create creates the bundle and outputs to a local tarball
-func (lo *LocalBundle) create(signature []byte) error {
+// create creates the bundle and outputs to a local tarball, it returns the desc of the bundle's root manifest
+func (lo *LocalBundle) create(ctx context.Context, signature []byte) (ocispec.Descriptor, error) {
 	bundle := lo.bundle
 	if bundle.Metadata.Architecture == "" {
-		return fmt.Errorf("architecture is required for bundling")
+		return ocispec.Descriptor{}, fmt.Errorf("architecture is required for bundling")
 	}
-	store, err := ocistore.NewWithContext(context.TODO(), lo.tmpDstDir)
-	ctx := context.TODO()
+	if err := checkSignature(bundle, lo.requireSig, signature); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	bundleYAMLBytes, err := goyaml.Marshal(bundle)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := verifySignature(lo.tmpDstDir, bundleYAMLBytes, signature, lo.verifySigKey); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	store, err := ocistore.NewWithContext(ctx, lo.tmpDstDir)
 
-	message.HeaderInfof("🐕 Fetching Packages")
+	if !lo.quiet {
+		message.HeaderInfof("🐕 Fetching Packages")
+	}
 
 	// create root manifest for bundle, will populate with refs to uds-bundle.yaml and zarf image manifests
 	rootManifest := ocispec.Manifest{
-		MediaType: ocispec.MediaTypeImageManifest,
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: config.BundleArtifactType,
 	}
 
 	fetcherConfig := fetcher.Config{
@@ -76,11 +145,14 @@ func (lo *LocalBundle) create(signature []byte) error {
 		TmpDstDir:          lo.tmpDstDir,
 		NumPkgs:            len(lo.bundle.Packages),
 		BundleRootManifest: &rootManifest,
+		SrcCredential:      lo.srcCredential,
+		NoCache:            lo.noCache,
+		Quiet:              lo.quiet,
 	}
 
 	message.Debug("Bundling", bundle.Metadata.Name, "to", lo.tmpDstDir)
 	if err != nil {
-		return err
+		return ocispec.Descriptor{}, err
 	}
 
 	artifactPathMap := make(types.PathMap)
@@ -88,49 +160,86 @@ func (lo *LocalBundle) create(signature []byte) error {
 	// grab all Zarf pkgs from OCI and put blobs in OCI store
 	for i, pkg := range bundle.Packages {
 		fetcherConfig.PkgIter = i
-		pkgFetcher, err := fetcher.NewPkgFetcher(pkg, fetcherConfig)
+		pkgFetcher, err := fetcher.NewPkgFetcher(ctx, pkg, fetcherConfig)
 		if err != nil {
-			return err
+			return ocispec.Descriptor{}, err
 		}
-		layerDescs, err := pkgFetcher.Fetch()
+		layerDescs, err := pkgFetcher.Fetch(ctx)
 		if err != nil {
-			return err
+			return ocispec.Descriptor{}, err
 		}
 		// add to artifactPathMap for local tarball
 		// todo: if we know the path to where the blobs are stored, we can use that instead of the artifactPathMap?
+		lo.layers = append(lo.layers, layerDescs...)
 		for _, layer := range layerDescs {
 			digest := layer.Digest.Encoded()
 			artifactPathMap[filepath.Join(lo.tmpDstDir, config.BlobsDir, digest)] = filepath.Join(config.BlobsDir, digest)
 		}
 	}
 
-	message.HeaderInfof("🚧 Building Bundle")
+	if !lo.quiet {
+		message.HeaderInfof("🚧 Building Bundle")
+	}
+
+	// generate the bundle's SBOM from the Zarf image manifests fetched into the root manifest
+	if lo.sbomFormat != "" {
+		sbom, err := generateBundleSBOM(lo.sbomFormat, bundle, rootManifest.Layers)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		sbomDesc, err := pushBundleSBOMToStore(store, sbom)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		rootManifest.Layers = append(rootManifest.Layers, sbomDesc)
+		lo.layers = append(lo.layers, sbomDesc)
+		digest := sbomDesc.Digest.Encoded()
+		artifactPathMap[filepath.Join(lo.tmpDstDir, config.BlobsDir, digest)] = filepath.Join(config.BlobsDir, digest)
+	}
 
 	// push uds-bundle.yaml to OCI store
-	bundleYAMLDesc, err := pushBundleYAMLToStore(store, bundle)
+	bundleYAMLDesc, err := pushBundleYAMLToStore(store, bundleYAMLBytes, lo.metadataMediaType)
 	if err != nil {
-		return err
+		return ocispec.Descriptor{}, err
 	}
 
 	// append uds-bundle.yaml layer to rootManifest and grab path for archiving
 	rootManifest.Layers = append(rootManifest.Layers, bundleYAMLDesc)
+	lo.layers = append(lo.layers, bundleYAMLDesc)
 	digest := bundleYAMLDesc.Digest.Encoded()
 	artifactPathMap[filepath.Join(lo.tmpDstDir, config.BlobsDir, digest)] = filepath.Join(config.BlobsDir, digest)
 
+	// push the extra files to OCI store
+	for _, file := range lo.extraFiles {
+		fileDesc, err := pushExtraFileToStore(store, file, lo.metadataMediaType)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		rootManifest.Layers = append(rootManifest.Layers, fileDesc)
+		lo.layers = append(lo.layers, fileDesc)
+		digest := fileDesc.Digest.Encoded()
+		artifactPathMap[filepath.Join(lo.tmpDstDir, config.BlobsDir, digest)] = filepath.Join(config.BlobsDir, digest)
+	}
+
 	// create and push bundle manifest config
-	manifestConfigDesc, err := pushManifestConfig(store, bundle.Metadata, bundle.Build)
+	manifestConfigDesc, err := pushManifestConfig(store, bundle.Metadata, bundle.Build, lo.configMediaType)
 	if err != nil {
-		return err
+		return ocispec.Descriptor{}, err
 	}
+	lo.layers = append(lo.layers, manifestConfigDesc)
 	manifestConfigDigest := manifestConfigDesc.Digest.Encoded()
 	artifactPathMap[filepath.Join(lo.tmpDstDir, config.BlobsDir, manifestConfigDigest)] = filepath.Join(config.BlobsDir, manifestConfigDigest)
 
 	rootManifest.Config = manifestConfigDesc
 	rootManifest.SchemaVersion = 2
-	rootManifest.Annotations = manifestAnnotationsFromMetadata(&bundle.Metadata) // maps to registry UI
+	rootManifest.Annotations = manifestAnnotationsFromMetadata(&bundle.Metadata, &bundle.Build) // maps to registry UI
+	// the Zarf image manifests are the first layers of the root manifest, in the order of the bundle's packages
+	if rootManifest.Annotations[config.BundlePackagesAnnotation], err = packagesAnnotation(bundle, rootManifest.Layers); err != nil {
+		return ocispec.Descriptor{}, err
+	}
 	rootManifestDesc, err := utils.ToOCIStore(rootManifest, ocispec.MediaTypeImageManifest, store)
 	if err != nil {
-		return err
+		return ocispec.Descriptor{}, err
 	}
 	digest = rootManifestDesc.Digest.Encoded()
 	artifactPathMap[filepath.Join(lo.tmpDstDir, config.BlobsDir, digest)] = filepath.Join(config.BlobsDir, digest)
@@ -143,9 +252,9 @@ func (lo *LocalBundle) create(signature []byte) error {
 
 	// push the bundle's signature todo: need to understand functionality and add tests
 	if len(signature) > 0 {
-		signatureDesc, err := pushBundleSignature(store, signature)
+		signatureDesc, err := pushBundleSignature(store, signature, lo.sigAnnotations, lo.metadataMediaType)
 		if err != nil {
-			return err
+			return ocispec.Descriptor{}, err
 		}
 		rootManifest.Layers = append(rootManifest.Layers, signatureDesc)
 		message.Debug("Pushed", config.BundleYAMLSignature+":", message.JSONValue(signatureDesc))
@@ -155,38 +264,103 @@ func (lo *LocalBundle) create(signature []byte) error {
 	// todo: no need to tag the local artifact
 	err = store.Tag(ctx, rootManifestDesc, bundle.Metadata.Version)
 	if err != nil {
-		return err
+		return ocispec.Descriptor{}, err
 	}
 	// ensure the bundle root manifest is the only manifest in the index.json
 	err = cleanIndexJSON(lo.tmpDstDir, rootManifestDesc)
 	if err != nil {
-		return err
+		return ocispec.Descriptor{}, err
 	}
 
 	if lo.outputDir == "" {
 		lo.outputDir = lo.sourceDir
 	}
+	if lo.ociLayout {
+		err = writeOCILayout(bundle, artifactPathMap, lo.outputDir, lo.quiet)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		return rootManifestDesc, nil
+	}
 	// tarball the bundle
-	err = writeTarball(bundle, artifactPathMap, lo.outputDir)
+	err = writeTarball(ctx, bundle, artifactPathMap, lo.outputDir, lo.quiet)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	return rootManifestDesc, nil
+}
+
+// publish copies the bundle from its OCI store to each of the OCI registry outputs, the layers are read from the
+// store the tarball was written from so the Zarf pkgs aren't fetched again and every output has the same root manifest
+func (lo *LocalBundle) publish(ctx context.Context, rootManifestDesc ocispec.Descriptor, outputs []string, dstCredential auth.Credential, force bool) error {
+	bundle := lo.bundle
+	store, err := ocistore.NewWithContext(ctx, lo.tmpDstDir)
 	if err != nil {
 		return err
 	}
+	platform := ocispec.Platform{
+		Architecture: config.GetArch(),
+		OS:           config.GetOS(bundle.Metadata.OS),
+	}
 
+	if !lo.quiet {
+		message.HeaderInfof("🚀 Publishing Bundle")
+	}
+	for _, output := range outputs {
+		ref, err := referenceFromMetadata(utils.EnsureOCIPrefix(output), &bundle.Metadata)
+		if err != nil {
+			return err
+		}
+		bundleRemote, err := zoci.NewRemote(ref, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+		if err != nil {
+			return err
+		}
+		utils.WithCredential(bundleRemote.OrasRemote, dstCredential)
+		dstRef := bundleRemote.Repo().Reference
+
+		index, err := utils.GetIndex(ctx, bundleRemote.OrasRemote, dstRef.String())
+		if err != nil {
+			return err
+		}
+		if existing, ok := utils.IndexConflict(index, bundle, rootManifestDesc); ok && !force {
+			return fmt.Errorf("%s already has a %s bundle with digest %s, refusing to overwrite it with %s, use --force to overwrite it",
+				dstRef, bundle.Metadata.Architecture, existing.Digest, rootManifestDesc.Digest)
+		} else if ok {
+			message.Warnf("Overwriting the %s bundle at %s (%s) with %s", bundle.Metadata.Architecture, dstRef, existing.Digest, rootManifestDesc.Digest)
+		}
+
+		// blobs that already exist in the registry are skipped, so a retry only pushes what's missing
+		copyOpts := utils.CreateCopyOpts(lo.layers, config.CommonOptions.OCIConcurrency)
+		err = utils.RetryOCI(ctx, "publish "+dstRef.String(), func() error {
+			return oras.CopyGraph(ctx, store, bundleRemote.Repo(), rootManifestDesc, copyOpts.CopyGraphOptions)
+		})
+		if err != nil {
+			return err
+		}
+
+		// merge into the latest index.json, it may have changed while the bundle was being published
+		err = utils.RetryOCI(ctx, "update index", func() error {
+			return utils.MergeIndex(ctx, bundleRemote.OrasRemote, bundle, rootManifestDesc, force)
+		})
+		if err != nil {
+			return err
+		}
+		if !lo.quiet {
+			message.Successf("Published %s", dstRef)
+		}
+	}
 	return nil
 }
 
 // pushBundleYAMLToStore pushes the uds-bundle.yaml to a provided OCI store
-func pushBundleYAMLToStore(store *ocistore.Store, bundle *types.UDSBundle) (ocispec.Descriptor, error) {
+func pushBundleYAMLToStore(store *ocistore.Store, bundleYAMLBytes []byte, mediaType string) (ocispec.Descriptor, error) {
 	ctx := context.TODO()
-	bundleYAMLBytes, err := goyaml.Marshal(bundle)
-	if err != nil {
-		return ocispec.Descriptor{}, err
-	}
-	bundleYamlDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, bundleYAMLBytes)
+	bundleYamlDesc := content.NewDescriptorFromBytes(mediaType, bundleYAMLBytes)
 	bundleYamlDesc.Annotations = map[string]string{
 		ocispec.AnnotationTitle: config.BundleYAML,
 	}
-	err = store.Push(ctx, bundleYamlDesc, bytes.NewReader(bundleYAMLBytes))
+	err := store.Push(ctx, bundleYamlDesc, bytes.NewReader(bundleYAMLBytes))
 	if err != nil {
 		return ocispec.Descriptor{}, err
 	}
@@ -194,18 +368,40 @@ func pushBundleYAMLToStore(store *ocistore.Store, bundle *types.UDSBundle) (ocis
 	return bundleYamlDesc, err
 }
 
-// pushManifestConfig creates a manifest config based on the uds-bundle.yaml
-func pushManifestConfig(store *ocistore.Store, metadata types.UDSMetadata, build types.UDSBuildData) (ocispec.Descriptor, error) {
-	annotations := map[string]string{
-		ocispec.AnnotationTitle:       metadata.Name,
-		ocispec.AnnotationDescription: metadata.Description,
+// pushExtraFileToStore pushes one of the bundle's extra files to a provided OCI store
+func pushExtraFileToStore(store *ocistore.Store, file ExtraFile, mediaType string) (ocispec.Descriptor, error) {
+	ctx := context.TODO()
+	fileDesc := content.NewDescriptorFromBytes(mediaType, file.Contents)
+	fileDesc.Annotations = extraFileAnnotations(file)
+	// two extra files with the same contents are stored once
+	if exists, err := store.Exists(ctx, fileDesc); err != nil || exists {
+		return fileDesc, err
 	}
-	manifestConfig := oci.ConfigPartial{
-		Architecture: build.Architecture,
-		OCIVersion:   "1.0.1",
-		Annotations:  annotations,
+	if err := store.Push(ctx, fileDesc, bytes.NewReader(file.Contents)); err != nil {
+		return ocispec.Descriptor{}, err
 	}
-	manifestConfigDesc, err := utils.ToOCIStore(manifestConfig, zoci.ZarfLayerMediaTypeBlob, store)
+	message.Debug("Pushed", file.Path+":", message.JSONValue(fileDesc))
+	return fileDesc, nil
+}
+
+// pushBundleSBOMToStore pushes the bundle's SBOM to a provided OCI store
+func pushBundleSBOMToStore(store *ocistore.Store, sbom []byte) (ocispec.Descriptor, error) {
+	ctx := context.TODO()
+	sbomDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, sbom)
+	sbomDesc.Annotations = map[string]string{
+		ocispec.AnnotationTitle: config.BundleSBOMJSON,
+	}
+	if err := store.Push(ctx, sbomDesc, bytes.NewReader(sbom)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	message.Debug("Pushed", config.BundleSBOMJSON+":", message.JSONValue(sbomDesc))
+	return sbomDesc, nil
+}
+
+// pushManifestConfig creates a manifest config based on the uds-bundle.yaml
+func pushManifestConfig(store *ocistore.Store, metadata types.UDSMetadata, build types.UDSBuildData, mediaType string) (ocispec.Descriptor, error) {
+	manifestConfig := manifestConfigFromMetadata(&metadata, &build)
+	manifestConfigDesc, err := utils.ToOCIStore(manifestConfig, mediaType, store)
 	if err != nil {
 		return ocispec.Descriptor{}, err
 	}
@@ -213,8 +409,8 @@ func pushManifestConfig(store *ocistore.Store, metadata types.UDSMetadata, build
 	return manifestConfigDesc, err
 }
 
-// writeTarball builds and writes a bundle tarball to disk based on a file map
-func writeTarball(bundle *types.UDSBundle, artifactPathMap types.PathMap, outputDir string) error {
+// writeTarball builds and writes a bundle tarball to disk based on a file map, quiet skips the progress bar
+func writeTarball(ctx context.Context, bundle *types.UDSBundle, artifactPathMap types.PathMap, outputDir string, quiet bool) (err error) {
 	format := archiver.CompressedArchive{
 		Compression: archiver.Zstd{},
 		Archival:    archiver.Tar{},
@@ -234,6 +430,13 @@ func writeTarball(bundle *types.UDSBundle, artifactPathMap types.PathMap, output
 		return err
 	}
 	defer out.Close()
+	// don't leave a truncated tarball behind if archiving fails or is canceled
+	defer func() {
+		if err != nil {
+			out.Close()
+			_ = os.Remove(dst)
+		}
+	}()
 	files, err := archiver.FilesFromDisk(nil, artifactPathMap)
 	if err != nil {
 		return err
@@ -252,9 +455,13 @@ func writeTarball(bundle *types.UDSBundle, artifactPathMap types.PathMap, output
 
 	close(jobs)
 
-	archiveErrGroup, ctx := errgroup.WithContext(context.TODO())
+	archiveErrGroup, ctx := errgroup.WithContext(ctx)
 
-	archiveBar := message.NewProgressBar(int64(len(jobs)), "Creating bundle archive")
+	// a zero ProgressBar doesn't render anything
+	archiveBar := &message.ProgressBar{}
+	if !quiet {
+		archiveBar = message.NewProgressBar(int64(len(jobs)), "Creating bundle archive")
+	}
 
 	defer archiveBar.Stop()
 
@@ -280,20 +487,44 @@ jobLoop:
 		return err
 	}
 
+	if quiet {
+		return nil
+	}
 	archiveBar.Successf("Created bundle archive at: %s", dst)
 	return nil
 }
 
-func pushBundleSignature(store *ocistore.Store, signature []byte) (ocispec.Descriptor, error) {
+// writeOCILayout copies the bundle's OCI store to outputDir as an OCI image layout directory, the same files that
+// are archived into a tarball, so tools like skopeo and oras can read the bundle by its version, e.g. oci:dir:0.0.1.
+// Blobs already in outputDir are kept but its index.json is replaced, so it only references this bundle
+func writeOCILayout(bundle *types.UDSBundle, artifactPathMap types.PathMap, outputDir string, quiet bool) error {
+	if err := helpers.CreateDirectory(filepath.Join(outputDir, config.BlobsDir), 0755); err != nil {
+		return err
+	}
+	for src, rel := range artifactPathMap {
+		dst := filepath.Join(outputDir, rel)
+		// blobs are content addressed, one that's already in the layout doesn't need to be copied again
+		if strings.HasPrefix(rel, config.BlobsDir) && !helpers.InvalidPath(dst) {
+			continue
+		}
+		if err := helpers.CreatePathAndCopy(src, dst); err != nil {
+			return fmt.Errorf("unable to write %s to the OCI layout: %w", rel, err)
+		}
+	}
+	if !quiet {
+		message.Successf("Created bundle %s OCI layout at: %s", bundle.Metadata.Name, outputDir)
+	}
+	return nil
+}
+
+func pushBundleSignature(store *ocistore.Store, signature []byte, sigAnnotations map[string]string, mediaType string) (ocispec.Descriptor, error) {
 	ctx := context.TODO()
-	signatureDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, signature)
+	signatureDesc := content.NewDescriptorFromBytes(mediaType, signature)
 	err := store.Push(ctx, signatureDesc, bytes.NewReader(signature))
 	if err != nil {
 		return ocispec.Descriptor{}, err
 	}
-	signatureDesc.Annotations = map[string]string{
-		ocispec.AnnotationTitle: config.BundleYAMLSignature,
-	}
+	signatureDesc.Annotations = signatureLayerAnnotations(sigAnnotations)
 	return signatureDesc, err
 }
 
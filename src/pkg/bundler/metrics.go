@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+)
+
+// the phases of creating a remote bundle that are timed
+const (
+	phaseFetchRoots       = "fetch-roots"
+	phasePushPackages     = "push-packages"
+	phasePushMetadata     = "push-metadata"
+	phasePushSignature    = "push-signature"
+	phasePushSBOMs        = "push-sboms"
+	phasePushRootManifest = "push-root-manifest"
+)
+
+// phaseMetric is the duration and bytes pushed of a phase of creating a remote bundle
+type phaseMetric struct {
+	phase string
+	// destination is the bundle reference the phase pushed to, it's empty for phases that aren't per destination
+	destination string
+	duration    time.Duration
+	bytes       int64
+}
+
+// packageMetric is the duration and bytes pushed of a Zarf pkg's push to every destination
+type packageMetric struct {
+	name     string
+	duration time.Duration
+	bytes    int64
+}
+
+// pushMetrics records the durations of a remote create, pkgs are pushed concurrently so it's safe for concurrent use
+type pushMetrics struct {
+	mu       sync.Mutex
+	phases   []phaseMetric
+	packages []packageMetric
+}
+
+// newPushMetrics creates the metrics for a bundle with numPkgs Zarf pkgs
+func newPushMetrics(numPkgs int) *pushMetrics {
+	return &pushMetrics{packages: make([]packageMetric, numPkgs)}
+}
+
+// addPhase records a phase that started at start
+func (m *pushMetrics) addPhase(phase, destination string, start time.Time, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.phases = append(m.phases, phaseMetric{phase: phase, destination: destination, duration: time.Since(start), bytes: bytes})
+}
+
+// addPackage records the push of the i-th Zarf pkg that started at start
+func (m *pushMetrics) addPackage(i int, name string, start time.Time, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.packages[i] = packageMetric{name: name, duration: time.Since(start), bytes: bytes}
+}
+
+// printSummary prints a table of the durations and bytes pushed of each phase and Zarf pkg
+func (m *pushMetrics) printSummary() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows [][]string
+	for _, p := range m.phases {
+		rows = append(rows, []string{p.phase, p.destination, p.duration.Round(time.Millisecond).String(), zarfUtils.ByteFormat(float64(p.bytes), 2)})
+	}
+	for _, pkg := range m.packages {
+		rows = append(rows, []string{"package " + pkg.name, "", pkg.duration.Round(time.Millisecond).String(), zarfUtils.ByteFormat(float64(pkg.bytes), 2)})
+	}
+	message.Table([]string{"Phase", "Destination", "Duration", "Pushed"}, rows)
+}
+
+// writePrometheus writes the metrics in the Prometheus text exposition format, labeled with the bundle's name, version and arch
+func (m *pushMetrics) writePrometheus(w io.Writer, metadata *types.UDSMetadata) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bundleLabels := fmt.Sprintf(`bundle="%s",version="%s",arch="%s"`, escapeLabel(metadata.Name), escapeLabel(metadata.Version), escapeLabel(metadata.Architecture))
+
+	var b strings.Builder
+	writeHeader := func(name, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+	writeHeader("uds_bundle_create_phase_duration_seconds", "Duration of each phase of creating a bundle in an OCI registry.")
+	for _, p := range m.phases {
+		fmt.Fprintf(&b, "uds_bundle_create_phase_duration_seconds{%s,phase=\"%s\",destination=\"%s\"} %g\n", bundleLabels, p.phase, escapeLabel(p.destination), p.duration.Seconds())
+	}
+	writeHeader("uds_bundle_create_phase_bytes", "Bytes pushed by each phase of creating a bundle in an OCI registry.")
+	for _, p := range m.phases {
+		fmt.Fprintf(&b, "uds_bundle_create_phase_bytes{%s,phase=\"%s\",destination=\"%s\"} %d\n", bundleLabels, p.phase, escapeLabel(p.destination), p.bytes)
+	}
+	writeHeader("uds_bundle_create_package_duration_seconds", "Duration of pushing each Zarf package to every destination.")
+	for _, pkg := range m.packages {
+		fmt.Fprintf(&b, "uds_bundle_create_package_duration_seconds{%s,package=\"%s\"} %g\n", bundleLabels, escapeLabel(pkg.name), pkg.duration.Seconds())
+	}
+	writeHeader("uds_bundle_create_package_bytes", "Bytes pushed for each Zarf package to every destination.")
+	for _, pkg := range m.packages {
+		fmt.Fprintf(&b, "uds_bundle_create_package_bytes{%s,package=\"%s\"} %d\n", bundleLabels, escapeLabel(pkg.name), pkg.bytes)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMetricsFile writes the metrics to path in the Prometheus text exposition format
+func (m *pushMetrics) writeMetricsFile(path string, metadata *types.UDSMetadata) error {
+	var buf bytes.Buffer
+	if err := m.writePrometheus(&buf, metadata); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("unable to write the metrics file: %w", err)
+	}
+	return nil
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
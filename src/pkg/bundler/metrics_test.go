@@ -0,0 +1,43 @@
+package bundler
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pushMetrics(t *testing.T) {
+	metrics := newPushMetrics(2)
+	start := time.Now().Add(-time.Second)
+	metrics.addPhase(phaseFetchRoots, "", start, 0)
+	metrics.addPackage(1, `prometheus "operator"`, start, 20)
+	metrics.addPackage(0, "podinfo", start, 10)
+	metrics.addPhase(phasePushMetadata, "ghcr.io/defenseunicorns/dev/bundle:0.0.1", start, 5)
+
+	path := filepath.Join(t.TempDir(), "metrics.prom")
+	require.NoError(t, metrics.writeMetricsFile(path, &types.UDSMetadata{Name: "bundle", Version: "0.0.1", Architecture: "amd64"}))
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+
+	labels := `bundle="bundle",version="0.0.1",arch="amd64"`
+	require.Contains(t, lines, "# TYPE uds_bundle_create_phase_duration_seconds gauge")
+	require.Contains(t, lines, `uds_bundle_create_phase_bytes{`+labels+`,phase="fetch-roots",destination=""} 0`)
+	require.Contains(t, lines, `uds_bundle_create_phase_bytes{`+labels+`,phase="push-metadata",destination="ghcr.io/defenseunicorns/dev/bundle:0.0.1"} 5`)
+	// pkgs are reported in bundle order regardless of which push finished first, and label values are escaped
+	podinfo := slices.Index(lines, `uds_bundle_create_package_bytes{`+labels+`,package="podinfo"} 10`)
+	operator := slices.Index(lines, `uds_bundle_create_package_bytes{`+labels+`,package="prometheus \"operator\""} 20`)
+	require.NotEqual(t, -1, podinfo)
+	require.Less(t, podinfo, operator)
+	for _, line := range lines {
+		if strings.HasPrefix(line, "uds_bundle_create_package_duration_seconds") {
+			require.NotContains(t, line, "} 0")
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// versionsToPrune returns the semver tags beyond the newest keep, newest first. The published version always counts
+// towards keep and is never pruned, even if newer versions were published before it. Tags that aren't a semver, like
+// digest tags, aren't versions and are never pruned
+func versionsToPrune(tags []string, published string, keep int) []string {
+	type version struct {
+		tag     string
+		version *semver.Version
+	}
+	var versions []version
+	publishedIsVersion := false
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		publishedIsVersion = publishedIsVersion || tag == published
+		versions = append(versions, version{tag: tag, version: v})
+	}
+	// a dry run plans the prune before the version is published
+	if v, err := semver.NewVersion(published); err == nil && !publishedIsVersion {
+		publishedIsVersion = true
+		versions = append(versions, version{tag: published, version: v})
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].version.GreaterThan(versions[j].version)
+	})
+
+	// the published version takes the first spot, the newest of the others fill the rest
+	kept := 0
+	if publishedIsVersion {
+		kept++
+	}
+	var pruned []string
+	for _, v := range versions {
+		if v.tag == published {
+			continue
+		}
+		if kept < keep {
+			kept++
+			continue
+		}
+		pruned = append(pruned, v.tag)
+	}
+	return pruned
+}
+
+// pruneTarget is a tag of an old version and what's deleted with it
+type pruneTarget struct {
+	tag string
+	// desc is the index (or root manifest) the tag points at
+	desc ocispec.Descriptor
+	// rootManifests are the root manifests of the tag's index that no kept tag references
+	rootManifests []ocispec.Descriptor
+}
+
+// planPruneVersions lists the old versions a create would prune from each destination after the bundle is published
+func (r *RemoteBundle) planPruneVersions(ctx context.Context, bundleRemotes []*zoci.Remote) error {
+	if r.pruneKeep == 0 {
+		return nil
+	}
+	for _, bundleRemote := range bundleRemotes {
+		targets, _, err := pruneTargets(ctx, bundleRemote, r.bundle.Metadata.Version, r.pruneKeep)
+		if err != nil {
+			return fmt.Errorf("unable to list the versions of %s to prune: %w", bundleRemote.Repo().Reference, err)
+		}
+		printPruneTargets(bundleRemote, targets, true)
+	}
+	return nil
+}
+
+// pruneVersions deletes the versions beyond the newest pruneKeep from each destination once the bundle is published.
+// The bundle is already published, so a version that can't be pruned is warned about instead of failing the create
+func (r *RemoteBundle) pruneVersions(ctx context.Context, bundleRemotes []*zoci.Remote) {
+	if r.pruneKeep == 0 {
+		return
+	}
+	for _, bundleRemote := range bundleRemotes {
+		if err := pruneVersions(ctx, bundleRemote, r.bundle.Metadata.Version, r.pruneKeep, r.quiet); err != nil {
+			message.Warnf("Unable to prune the old versions of %s: %s", bundleRemote.Repo().Reference, err)
+		}
+	}
+}
+
+// pruneVersions deletes the index of each old version of a destination, then the root manifests and blobs that only
+// the pruned versions referenced. Registries that don't support deleting blobs leave them for their garbage collection
+func pruneVersions(ctx context.Context, bundleRemote *zoci.Remote, published string, keep int, quiet bool) error {
+	targets, keptBlobs, err := pruneTargets(ctx, bundleRemote, published, keep)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	if !quiet {
+		printPruneTargets(bundleRemote, targets, false)
+	}
+
+	repo := bundleRemote.Repo()
+	blobs := make(map[digest.Digest]ocispec.Descriptor)
+	for _, target := range targets {
+		// the root manifests' graphs are read before they're deleted
+		for _, rootManifest := range target.rootManifests {
+			if err := manifestBlobs(ctx, bundleRemote, rootManifest, blobs); err != nil {
+				return fmt.Errorf("unable to list the blobs of %s: %w", rootManifest.Digest, err)
+			}
+		}
+		if err := repo.Manifests().Delete(ctx, target.desc); err != nil && !errors.Is(err, errdef.ErrNotFound) {
+			return fmt.Errorf("unable to delete %s: %w", target.tag, err)
+		}
+		for _, rootManifest := range target.rootManifests {
+			if rootManifest.Digest == target.desc.Digest {
+				continue
+			}
+			if err := repo.Manifests().Delete(ctx, rootManifest); err != nil && !errors.Is(err, errdef.ErrNotFound) {
+				return fmt.Errorf("unable to delete the root manifest %s of %s: %w", rootManifest.Digest, target.tag, err)
+			}
+		}
+		if !quiet {
+			message.Successf("Pruned %s:%s", repo.Reference.Repository, target.tag)
+		}
+	}
+
+	var deleted, skipped int
+	for d, blob := range blobs {
+		if keptBlobs[d] {
+			continue
+		}
+		if err := repo.Blobs().Delete(ctx, blob); err != nil && !errors.Is(err, errdef.ErrNotFound) {
+			// the registry likely doesn't support deleting blobs, its garbage collection removes them instead
+			message.Debugf("Unable to delete blob %s from %s: %s", d, repo.Reference, err)
+			skipped++
+			continue
+		}
+		deleted++
+	}
+	message.Debugf("Deleted %d blobs of the pruned versions of %s, %d were left for the registry's garbage collection", deleted, repo.Reference, skipped)
+	return nil
+}
+
+// pruneTargets resolves the versions to prune from a destination, along with the blobs the tags that are kept reference
+// so they're never deleted. An index or root manifest that a kept tag also points at isn't deleted, since deleting it
+// by digest would remove the kept tag too
+func pruneTargets(ctx context.Context, bundleRemote *zoci.Remote, published string, keep int) ([]pruneTarget, map[digest.Digest]bool, error) {
+	var tags []string
+	err := bundleRemote.Repo().Tags(ctx, "", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list tags: %w", err)
+	}
+	prune := versionsToPrune(tags, published, keep)
+	if len(prune) == 0 {
+		return nil, nil, nil
+	}
+	pruned := make(map[string]bool, len(prune))
+	for _, tag := range prune {
+		pruned[tag] = true
+	}
+
+	// everything a kept tag references directly or through its index is kept
+	kept := make(map[digest.Digest]bool)
+	keptBlobs := make(map[digest.Digest]bool)
+	for _, tag := range tags {
+		if pruned[tag] {
+			continue
+		}
+		desc, rootManifests, err := resolveVersion(ctx, bundleRemote, tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		kept[desc.Digest] = true
+		for _, rootManifest := range rootManifests {
+			kept[rootManifest.Digest] = true
+			blobs := make(map[digest.Digest]ocispec.Descriptor)
+			if err := manifestBlobs(ctx, bundleRemote, rootManifest, blobs); err != nil {
+				return nil, nil, fmt.Errorf("unable to list the blobs of %s: %w", tag, err)
+			}
+			for d := range blobs {
+				keptBlobs[d] = true
+			}
+		}
+	}
+
+	var targets []pruneTarget
+	for _, tag := range prune {
+		desc, rootManifests, err := resolveVersion(ctx, bundleRemote, tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		if kept[desc.Digest] {
+			message.Debugf("Not pruning %s, another tag points at the same bundle %s", tag, desc.Digest)
+			continue
+		}
+		target := pruneTarget{tag: tag, desc: desc}
+		for _, rootManifest := range rootManifests {
+			if !kept[rootManifest.Digest] {
+				target.rootManifests = append(target.rootManifests, rootManifest)
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets, keptBlobs, nil
+}
+
+// resolveVersion resolves a tag to the index or root manifest it points at and the root manifests it references
+func resolveVersion(ctx context.Context, bundleRemote *zoci.Remote, tag string) (ocispec.Descriptor, []ocispec.Descriptor, error) {
+	desc, err := bundleRemote.Repo().Resolve(ctx, tag)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("unable to resolve %s: %w", tag, err)
+	}
+	ref := bundleRemote.Repo().Reference
+	ref.Reference = tag
+	index, err := utils.GetIndex(ctx, bundleRemote.OrasRemote, ref.String())
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("unable to fetch the index of %s: %w", tag, err)
+	}
+	if index == nil {
+		return desc, []ocispec.Descriptor{desc}, nil
+	}
+	return desc, index.Manifests, nil
+}
+
+// manifestBlobs adds the blobs a root manifest references to blobs: its config and layers, and the config and layers
+// of each Zarf pkg manifest among its layers. Anything that isn't a root manifest, e.g. a tag that points at something
+// other than a bundle, has no blobs
+func manifestBlobs(ctx context.Context, bundleRemote *zoci.Remote, rootManifestDesc ocispec.Descriptor, blobs map[digest.Digest]ocispec.Descriptor) error {
+	if rootManifestDesc.MediaType != ocispec.MediaTypeImageManifest {
+		return nil
+	}
+	rootManifest, err := bundleRemote.FetchManifest(ctx, rootManifestDesc)
+	if err != nil {
+		return err
+	}
+	for _, desc := range append([]ocispec.Descriptor{rootManifest.Config}, rootManifest.Layers...) {
+		blobs[desc.Digest] = desc
+		// the Zarf pkg manifests are the untitled layers
+		if _, ok := desc.Annotations[ocispec.AnnotationTitle]; ok || desc.MediaType != zoci.ZarfLayerMediaTypeBlob {
+			continue
+		}
+		b, err := content.FetchAll(ctx, bundleRemote.Repo().Blobs(), desc)
+		if err != nil {
+			return err
+		}
+		var pkgManifest oci.Manifest
+		if err := json.Unmarshal(b, &pkgManifest); err != nil {
+			// an untitled blob that isn't a manifest, e.g. the config of an older bundle
+			continue
+		}
+		for _, layer := range append([]ocispec.Descriptor{pkgManifest.Config}, pkgManifest.Layers...) {
+			if layer.Digest != "" {
+				blobs[layer.Digest] = layer
+			}
+		}
+	}
+	return nil
+}
+
+// printPruneTargets lists the versions that are (or, for a dry run, would be) pruned from a destination
+func printPruneTargets(bundleRemote *zoci.Remote, targets []pruneTarget, dryRun bool) {
+	if len(targets) == 0 {
+		if dryRun {
+			message.Infof("No old versions would be pruned from %s", bundleRemote.Repo().Reference)
+		}
+		return
+	}
+	tags := make([]string, len(targets))
+	for i, target := range targets {
+		tags[i] = target.tag
+	}
+	if dryRun {
+		message.Infof("%d old versions would be pruned from %s: %s", len(targets), bundleRemote.Repo().Reference, strings.Join(tags, ", "))
+		return
+	}
+	message.Infof("Pruning %d old versions from %s: %s", len(targets), bundleRemote.Repo().Reference, strings.Join(tags, ", "))
+}
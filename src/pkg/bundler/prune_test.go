@@ -0,0 +1,278 @@
+package bundler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_versionsToPrune(t *testing.T) {
+	tests := []struct {
+		name      string
+		tags      []string
+		published string
+		keep      int
+		expected  []string
+	}{
+		{
+			name:      "older versions beyond keep",
+			tags:      []string{"1.0.0", "1.2.0", "1.10.0", "1.1.0"},
+			published: "1.10.0",
+			keep:      2,
+			expected:  []string{"1.1.0", "1.0.0"},
+		},
+		{
+			name:      "the published version is kept even if it isn't the newest",
+			tags:      []string{"1.0.0", "2.0.0", "3.0.0", "1.0.1"},
+			published: "1.0.1",
+			keep:      2,
+			expected:  []string{"2.0.0", "1.0.0"},
+		},
+		{
+			name:      "tags that aren't versions are never pruned",
+			tags:      []string{"latest", "sha256-0123456789ab", "0.1.0", "0.2.0"},
+			published: "0.2.0",
+			keep:      1,
+			expected:  []string{"0.1.0"},
+		},
+		{
+			name:      "fewer versions than keep",
+			tags:      []string{"0.1.0", "0.2.0"},
+			published: "0.2.0",
+			keep:      5,
+		},
+		{
+			name:      "the published version isn't pushed yet in a dry run",
+			tags:      []string{"0.1.0", "0.2.0"},
+			published: "0.3.0",
+			keep:      2,
+			expected:  []string{"0.1.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, versionsToPrune(tt.tags, tt.published, tt.keep))
+		})
+	}
+}
+
+// memoryRegistry is a fake registry that stores manifests and blobs in memory and supports pushes, listing tags and
+// deletes
+type memoryRegistry struct {
+	mu        sync.Mutex
+	manifests map[string][]byte
+	mediaType map[string]string
+	tags      map[string]string
+	blobs     map[string][]byte
+	// noBlobDeletes rejects blob deletes like registries that only remove blobs with their garbage collection
+	noBlobDeletes bool
+}
+
+func newMemoryRegistry() *memoryRegistry {
+	return &memoryRegistry{manifests: map[string][]byte{}, mediaType: map[string]string{}, tags: map[string]string{}, blobs: map[string][]byte{}}
+}
+
+func (p *memoryRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	const prefix = "/v2/dev/bundle/"
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+	switch {
+	case path == "tags/list":
+		tags := make([]string, 0, len(p.tags))
+		for tag := range p.tags {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": "dev/bundle", "tags": tags})
+	case path == "blobs/uploads/" && r.Method == http.MethodPost:
+		w.Header().Set("Location", prefix+"blobs/uploads/upload")
+		w.WriteHeader(http.StatusAccepted)
+	case path == "blobs/uploads/upload" && r.Method == http.MethodPut:
+		b, _ := io.ReadAll(r.Body)
+		p.blobs[r.URL.Query().Get("digest")] = b
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "manifests/") && r.Method == http.MethodPut:
+		ref := strings.TrimPrefix(path, "manifests/")
+		b, _ := io.ReadAll(r.Body)
+		d := digest.FromBytes(b).String()
+		p.manifests[d] = b
+		p.mediaType[d] = r.Header.Get("Content-Type")
+		if ref != d {
+			p.tags[ref] = d
+		}
+		w.Header().Set("Docker-Content-Digest", d)
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "manifests/"):
+		ref := strings.TrimPrefix(path, "manifests/")
+		d, ok := p.tags[ref]
+		if !ok {
+			d = ref
+		}
+		b, ok := p.manifests[d]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			// deleting a manifest removes every tag that points at it
+			delete(p.manifests, d)
+			for tag, tagged := range p.tags {
+				if tagged == d {
+					delete(p.tags, tag)
+				}
+			}
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", p.mediaType[d])
+		w.Header().Set("Docker-Content-Digest", d)
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	case strings.HasPrefix(path, "blobs/"):
+		d := strings.TrimPrefix(path, "blobs/")
+		b, ok := p.blobs[d]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			if p.noBlobDeletes {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			delete(p.blobs, d)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// pushVersion stores a bundle version like a create would: an index at the tag that references a root manifest, whose
+// layers are a Zarf pkg manifest and the bundle's YAML. The pkg has a layer shared by every version and one of its own
+func (p *memoryRegistry) pushVersion(t *testing.T, tag string) (rootManifest ocispec.Descriptor, unique []digest.Digest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	blob := func(mediaType string, b []byte) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes(mediaType, b)
+		p.blobs[desc.Digest.String()] = b
+		return desc
+	}
+	manifest := func(mediaType string, v any) ocispec.Descriptor {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		desc := content.NewDescriptorFromBytes(mediaType, b)
+		p.manifests[desc.Digest.String()] = b
+		p.mediaType[desc.Digest.String()] = mediaType
+		return desc
+	}
+	config := blob(ocispec.MediaTypeImageConfig, []byte("{}"))
+	shared := blob(zoci.ZarfLayerMediaTypeBlob, []byte("shared"))
+	own := blob(zoci.ZarfLayerMediaTypeBlob, []byte("layer of "+tag))
+	pkgManifestBytes, err := json.Marshal(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: config, Layers: []ocispec.Descriptor{shared, own}})
+	require.NoError(t, err)
+	pkgManifest := blob(zoci.ZarfLayerMediaTypeBlob, pkgManifestBytes)
+	bundleYAML := blob(zoci.ZarfLayerMediaTypeBlob, []byte("version: "+tag))
+	bundleYAML.Annotations = map[string]string{ocispec.AnnotationTitle: "uds-bundle.yaml"}
+
+	rootManifest = manifest(ocispec.MediaTypeImageManifest, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: config, Layers: []ocispec.Descriptor{pkgManifest, bundleYAML}})
+	rootManifest.Platform = &ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}
+	index := manifest(ocispec.MediaTypeImageIndex, ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{rootManifest}})
+	p.tags[tag] = index.Digest.String()
+	return rootManifest, []digest.Digest{own.Digest, pkgManifest.Digest, bundleYAML.Digest}
+}
+
+func Test_pruneVersions(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T, noBlobDeletes bool) (*memoryRegistry, *zoci.Remote, map[string]ocispec.Descriptor, map[string][]digest.Digest) {
+		registry := newMemoryRegistry()
+		registry.noBlobDeletes = noBlobDeletes
+		server := httptest.NewServer(registry)
+		t.Cleanup(server.Close)
+		rootManifests := map[string]ocispec.Descriptor{}
+		unique := map[string][]digest.Digest{}
+		for _, tag := range []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"} {
+			rootManifests[tag], unique[tag] = registry.pushVersion(t, tag)
+		}
+		remote, err := zoci.NewRemote(strings.TrimPrefix(server.URL, "http://")+"/dev/bundle:2.0.0", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+		require.NoError(t, err)
+		return registry, remote, rootManifests, unique
+	}
+	tags := func(registry *memoryRegistry) []string {
+		var tags []string
+		for tag := range registry.tags {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		return tags
+	}
+
+	t.Run("old versions and the blobs only they reference are deleted", func(t *testing.T) {
+		registry, remote, rootManifests, unique := setup(t, false)
+		require.NoError(t, pruneVersions(ctx, remote, "2.0.0", 2, true))
+		require.Equal(t, []string{"1.2.0", "2.0.0"}, tags(registry))
+		for _, tag := range []string{"1.0.0", "1.1.0"} {
+			require.NotContains(t, registry.manifests, rootManifests[tag].Digest.String())
+			for _, d := range unique[tag] {
+				require.NotContains(t, registry.blobs, d.String())
+			}
+		}
+		for _, tag := range []string{"1.2.0", "2.0.0"} {
+			require.Contains(t, registry.manifests, rootManifests[tag].Digest.String())
+			for _, d := range unique[tag] {
+				require.Contains(t, registry.blobs, d.String())
+			}
+		}
+		// the layer every version shares and the config are kept
+		require.Contains(t, registry.blobs, digest.FromBytes([]byte("shared")).String())
+		require.Contains(t, registry.blobs, digest.FromBytes([]byte("{}")).String())
+	})
+
+	t.Run("a version another tag points at isn't pruned", func(t *testing.T) {
+		registry, remote, _, _ := setup(t, false)
+		registry.tags["latest"] = registry.tags["1.0.0"]
+		require.NoError(t, pruneVersions(ctx, remote, "2.0.0", 2, true))
+		require.Equal(t, []string{"1.0.0", "1.2.0", "2.0.0", "latest"}, tags(registry))
+	})
+
+	t.Run("blobs are left behind if the registry can't delete them", func(t *testing.T) {
+		registry, remote, rootManifests, unique := setup(t, true)
+		require.NoError(t, pruneVersions(ctx, remote, "2.0.0", 3, true))
+		require.Equal(t, []string{"1.1.0", "1.2.0", "2.0.0"}, tags(registry))
+		require.NotContains(t, registry.manifests, rootManifests["1.0.0"].Digest.String())
+		for _, d := range unique["1.0.0"] {
+			require.Contains(t, registry.blobs, d.String())
+		}
+	})
+
+	t.Run("a dry run only lists the versions", func(t *testing.T) {
+		registry, remote, _, _ := setup(t, false)
+		targets, _, err := pruneTargets(ctx, remote, "2.1.0", 2)
+		require.NoError(t, err)
+		require.Len(t, targets, 3)
+		require.Equal(t, "1.1.0", targets[1].tag)
+		require.Len(t, registry.tags, 4)
+	})
+}
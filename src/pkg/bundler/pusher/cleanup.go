@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package pusher contains functionality to push Zarf pkgs to remote bundles
+package pusher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// PushedBlobs tracks the blobs and manifests a single create writes to each remote bundle that didn't already exist
+// there, so they can be deleted if the create fails before the bundle is published
+type PushedBlobs struct {
+	mu     sync.Mutex
+	pushed []pushedBlob
+	// committed are the remote bundles the bundle was published to, their blobs are never deleted
+	committed map[*zoci.Remote]struct{}
+}
+
+type pushedBlob struct {
+	dst  *zoci.Remote
+	desc ocispec.Descriptor
+}
+
+// Orphan is a blob or manifest a failed create pushed to a remote bundle that couldn't be deleted
+type Orphan struct {
+	// Repository is the registry and repository of the remote bundle, e.g. ghcr.io/defenseunicorns/bundle
+	Repository string
+	Desc       ocispec.Descriptor
+	Err        error
+}
+
+// NewPushedBlobs creates an empty set of pushed blobs
+func NewPushedBlobs() *PushedBlobs {
+	return &PushedBlobs{committed: make(map[*zoci.Remote]struct{})}
+}
+
+// Track records the descs that don't exist in the remote bundle yet, it must be called before they're pushed; blobs
+// that already exist may be referenced by other bundles so they're never deleted
+func (b *PushedBlobs) Track(ctx context.Context, dst *zoci.Remote, descs ...ocispec.Descriptor) error {
+	if b == nil {
+		return nil
+	}
+	for _, desc := range descs {
+		if desc.Digest == "" {
+			continue
+		}
+		exists, err := b.exists(ctx, dst, desc)
+		if err != nil {
+			return fmt.Errorf("unable to check if %s exists in %s: %w", desc.Digest, dst.Repo().Reference, err)
+		}
+		if exists {
+			continue
+		}
+		b.mu.Lock()
+		b.pushed = append(b.pushed, pushedBlob{dst: dst, desc: desc})
+		b.mu.Unlock()
+	}
+	return nil
+}
+
+// Commit marks the bundle as published to the remote bundle, none of the blobs pushed to it are deleted after this
+func (b *PushedBlobs) Commit(dst *zoci.Remote) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.committed[dst] = struct{}{}
+}
+
+// Delete deletes the tracked blobs and manifests from the remote bundles the bundle wasn't published to, newest first,
+// returning the ones that couldn't be deleted (e.g. the registry doesn't support deletes)
+func (b *PushedBlobs) Delete(ctx context.Context) []Orphan {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var orphans []Orphan
+	deleted := make(map[string]struct{})
+	for i := len(b.pushed) - 1; i >= 0; i-- {
+		blob := b.pushed[i]
+		if _, ok := b.committed[blob.dst]; ok {
+			continue
+		}
+		ref := blob.dst.Repo().Reference
+		repo := ref.Registry + "/" + ref.Repository
+		key := repo + "@" + blob.desc.Digest.String()
+		if _, ok := deleted[key]; ok {
+			continue
+		}
+		deleted[key] = struct{}{}
+
+		var err error
+		if blob.desc.MediaType == ocispec.MediaTypeImageManifest {
+			err = blob.dst.Repo().Manifests().Delete(ctx, blob.desc)
+		} else {
+			err = blob.dst.Repo().Blobs().Delete(ctx, blob.desc)
+		}
+		// the push may have failed before the blob was written
+		if err != nil && !errors.Is(err, errdef.ErrNotFound) {
+			orphans = append(orphans, Orphan{Repository: repo, Desc: blob.desc, Err: err})
+		}
+	}
+	b.pushed = nil
+	return orphans
+}
+
+// exists returns true if the blob or manifest exists in the remote bundle
+func (b *PushedBlobs) exists(ctx context.Context, dst *zoci.Remote, desc ocispec.Descriptor) (bool, error) {
+	if desc.MediaType == ocispec.MediaTypeImageManifest {
+		return dst.Repo().Manifests().Exists(ctx, desc)
+	}
+	return dst.Repo().Blobs().Exists(ctx, desc)
+}
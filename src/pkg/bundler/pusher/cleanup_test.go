@@ -0,0 +1,91 @@
+package pusher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_PushedBlobs(t *testing.T) {
+	existing := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("existing"))
+	layer := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("layer"))
+	undeletable := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("undeletable"))
+	rootManifestBytes, err := json.Marshal(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: ocispec.DescriptorEmptyJSON})
+	require.NoError(t, err)
+	rootManifest := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, rootManifestBytes)
+
+	var mu sync.Mutex
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			if strings.HasSuffix(r.URL.Path, existing.Digest.String()) {
+				w.Header().Set("Content-Length", "8")
+				w.Header().Set("Docker-Content-Digest", existing.Digest.String())
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case http.MethodGet:
+			// a manifest is fetched before it's deleted to clean up its referrers
+			if r.URL.Path != "/v2/dev/bundle/manifests/"+rootManifest.Digest.String() {
+				t.Errorf("unexpected GET %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", rootManifest.Digest.String())
+			_, _ = w.Write(rootManifestBytes)
+		case http.MethodDelete:
+			if strings.HasSuffix(r.URL.Path, undeletable.Digest.String()) {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			mu.Lock()
+			deleted = append(deleted, r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	platform := ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}
+	dst, err := zoci.NewRemote(host+"/dev/bundle:0.0.1", platform, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	published, err := zoci.NewRemote(host+"/published/bundle:0.0.1", platform, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	pushed := NewPushedBlobs()
+	require.NoError(t, pushed.Track(ctx, dst, existing, layer, undeletable, rootManifest))
+	require.NoError(t, pushed.Track(ctx, published, layer))
+	pushed.Commit(published)
+
+	// blobs that existed before the create and blobs in a destination the bundle was published to are kept
+	orphans := pushed.Delete(ctx)
+	require.ElementsMatch(t, []string{
+		"/v2/dev/bundle/manifests/" + rootManifest.Digest.String(),
+		"/v2/dev/bundle/blobs/" + layer.Digest.String(),
+	}, deleted)
+	require.Len(t, orphans, 1)
+	require.Equal(t, host+"/dev/bundle", orphans[0].Repository)
+	require.Equal(t, undeletable.Digest, orphans[0].Desc.Digest)
+	require.Error(t, orphans[0].Err)
+
+	// without a set nothing is tracked or deleted
+	var noCleanup *PushedBlobs
+	require.NoError(t, noCleanup.Track(ctx, dst, layer))
+	noCleanup.Commit(dst)
+	require.Empty(t, noCleanup.Delete(ctx))
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package pusher contains functionality to push Zarf pkgs to remote bundles
+package pusher
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// Limiter bounds the OCI operations running at the same time across every phase of a create, so fetching the Zarf
+// pkgs and pushing them to the remote bundle never make more requests at once than a shared registry can take. A nil
+// Limiter doesn't limit anything, the per-phase concurrency settings still apply
+type Limiter struct {
+	sem *semaphore.Weighted
+}
+
+// NewLimiter returns a Limiter allowing n OCI operations at the same time, nil is returned if n is below 1
+func NewLimiter(n int) *Limiter {
+	if n < 1 {
+		return nil
+	}
+	return &Limiter{sem: semaphore.NewWeighted(int64(n))}
+}
+
+// Do runs fn once an operation slot is free, fn must not call Do itself or it can wait on its own slot
+func (l *Limiter) Do(ctx context.Context, fn func() error) error {
+	if l == nil {
+		return fn()
+	}
+	if err := l.sem.Acquire(ctx, 1); err != nil {
+		return err
+	}
+	defer l.sem.Release(1)
+	return fn()
+}
+
+// forEachLayer calls fn for each of the n layers of a Zarf pkg with at most limit calls running at the same time, a
+// limit below 1 runs them one at a time. Every call runs even if another fails so a single create reports every layer
+// that failed, the errors are joined in layer order rather than the order they happened in
+func forEachLayer(n, limit int, fn func(i int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// forEachLayer calls fn for each of the n layers of the Zarf pkg like forEachLayer, each call also waits for a slot of
+// the create's Limiter
+func (p *RemotePusher) forEachLayer(ctx context.Context, n, limit int, fn func(i int) error) error {
+	return forEachLayer(n, limit, func(i int) error {
+		return p.cfg.Limiter.Do(ctx, func() error { return fn(i) })
+	})
+}
@@ -0,0 +1,91 @@
+package pusher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_forEachLayer(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+	}{
+		{name: "one at a time", limit: 0},
+		{name: "bounded", limit: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning atomic.Int32
+			called := make([]bool, 10)
+			err := forEachLayer(len(called), tt.limit, func(i int) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				called[i] = true
+				if i%4 == 1 {
+					return fmt.Errorf("layer %d failed", i)
+				}
+				return nil
+			})
+			// every layer is attempted and the errors are reported in layer order
+			require.EqualError(t, err, "layer 1 failed\nlayer 5 failed\nlayer 9 failed")
+			require.NotContains(t, called, false)
+			require.LessOrEqual(t, maxRunning.Load(), int32(max(tt.limit, 1)))
+		})
+	}
+}
+
+func Test_Limiter(t *testing.T) {
+	// the limit is shared by every pusher, whatever their own layer concurrency
+	limiter := NewLimiter(2)
+	var running, maxRunning atomic.Int32
+	op := func(int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := NewPkgPusher(types.Package{}, Config{Limiter: limiter})
+			require.NoError(t, p.forEachLayer(context.Background(), 10, 5, op))
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(2), maxRunning.Load())
+
+	// no limit is a nil limiter, which runs every operation
+	require.Nil(t, NewLimiter(0))
+	var noLimit *Limiter
+	require.NoError(t, noLimit.Do(context.Background(), func() error { return nil }))
+
+	// waiting for a slot stops when the create is canceled
+	limiter = NewLimiter(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, limiter.Do(ctx, func() error {
+		cancel()
+		return nil
+	}))
+	require.ErrorIs(t, limiter.Do(ctx, func() error { return nil }), context.Canceled)
+}
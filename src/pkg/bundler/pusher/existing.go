@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package pusher contains functionality to push Zarf pkgs to remote bundles
+package pusher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// existingBlobs returns the digests of the descs that already exist in the remote bundle, e.g. pushed by a previous
+// create that was interrupted, so re-running the create only pushes what's missing. A blob only counts as existing if
+// the registry has it with the same size, a blob with a different size is pushed again
+func existingBlobs(ctx context.Context, dst *zoci.Remote, descs ...ocispec.Descriptor) (map[digest.Digest]bool, error) {
+	existing := make(map[digest.Digest]bool)
+	for _, desc := range descs {
+		if desc.Digest == "" || existing[desc.Digest] {
+			continue
+		}
+		resolved, err := dst.Repo().Blobs().Resolve(ctx, desc.Digest.String())
+		if errors.Is(err, errdef.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to check if %s exists in %s: %w", desc.Digest, dst.Repo().Reference, err)
+		}
+		if resolved.Size == desc.Size {
+			existing[desc.Digest] = true
+		}
+	}
+	return existing, nil
+}
@@ -0,0 +1,54 @@
+package pusher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_existingBlobs(t *testing.T) {
+	existing := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("existing"))
+	truncated := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("truncated"))
+	missing := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("missing"))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			return
+		}
+		for _, desc := range []ocispec.Descriptor{existing, truncated} {
+			if strings.HasSuffix(r.URL.Path, desc.Digest.String()) {
+				size := desc.Size
+				if desc.Digest == truncated.Digest {
+					size--
+				}
+				w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+				w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	platform := ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}
+	dst, err := zoci.NewRemote(host+"/dev/bundle:0.0.1", platform, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	// a blob with a different size than the source's is pushed again
+	found, err := existingBlobs(context.Background(), dst, existing, truncated, missing, ocispec.Descriptor{})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.True(t, found[existing.Digest])
+}
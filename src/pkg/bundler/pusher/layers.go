@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package pusher contains functionality to push Zarf pkgs to remote bundles
+package pusher
+
+import (
+	"sync"
+
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// PushedLayers tracks the layers written to each remote bundle during a single create, so layers shared by
+// multiple Zarf pkgs are only pushed once
+type PushedLayers struct {
+	mu     sync.Mutex
+	layers map[string]struct{}
+}
+
+// NewPushedLayers creates an empty set of pushed layers
+func NewPushedLayers() *PushedLayers {
+	return &PushedLayers{layers: make(map[string]struct{})}
+}
+
+// claim returns the layers that haven't been claimed by another pusher for the remote bundle yet, marking them as
+// claimed, and the layers that were already claimed
+func (s *PushedLayers) claim(dst *zoci.Remote, layers []ocispec.Descriptor) (toPush []ocispec.Descriptor, skipped []ocispec.Descriptor) {
+	if s == nil {
+		return layers, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := dst.Repo().Reference.Registry + "/" + dst.Repo().Reference.Repository
+	for _, layer := range layers {
+		key := repo + "@" + layer.Digest.String()
+		if _, ok := s.layers[key]; ok {
+			skipped = append(skipped, layer)
+			continue
+		}
+		s.layers[key] = struct{}{}
+		toPush = append(toPush, layer)
+	}
+	return toPush, skipped
+}
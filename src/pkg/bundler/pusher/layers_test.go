@@ -0,0 +1,44 @@
+package pusher
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_PushedLayers(t *testing.T) {
+	platform := ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}
+	dst, err := zoci.NewRemote("localhost:888/bundle:0.0.1", platform)
+	require.NoError(t, err)
+	mirror, err := zoci.NewRemote("localhost:889/bundle:0.0.1", platform)
+	require.NoError(t, err)
+
+	base := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("base"))
+	app := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("app"))
+	other := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("other"))
+
+	pushed := NewPushedLayers()
+	toPush, skipped := pushed.claim(dst, []ocispec.Descriptor{base, app})
+	require.Equal(t, []ocispec.Descriptor{base, app}, toPush)
+	require.Empty(t, skipped)
+
+	// a second pkg sharing the base layer only pushes its own layers
+	toPush, skipped = pushed.claim(dst, []ocispec.Descriptor{base, other})
+	require.Equal(t, []ocispec.Descriptor{other}, toPush)
+	require.Equal(t, []ocispec.Descriptor{base}, skipped)
+
+	// layers are tracked per destination
+	toPush, skipped = pushed.claim(mirror, []ocispec.Descriptor{base})
+	require.Equal(t, []ocispec.Descriptor{base}, toPush)
+	require.Empty(t, skipped)
+
+	// without a set every layer is pushed
+	var noDedup *PushedLayers
+	toPush, skipped = noDedup.claim(dst, []ocispec.Descriptor{base})
+	require.Equal(t, []ocispec.Descriptor{base}, toPush)
+	require.Empty(t, skipped)
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package pusher contains functionality to push Zarf pkgs to remote bundles
+package pusher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	goyaml "github.com/goccy/go-yaml"
+	av3 "github.com/mholt/archiver/v3"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// StageLocalPkg pushes a local Zarf pkg tarball's layers and manifest to a remote bundle the way Zarf publishes a pkg,
+// so the pkg can be pushed to each remote bundle like a remote Zarf pkg. It returns a remote for the staged pkg,
+// referenced by the digest of its manifest. A quiet stage doesn't start a spinner or write a success line
+func StageLocalPkg(ctx context.Context, pkg types.Package, dst *zoci.Remote, credential auth.Credential, pushedBlobs *PushedBlobs, quiet bool) (*zoci.Remote, error) {
+	spinner := newReporter(false, quiet, "Staging local package %s", pkg.Name)
+	defer spinner.Stop()
+
+	pkgTmp, err := zarfUtils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(pkgTmp)
+	if err := av3.Unarchive(pkg.Path, pkgTmp); err != nil {
+		return nil, fmt.Errorf("%s is not a valid Zarf package, unable to extract it: %w", pkg.Path, err)
+	}
+	zarfPkg, err := loadLocalPkg(pkgTmp, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := file.New(pkgTmp)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	var descs []ocispec.Descriptor
+	err = filepath.WalkDir(pkgTmp, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(pkgTmp, path)
+		if err != nil {
+			return err
+		}
+		desc, err := src.Add(ctx, filepath.ToSlash(name), zoci.ZarfLayerMediaTypeBlob, path)
+		if err != nil {
+			return err
+		}
+		descs = append(descs, desc)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the layers of the package: %w", err)
+	}
+
+	// the config records the pkg's arch, the pkg's platform is checked against it before it's pushed to the bundle
+	annotations := map[string]string{
+		ocispec.AnnotationTitle:       zarfPkg.Metadata.Name,
+		ocispec.AnnotationDescription: zarfPkg.Metadata.Description,
+	}
+	configBytes, err := json.Marshal(oci.ConfigPartial{
+		Architecture: zarfPkg.Build.Architecture,
+		OCIVersion:   specs.Version,
+		Annotations:  annotations,
+	})
+	if err != nil {
+		return nil, err
+	}
+	configDesc := content.NewDescriptorFromBytes(zoci.ZarfConfigMediaType, configBytes)
+	if err := src.Push(ctx, configDesc, bytes.NewReader(configBytes)); err != nil {
+		return nil, err
+	}
+	root, err := oras.PackManifest(ctx, src, oras.PackManifestVersion1_1_RC4, "", oras.PackManifestOptions{
+		Layers:              descs,
+		ConfigDescriptor:    &configDesc,
+		ManifestAnnotations: annotations,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := pushedBlobs.Track(ctx, dst, append(descs, configDesc, root)...); err != nil {
+		return nil, err
+	}
+
+	spinner.Updatef("Pushing local package %s to %s", pkg.Name, dst.Repo().Reference)
+	err = utils.RetryOCI(ctx, "stage "+pkg.Name, func() error {
+		_, err := oras.Copy(ctx, src, root.Digest.String(), dst.Repo(), "", dst.GetDefaultCopyOpts())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stagedRef := dst.Repo().Reference
+	stagedRef.Reference = root.Digest.String()
+	staged, err := zoci.NewRemote(stagedRef.String(), utils.GetPkgPlatform(pkg), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+	if err != nil {
+		return nil, err
+	}
+	utils.WithCredential(staged.OrasRemote, credential)
+	spinner.Successf("Staged local package %s", pkg.Name)
+	return staged, nil
+}
+
+// loadLocalPkg reads the zarf.yaml of a local Zarf pkg extracted into dir, returning an error if it isn't a Zarf pkg
+// or isn't the pkg the bundle references
+func loadLocalPkg(dir string, pkg types.Package) (zarfTypes.ZarfPackage, error) {
+	b, err := os.ReadFile(filepath.Join(dir, config.ZarfYAML))
+	if errors.Is(err, os.ErrNotExist) {
+		return zarfTypes.ZarfPackage{}, fmt.Errorf("%s is not a valid Zarf package, it doesn't have a %s", pkg.Path, config.ZarfYAML)
+	}
+	if err != nil {
+		return zarfTypes.ZarfPackage{}, err
+	}
+	var zarfPkg zarfTypes.ZarfPackage
+	if err := goyaml.Unmarshal(b, &zarfPkg); err != nil {
+		return zarfTypes.ZarfPackage{}, fmt.Errorf("%s is not a valid Zarf package, unable to parse its %s: %w", pkg.Path, config.ZarfYAML, err)
+	}
+	if zarfPkg.Kind != zarfTypes.ZarfPackageConfig && zarfPkg.Kind != zarfTypes.ZarfInitConfig {
+		return zarfTypes.ZarfPackage{}, fmt.Errorf("%s is not a valid Zarf package, its kind is %q", pkg.Path, zarfPkg.Kind)
+	}
+	if zarfPkg.Build.Architecture == "" {
+		return zarfTypes.ZarfPackage{}, fmt.Errorf("%s is not a valid Zarf package, it wasn't built with zarf package create", pkg.Path)
+	}
+	if zarfPkg.Metadata.Name != pkg.Name {
+		return zarfTypes.ZarfPackage{}, fmt.Errorf("the Zarf package at %s is %s, not %s", pkg.Path, zarfPkg.Metadata.Name, pkg.Name)
+	}
+	return zarfPkg, nil
+}
@@ -0,0 +1,58 @@
+package pusher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadLocalPkg(t *testing.T) {
+	pkg := types.Package{Name: "podinfo", Path: "zarf-package-podinfo-amd64-0.0.1.tar.zst", Ref: "0.0.1"}
+	testCases := []struct {
+		name        string
+		zarfYAML    string
+		expectedErr string
+	}{
+		{
+			name:     "valid",
+			zarfYAML: "kind: ZarfPackageConfig\nmetadata:\n  name: podinfo\nbuild:\n  architecture: amd64\n",
+		},
+		{
+			name:        "missing zarf.yaml",
+			expectedErr: "zarf-package-podinfo-amd64-0.0.1.tar.zst is not a valid Zarf package, it doesn't have a zarf.yaml",
+		},
+		{
+			name:        "wrong kind",
+			zarfYAML:    "kind: UDSBundle\nmetadata:\n  name: podinfo\n",
+			expectedErr: `zarf-package-podinfo-amd64-0.0.1.tar.zst is not a valid Zarf package, its kind is "UDSBundle"`,
+		},
+		{
+			name:        "not built",
+			zarfYAML:    "kind: ZarfPackageConfig\nmetadata:\n  name: podinfo\n",
+			expectedErr: "zarf-package-podinfo-amd64-0.0.1.tar.zst is not a valid Zarf package, it wasn't built with zarf package create",
+		},
+		{
+			name:        "different package",
+			zarfYAML:    "kind: ZarfPackageConfig\nmetadata:\n  name: nginx\nbuild:\n  architecture: amd64\n",
+			expectedErr: "the Zarf package at zarf-package-podinfo-amd64-0.0.1.tar.zst is nginx, not podinfo",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tc.zarfYAML != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "zarf.yaml"), []byte(tc.zarfYAML), 0600))
+			}
+			zarfPkg, err := loadLocalPkg(dir, pkg)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "amd64", zarfPkg.Build.Architecture)
+		})
+	}
+}
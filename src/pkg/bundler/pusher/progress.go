@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package pusher contains functionality to push Zarf pkgs to remote bundles
+package pusher
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/term"
+)
+
+// progressLogStep is the percentage between progress logs when stdout isn't a TTY
+const progressLogStep = 10
+
+// ProgressUpdate is reported to a ProgressFn each time bytes of a Zarf pkg are pushed
+type ProgressUpdate struct {
+	// Package is the name of the Zarf pkg being pushed
+	Package string
+	// Layer is the digest of the layer being pushed
+	Layer digest.Digest
+	// Bytes is the number of bytes pushed since the last update for the layer, it's negative when the bytes of a push
+	// that failed partway are taken back
+	Bytes int64
+}
+
+// ProgressFn is called as the pusher writes layers, it's called from every pusher's goroutine so it must be safe for
+// concurrent use when packages are pushed concurrently
+type ProgressFn func(update ProgressUpdate)
+
+// PackagePushed is reported to a PackagePushedFn each time a Zarf pkg finishes pushing to every remote bundle
+type PackagePushed struct {
+	// Package is the name of the Zarf pkg that was pushed
+	Package string
+	// Manifest is the desc of the Zarf pkg's manifest in the remote bundles
+	Manifest ocispec.Descriptor
+	// Bytes is the size of the manifest, config and layers pushed for the pkg across every remote bundle
+	Bytes int64
+	// Duration is how long the pkg took to push
+	Duration time.Duration
+}
+
+// PackagePushedFn is called when a Zarf pkg finishes pushing, it's called from every pusher's goroutine so it must be
+// safe for concurrent use when packages are pushed concurrently
+type PackagePushedFn func(pushed PackagePushed)
+
+// Progress aggregates the bytes pushed by every pusher into a single progress bar, falling back to periodic
+// percentage logs when stdout isn't a TTY
+type Progress struct {
+	mu         sync.Mutex
+	bar        *message.ProgressBar
+	title      string
+	total      int64
+	current    int64
+	lastLogged int64
+	quiet      bool
+}
+
+// NewProgress creates a Progress for a push of total bytes, a quiet Progress only counts the bytes
+func NewProgress(total int64, title string, quiet bool) *Progress {
+	p := &Progress{title: title, total: total, quiet: quiet}
+	if quiet {
+		return p
+	}
+	if !message.NoProgress && term.IsTerminal(int(os.Stdout.Fd())) {
+		p.bar = message.NewProgressBar(total, title)
+	} else {
+		message.Infof("%s (%s)", title, zarfUtils.ByteFormat(float64(total), 2))
+	}
+	return p
+}
+
+// Add records n pushed bytes
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += n
+	if p.bar != nil {
+		p.bar.Add(int(n))
+		return
+	}
+	if p.total <= 0 || p.quiet {
+		return
+	}
+	percent := min(p.current*100/p.total, 100)
+	if percent >= p.lastLogged+progressLogStep {
+		p.lastLogged = percent - percent%progressLogStep
+		message.Infof("%s: %d%% (%s of %s)", p.title, percent, zarfUtils.ByteFormat(float64(p.current), 2), zarfUtils.ByteFormat(float64(p.total), 2))
+	}
+}
+
+// Successf stops the progress bar and marks the push as successful
+func (p *Progress) Successf(format string, a ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.bar != nil {
+		p.bar.Successf(format, a...)
+		p.bar = nil
+		return
+	}
+	if p.quiet {
+		return
+	}
+	message.Successf(format, a...)
+}
+
+// Stop stops the progress bar, if there is one
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.bar != nil {
+		p.bar.Stop()
+		p.bar = nil
+	}
+}
+
+// UpdateTitle prefixes a Zarf pkg's copy status with the pkg name, it's only logged at debug level without a progress
+// bar. A nil Progress logs the status
+func (p *Progress) UpdateTitle(name, title string) {
+	title = fmt.Sprintf("%s: %s", name, title)
+	if p == nil {
+		message.Debug(title)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.bar != nil {
+		p.bar.UpdateTitle(title)
+		return
+	}
+	message.Debug(title)
+}
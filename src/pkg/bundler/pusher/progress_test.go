@@ -0,0 +1,86 @@
+package pusher
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_Progress(t *testing.T) {
+	// stdout isn't a TTY under go test, so this exercises the percentage log fallback
+	progress := NewProgress(1000, "Pushing bundle test", false)
+	defer progress.Stop()
+	require.Nil(t, progress.bar)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			progress.Add(25)
+			progress.UpdateTitle("test", "[1/1] layers copied")
+			progress.Add(25)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int64(500), progress.current)
+	require.Equal(t, int64(50), progress.lastLogged)
+
+	progress.Add(495)
+	require.Equal(t, int64(90), progress.lastLogged)
+	progress.Add(100)
+	require.Equal(t, int64(100), progress.lastLogged)
+}
+
+func Test_ProgressQuiet(t *testing.T) {
+	// a quiet progress counts the pushed bytes without logging percentages
+	progress := NewProgress(1000, "Pushing bundle test", true)
+	defer progress.Stop()
+	require.Nil(t, progress.bar)
+
+	progress.Add(500)
+	require.Equal(t, int64(500), progress.current)
+	require.Equal(t, int64(0), progress.lastLogged)
+}
+
+func Test_ProgressUpdateTitle(t *testing.T) {
+	// a nil progress only logs the title, so a pusher without a progress bar can still report its layers
+	var noProgress *Progress
+	noProgress.UpdateTitle("test", "[1/2] layers copied")
+}
+
+func Test_reportPushed(t *testing.T) {
+	manifest := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("manifest"))
+
+	// without a PackagePushedFn nothing is reported
+	p := NewPkgPusher(types.Package{Name: "podinfo"}, Config{})
+	p.reportPushed(manifest, 1024, time.Second)
+
+	var reported []PackagePushed
+	p = NewPkgPusher(types.Package{Name: "podinfo"}, Config{PackagePushedFn: func(pushed PackagePushed) {
+		reported = append(reported, pushed)
+	}})
+	p.reportPushed(manifest, 1024, time.Second)
+	require.Equal(t, []PackagePushed{{Package: "podinfo", Manifest: manifest, Bytes: 1024, Duration: time.Second}}, reported)
+}
+
+func Test_copyOrder(t *testing.T) {
+	zarfYAML := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("zarf.yaml"))
+	first := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("first"))
+	second := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("second"))
+	config := content.NewDescriptorFromBytes(ocispec.MediaTypeImageConfig, []byte("config"))
+	root := &oci.Manifest{Manifest: ocispec.Manifest{Config: config, Layers: []ocispec.Descriptor{first, zarfYAML, second}}}
+
+	// layers are copied in root manifest order, not the order they were requested in
+	require.Equal(t, []ocispec.Descriptor{first, second, config}, copyOrder(root, []ocispec.Descriptor{config, second, first}))
+	// the config is only copied if it's requested, e.g. not if another pkg already pushed it
+	require.Equal(t, []ocispec.Descriptor{first, second}, copyOrder(root, []ocispec.Descriptor{second, first}))
+}
@@ -6,16 +6,26 @@ package pusher
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/config"
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/layout"
 	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
 	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
 )
 
 // RemotePusher contains methods for pulling remote Zarf packages into a bundle
@@ -28,10 +38,40 @@ type RemotePusher struct {
 type Config struct {
 	PkgRootManifest *oci.Manifest
 	RemoteSrc       zoci.Remote
-	RemoteDst       zoci.Remote
-	PkgIter         int
-	NumPkgs         int
-	Bundle          *types.UDSBundle
+	// RemoteDsts are the remote bundles to push the Zarf pkg to, the pkg's layers are only resolved once for all of them
+	RemoteDsts []*zoci.Remote
+	PkgIter    int
+	NumPkgs    int
+	Bundle     *types.UDSBundle
+	// Concurrent is true when multiple packages are being pushed at the same time
+	Concurrent bool
+	// VerifyKeys are paths to public keys used to verify the source Zarf pkg's signature before pushing it
+	VerifyKeys []string
+	// Progress tracks the bytes pushed across all of the bundle's Zarf pkgs
+	Progress *Progress
+	// ProgressFn is called as layers are pushed, it's optional
+	ProgressFn ProgressFn
+	// PackagePushedFn is called once the pkg is pushed to every remote bundle, it's optional
+	PackagePushedFn PackagePushedFn
+	// Logger receives structured events as the pkg is pushed, the events are dropped if it's nil
+	Logger *slog.Logger
+	// PushedLayers is shared by every pusher in a create, layers already pushed by another pusher are skipped
+	PushedLayers *PushedLayers
+	// PushedBlobs tracks the blobs that are new to each remote bundle so they can be deleted if the create fails, nil
+	// unless failed creates are cleaned up
+	PushedBlobs *PushedBlobs
+	// Pruned is the Zarf pkg with the images excluded by the bundle removed, nil if no images are excluded
+	Pruned *utils.PrunedPackage
+	// CompressionLevel is the zstd level the Zarf pkg's component tarballs are compressed at, 0 pushes them as is
+	CompressionLevel int
+	// Quiet suppresses the spinners and success lines, warnings are still written
+	Quiet bool
+	// LayerConcurrency is the number of the pkg's layers pushed at the same time, layers streamed from another
+	// registry default to the OCI concurrency and the others to one at a time if it's below 1
+	LayerConcurrency int
+	// Limiter is shared by every pusher and the fetch of the Zarf pkgs' root manifests, each layer operation waits for
+	// one of its slots; nil doesn't limit them beyond LayerConcurrency
+	Limiter *Limiter
 }
 
 // NewPkgPusher creates a pusher object to push Zarf pkgs to a remote bundle
@@ -39,96 +79,423 @@ func NewPkgPusher(pkg types.Package, cfg Config) RemotePusher {
 	return RemotePusher{pkg: pkg, cfg: cfg}
 }
 
-// Push pushes a Zarf pkg to a remote bundle
-func (p *RemotePusher) Push() (ocispec.Descriptor, error) {
-	zarfManifestDesc, err := p.PushManifest()
+// Push pushes a Zarf pkg to each remote bundle, returning the pkg's manifest desc and the total size of the layers pushed
+func (p *RemotePusher) Push(ctx context.Context) (ocispec.Descriptor, int64, error) {
+	start := time.Now()
+	if len(p.cfg.VerifyKeys) > 0 {
+		if err := p.verifySignature(ctx); err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+	}
+
+	// spinners and the aggregate progress bar would fight over the terminal, so fall back to log lines
+	pushSpinner := newReporter(p.cfg.Concurrent || p.cfg.Progress != nil, p.cfg.Quiet, "")
+	defer pushSpinner.Stop()
+
+	pushSpinner.Updatef("Fetching %s package layer metadata (package %d of %d)", p.pkg.Name, p.cfg.PkgIter+1, len(p.cfg.Bundle.Packages))
+	// get only the layers that are required by the components
+	layersToCopy, err := utils.GetZarfLayers(ctx, p.cfg.RemoteSrc, p.cfg.PkgRootManifest, p.pkg.OptionalComponents)
 	if err != nil {
-		return ocispec.Descriptor{}, err
+		return ocispec.Descriptor{}, 0, err
 	}
+	// drop the images excluded by the bundle and override image registries, the pkg's metadata is rewritten to match
+	var rewrittenBlobs []utils.RewrittenBlob
+	if pruned := p.cfg.Pruned; pruned != nil {
+		if len(pruned.ExcludedImages) > 0 {
+			message.Debugf("Excluding images from package %s: %s", p.pkg.Name, strings.Join(pruned.ExcludedImages, ", "))
+			p.log().Info("excluding images", "package", p.pkg.Name, "images", pruned.ExcludedImages)
+		}
+		for image, rewritten := range pruned.OverriddenImages {
+			message.Debugf("Overriding the registry of image %s in package %s: %s", image, p.pkg.Name, rewritten)
+		}
+		p.cfg.PkgRootManifest = pruned.Root
+		layersToCopy = pruned.Filter(layersToCopy)
+		rewrittenBlobs = pruned.Blobs
+	}
+	layersToCopy = oci.RemoveDuplicateDescriptors(layersToCopy)
+	// compress the component tarballs once for every destination, the pkg's manifest is rewritten to reference them
+	var compressedLayers []utils.CompressedLayer
+	if p.cfg.CompressionLevel > 0 {
+		tmpDir, err := zarfUtils.MakeTempDir(config.CommonOptions.TempDirectory)
+		if err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+		defer os.RemoveAll(tmpDir)
+		pushSpinner.Updatef("Compressing %s package layers (package %d of %d)", p.pkg.Name, p.cfg.PkgIter+1, len(p.cfg.Bundle.Packages))
+		if layersToCopy, compressedLayers, err = p.compressLayers(ctx, tmpDir, layersToCopy); err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+	}
+	pushSpinner.Stop()
 
-	// ensure media type is a Zarf blob and append to bundle root manifest
-	zarfManifestDesc.MediaType = zoci.ZarfLayerMediaTypeBlob
+	var zarfManifestDesc ocispec.Descriptor
+	var pushedBytes int64
 	url := fmt.Sprintf("%s:%s", p.pkg.Repository, p.pkg.Ref)
-	message.Debugf("Pushed %s sub-manifest into %s: %s", url, p.cfg.RemoteDst.Repo().Reference, message.JSONValue(zarfManifestDesc))
+	for _, dst := range p.cfg.RemoteDsts {
+		if err := p.trackManifest(ctx, dst); err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+		err = p.cfg.Limiter.Do(ctx, func() (err error) {
+			zarfManifestDesc, err = p.PushManifest(ctx, dst)
+			return err
+		})
+		if err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
 
-	pushSpinner := message.NewProgressSpinner("")
-	defer pushSpinner.Stop()
+		p.addProgress(zarfManifestDesc.Digest, zarfManifestDesc.Size)
 
-	_, err = p.LayersToRemoteBundle(pushSpinner, p.cfg.PkgIter+1, len(p.cfg.Bundle.Packages))
-	if err != nil {
-		return ocispec.Descriptor{}, err
+		// ensure media type is a Zarf blob and append to bundle root manifest
+		zarfManifestDesc.MediaType = zoci.ZarfLayerMediaTypeBlob
+		message.Debugf("Pushed %s sub-manifest into %s: %s", url, dst.Repo().Reference, message.JSONValue(zarfManifestDesc))
+		p.log().Debug("pushed package manifest", "package", p.pkg.Name, "source", url, "destination", dst.Repo().Reference.String(), "digest", zarfManifestDesc.Digest.String(), "bytes", zarfManifestDesc.Size)
+
+		// skip the layers another pkg in the bundle already pushed, they're verified by the pusher that claimed them
+		layersToPush, skipped := p.cfg.PushedLayers.claim(dst, layersToCopy)
+		for _, layer := range skipped {
+			message.Debugf("Skipping layer %s of package %s, it was already pushed to %s", layer.Digest, p.pkg.Name, dst.Repo().Reference)
+			p.log().Debug("skipped layer", "package", p.pkg.Name, "destination", dst.Repo().Reference.String(), "digest", layer.Digest.String(), "bytes", layer.Size)
+			p.addProgress(layer.Digest, layer.Size)
+		}
+
+		// skip the blobs that are already in the remote bundle, e.g. pushed by an interrupted create
+		layersToPush, dstRewrittenBlobs, dstCompressedLayers, err := p.skipExisting(ctx, dst, layersToPush, rewrittenBlobs, compressedLayers)
+		if err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+
+		if err := p.cfg.PushedBlobs.Track(ctx, dst, layersToPush...); err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+		for _, blob := range dstRewrittenBlobs {
+			if err := p.cfg.PushedBlobs.Track(ctx, dst, blob.Desc); err != nil {
+				return ocispec.Descriptor{}, 0, err
+			}
+		}
+		for _, layer := range dstCompressedLayers {
+			if err := p.cfg.PushedBlobs.Track(ctx, dst, layer.Desc); err != nil {
+				return ocispec.Descriptor{}, 0, err
+			}
+		}
+
+		pushSpinner.Updatef("Pushing package %s layers to %s (package %d of %d)", p.pkg.Name, dst.Repo().Reference.Registry, p.cfg.PkgIter+1, len(p.cfg.Bundle.Packages))
+		if err := p.remoteToRemote(ctx, dst, layersToPush); err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+		rewrittenDescs, err := p.pushRewrittenBlobs(ctx, dst, dstRewrittenBlobs)
+		if err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+		compressedDescs, err := p.pushCompressedLayers(ctx, dst, dstCompressedLayers)
+		if err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+		rewrittenDescs = append(rewrittenDescs, compressedDescs...)
+		if err := p.verifyLayers(ctx, dst, append(layersToPush, rewrittenDescs...)); err != nil {
+			return ocispec.Descriptor{}, 0, err
+		}
+		// the pkg's config is one of the layers, so it only counts if this pusher pushed it
+		pushedBytes += zarfManifestDesc.Size
+		for _, layer := range append(layersToPush, rewrittenDescs...) {
+			pushedBytes += layer.Size
+		}
 	}
 
 	pushSpinner.Successf("Pushed package: %s", p.pkg.Name)
-	return zarfManifestDesc, nil
+	p.log().Info("pushed package", "package", p.pkg.Name, "digest", zarfManifestDesc.Digest.String(), "bytes", pushedBytes, "duration", time.Since(start))
+	p.reportPushed(zarfManifestDesc, pushedBytes, time.Since(start))
+	return zarfManifestDesc, pushedBytes, nil
+}
+
+// skipExisting drops the layers, rewritten blobs and compressed layers that already exist in the remote bundle with the
+// same digest and size, counting them towards the progress as if they were pushed
+func (p *RemotePusher) skipExisting(ctx context.Context, dst *zoci.Remote, layers []ocispec.Descriptor, rewrittenBlobs []utils.RewrittenBlob, compressedLayers []utils.CompressedLayer) ([]ocispec.Descriptor, []utils.RewrittenBlob, []utils.CompressedLayer, error) {
+	descs := append([]ocispec.Descriptor{}, layers...)
+	for _, blob := range rewrittenBlobs {
+		descs = append(descs, blob.Desc)
+	}
+	for _, layer := range compressedLayers {
+		descs = append(descs, layer.Desc)
+	}
+	existing, err := existingBlobs(ctx, dst, descs...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(existing) == 0 {
+		return layers, rewrittenBlobs, compressedLayers, nil
+	}
+
+	skip := func(desc ocispec.Descriptor, progressBytes int64) bool {
+		if !existing[desc.Digest] {
+			return false
+		}
+		message.Debugf("Skipping layer %s of package %s, it already exists in %s", desc.Digest, p.pkg.Name, dst.Repo().Reference)
+		p.log().Debug("skipped existing layer", "package", p.pkg.Name, "destination", dst.Repo().Reference.String(), "digest", desc.Digest.String(), "bytes", desc.Size)
+		p.addProgress(desc.Digest, progressBytes)
+		return true
+	}
+	var missingLayers []ocispec.Descriptor
+	for _, layer := range layers {
+		if !skip(layer, layer.Size) {
+			missingLayers = append(missingLayers, layer)
+		}
+	}
+	var missingBlobs []utils.RewrittenBlob
+	for _, blob := range rewrittenBlobs {
+		if !skip(blob.Desc, blob.Desc.Size) {
+			missingBlobs = append(missingBlobs, blob)
+		}
+	}
+	var missingCompressed []utils.CompressedLayer
+	for _, layer := range compressedLayers {
+		// the progress was sized from the source layers
+		if !skip(layer.Desc, layer.Source.Size) {
+			missingCompressed = append(missingCompressed, layer)
+		}
+	}
+	return missingLayers, missingBlobs, missingCompressed, nil
 }
 
-// PushManifest pushes the Zarf pkg's manifest to either a local or remote bundle
-func (p *RemotePusher) PushManifest() (ocispec.Descriptor, error) {
+// pushRewrittenBlobs pushes the Zarf pkg metadata rewritten by excluding images, these don't exist in the source pkg
+func (p *RemotePusher) pushRewrittenBlobs(ctx context.Context, dst *zoci.Remote, blobs []utils.RewrittenBlob) ([]ocispec.Descriptor, error) {
+	descs := make([]ocispec.Descriptor, len(blobs))
+	err := p.forEachLayer(ctx, len(blobs), p.cfg.LayerConcurrency, func(i int) error {
+		blob := blobs[i]
+		err := utils.RetryOCI(ctx, "push "+blob.Desc.Annotations[ocispec.AnnotationTitle], func() error {
+			_, err := dst.PushLayer(ctx, blob.Content, blob.Desc.MediaType)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		p.addProgress(blob.Desc.Digest, blob.Desc.Size)
+		descs[i] = blob.Desc
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return descs, nil
+}
+
+// compressLayers compresses the component tarballs in layersToCopy into dir, returning the layers that are still copied
+// from the source pkg; the pkg's manifest is rewritten to reference the compressed layers, a layer that doesn't shrink
+// is copied as is
+func (p *RemotePusher) compressLayers(ctx context.Context, dir string, layersToCopy []ocispec.Descriptor) ([]ocispec.Descriptor, []utils.CompressedLayer, error) {
+	var toCopy []ocispec.Descriptor
+	var compressedLayers []utils.CompressedLayer
+	replaced := make(map[digest.Digest]ocispec.Descriptor)
+	for _, layer := range layersToCopy {
+		if !utils.IsCompressibleLayer(layer) {
+			toCopy = append(toCopy, layer)
+			continue
+		}
+		compressed, err := utils.CompressLayer(ctx, p.cfg.RemoteSrc.OrasRemote, layer, p.cfg.CompressionLevel, dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to compress a layer of package %s: %w", p.pkg.Name, err)
+		}
+		if compressed.Desc.Size >= layer.Size {
+			message.Debugf("Not compressing %s of package %s, it doesn't get smaller", layer.Annotations[ocispec.AnnotationTitle], p.pkg.Name)
+			toCopy = append(toCopy, layer)
+			continue
+		}
+		p.log().Debug("compressed layer", "package", p.pkg.Name, "title", layer.Annotations[ocispec.AnnotationTitle], "digest", compressed.Desc.Digest.String(), "bytes", compressed.Desc.Size, "sourceBytes", layer.Size)
+		compressedLayers = append(compressedLayers, compressed)
+		replaced[layer.Digest] = compressed.Desc
+	}
+	if len(compressedLayers) == 0 {
+		return toCopy, nil, nil
+	}
+
+	// the root manifest may be shared with other pushers, so it's copied before it's rewritten
+	root := &oci.Manifest{Manifest: p.cfg.PkgRootManifest.Manifest}
+	root.Layers = make([]ocispec.Descriptor, len(p.cfg.PkgRootManifest.Layers))
+	for i, layer := range p.cfg.PkgRootManifest.Layers {
+		if compressed, ok := replaced[layer.Digest]; ok {
+			layer = compressed
+		}
+		root.Layers[i] = layer
+	}
+	p.cfg.PkgRootManifest = root
+	return toCopy, compressedLayers, nil
+}
+
+// pushCompressedLayers pushes the compressed component tarballs, these don't exist in the source pkg
+func (p *RemotePusher) pushCompressedLayers(ctx context.Context, dst *zoci.Remote, layers []utils.CompressedLayer) ([]ocispec.Descriptor, error) {
+	descs := make([]ocispec.Descriptor, len(layers))
+	err := p.forEachLayer(ctx, len(layers), p.cfg.LayerConcurrency, func(i int) error {
+		layer := layers[i]
+		err := utils.RetryOCI(ctx, "push "+layer.Desc.Annotations[ocispec.AnnotationTitle], func() error {
+			f, err := os.Open(layer.Path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return dst.Repo().Blobs().Push(ctx, layer.Desc, f)
+		})
+		if err != nil {
+			return err
+		}
+		// the progress was sized from the source layers
+		p.addProgress(layer.Desc.Digest, layer.Source.Size)
+		descs[i] = layer.Desc
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return descs, nil
+}
+
+// verifySignature verifies the source Zarf pkg's signature against any of the provided public keys
+func (p *RemotePusher) verifySignature(ctx context.Context) error {
+	url := fmt.Sprintf("%s:%s", p.pkg.Repository, p.pkg.Ref)
+	if oci.IsEmptyDescriptor(p.cfg.PkgRootManifest.Locate(layout.Signature)) {
+		return fmt.Errorf("unable to verify package %s: package is not signed", url)
+	}
+
+	tmpDir, err := zarfUtils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if _, err := p.cfg.RemoteSrc.PullPaths(ctx, tmpDir, []string{layout.ZarfYAML, layout.Signature}); err != nil {
+		return fmt.Errorf("unable to pull the signature of package %s: %w", url, err)
+	}
+	zarfYAMLPath := filepath.Join(tmpDir, layout.ZarfYAML)
+	signaturePath := filepath.Join(tmpDir, layout.Signature)
+	for _, key := range p.cfg.VerifyKeys {
+		if err = zarfUtils.CosignVerifyBlob(zarfYAMLPath, signaturePath, key); err == nil {
+			return nil
+		}
+		message.Debugf("Package %s signature doesn't match key %s: %s", url, key, err)
+		p.log().Debug("package signature doesn't match key", "package", p.pkg.Name, "key", key, "error", err)
+	}
+	return fmt.Errorf("unable to verify the signature of package %s: %w", url, err)
+}
+
+// verifyLayers checks that each layer pushed to the remote bundle matches the digest and size of the source layer
+func (p *RemotePusher) verifyLayers(ctx context.Context, dst *zoci.Remote, layers []ocispec.Descriptor) error {
+	return p.forEachLayer(ctx, len(layers), p.cfg.LayerConcurrency, func(i int) error {
+		layer := layers[i]
+		if layer.Digest == "" {
+			return nil
+		}
+		pushedDesc, err := dst.Repo().Blobs().Resolve(ctx, layer.Digest.String())
+		if err != nil {
+			return fmt.Errorf("unable to verify layer %s of package %s in %s: %w", layer.Digest, p.pkg.Name, dst.Repo().Reference, err)
+		}
+		if pushedDesc.Digest != layer.Digest || pushedDesc.Size != layer.Size {
+			return fmt.Errorf("layer %s of package %s in %s doesn't match the source: expected %s (%d bytes), got %s (%d bytes)",
+				layer.Digest, p.pkg.Name, dst.Repo().Reference, layer.Digest, layer.Size, pushedDesc.Digest, pushedDesc.Size)
+		}
+		return nil
+	})
+}
+
+// log returns the pusher's structured logger
+func (p *RemotePusher) log() *slog.Logger {
+	return utils.LoggerOrDiscard(p.cfg.Logger)
+}
+
+// addProgress records n pushed bytes of a layer if the push is being tracked
+func (p *RemotePusher) addProgress(layer digest.Digest, n int64) {
+	if p.cfg.Progress != nil {
+		p.cfg.Progress.Add(n)
+	}
+	if p.cfg.ProgressFn != nil {
+		p.cfg.ProgressFn(ProgressUpdate{Package: p.pkg.Name, Layer: layer, Bytes: n})
+	}
+}
+
+// reportPushed tells the PackagePushedFn the pkg finished pushing if one is set
+func (p *RemotePusher) reportPushed(zarfManifestDesc ocispec.Descriptor, pushedBytes int64, duration time.Duration) {
+	if p.cfg.PackagePushedFn != nil {
+		p.cfg.PackagePushedFn(PackagePushed{Package: p.pkg.Name, Manifest: zarfManifestDesc, Bytes: pushedBytes, Duration: duration})
+	}
+}
+
+// PushManifest pushes the Zarf pkg's manifest to a remote bundle
+func (p *RemotePusher) PushManifest(ctx context.Context, dst *zoci.Remote) (ocispec.Descriptor, error) {
 	var zarfManifestDesc ocispec.Descriptor
-	desc, err := utils.ToOCIRemote(p.cfg.PkgRootManifest, zoci.ZarfLayerMediaTypeBlob, p.cfg.RemoteDst.OrasRemote)
+	desc, err := utils.ToOCIRemote(ctx, p.cfg.PkgRootManifest, zoci.ZarfLayerMediaTypeBlob, dst.OrasRemote)
 	if err != nil {
 		return ocispec.Descriptor{}, err
 	}
 	zarfManifestDesc = *desc
+	if !p.cfg.Quiet {
+		message.Successf("Published %s [%s]", dst.Repo().Reference, zarfManifestDesc.MediaType)
+	}
 	return zarfManifestDesc, nil
 }
 
-// LayersToRemoteBundle pushes the Zarf pkg's layers to a remote bundle
-func (p *RemotePusher) LayersToRemoteBundle(spinner *message.Spinner, currentPackageIter int, totalPackages int) ([]ocispec.Descriptor, error) {
-	spinner.Updatef("Fetching %s package layer metadata (package %d of %d)", p.pkg.Name, currentPackageIter, totalPackages)
-	// get only the layers that are required by the components
-	layersToCopy, err := utils.GetZarfLayers(p.cfg.RemoteSrc, p.cfg.PkgRootManifest, p.pkg.OptionalComponents)
-	if err != nil {
-		return nil, err
+// trackManifest tracks the Zarf pkg's manifest if it's new to the remote bundle, it's pushed as a blob by PushManifest
+func (p *RemotePusher) trackManifest(ctx context.Context, dst *zoci.Remote) error {
+	if p.cfg.PushedBlobs == nil {
+		return nil
 	}
-	spinner.Stop()
-	spinner.Updatef("Pushing package %s layers to registry (package %d of %d)", p.pkg.Name, currentPackageIter, totalPackages)
-	err = p.remoteToRemote(layersToCopy)
+	b, err := json.Marshal(p.cfg.PkgRootManifest)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return nil, err
+	return p.cfg.PushedBlobs.Track(ctx, dst, content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, b))
 }
 
 // remoteToRemote copies a remote Zarf pkg to a remote OCI registry
-func (p *RemotePusher) remoteToRemote(layersToCopy []ocispec.Descriptor) error {
-	ctx := context.TODO()
+func (p *RemotePusher) remoteToRemote(ctx context.Context, dst *zoci.Remote, layersToCopy []ocispec.Descriptor) error {
 	srcRef := p.cfg.RemoteSrc.Repo().Reference
-	dstRef := p.cfg.RemoteDst.Repo().Reference
+	dstRef := dst.Repo().Reference
 	// stream copy if different registry
 	if srcRef.Registry != dstRef.Registry {
 		message.Debugf("Streaming layers from %s --> %s", srcRef, dstRef)
-		// filterLayers returns true if the layer is in the list of layers to copy, this allows for
-		// copying only the layers that are required by the required + specified optional components
-		filterLayers := func(d ocispec.Descriptor) bool {
-			for _, layer := range layersToCopy {
-				if layer.Digest == d.Digest {
-					return true
-				}
-			}
-			return false
+		p.log().Debug("streaming layers", "package", p.pkg.Name, "source", srcRef.String(), "destination", dstRef.String(), "layers", len(layersToCopy))
+		concurrency := config.CommonOptions.OCIConcurrency
+		if p.cfg.LayerConcurrency > 0 {
+			concurrency = p.cfg.LayerConcurrency
 		}
-		if err := oci.Copy(ctx, p.cfg.RemoteSrc.OrasRemote, p.cfg.RemoteDst.OrasRemote, filterLayers, config.CommonOptions.OCIConcurrency, nil); err != nil {
+		if err := p.streamLayers(ctx, &p.cfg.RemoteSrc, dst, copyOrder(p.cfg.PkgRootManifest, layersToCopy), concurrency); err != nil {
 			return err
 		}
 	} else {
 		// blob mount if same registry
 		message.Debugf("Performing a cross repository blob mount on %s from %s --> %s", dstRef, dstRef.Repository, dstRef.Repository)
-		spinner := message.NewProgressSpinner("Mounting layers from %s", srcRef.Repository)
-		layersToCopy = append(layersToCopy, p.cfg.PkgRootManifest.Config)
-		for _, layer := range layersToCopy {
+		p.log().Debug("mounting layers", "package", p.pkg.Name, "source", srcRef.String(), "destination", dstRef.String(), "layers", len(layersToCopy))
+		// a spinner can't be updated by concurrent mounts, so fall back to log lines
+		spinner := newReporter(p.cfg.Concurrent || p.cfg.Progress != nil || p.cfg.LayerConcurrency > 1, p.cfg.Quiet, "Mounting layers from %s", srcRef.Repository)
+		err := p.forEachLayer(ctx, len(layersToCopy), p.cfg.LayerConcurrency, func(i int) error {
+			layer := layersToCopy[i]
 			if layer.Digest == "" {
-				continue
+				return nil
 			}
 			spinner.Updatef("Mounting %s", layer.Digest.Encoded())
-			if err := p.cfg.RemoteDst.Repo().Mount(ctx, layer, srcRef.Repository, func() (io.ReadCloser, error) {
+			if err := dst.Repo().Mount(ctx, layer, srcRef.Repository, func() (io.ReadCloser, error) {
 				return p.cfg.RemoteSrc.Repo().Fetch(ctx, layer)
 			}); err != nil {
 				return err
 			}
+			p.addProgress(layer.Digest, layer.Size)
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 		spinner.Successf("Mounted %d layers", len(layersToCopy))
 	}
 	return nil
 }
+
+// copyOrder returns the layers streamLayers copies in the order it starts them, the root manifest's layers that are
+// being copied followed by its config if it's being copied
+func copyOrder(pkgRootManifest *oci.Manifest, layersToCopy []ocispec.Descriptor) []ocispec.Descriptor {
+	var layers []ocispec.Descriptor
+	for _, layer := range append(append([]ocispec.Descriptor{}, pkgRootManifest.Layers...), pkgRootManifest.Config) {
+		for _, toCopy := range layersToCopy {
+			if layer.Digest == toCopy.Digest {
+				layers = append(layers, layer)
+				break
+			}
+		}
+	}
+	return layers
+}
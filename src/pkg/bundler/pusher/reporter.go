@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package pusher contains functionality to push Zarf pkgs to remote bundles
+package pusher
+
+import (
+	"sync"
+
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+)
+
+// outputLock serializes the plain log lines written by concurrent pushers
+var outputLock sync.Mutex
+
+// reporter wraps a Zarf spinner for serial pushes and falls back to plain log lines for concurrent pushes,
+// Zarf only tracks a single active spinner so sharing one across goroutines garbles the output
+type reporter struct {
+	spinner *message.Spinner
+	quiet   bool
+}
+
+// newReporter creates a reporter, only starting a spinner if packages are pushed serially; a quiet reporter doesn't
+// start a spinner or write success lines
+func newReporter(concurrent, quiet bool, format string, a ...any) *reporter {
+	if quiet {
+		return &reporter{quiet: true}
+	}
+	if concurrent {
+		if format != "" {
+			outputLock.Lock()
+			defer outputLock.Unlock()
+			message.Infof(format, a...)
+		}
+		return &reporter{}
+	}
+	return &reporter{spinner: message.NewProgressSpinner(format, a...)}
+}
+
+// Updatef updates the spinner text or writes a debug line
+func (r *reporter) Updatef(format string, a ...any) {
+	if r.spinner != nil {
+		r.spinner.Updatef(format, a...)
+		return
+	}
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	message.Debugf(format, a...)
+}
+
+// Successf marks the spinner as successful or writes a success line
+func (r *reporter) Successf(format string, a ...any) {
+	if r.spinner != nil {
+		r.spinner.Successf(format, a...)
+		return
+	}
+	if r.quiet {
+		return
+	}
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	message.Successf(format, a...)
+}
+
+// Stop stops the spinner, if there is one
+func (r *reporter) Stop() {
+	if r.spinner != nil {
+		r.spinner.Stop()
+	}
+}
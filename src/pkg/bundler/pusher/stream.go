@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package pusher contains functionality to push Zarf pkgs to remote bundles
+package pusher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// streamChunkSize caps how much of a layer a single read moves from the source to the destination so the progress
+// moves steadily however large a buffer the upload reads with
+const streamChunkSize = 1 << 20
+
+// streamLayers copies layers from the source Zarf pkg to the remote bundle, the body of each layer's download is the
+// body of its upload so a layer is never buffered whole. The layers that already exist in the remote bundle were
+// dropped by skipExisting
+func (p *RemotePusher) streamLayers(ctx context.Context, src, dst *zoci.Remote, layers []ocispec.Descriptor, concurrency int) error {
+	var copied atomic.Int64
+	return p.forEachLayer(ctx, len(layers), concurrency, func(i int) error {
+		layer := layers[i]
+		if layer.Digest == "" {
+			return nil
+		}
+		if err := p.streamLayer(ctx, src, dst, layer); err != nil {
+			return err
+		}
+		p.cfg.Progress.UpdateTitle(p.pkg.Name, fmt.Sprintf("[%d/%d] layers copied", copied.Add(1), len(layers)))
+		return nil
+	})
+}
+
+// streamLayer pipes a single layer from the source Zarf pkg to the remote bundle. A stream can't be rewound, so a push
+// that fails with a retriable error fetches the layer from the source again, and the progress of the failed attempt is
+// taken back so the retry doesn't count the layer twice
+func (p *RemotePusher) streamLayer(ctx context.Context, src, dst *zoci.Remote, layer ocispec.Descriptor) error {
+	return utils.RetryOCI(ctx, "stream layer "+layer.Digest.String(), func() error {
+		rc, err := src.Repo().Fetch(ctx, layer)
+		if err != nil {
+			return fmt.Errorf("failed to fetch layer %s from %s: %w", layer.Digest, src.Repo().Reference, err)
+		}
+		defer rc.Close()
+		var streamed int64
+		r := &progressReader{r: rc, chunk: streamChunkSize, add: func(n int64) {
+			streamed += n
+			p.addProgress(layer.Digest, n)
+		}}
+		if err := dst.Repo().Blobs().Push(ctx, layer, r); err != nil {
+			if streamed > 0 {
+				p.addProgress(layer.Digest, -streamed)
+			}
+			return fmt.Errorf("failed to push layer %s to %s: %w", layer.Digest, dst.Repo().Reference, err)
+		}
+		return nil
+	})
+}
+
+// progressReader reports the bytes read through it, a read is capped at chunk bytes so the progress moves steadily
+// however large a buffer the reader is handed
+type progressReader struct {
+	r     io.Reader
+	chunk int
+	add   func(n int64)
+}
+
+// Read reads at most chunk bytes from the underlying reader
+func (r *progressReader) Read(b []byte) (int, error) {
+	if len(b) > r.chunk {
+		b = b[:r.chunk]
+	}
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.add(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,178 @@
+package pusher
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+// largeLayer is a layer of size bytes that's generated as it's read, so the test itself never holds it in memory
+type largeLayer struct {
+	pattern []byte
+	size    int64
+}
+
+func (l largeLayer) reader() io.Reader {
+	return io.LimitReader(&repeatReader{pattern: l.pattern}, l.size)
+}
+
+func (l largeLayer) desc() ocispec.Descriptor {
+	h := sha256.New()
+	_, _ = io.Copy(h, l.reader())
+	return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: digest.NewDigest(digest.SHA256, h), Size: l.size}
+}
+
+type repeatReader struct {
+	pattern []byte
+	off     int
+}
+
+func (r *repeatReader) Read(b []byte) (int, error) {
+	n := 0
+	for n < len(b) {
+		c := copy(b[n:], r.pattern[r.off:])
+		n += c
+		r.off = (r.off + c) % len(r.pattern)
+	}
+	return n, nil
+}
+
+func Test_streamLayers(t *testing.T) {
+	large := largeLayer{pattern: bytes.Repeat([]byte("uds"), 1<<10), size: 256 << 20}
+	largeDesc := large.desc()
+	small := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("small"))
+	failing := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("rejected partway through the upload"))
+	flaky := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("rejected partway through the first upload"))
+
+	originalBackoff, originalRetries := utils.RetryBackoff, config.CommonOptions.OCIRetries
+	utils.RetryBackoff, config.CommonOptions.OCIRetries = time.Millisecond, 3
+	defer func() {
+		utils.RetryBackoff, config.CommonOptions.OCIRetries = originalBackoff, originalRetries
+	}()
+
+	var mu sync.Mutex
+	pushed := map[string]int64{}
+	fetches := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/src/blobs/"):
+			var body io.Reader
+			var size int64
+			mu.Lock()
+			fetches[strings.TrimPrefix(r.URL.Path, "/v2/src/blobs/")]++
+			mu.Unlock()
+			switch strings.TrimPrefix(r.URL.Path, "/v2/src/blobs/") {
+			case largeDesc.Digest.String():
+				body, size = large.reader(), large.size
+			case small.Digest.String():
+				body, size = strings.NewReader("small"), small.Size
+			case failing.Digest.String():
+				body, size = strings.NewReader("rejected partway through the upload"), failing.Size
+			case flaky.Digest.String():
+				body, size = strings.NewReader("rejected partway through the first upload"), flaky.Size
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+			_, _ = io.Copy(w, body)
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/dst/blobs/uploads/":
+			w.Header().Set("Location", "/v2/dst/blobs/uploads/upload")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/dst/blobs/uploads/upload":
+			// the upload is digested as it's received, like a registry would, rather than buffered
+			mu.Lock()
+			firstFlaky := r.URL.Query().Get("digest") == flaky.Digest.String() && fetches[flaky.Digest.String()] == 1
+			mu.Unlock()
+			if r.URL.Query().Get("digest") == failing.Digest.String() || firstFlaky {
+				_, _ = io.CopyN(io.Discard, r.Body, 8)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			h := sha256.New()
+			size, err := io.Copy(h, r.Body)
+			if err != nil || digest.NewDigest(digest.SHA256, h).String() != r.URL.Query().Get("digest") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			pushed[r.URL.Query().Get("digest")] = size
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+		default:
+			// layers that already exist were dropped before they're streamed, so they aren't checked again
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	platform := ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}
+	src, err := zoci.NewRemote(host+"/src:0.0.1", platform, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	dst, err := zoci.NewRemote(host+"/dst:0.0.1", platform, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	progressed := make(map[digest.Digest]int64)
+	p := NewPkgPusher(types.Package{Name: "test"}, Config{ProgressFn: func(update ProgressUpdate) {
+		mu.Lock()
+		defer mu.Unlock()
+		progressed[update.Layer] += update.Bytes
+	}})
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	err = p.streamLayers(context.Background(), src, dst, []ocispec.Descriptor{largeDesc, small}, 2)
+	runtime.ReadMemStats(&after)
+	require.NoError(t, err)
+
+	// the large layer is piped through without being buffered, the test and the fake registry together allocate a
+	// small fraction of its size over the whole copy
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(large.size/16))
+	require.Equal(t, largeDesc.Size, pushed[largeDesc.Digest.String()])
+	require.Equal(t, small.Size, pushed[small.Digest.String()])
+	require.Equal(t, map[digest.Digest]int64{largeDesc.Digest: largeDesc.Size, small.Digest: small.Size}, progressed)
+
+	// a push that fails partway is retried with a new fetch of the layer, the failed attempt's progress is taken back
+	require.NoError(t, p.streamLayers(context.Background(), src, dst, []ocispec.Descriptor{flaky}, 1))
+	require.Equal(t, 2, fetches[flaky.Digest.String()])
+	require.Equal(t, flaky.Size, pushed[flaky.Digest.String()])
+	require.Equal(t, flaky.Size, progressed[flaky.Digest])
+
+	// every attempt fails, none of them counts towards the progress
+	err = p.streamLayers(context.Background(), src, dst, []ocispec.Descriptor{failing}, 1)
+	require.ErrorContains(t, err, "failed to push layer "+failing.Digest.String())
+	require.Equal(t, 3, fetches[failing.Digest.String()])
+	require.Zero(t, progressed[failing.Digest])
+}
+
+func Test_progressReader(t *testing.T) {
+	var reads []int64
+	r := &progressReader{r: strings.NewReader("0123456789"), chunk: 4, add: func(n int64) { reads = append(reads, n) }}
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "0123456789", string(b))
+	// reads are capped at the chunk size however large a buffer is passed
+	require.Equal(t, []int64{4, 4, 2}, reads)
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// supportsReferrers probes the registry's referrers endpoint, registries that implement the OCI 1.1 referrers API
+// respond with an image index even if the subject doesn't exist
+func supportsReferrers(ctx context.Context, bundleRemote *zoci.Remote) (bool, error) {
+	repo := bundleRemote.Repo()
+	scheme := "https"
+	if repo.PlainHTTP {
+		scheme = "http"
+	}
+	url := fmt.Sprintf("%s://%s/v2/%s/referrers/%s", scheme, repo.Reference.Host(), repo.Reference.Repository, ocispec.DescriptorEmptyJSON.Digest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", ocispec.MediaTypeImageIndex)
+	resp, err := repo.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message.Debugf("Registry %s doesn't support the referrers API: %s", repo.Reference.Registry, resp.Status)
+		return false, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == ocispec.MediaTypeImageIndex, nil
+}
+
+// allSupportReferrers returns true if every bundle remote supports the referrers API
+func allSupportReferrers(ctx context.Context, bundleRemotes []*zoci.Remote) (bool, error) {
+	for _, bundleRemote := range bundleRemotes {
+		supported, err := supportsReferrers(ctx, bundleRemote)
+		if err != nil || !supported {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// pushSignatureReferrer pushes the bundle's signature as an artifact manifest whose subject is the bundle's root manifest
+func pushSignatureReferrer(ctx context.Context, bundleRemote *zoci.Remote, signature []byte, sigAnnotations map[string]string, metadataMediaType string, subject ocispec.Descriptor) (ocispec.Descriptor, error) {
+	var signatureDesc *ocispec.Descriptor
+	err := utils.RetryOCI(ctx, "push "+config.BundleYAMLSignature, func() (err error) {
+		signatureDesc, err = bundleRemote.PushLayer(ctx, signature, metadataMediaType)
+		return err
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	signatureDesc.Annotations = signatureLayerAnnotations(sigAnnotations)
+
+	manifestDesc, err := pushReferrerManifest(ctx, bundleRemote, config.BundleSignatureArtifactType, *signatureDesc, nil, subject)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	message.Debug("Pushed signature referrer:", message.JSONValue(manifestDesc))
+	return manifestDesc, nil
+}
+
+// pushSBOMReferrer pushes an SBOM as an artifact manifest whose subject is the bundle's root manifest, the SBOM's
+// media type is also the artifact type so tools like oras discover can filter by SBOM format
+func pushSBOMReferrer(ctx context.Context, bundleRemote *zoci.Remote, sbom []byte, mediaType string, subject ocispec.Descriptor) (ocispec.Descriptor, error) {
+	var sbomDesc *ocispec.Descriptor
+	err := utils.RetryOCI(ctx, "push "+config.BundleSBOMJSON, func() (err error) {
+		sbomDesc, err = bundleRemote.PushLayer(ctx, sbom, mediaType)
+		return err
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	sbomDesc.Annotations = map[string]string{ocispec.AnnotationTitle: config.BundleSBOMJSON}
+	manifestDesc, err := pushReferrerManifest(ctx, bundleRemote, mediaType, *sbomDesc, nil, subject)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	message.Debug("Pushed SBOM referrer:", message.JSONValue(manifestDesc))
+	return manifestDesc, nil
+}
+
+// pushPackageSBOMsReferrer attaches a Zarf pkg's sboms.tar to the bundle's root manifest, the sboms.tar was already
+// pushed with the pkg so only the artifact manifest referencing it is pushed
+func pushPackageSBOMsReferrer(ctx context.Context, bundleRemote *zoci.Remote, pkgName string, sbomsDesc ocispec.Descriptor, subject ocispec.Descriptor) (ocispec.Descriptor, error) {
+	annotations := map[string]string{
+		config.PackageNameAnnotation: pkgName,
+		ocispec.AnnotationTitle:      pkgName + "/" + config.SBOMsTar,
+	}
+	manifestDesc, err := pushReferrerManifest(ctx, bundleRemote, config.PackageSBOMsArtifactType, sbomsDesc, annotations, subject)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	message.Debug("Pushed", pkgName, "SBOMs referrer:", message.JSONValue(manifestDesc))
+	return manifestDesc, nil
+}
+
+// pushReferrerManifest pushes an artifact manifest with an empty config and a single layer whose subject is the
+// bundle's root manifest, the layer must already exist in the bundle remote
+func pushReferrerManifest(ctx context.Context, bundleRemote *zoci.Remote, artifactType string, layer ocispec.Descriptor, annotations map[string]string, subject ocispec.Descriptor) (ocispec.Descriptor, error) {
+	err := utils.RetryOCI(ctx, "push empty config", func() error {
+		_, err := bundleRemote.PushLayer(ctx, ocispec.DescriptorEmptyJSON.Data, ocispec.MediaTypeEmptyJSON)
+		return err
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	manifest := ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: artifactType,
+		Config:       ocispec.DescriptorEmptyJSON,
+		Layers:       []ocispec.Descriptor{layer},
+		Subject:      &subject,
+		Annotations:  annotations,
+	}
+	manifest.SchemaVersion = 2
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	manifestDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, b)
+	manifestDesc.ArtifactType = artifactType
+	manifestDesc.Annotations = annotations
+	err = utils.RetryOCI(ctx, "push "+artifactType+" manifest", func() error {
+		return bundleRemote.Repo().Manifests().Push(ctx, manifestDesc, bytes.NewReader(b))
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return manifestDesc, nil
+}
@@ -0,0 +1,102 @@
+package bundler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_supportsReferrers(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		wantResult  bool
+	}{
+		{name: "Supported", status: http.StatusOK, contentType: ocispec.MediaTypeImageIndex, wantResult: true},
+		{name: "NotFound", status: http.StatusNotFound, wantResult: false},
+		{name: "WrongContentType", status: http.StatusOK, contentType: "text/html", wantResult: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/v2/dev/bundle/referrers/"+ocispec.DescriptorEmptyJSON.Digest.String(), r.URL.Path)
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			ref := strings.TrimPrefix(server.URL, "http://") + "/dev/bundle:0.0.1"
+			bundleRemote, err := zoci.NewRemote(ref, ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+			require.NoError(t, err)
+
+			supported, err := supportsReferrers(context.Background(), bundleRemote)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantResult, supported)
+		})
+	}
+}
+
+func Test_pushPackageSBOMsReferrer(t *testing.T) {
+	subject := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("root"))
+	sbomsDesc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("sboms"))
+	sbomsDesc.Annotations = map[string]string{ocispec.AnnotationTitle: config.SBOMsTar}
+
+	var pushedManifest []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/dev/bundle/blobs/uploads/":
+			w.Header().Set("Location", "/v2/dev/bundle/blobs/uploads/upload")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/dev/bundle/blobs/uploads/upload":
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/v2/dev/bundle/manifests/"):
+			pushedManifest, _ = io.ReadAll(r.Body)
+			// registries that support the referrers API acknowledge the subject, otherwise ORAS falls back to the tag schema
+			w.Header().Set("OCI-Subject", subject.Digest.String())
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ref := strings.TrimPrefix(server.URL, "http://") + "/dev/bundle:0.0.1"
+	bundleRemote, err := zoci.NewRemote(ref, ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	// only the referrer manifest is pushed, the sboms.tar was pushed with the pkg
+	desc, err := pushPackageSBOMsReferrer(context.Background(), bundleRemote, "podinfo", sbomsDesc, subject)
+	require.NoError(t, err)
+	require.Equal(t, config.PackageSBOMsArtifactType, desc.ArtifactType)
+	require.Equal(t, content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, pushedManifest).Digest, desc.Digest)
+
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(pushedManifest, &manifest))
+	require.Equal(t, config.PackageSBOMsArtifactType, manifest.ArtifactType)
+	require.Equal(t, ocispec.DescriptorEmptyJSON.Digest, manifest.Config.Digest)
+	require.Equal(t, []ocispec.Descriptor{sbomsDesc}, manifest.Layers)
+	require.Equal(t, subject.Digest, manifest.Subject.Digest)
+	require.Equal(t, "podinfo", manifest.Annotations[config.PackageNameAnnotation])
+}
+
+func Test_sbomMediaType(t *testing.T) {
+	require.Equal(t, "application/spdx+json", sbomMediaType(SBOMFormatSPDX))
+	require.Equal(t, "application/vnd.cyclonedx+json", sbomMediaType(SBOMFormatCycloneDX))
+}
@@ -5,8 +5,17 @@
 package bundler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/config"
@@ -14,158 +23,1140 @@ import (
 	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	"github.com/defenseunicorns/uds-cli/src/types"
 	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
 	"github.com/defenseunicorns/zarf/src/pkg/zoci"
-	goyaml "github.com/goccy/go-yaml"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
 )
 
 // RemoteBundleOpts are the options for creating a remote bundle
 type RemoteBundleOpts struct {
-	Bundle    *types.UDSBundle
-	TmpDstDir string
-	Output    string
+	Bundle           *types.UDSBundle
+	TmpDstDir        string
+	Outputs          []string
+	MaxConcurrency   int
+	DryRun           bool
+	VerifySourceKeys []string
+	OutputFormat     string
+	// SignatureReferrer attaches the bundle's signature with the OCI referrers API when every destination supports it
+	SignatureReferrer bool
+	// DetachedSignature pushes the bundle's signature as a blob referenced by a root manifest annotation, keeping the
+	// root manifest's layers to the Zarf pkgs and the bundle's own metadata
+	DetachedSignature bool
+	// SBOMFormat is the format of the bundle-level SBOM to push with the bundle, if any
+	SBOMFormat string
+	// SBOMReferrers attaches the bundle-level SBOM and each Zarf pkg's sboms.tar with the OCI referrers API when every
+	// destination supports it
+	SBOMReferrers bool
+	// SignatureAnnotations are added to the bundle's signature layer
+	SignatureAnnotations map[string]string
+	// SrcCredential and DstCredential authenticate to the source and destination registries, the docker config is
+	// used if they're empty
+	SrcCredential auth.Credential
+	DstCredential auth.Credential
+	// RequireSignature fails the create before anything is pushed if the bundle isn't signed
+	RequireSignature bool
+	// NoCache fetches each Zarf pkg's root manifest instead of reusing the one cached on disk
+	NoCache bool
+	// Force overwrites the root manifest an existing index has for the bundle's arch
+	Force bool
+	// MetadataMediaType is the media type of the bundle's YAML and signature layers, defaults to the Zarf layer media type
+	MetadataMediaType string
+	// ConfigMediaType is the media type of the bundle's manifest config, defaults to config.BundleConfigMediaType
+	ConfigMediaType string
+	// ProgressFn is called as the Zarf pkgs' layers are pushed, the progress is still written to the terminal
+	ProgressFn pusher.ProgressFn
+	// PackagePushedFn is called as each Zarf pkg finishes pushing
+	PackagePushedFn pusher.PackagePushedFn
+	// Logger receives structured events as the bundle is pushed, the events are dropped if it's nil
+	Logger *slog.Logger
+	// MetricsFile is the path the push durations are written to in the Prometheus text format, if any
+	MetricsFile string
+	// DigestTag also tags the root manifest with its digest in each destination, DigestTagFull or DigestTagShort
+	DigestTag string
+	// CleanupOnFailure deletes the blobs a failed create pushed to each destination that it didn't publish the bundle
+	// to, blobs that existed before the create are never deleted
+	CleanupOnFailure bool
+	// CompressionLevel is the zstd level the Zarf pkgs' component tarballs are compressed at, 0 pushes them as is
+	CompressionLevel int
+	// AllowedMediaTypes are the only media types the Zarf pkgs' layers may have, any media type is allowed if it's empty
+	AllowedMediaTypes []string
+	// VulnScanner reports the images of the Zarf pkgs with known vulnerabilities before anything is pushed, nothing is
+	// scanned if it's nil
+	VulnScanner VulnScanner
+	// FailOnVuln fails the create if the VulnScanner reports any images instead of warning about them
+	FailOnVuln bool
+	// UncompressedSize is how the total uncompressed size of the Zarf pkgs' layers is computed (UncompressedSizeExact
+	// or UncompressedSizeEstimate) to annotate the root manifest with, it isn't computed if it's empty
+	UncompressedSize string
+	// PruneKeep deletes the versions beyond the newest PruneKeep from each destination once the bundle is published
+	PruneKeep int
+	// Quiet suppresses the progress, success lines, metrics summary and the inspect/deploy/pull hints
+	Quiet bool
+	// LayerConcurrency is the number of each Zarf pkg's layers pushed at the same time
+	LayerConcurrency int
+	// ConcurrencyLimit bounds the OCI operations running at the same time across fetching and pushing the Zarf pkgs,
+	// 0 doesn't limit them beyond the other concurrency settings
+	ConcurrencyLimit int
+	// TransformBundle mutates a copy of the bundle before it's pushed as the bundle's YAML layer, the bundle can't be
+	// signed since the signature is of the untransformed YAML
+	TransformBundle BundleTransformFn
+	// VerifySignatureKey is the path to a public key the bundle's signature is verified with before anything is pushed
+	VerifySignatureKey string
+	// SourceMirrors are registry hosts the Zarf pkgs are fetched from, in order, if fetching a pkg from its own
+	// registry fails
+	SourceMirrors []string
+	// RegistryOverrides rewrite the image references in the Zarf pkgs from an old registry (and optional path) to a new
+	// one
+	RegistryOverrides map[string]string
+	// ExtraFiles are pushed as layers alongside the bundle's YAML, titled with their path
+	ExtraFiles []ExtraFile
 }
 
 // RemoteBundle enables create ops with remote bundles
 type RemoteBundle struct {
-	bundle    *types.UDSBundle
-	tmpDstDir string
-	output    string
+	bundle            *types.UDSBundle
+	tmpDstDir         string
+	outputs           []string
+	maxConcurrency    int
+	dryRun            bool
+	verifySourceKeys  []string
+	outputFormat      string
+	signatureReferrer bool
+	detachedSignature bool
+	sbomFormat        string
+	sbomReferrers     bool
+	sigAnnotations    map[string]string
+	srcCredential     auth.Credential
+	dstCredential     auth.Credential
+	requireSig        bool
+	noCache           bool
+	force             bool
+	metadataMediaType string
+	configMediaType   string
+	progressFn        pusher.ProgressFn
+	packagePushedFn   pusher.PackagePushedFn
+	log               *slog.Logger
+	metricsFile       string
+	digestTag         string
+	cleanupOnFailure  bool
+	compressionLevel  int
+	allowedMediaTypes []string
+	vulnScanner       VulnScanner
+	failOnVuln        bool
+	// uncompressedSizeMode is how the uncompressed size of the Zarf pkgs is computed, it isn't computed if it's empty
+	uncompressedSizeMode string
+	// pruneKeep is how many versions are kept in each destination when the old versions are pruned, 0 doesn't prune
+	pruneKeep         int
+	quiet             bool
+	layerConcurrency  int
+	limiter           *pusher.Limiter
+	transformBundle   BundleTransformFn
+	verifySigKey      string
+	sourceMirrors     []string
+	registryOverrides map[string]string
+	extraFiles        []ExtraFile
+	// bundleYAMLDesc is the desc of the YAML layer pushed by the last create, the bundle's signature signs its content
+	bundleYAMLDesc ocispec.Descriptor
+}
+
+// cleanupTimeout bounds the cleanup of a failed create, it isn't canceled with the create
+const cleanupTimeout = 2 * time.Minute
+
+// CreateResult is the machine-readable result of creating a remote bundle
+type CreateResult struct {
+	// References are the references the bundle was pushed to
+	References []string `json:"references"`
+	// Digest is the digest of the bundle's root manifest
+	Digest   string          `json:"digest"`
+	Packages []PackageResult `json:"packages"`
+	// TotalBytes is the total size of the layers pushed to every reference
+	TotalBytes int64 `json:"totalBytes"`
+	// Signed is true if a signature was attached to the bundle
+	Signed bool `json:"signed"`
+	// BundleYAMLDigest is the digest of the bundle's YAML layer, the content the bundle's signature signs, so an external
+	// signer can sign an unsigned bundle and attach the signature with resign
+	BundleYAMLDigest string `json:"bundleYAMLDigest"`
+	// DigestReferences are the references the root manifest was tagged with by its digest, if any
+	DigestReferences []string `json:"digestReferences,omitempty"`
+}
+
+// PackageResult is the machine-readable result of pushing a Zarf pkg to a remote bundle
+type PackageResult struct {
+	Name string `json:"name"`
+	// Digest is the digest of the Zarf pkg's manifest in the bundle
+	Digest string `json:"digest"`
 }
 
 // NewRemoteBundle creates a new remote bundle
 func NewRemoteBundle(opts *RemoteBundleOpts) *RemoteBundle {
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	metadataMediaType := opts.MetadataMediaType
+	if metadataMediaType == "" {
+		metadataMediaType = zoci.ZarfLayerMediaTypeBlob
+	}
+	configMediaType := opts.ConfigMediaType
+	if configMediaType == "" {
+		configMediaType = config.BundleConfigMediaType
+	}
 	return &RemoteBundle{
-		bundle:    opts.Bundle,
-		tmpDstDir: opts.TmpDstDir,
-		output:    opts.Output,
+		bundle:               opts.Bundle,
+		tmpDstDir:            opts.TmpDstDir,
+		outputs:              opts.Outputs,
+		maxConcurrency:       maxConcurrency,
+		dryRun:               opts.DryRun,
+		verifySourceKeys:     opts.VerifySourceKeys,
+		outputFormat:         opts.OutputFormat,
+		signatureReferrer:    opts.SignatureReferrer,
+		detachedSignature:    opts.DetachedSignature,
+		sbomFormat:           opts.SBOMFormat,
+		sbomReferrers:        opts.SBOMReferrers,
+		sigAnnotations:       opts.SignatureAnnotations,
+		srcCredential:        opts.SrcCredential,
+		dstCredential:        opts.DstCredential,
+		requireSig:           opts.RequireSignature,
+		noCache:              opts.NoCache,
+		force:                opts.Force,
+		metadataMediaType:    metadataMediaType,
+		configMediaType:      configMediaType,
+		progressFn:           opts.ProgressFn,
+		packagePushedFn:      opts.PackagePushedFn,
+		log:                  utils.LoggerOrDiscard(opts.Logger),
+		metricsFile:          opts.MetricsFile,
+		digestTag:            opts.DigestTag,
+		cleanupOnFailure:     opts.CleanupOnFailure,
+		compressionLevel:     opts.CompressionLevel,
+		allowedMediaTypes:    opts.AllowedMediaTypes,
+		vulnScanner:          opts.VulnScanner,
+		failOnVuln:           opts.FailOnVuln,
+		uncompressedSizeMode: opts.UncompressedSize,
+		pruneKeep:            opts.PruneKeep,
+		quiet:                opts.Quiet,
+		layerConcurrency:     opts.LayerConcurrency,
+		limiter:              pusher.NewLimiter(opts.ConcurrencyLimit),
+		transformBundle:      opts.TransformBundle,
+		verifySigKey:         opts.VerifySignatureKey,
+		sourceMirrors:        opts.SourceMirrors,
+		registryOverrides:    opts.RegistryOverrides,
+		extraFiles:           opts.ExtraFiles,
 	}
 }
 
-// create creates the bundle in a remote OCI registry publishes w/ optional signature to the remote repository.
-func (r *RemoteBundle) create(signature []byte) error {
-	ctx := context.TODO()
+// create creates the bundle in one or more remote OCI registries and publishes w/ optional signature to each remote repository,
+// returning the desc of the bundle's root manifest (the same in every registry), or an empty desc for a dry run
+func (r *RemoteBundle) create(ctx context.Context, signature []byte) (_ ocispec.Descriptor, err error) {
+	start := time.Now()
 
-	// set the bundle remote's reference from metadata
-	r.output = utils.EnsureOCIPrefix(r.output)
-	ref, err := referenceFromMetadata(r.output, &r.bundle.Metadata)
+	// track the blobs that are new to each destination so a failed create doesn't leave them behind
+	var pushedBlobs *pusher.PushedBlobs
+	if r.cleanupOnFailure {
+		pushedBlobs = pusher.NewPushedBlobs()
+		defer func() {
+			if err != nil {
+				r.cleanup(ctx, pushedBlobs)
+			}
+		}()
+	}
+
+	bundle := r.bundle
+	if bundle.Metadata.Architecture == "" {
+		return ocispec.Descriptor{}, fmt.Errorf("architecture is required for bundling")
+	}
+	if err := checkSignature(bundle, r.requireSig, signature); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if r.transformBundle != nil && len(signature) > 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("a transformed %s can't be signed, the signature wouldn't match the pushed YAML", config.BundleYAML)
+	}
+	// local pkgs are pinned to the digest they're staged with, which isn't known until they're pushed
+	hasLocalPkgs := slices.ContainsFunc(bundle.Packages, func(pkg types.Package) bool { return !utils.IsRemotePkg(pkg) })
+	if hasLocalPkgs && len(signature) > 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("a bundle with local Zarf packages can't be signed when it's created in an OCI registry, the packages' digests aren't known until they're pushed")
+	}
+	if hasLocalPkgs && r.dryRun {
+		return ocispec.Descriptor{}, fmt.Errorf("a dry run isn't supported for a bundle with local Zarf packages, they're staged in the destination before they're pushed")
+	}
+	// the YAML reflects the transform, the Zarf pkgs are pushed from the original bundle
+	bundleYamlBytes, err := bundleYAML(bundle, r.transformBundle)
 	if err != nil {
-		return err
+		return ocispec.Descriptor{}, err
+	}
+	if err := verifySignature(r.tmpDstDir, bundleYamlBytes, signature, r.verifySigKey); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if len(r.outputs) == 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("at least one output is required for bundling")
 	}
 	platform := ocispec.Platform{
 		Architecture: config.GetArch(),
-		OS:           oci.MultiOS,
+		OS:           config.GetOS(bundle.Metadata.OS),
+	}
+	if err := checkBundlePlatform(&bundle.Build, platform); err != nil {
+		return ocispec.Descriptor{}, err
 	}
 
-	// create the bundle remote
-	bundleRemote, err := zoci.NewRemote(ref, platform)
-	if err != nil {
-		return err
+	// create a bundle remote for each output, setting its reference from metadata
+	bundleRemotes := make([]*zoci.Remote, len(r.outputs))
+	for i, output := range r.outputs {
+		r.outputs[i] = utils.EnsureOCIPrefix(output)
+		ref, err := referenceFromMetadata(r.outputs[i], &bundle.Metadata)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		bundleRemote, err := zoci.NewRemote(ref, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		utils.WithCredential(bundleRemote.OrasRemote, r.dstCredential)
+		message.Debug("Bundling", bundle.Metadata.Name, "to", bundleRemote.Repo().Reference)
+		r.log.Info("bundling", "bundle", bundle.Metadata.Name, "version", bundle.Metadata.Version, "arch", bundle.Metadata.Architecture, "destination", bundleRemote.Repo().Reference.String())
+		bundleRemotes[i] = bundleRemote
 	}
-	bundle := r.bundle
-	if bundle.Metadata.Architecture == "" {
-		return fmt.Errorf("architecture is required for bundling")
+
+	// a registry that doesn't accept the config media type only rejects it when the root manifest is pushed, so check
+	// before the pkgs are pushed
+	if !r.dryRun && needsConfigMediaTypeCheck(r.configMediaType) {
+		for _, bundleRemote := range bundleRemotes {
+			if err := checkConfigMediaType(ctx, bundleRemote, r.configMediaType, pushedBlobs); err != nil {
+				return ocispec.Descriptor{}, err
+			}
+		}
 	}
-	dstRef := bundleRemote.Repo().Reference
-	message.Debug("Bundling", bundle.Metadata.Name, "to", dstRef)
 
-	rootManifest := ocispec.Manifest{}
 	pusherConfig := pusher.Config{
-		Bundle:    bundle,
-		RemoteDst: *bundleRemote,
-		NumPkgs:   len(bundle.Packages),
+		Bundle:          bundle,
+		RemoteDsts:      bundleRemotes,
+		NumPkgs:         len(bundle.Packages),
+		Concurrent:      r.maxConcurrency > 1 && len(bundle.Packages) > 1,
+		VerifyKeys:      r.verifySourceKeys,
+		ProgressFn:      r.progressFn,
+		PackagePushedFn: r.packagePushedFn,
+		Logger:          r.log,
+		// shared layers (e.g. common base images) are only pushed once per destination
+		PushedLayers: pusher.NewPushedLayers(),
+		PushedBlobs:  pushedBlobs,
+		// component tarballs are compressed as they're pushed, which changes their digests from the source pkg's
+		CompressionLevel: r.compressionLevel,
+		Quiet:            r.quiet,
+		LayerConcurrency: r.layerConcurrency,
+		Limiter:          r.limiter,
 	}
 
+	// stage the local pkgs in the first destination, they're pushed from there like remote pkgs
+	var stagedRemotes []*zoci.Remote
+	if hasLocalPkgs {
+		if stagedRemotes, err = r.stageLocalPkgs(ctx, bundleRemotes[0], pushedBlobs); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if bundleYamlBytes, err = bundleYAML(bundle, r.transformBundle); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	// create the source remotes up front, the underlying ORAS auth client is shared and isn't safe to configure concurrently
+	srcRemotes, err := r.newSrcRemotes(stagedRemotes)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	metrics := newPushMetrics(len(bundle.Packages))
+	fetchStart := time.Now()
+	pkgRootManifests, err := r.fetchRoots(ctx, srcRemotes)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	metrics.addPhase(phaseFetchRoots, "", fetchStart, 0)
+	if err := r.checkPkgPlatforms(ctx, srcRemotes, pkgRootManifests); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	prunedPkgs, err := r.pruneImages(ctx, srcRemotes, pkgRootManifests)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	// fail before anything is pushed rather than when the registry rejects a layer partway through the push
+	if err := checkMediaTypes(r.bundle.Packages, pkgRootManifests, prunedPkgs, r.allowedMediaTypes); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := r.checkVulns(ctx, srcRemotes, pkgRootManifests, prunedPkgs); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	if r.dryRun {
+		if err := r.planPush(ctx, srcRemotes, pkgRootManifests, prunedPkgs, signature); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		return ocispec.Descriptor{}, r.planPruneVersions(ctx, bundleRemotes)
+	}
+
+	// size the push up front so a single progress bar can track every package
+	estimate, err := r.estimate(ctx, srcRemotes, pkgRootManifests, prunedPkgs, signature)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var uncompressedBytes int64
+	if r.uncompressedSizeMode != "" {
+		if uncompressedBytes, err = r.uncompressedSize(ctx, srcRemotes, pkgRootManifests, prunedPkgs); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	progress := pusher.NewProgress(estimate.TotalBytes*int64(len(bundleRemotes)), fmt.Sprintf("Pushing bundle %s", bundle.Metadata.Name), r.quiet)
+	defer progress.Stop()
+	pusherConfig.Progress = progress
+
+	// push the packages concurrently, collecting the Zarf manifest descs by index so the root manifest layer order
+	// (and therefore its digest) doesn't depend on which push finishes first
+	zarfManifestDescs := make([]ocispec.Descriptor, len(bundle.Packages))
+	pkgPushedBytes := make([]int64, len(bundle.Packages))
+	pushStart := time.Now()
+	pushGroup, pushCtx := errgroup.WithContext(ctx)
+	pushGroup.SetLimit(r.maxConcurrency)
 	for i, pkg := range bundle.Packages {
-		// todo: can leave this block here or move to pusher.NewPkgPusher (would be closer to NewPkgFetcher pattern)
-		pkgURL := fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref)
-		src, err := zoci.NewRemote(pkgURL, platform)
+		i, pkg := i, pkg
+		pushGroup.Go(func() error {
+			pkgPusherConfig := pusherConfig
+			pkgPusherConfig.RemoteSrc = *srcRemotes[i]
+			pkgPusherConfig.PkgRootManifest = pkgRootManifests[i]
+			pkgPusherConfig.Pruned = prunedPkgs[i]
+			pkgPusherConfig.PkgIter = i
+
+			pkgStart := time.Now()
+			remotePusher := pusher.NewPkgPusher(pkg, pkgPusherConfig)
+			zarfManifestDesc, pushedBytes, err := remotePusher.Push(pushCtx)
+			if err != nil {
+				return err
+			}
+			metrics.addPackage(i, pkg.Name, pkgStart, pushedBytes)
+			// record the pkg's actual arch since it can differ from the bundle's
+			pkgPlatform := utils.GetPkgPlatform(pkg)
+			zarfManifestDesc.Platform = &pkgPlatform
+			zarfManifestDescs[i] = zarfManifestDesc
+			pkgPushedBytes[i] = pushedBytes
+			return nil
+		})
+	}
+	if err := pushGroup.Wait(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var totalPushed int64
+	for _, pushed := range pkgPushedBytes {
+		totalPushed += pushed
+	}
+	metrics.addPhase(phasePushPackages, "", pushStart, totalPushed)
+
+	// attach the signature with the referrers API if every destination supports it, otherwise keep it as a layer of the
+	// root manifest so the root manifest is the same everywhere
+	inlineSignature := signature
+	if r.detachedSignature {
+		inlineSignature = nil
+	}
+	signatureReferrer := r.signatureReferrer && len(signature) > 0
+	referrersSupported := false
+	if signatureReferrer || r.sbomReferrers {
+		referrersSupported, err = allSupportReferrers(ctx, bundleRemotes)
 		if err != nil {
-			return err
+			return ocispec.Descriptor{}, err
 		}
-		pusherConfig.RemoteSrc = *src
-		pkgRootManifest, err := src.FetchRoot(ctx)
+	}
+	useReferrers := signatureReferrer && referrersSupported
+	if useReferrers {
+		inlineSignature = nil
+	} else if signatureReferrer {
+		message.Warnf("Not every destination registry supports the OCI referrers API, the signature will be pushed as a layer of the bundle")
+		r.log.Warn("referrers API unsupported, pushing the signature as a layer", "bundle", bundle.Metadata.Name)
+	}
+	// the SBOMs are attached the same way, the pkgs' sboms.tar are always in their own manifests
+	sbomReferrers := r.sbomReferrers && referrersSupported
+	if r.sbomReferrers && !referrersSupported {
+		message.Warnf("Not every destination registry supports the OCI referrers API, the SBOMs will only be pushed as layers of the bundle and its packages")
+		r.log.Warn("referrers API unsupported, not attaching SBOMs", "bundle", bundle.Metadata.Name)
+	}
+
+	// push the bundle's metadata to each destination, the resulting descs are the same everywhere so the root manifest
+	// is only assembled once
+	var sbom []byte
+	if r.sbomFormat != "" {
+		if sbom, err = generateBundleSBOM(r.sbomFormat, bundle, zarfManifestDescs); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	inlineSBOM := sbom
+	if sbomReferrers {
+		inlineSBOM = nil
+	}
+	rootManifest := ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: config.BundleArtifactType,
+	}
+	rootManifest.Layers = append(rootManifest.Layers, zarfManifestDescs...)
+	metadataBlobs, err := bundleMetadataBlobs(bundle, bundleYamlBytes, inlineSignature, inlineSBOM, r.extraFiles, r.metadataMediaType, r.configMediaType)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	r.bundleYAMLDesc = content.NewDescriptorFromBytes(r.metadataMediaType, bundleYamlBytes)
+	for i, bundleRemote := range bundleRemotes {
+		metadataStart := time.Now()
+		if err := pushedBlobs.Track(ctx, bundleRemote, metadataBlobs...); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		metadataDescs, configDesc, err := pushBundleMetadata(ctx, bundleRemote, bundle, bundleYamlBytes, inlineSignature, r.sigAnnotations, inlineSBOM, r.extraFiles, r.metadataMediaType, r.configMediaType, r.log)
 		if err != nil {
-			return err
+			return ocispec.Descriptor{}, err
+		}
+		if !r.quiet {
+			message.Successf("Published %s [%s]", bundleRemote.Repo().Reference, configDesc.MediaType)
 		}
-		pusherConfig.PkgRootManifest = pkgRootManifest
-		pusherConfig.PkgIter = i
+		metadataBytes := configDesc.Size
+		for _, desc := range metadataDescs {
+			progress.Add(desc.Size)
+			metadataBytes += desc.Size
+		}
+		metrics.addPhase(phasePushMetadata, bundleRemote.Repo().Reference.String(), metadataStart, metadataBytes)
+		if i == 0 {
+			rootManifest.Layers = append(rootManifest.Layers, metadataDescs...)
+			rootManifest.Config = configDesc
+		}
+	}
+	rootManifest.SchemaVersion = 2
+	rootManifest.Annotations = manifestAnnotationsFromMetadata(&bundle.Metadata, &bundle.Build) // maps to registry UI
+	if rootManifest.Annotations[config.BundlePackagesAnnotation], err = packagesAnnotation(bundle, zarfManifestDescs); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if r.uncompressedSizeMode != "" {
+		rootManifest.Annotations[config.BundleUncompressedSizeAnnotation] = strconv.FormatInt(uncompressedBytes, 10)
+		if r.uncompressedSizeMode == UncompressedSizeEstimate {
+			rootManifest.Annotations[config.BundleUncompressedSizeEstimatedAnnotation] = "true"
+		}
+	}
+	if r.detachedSignature && len(signature) > 0 {
+		for _, bundleRemote := range bundleRemotes {
+			signatureStart := time.Now()
+			if err := pushedBlobs.Track(ctx, bundleRemote, content.NewDescriptorFromBytes(r.metadataMediaType, signature)); err != nil {
+				return ocispec.Descriptor{}, err
+			}
+			signatureDesc, err := pushDetachedSignature(ctx, bundleRemote, signature, r.metadataMediaType, r.log)
+			if err != nil {
+				return ocispec.Descriptor{}, err
+			}
+			progress.Add(signatureDesc.Size)
+			metrics.addPhase(phasePushSignature, bundleRemote.Repo().Reference.String(), signatureStart, signatureDesc.Size)
+			addDetachedSignatureAnnotations(&rootManifest, signatureDesc, r.sigAnnotations)
+		}
+	}
 
-		remotePusher := pusher.NewPkgPusher(pkg, pusherConfig)
-		zarfManifestDesc, err := remotePusher.Push()
+	// check every destination's existing index before tagging anything, another create may have pushed the same
+	// version for the same arch
+	newRootManifestDesc, err := utils.RootManifestDesc(rootManifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	for _, bundleRemote := range bundleRemotes {
+		dstRef := bundleRemote.Repo().Reference
+		index, err := utils.GetIndex(ctx, bundleRemote.OrasRemote, dstRef.String())
 		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if existing, ok := utils.IndexConflict(index, bundle, newRootManifestDesc); ok && !r.force {
+			return ocispec.Descriptor{}, fmt.Errorf("%s already has a %s bundle with digest %s, refusing to overwrite it with %s, use --force to overwrite it",
+				dstRef, bundle.Metadata.Architecture, existing.Digest, newRootManifestDesc.Digest)
+		} else if ok {
+			message.Warnf("Overwriting the %s bundle at %s (%s) with %s", bundle.Metadata.Architecture, dstRef, existing.Digest, newRootManifestDesc.Digest)
+			r.log.Warn("overwriting existing bundle", "destination", dstRef.String(), "arch", bundle.Metadata.Architecture, "digest", existing.Digest.String(), "newDigest", newRootManifestDesc.Digest.String())
+		}
+	}
+
+	var rootManifestDesc *ocispec.Descriptor
+	var digestRefs []string
+	for _, bundleRemote := range bundleRemotes {
+		dstRef := bundleRemote.Repo().Reference.String()
+
+		// push bundle root manifest, it's tagged through the index
+		rootManifestStart := time.Now()
+		if err := pushedBlobs.Track(ctx, bundleRemote, newRootManifestDesc); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		err = utils.RetryOCI(ctx, "push root manifest", func() (err error) {
+			rootManifestDesc, err = utils.PushRootManifest(ctx, rootManifest, bundleRemote.OrasRemote)
 			return err
+		})
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if !r.quiet {
+			message.Successf("Published %s@%s [%s]", bundleRemote.Repo().Reference.Repository, rootManifestDesc.Digest, rootManifestDesc.MediaType)
+		}
+		r.log.Info("pushed root manifest", "destination", dstRef, "digest", rootManifestDesc.Digest.String(), "bytes", rootManifestDesc.Size)
+
+		// merge into the latest index.json, another writer may have updated it since it was checked
+		err = utils.RetryOCI(ctx, "update index", func() error {
+			return utils.MergeIndex(ctx, bundleRemote.OrasRemote, bundle, *rootManifestDesc, r.force)
+		})
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		r.log.Info("updated index", "destination", dstRef, "arch", bundle.Metadata.Architecture)
+		// the bundle is published to this destination, its blobs are kept even if a later destination fails
+		pushedBlobs.Commit(bundleRemote)
+
+		// tag the root manifest by its digest so there's an immutable reference to this arch's bundle
+		if r.digestTag != "" {
+			digestRef := bundleRemote.Repo().Reference
+			digestRef.Reference = utils.DigestTag(*rootManifestDesc, r.digestTag == DigestTagShort)
+			err = utils.RetryOCI(ctx, "tag root manifest", func() error {
+				return utils.TagRootManifest(ctx, rootManifest, bundleRemote.OrasRemote, digestRef.Reference)
+			})
+			if err != nil {
+				return ocispec.Descriptor{}, err
+			}
+			if !r.quiet {
+				message.Successf("Tagged %s", digestRef)
+			}
+			r.log.Info("tagged root manifest", "destination", digestRef.String(), "digest", rootManifestDesc.Digest.String())
+			digestRefs = append(digestRefs, digestRef.String())
+		}
+		metrics.addPhase(phasePushRootManifest, dstRef, rootManifestStart, rootManifestDesc.Size)
+
+		if useReferrers {
+			referrerStart := time.Now()
+			referrerDesc, err := pushSignatureReferrer(ctx, bundleRemote, signature, r.sigAnnotations, r.metadataMediaType, *rootManifestDesc)
+			if err != nil {
+				return ocispec.Descriptor{}, err
+			}
+			r.log.Info("pushed signature referrer", "destination", dstRef, "digest", referrerDesc.Digest.String(), "subject", rootManifestDesc.Digest.String())
+			metrics.addPhase(phasePushSignature, dstRef, referrerStart, int64(len(signature))+referrerDesc.Size)
+		}
+		if sbomReferrers {
+			sbomStart := time.Now()
+			pushed, err := r.pushSBOMReferrers(ctx, bundleRemote, sbom, pkgRootManifests, prunedPkgs, *rootManifestDesc)
+			if err != nil {
+				return ocispec.Descriptor{}, err
+			}
+			metrics.addPhase(phasePushSBOMs, dstRef, sbomStart, pushed)
+		}
+	}
+
+	progress.Successf("Pushed bundle %s", bundle.Metadata.Name)
+	r.log.Info("created bundle", "bundle", bundle.Metadata.Name, "version", bundle.Metadata.Version, "digest", rootManifestDesc.Digest.String(), "packageBytes", totalPushed, "duration", time.Since(start))
+	if !r.quiet {
+		metrics.printSummary()
+	}
+	if r.metricsFile != "" {
+		if err := metrics.writeMetricsFile(r.metricsFile, &bundle.Metadata); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	r.pruneVersions(ctx, bundleRemotes)
+
+	if r.outputFormat == OutputFormatJSON {
+		result := CreateResult{
+			Digest:     rootManifestDesc.Digest.String(),
+			TotalBytes: int64(len(bundleRemotes)) * (rootManifestDesc.Size + rootManifest.Config.Size),
+			Signed:     len(signature) > 0,
+			// the YAML is the same for every destination
+			BundleYAMLDigest: r.bundleYAMLDesc.Digest.String(),
+		}
+		for _, bundleRemote := range bundleRemotes {
+			result.References = append(result.References, bundleRemote.Repo().Reference.String())
+		}
+		result.DigestReferences = digestRefs
+		for i, pkg := range bundle.Packages {
+			result.Packages = append(result.Packages, PackageResult{Name: pkg.Name, Digest: zarfManifestDescs[i].Digest.String()})
+			result.TotalBytes += pkgPushedBytes[i]
+		}
+		for _, layer := range rootManifest.Layers[len(zarfManifestDescs):] {
+			result.TotalBytes += int64(len(bundleRemotes)) * layer.Size
+		}
+		return *rootManifestDesc, printJSON(result)
+	}
+	if r.outputFormat == OutputFormatDigest {
+		fmt.Println(rootManifestDesc.Digest)
+		return *rootManifestDesc, nil
+	}
+	if r.quiet {
+		return *rootManifestDesc, nil
+	}
+
+	flags := ""
+	if config.CommonOptions.Insecure {
+		flags = "--insecure"
+	} else if config.CommonOptions.InsecureSkipTLSVerify {
+		flags = "--insecure-skip-tls-verify"
+	}
+	for _, bundleRemote := range bundleRemotes {
+		dstRef := bundleRemote.Repo().Reference
+		message.HorizontalRule()
+		message.Title("To inspect/deploy/pull:", "")
+		message.Command("inspect oci://%s %s", dstRef, flags)
+		message.Command("deploy oci://%s %s", dstRef, flags)
+		message.Command("pull oci://%s %s", dstRef, flags)
+	}
+
+	return *rootManifestDesc, nil
+}
+
+// pushSBOMReferrers attaches the bundle-level SBOM, if any, and the sboms.tar of each Zarf pkg that has one to the
+// bundle's root manifest, returning the bytes pushed
+func (r *RemoteBundle) pushSBOMReferrers(ctx context.Context, bundleRemote *zoci.Remote, sbom []byte, pkgRootManifests []*oci.Manifest, prunedPkgs []*utils.PrunedPackage, subject ocispec.Descriptor) (int64, error) {
+	dstRef := bundleRemote.Repo().Reference.String()
+	var pushed int64
+	if len(sbom) > 0 {
+		referrerDesc, err := pushSBOMReferrer(ctx, bundleRemote, sbom, sbomMediaType(r.sbomFormat), subject)
+		if err != nil {
+			return 0, err
+		}
+		r.log.Info("pushed SBOM referrer", "destination", dstRef, "digest", referrerDesc.Digest.String(), "subject", subject.Digest.String())
+		pushed += int64(len(sbom)) + referrerDesc.Size
+	}
+	for i, pkg := range r.bundle.Packages {
+		root := pkgRootManifests[i]
+		if prunedPkgs[i] != nil {
+			root = prunedPkgs[i].Root
+		}
+		sbomsDesc := root.Locate(config.SBOMsTar)
+		if oci.IsEmptyDescriptor(sbomsDesc) {
+			message.Debugf("Package %s doesn't have an %s, not attaching its SBOMs", pkg.Name, config.SBOMsTar)
+			continue
 		}
-		rootManifest.Layers = append(rootManifest.Layers, zarfManifestDesc)
+		referrerDesc, err := pushPackageSBOMsReferrer(ctx, bundleRemote, pkg.Name, sbomsDesc, subject)
+		if err != nil {
+			return 0, err
+		}
+		r.log.Info("pushed package SBOMs referrer", "destination", dstRef, "package", pkg.Name, "digest", referrerDesc.Digest.String(), "subject", subject.Digest.String())
+		pushed += referrerDesc.Size
 	}
+	return pushed, nil
+}
+
+// pushBundleMetadata pushes the bundle's YAML, optional signature, optional SBOM, extra files and manifest config to a
+// bundle remote, the YAML, signature and extra file layers are pushed with metadataMediaType and the config with
+// configMediaType
+func pushBundleMetadata(ctx context.Context, bundleRemote *zoci.Remote, bundle *types.UDSBundle, bundleYamlBytes []byte, signature []byte, sigAnnotations map[string]string, sbom []byte, extraFiles []ExtraFile, metadataMediaType string, configMediaType string, log *slog.Logger) ([]ocispec.Descriptor, ocispec.Descriptor, error) {
+	var metadataDescs []ocispec.Descriptor
 
 	// push the bundle's metadata
-	bundleYamlBytes, err := goyaml.Marshal(bundle)
-	if err != nil {
+	var bundleYamlDesc *ocispec.Descriptor
+	err := utils.RetryOCI(ctx, "push "+config.BundleYAML, func() (err error) {
+		bundleYamlDesc, err = bundleRemote.PushLayer(ctx, bundleYamlBytes, metadataMediaType)
 		return err
-	}
-	bundleYamlDesc, err := bundleRemote.PushLayer(ctx, bundleYamlBytes, zoci.ZarfLayerMediaTypeBlob)
+	})
 	if err != nil {
-		return err
+		return nil, ocispec.Descriptor{}, err
 	}
 	bundleYamlDesc.Annotations = map[string]string{
 		ocispec.AnnotationTitle: config.BundleYAML,
 	}
 
 	message.Debug("Pushed", config.BundleYAML+":", message.JSONValue(bundleYamlDesc))
-	rootManifest.Layers = append(rootManifest.Layers, *bundleYamlDesc)
+	logPushedMetadata(log, bundleRemote, config.BundleYAML, *bundleYamlDesc)
+	metadataDescs = append(metadataDescs, *bundleYamlDesc)
 
 	// push the bundle's signature
 	if len(signature) > 0 {
-		bundleYamlSigDesc, err := bundleRemote.PushLayer(ctx, signature, zoci.ZarfLayerMediaTypeBlob)
+		var bundleYamlSigDesc *ocispec.Descriptor
+		err = utils.RetryOCI(ctx, "push "+config.BundleYAMLSignature, func() (err error) {
+			bundleYamlSigDesc, err = bundleRemote.PushLayer(ctx, signature, metadataMediaType)
+			return err
+		})
 		if err != nil {
+			return nil, ocispec.Descriptor{}, err
+		}
+		bundleYamlSigDesc.Annotations = signatureLayerAnnotations(sigAnnotations)
+		metadataDescs = append(metadataDescs, *bundleYamlSigDesc)
+		message.Debug("Pushed", config.BundleYAMLSignature+":", message.JSONValue(bundleYamlSigDesc))
+		logPushedMetadata(log, bundleRemote, config.BundleYAMLSignature, *bundleYamlSigDesc)
+	}
+
+	// push the bundle's SBOM
+	if len(sbom) > 0 {
+		var sbomDesc *ocispec.Descriptor
+		err = utils.RetryOCI(ctx, "push "+config.BundleSBOMJSON, func() (err error) {
+			sbomDesc, err = bundleRemote.PushLayer(ctx, sbom, zoci.ZarfLayerMediaTypeBlob)
 			return err
+		})
+		if err != nil {
+			return nil, ocispec.Descriptor{}, err
 		}
-		bundleYamlSigDesc.Annotations = map[string]string{
-			ocispec.AnnotationTitle: config.BundleYAMLSignature,
+		sbomDesc.Annotations = map[string]string{
+			ocispec.AnnotationTitle: config.BundleSBOMJSON,
 		}
-		rootManifest.Layers = append(rootManifest.Layers, *bundleYamlSigDesc)
-		message.Debug("Pushed", config.BundleYAMLSignature+":", message.JSONValue(bundleYamlSigDesc))
+		metadataDescs = append(metadataDescs, *sbomDesc)
+		message.Debug("Pushed", config.BundleSBOMJSON+":", message.JSONValue(sbomDesc))
+		logPushedMetadata(log, bundleRemote, config.BundleSBOMJSON, *sbomDesc)
+	}
+
+	// push the bundle's extra files
+	for _, file := range extraFiles {
+		var fileDesc *ocispec.Descriptor
+		err = utils.RetryOCI(ctx, "push "+file.Path, func() (err error) {
+			fileDesc, err = bundleRemote.PushLayer(ctx, file.Contents, metadataMediaType)
+			return err
+		})
+		if err != nil {
+			return nil, ocispec.Descriptor{}, err
+		}
+		fileDesc.Annotations = extraFileAnnotations(file)
+		metadataDescs = append(metadataDescs, *fileDesc)
+		message.Debug("Pushed", file.Path+":", message.JSONValue(fileDesc))
+		logPushedMetadata(log, bundleRemote, file.Path, *fileDesc)
 	}
 
 	// push the bundle manifest config
-	configDesc, err := pushManifestConfigFromMetadata(bundleRemote.OrasRemote, &bundle.Metadata, &bundle.Build)
+	configDesc, err := pushManifestConfigFromMetadata(ctx, bundleRemote.OrasRemote, &bundle.Metadata, &bundle.Build, configMediaType)
 	if err != nil {
-		return err
+		return nil, ocispec.Descriptor{}, err
 	}
 
 	message.Debug("Pushed config:", message.JSONValue(configDesc))
+	logPushedMetadata(log, bundleRemote, "config", configDesc)
+	return metadataDescs, configDesc, nil
+}
 
-	// check for existing index
-	index, err := utils.GetIndex(bundleRemote.OrasRemote, dstRef.String())
+// bundleMetadataBlobs returns the descs of the blobs pushBundleMetadata pushes, so they can be tracked before they're pushed
+func bundleMetadataBlobs(bundle *types.UDSBundle, bundleYamlBytes []byte, signature []byte, sbom []byte, extraFiles []ExtraFile, metadataMediaType string, configMediaType string) ([]ocispec.Descriptor, error) {
+	configBytes, err := json.Marshal(manifestConfigFromMetadata(&bundle.Metadata, &bundle.Build))
 	if err != nil {
-		return err
+		return nil, err
+	}
+	blobs := []ocispec.Descriptor{
+		content.NewDescriptorFromBytes(metadataMediaType, bundleYamlBytes),
+		content.NewDescriptorFromBytes(configMediaType, configBytes),
+	}
+	if len(signature) > 0 {
+		blobs = append(blobs, content.NewDescriptorFromBytes(metadataMediaType, signature))
+	}
+	if len(sbom) > 0 {
+		blobs = append(blobs, content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, sbom))
 	}
+	for _, file := range extraFiles {
+		blobs = append(blobs, content.NewDescriptorFromBytes(metadataMediaType, file.Contents))
+	}
+	return blobs, nil
+}
 
-	// push bundle root manifest
-	rootManifest.Config = configDesc
-	rootManifest.SchemaVersion = 2
-	rootManifest.Annotations = manifestAnnotationsFromMetadata(&bundle.Metadata) // maps to registry UI
-	rootManifestDesc, err := utils.ToOCIRemote(rootManifest, ocispec.MediaTypeImageManifest, bundleRemote.OrasRemote)
+// needsConfigMediaTypeCheck returns true if the config media type was set with --config-media-type to one registries
+// aren't known to accept, the default and the Zarf blob media type Zarf pkgs are published with are never checked
+func needsConfigMediaTypeCheck(mediaType string) bool {
+	return mediaType != config.BundleConfigMediaType && mediaType != zoci.ZarfLayerMediaTypeBlob
+}
+
+// checkConfigMediaType pushes an untagged manifest whose config has the config media type to a bundle remote and deletes
+// it and its config again, returning an error if the registry rejects the manifest. The probe is tracked in pushedBlobs
+// so the cleanup of a failed create retries deleting whatever the probe couldn't
+func checkConfigMediaType(ctx context.Context, bundleRemote *zoci.Remote, mediaType string, pushedBlobs *pusher.PushedBlobs) error {
+	probe := pusher.NewPushedBlobs()
+	track := func(desc ocispec.Descriptor) error {
+		for _, tracker := range []*pusher.PushedBlobs{probe, pushedBlobs} {
+			if err := tracker.Track(ctx, bundleRemote, desc); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	configDesc := content.NewDescriptorFromBytes(mediaType, ocispec.DescriptorEmptyJSON.Data)
+	if err := track(configDesc); err != nil {
+		return err
+	}
+	if _, err := bundleRemote.PushLayer(ctx, ocispec.DescriptorEmptyJSON.Data, mediaType); err != nil {
+		return fmt.Errorf("unable to push the config that checks the config media type to %s: %w", bundleRemote.Repo().Reference, err)
+	}
+	manifest := ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    []ocispec.Descriptor{},
+	}
+	manifest.SchemaVersion = 2
+	b, err := json.Marshal(manifest)
 	if err != nil {
 		return err
 	}
+	manifestDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, b)
+	if err := track(manifestDesc); err != nil {
+		return err
+	}
+	err = bundleRemote.Repo().Manifests().Push(ctx, manifestDesc, bytes.NewReader(b))
+	// the config is deleted whether or not the manifest was accepted
+	for _, orphan := range probe.Delete(ctx) {
+		message.Warnf("Unable to delete %s@%s that checked the config media type, delete it manually: %s", orphan.Repository, orphan.Desc.Digest, orphan.Err)
+	}
+	if err != nil {
+		if isManifestRejected(err) {
+			return fmt.Errorf("%s doesn't accept the config media type %s, set a different one with --config-media-type: %w", bundleRemote.Repo().Reference.Registry, mediaType, err)
+		}
+		return fmt.Errorf("unable to push the manifest that checks the config media type to %s: %w", bundleRemote.Repo().Reference, err)
+	}
+	return nil
+}
+
+// isManifestRejected returns true if a registry rejected a manifest's contents, rather than e.g. the credentials the
+// manifest was pushed with
+func isManifestRejected(err error) bool {
+	var errResp *errcode.ErrorResponse
+	return errors.As(err, &errResp) && (errResp.StatusCode == http.StatusBadRequest || errResp.StatusCode == http.StatusUnsupportedMediaType)
+}
+
+// cleanup deletes the blobs a failed create pushed to the destinations it didn't publish the bundle to, registries that
+// don't support deletes leave them behind so they're listed for the user to delete
+func (r *RemoteBundle) cleanup(ctx context.Context, pushedBlobs *pusher.PushedBlobs) {
+	message.Debug("Deleting the blobs pushed by the failed create of", r.bundle.Metadata.Name)
+	// the create may have failed because it was canceled or timed out, the cleanup still needs to reach the registries
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+	defer cancel()
+	orphans := pushedBlobs.Delete(ctx)
+	if len(orphans) == 0 {
+		return
+	}
+	var lines []string
+	for _, orphan := range orphans {
+		lines = append(lines, fmt.Sprintf("  %s@%s (%s)", orphan.Repository, orphan.Desc.Digest, orphan.Err))
+		r.log.Warn("unable to delete orphaned blob", "repository", orphan.Repository, "digest", orphan.Desc.Digest.String(), "bytes", orphan.Desc.Size, "error", orphan.Err)
+	}
+	message.Warnf("Unable to delete %d blobs pushed by the failed create, delete them manually:\n%s", len(orphans), strings.Join(lines, "\n"))
+}
 
-	// create or update, then push index.json
-	err = utils.UpdateIndex(index, bundleRemote.OrasRemote, bundle, *rootManifestDesc)
+// logPushedMetadata logs a structured event for one of the bundle's own blobs
+func logPushedMetadata(log *slog.Logger, bundleRemote *zoci.Remote, title string, desc ocispec.Descriptor) {
+	log.Debug("pushed bundle metadata", "destination", bundleRemote.Repo().Reference.String(), "title", title, "digest", desc.Digest.String(), "bytes", desc.Size)
+}
+
+// pushDetachedSignature pushes the bundle's signature as a blob that isn't a layer of the root manifest
+func pushDetachedSignature(ctx context.Context, bundleRemote *zoci.Remote, signature []byte, metadataMediaType string, log *slog.Logger) (ocispec.Descriptor, error) {
+	var signatureDesc *ocispec.Descriptor
+	err := utils.RetryOCI(ctx, "push detached "+config.BundleYAMLSignature, func() (err error) {
+		signatureDesc, err = bundleRemote.PushLayer(ctx, signature, metadataMediaType)
+		return err
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	message.Debug("Pushed detached", config.BundleYAMLSignature+":", message.JSONValue(signatureDesc))
+	logPushedMetadata(log, bundleRemote, "detached "+config.BundleYAMLSignature, *signatureDesc)
+	return *signatureDesc, nil
+}
+
+// addDetachedSignatureAnnotations points the root manifest at a detached signature, the signature's own annotations
+// (e.g. the certificate of a keyless signature) move to the root manifest since there's no layer to hold them
+func addDetachedSignatureAnnotations(rootManifest *ocispec.Manifest, signatureDesc ocispec.Descriptor, sigAnnotations map[string]string) {
+	if rootManifest.Annotations == nil {
+		rootManifest.Annotations = make(map[string]string)
+	}
+	for key, value := range sigAnnotations {
+		rootManifest.Annotations[key] = value
+	}
+	rootManifest.Annotations[config.BundleSignatureDigestAnnotation] = signatureDesc.Digest.String()
+}
+
+// stageLocalPkgs pushes each local Zarf pkg to dst and pins the pkg's ref to the digest of its staged manifest,
+// returning a remote for each staged pkg; the remotes of remote pkgs are nil
+func (r *RemoteBundle) stageLocalPkgs(ctx context.Context, dst *zoci.Remote, pushedBlobs *pusher.PushedBlobs) ([]*zoci.Remote, error) {
+	stagedRemotes := make([]*zoci.Remote, len(r.bundle.Packages))
+	for i, pkg := range r.bundle.Packages {
+		if utils.IsRemotePkg(pkg) {
+			continue
+		}
+		staged, err := pusher.StageLocalPkg(ctx, pkg, dst, r.dstCredential, pushedBlobs, r.quiet)
+		if err != nil {
+			return nil, fmt.Errorf("unable to stage local package %s (packages[%d]) at %s: %w", pkg.Name, i, pkg.Path, err)
+		}
+		r.bundle.Packages[i].Ref = pkg.Ref + "@" + staged.Repo().Reference.Reference
+		r.log.Info("staged local package", "package", pkg.Name, "path", pkg.Path, "destination", staged.Repo().Reference.String())
+		stagedRemotes[i] = staged
+	}
+	return stagedRemotes, nil
+}
+
+// newSrcRemotes creates a remote for each of the bundle's Zarf pkgs using the pkg's platform, a local pkg's source is
+// the remote it was staged in
+func (r *RemoteBundle) newSrcRemotes(stagedRemotes []*zoci.Remote) ([]*zoci.Remote, error) {
+	srcRemotes := make([]*zoci.Remote, len(r.bundle.Packages))
+	for i, pkg := range r.bundle.Packages {
+		if stagedRemotes != nil && stagedRemotes[i] != nil {
+			srcRemotes[i] = stagedRemotes[i]
+			continue
+		}
+		// todo: can leave this block here or move to pusher.NewPkgPusher (would be closer to NewPkgFetcher pattern)
+		pkgURL := fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref)
+		src, err := zoci.NewRemote(pkgURL, utils.GetPkgPlatform(pkg), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+		if err != nil {
+			return nil, fmt.Errorf("unable to create a remote for package %s (packages[%d]) at %s: %w", pkg.Name, i, pkgURL, err)
+		}
+		utils.WithCredential(src.OrasRemote, r.srcCredential)
+		srcRemotes[i] = src
+	}
+	return srcRemotes, nil
+}
+
+// newMirrorRemotes creates a remote in each source mirror for each Zarf pkg, in the same order as the mirrors. Like
+// the source remotes they're created up front, the mirrors are authenticated with the docker config
+func (r *RemoteBundle) newMirrorRemotes() ([][]*zoci.Remote, error) {
+	mirrorRemotes := make([][]*zoci.Remote, len(r.bundle.Packages))
+	if len(r.sourceMirrors) == 0 {
+		return mirrorRemotes, nil
+	}
+	for i, pkg := range r.bundle.Packages {
+		if !utils.IsRemotePkg(pkg) {
+			continue
+		}
+		pkgURL := fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref)
+		for _, mirror := range r.sourceMirrors {
+			mirrorURL := utils.MirrorURL(pkgURL, mirror)
+			mirrorRemote, err := zoci.NewRemote(mirrorURL, utils.GetPkgPlatform(pkg), utils.WithSkipTLSVerify(), utils.WithRetryAfter())
+			if err != nil {
+				return nil, fmt.Errorf("unable to create a remote for package %s (packages[%d]) at %s: %w", pkg.Name, i, mirrorURL, err)
+			}
+			mirrorRemotes[i] = append(mirrorRemotes[i], mirrorRemote)
+		}
+	}
+	return mirrorRemotes, nil
+}
+
+// fetchRoots concurrently fetches the root manifest of each Zarf pkg, a pkg referenced more than once (same URL and
+// platform) is only fetched once. If a pkg is fetched from a source mirror its source remote is replaced with the
+// mirror's, so the pkg's layers are fetched from the mirror too
+func (r *RemoteBundle) fetchRoots(ctx context.Context, srcRemotes []*zoci.Remote) ([]*oci.Manifest, error) {
+	mirrorRemotes, err := r.newMirrorRemotes()
+	if err != nil {
+		return nil, err
+	}
+	pkgRootManifests := make([]*oci.Manifest, len(srcRemotes))
+	// firstIdx maps each unique pkg to the index of the first remote referencing it
+	firstIdx := make(map[string]int)
+	fetchGroup, fetchCtx := errgroup.WithContext(ctx)
+	fetchGroup.SetLimit(max(config.CommonOptions.OCIConcurrency, 1))
+	for i, src := range srcRemotes {
+		key := pkgRootKey(r.bundle.Packages[i])
+		if _, ok := firstIdx[key]; ok {
+			continue
+		}
+		firstIdx[key] = i
+		i, src := i, src
+		fetchGroup.Go(func() error {
+			pkg := r.bundle.Packages[i]
+			pkgURL := fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref)
+			if !utils.IsRemotePkg(pkg) {
+				pkgURL = src.Repo().Reference.String()
+			}
+			var fetchedFrom *zoci.Remote
+			var pkgRootManifest *oci.Manifest
+			err := r.limiter.Do(fetchCtx, func() (err error) {
+				fetchedFrom, pkgRootManifest, err = r.fetchRootWithMirrors(fetchCtx, src, mirrorRemotes[i], pkgURL)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("unable to fetch the root manifest of package %s (packages[%d]) at %s: %w", pkg.Name, i, pkgURL, err)
+			}
+			srcRemotes[i] = fetchedFrom
+			pkgRootManifests[i] = pkgRootManifest
+			return nil
+		})
+	}
+	if err := fetchGroup.Wait(); err != nil {
+		return nil, err
+	}
+	for i := range srcRemotes {
+		first := firstIdx[pkgRootKey(r.bundle.Packages[i])]
+		pkgRootManifests[i] = pkgRootManifests[first]
+		if len(r.sourceMirrors) > 0 {
+			srcRemotes[i] = srcRemotes[first]
+		}
+	}
+	return pkgRootManifests, nil
+}
+
+// fetchRootWithMirrors fetches a Zarf pkg's root manifest from its registry, if that fails it's fetched from each
+// mirror in order until one succeeds. It returns the remote the root manifest was fetched from
+func (r *RemoteBundle) fetchRootWithMirrors(ctx context.Context, src *zoci.Remote, mirrors []*zoci.Remote, pkgURL string) (*zoci.Remote, *oci.Manifest, error) {
+	pkgRootManifest, err := utils.FetchRoot(ctx, src.OrasRemote, pkgURL, !r.noCache)
+	if err == nil || len(mirrors) == 0 || ctx.Err() != nil {
+		return src, pkgRootManifest, err
+	}
+	errs := []error{err}
+	for _, mirror := range mirrors {
+		mirrorURL := mirror.Repo().Reference.String()
+		pkgRootManifest, mirrorErr := utils.FetchRoot(ctx, mirror.OrasRemote, mirrorURL, !r.noCache)
+		if mirrorErr == nil {
+			message.Warnf("Unable to fetch %s, fetching it from mirror %s instead: %s", pkgURL, mirrorURL, err)
+			r.log.Warn("fetching package from mirror", "source", pkgURL, "mirror", mirrorURL, "error", err)
+			return mirror, pkgRootManifest, nil
+		}
+		message.Debugf("Unable to fetch %s from mirror %s: %s", pkgURL, mirrorURL, mirrorErr)
+		errs = append(errs, fmt.Errorf("mirror %s: %w", mirrorURL, mirrorErr))
+	}
+	return nil, nil, errors.Join(errs...)
+}
+
+// checkPkgPlatforms returns an error if any Zarf pkg's config is for a different arch than the one it was fetched for,
+// pkgs that resolve to the same root manifest share a config so each config is only fetched once
+func (r *RemoteBundle) checkPkgPlatforms(ctx context.Context, srcRemotes []*zoci.Remote, pkgRootManifests []*oci.Manifest) error {
+	pkgConfigs := make(map[string]oci.ConfigPartial)
+	for i, pkg := range r.bundle.Packages {
+		configDesc := pkgRootManifests[i].Config
+		pkgConfig, ok := pkgConfigs[configDesc.Digest.String()]
+		if !ok {
+			b, err := srcRemotes[i].FetchLayer(ctx, configDesc)
+			if err != nil {
+				return fmt.Errorf("unable to fetch the config of package %s: %w", pkg.Name, err)
+			}
+			if err := json.Unmarshal(b, &pkgConfig); err != nil {
+				return fmt.Errorf("unable to parse the config of package %s: %w", pkg.Name, err)
+			}
+			pkgConfigs[configDesc.Digest.String()] = pkgConfig
+		}
+		if err := checkPkgPlatform(pkg, pkgConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneImages removes the images excluded by the bundle from each Zarf pkg and overrides the registries of the rest,
+// the result for a pkg is nil if none of its images are excluded or overridden
+func (r *RemoteBundle) pruneImages(ctx context.Context, srcRemotes []*zoci.Remote, pkgRootManifests []*oci.Manifest) ([]*utils.PrunedPackage, error) {
+	prunedPkgs := make([]*utils.PrunedPackage, len(srcRemotes))
+	for i, pkg := range r.bundle.Packages {
+		pruned, err := utils.PruneImages(ctx, *srcRemotes[i], pkgRootManifests[i], pkg, r.registryOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("unable to rewrite the images of package %s: %w", pkg.Name, err)
+		}
+		prunedPkgs[i] = pruned
+	}
+	return prunedPkgs, nil
+}
+
+// pkgRootKey identifies the root manifest a Zarf pkg resolves to, by its URL (or a local pkg's path) and platform
+func pkgRootKey(pkg types.Package) string {
+	if !utils.IsRemotePkg(pkg) {
+		return fmt.Sprintf("%s:%s|%s", pkg.Path, pkg.Ref, utils.GetPkgPlatform(pkg).Architecture)
+	}
+	return fmt.Sprintf("%s:%s|%s", pkg.Repository, pkg.Ref, utils.GetPkgPlatform(pkg).Architecture)
+}
+
+// planPush resolves each package and prints the layers that would be pushed to the bundle without pushing anything
+func (r *RemoteBundle) planPush(ctx context.Context, srcRemotes []*zoci.Remote, pkgRootManifests []*oci.Manifest, prunedPkgs []*utils.PrunedPackage, signature []byte) error {
+	estimate, err := r.estimate(ctx, srcRemotes, pkgRootManifests, prunedPkgs, signature)
 	if err != nil {
 		return err
 	}
 
-	message.HorizontalRule()
-	flags := ""
-	if config.CommonOptions.Insecure {
-		flags = "--insecure"
+	var rows [][]string
+	for _, layer := range estimate.Layers {
+		rows = append(rows, []string{layer.Title, layer.Digest.String(), zarfUtils.ByteFormat(float64(layer.Size), 2)})
 	}
-	message.Title("To inspect/deploy/pull:", "")
-	message.Command("inspect oci://%s %s", dstRef, flags)
-	message.Command("deploy oci://%s %s", dstRef, flags)
-	message.Command("pull oci://%s %s", dstRef, flags)
+	message.Table([]string{"Layer", "Digest", "Size"}, rows)
+	message.Infof("Dry run complete, %s (%d bytes) would be pushed to %s", zarfUtils.ByteFormat(float64(estimate.TotalBytes), 2), estimate.TotalBytes, strings.Join(r.outputs, ", "))
+	return nil
+}
 
+// printJSON writes a value to stdout as JSON, all other output goes to stderr so stdout can be parsed
+func printJSON(v any) error {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(output) + "\n")
 	return nil
 }
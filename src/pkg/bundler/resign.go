@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ResignOptions are the options for replacing the signature of a published bundle
+type ResignOptions struct {
+	// Signature is the new signature of the bundle's YAML as published
+	Signature []byte
+	// SignatureAnnotations are added to the new signature layer, e.g. the certificate of a keyless signature
+	SignatureAnnotations map[string]string
+	// Logger receives structured events as the signature is pushed, the events are dropped if it's nil
+	Logger *slog.Logger
+	// VerifySignatureKey is the path to a public key the signature is verified with against the published YAML before
+	// it's pushed, e.g. to catch an external signer that signed something else
+	VerifySignatureKey string
+}
+
+// Resign pushes a new signature for the bundle published at the bundle remote's reference and points the bundle's
+// root manifest and index at it, the bundle's Zarf pkgs and YAML aren't pushed again. It returns the desc of the new
+// root manifest
+func Resign(ctx context.Context, bundleRemote *zoci.Remote, bundle *types.UDSBundle, opts ResignOptions) (ocispec.Descriptor, error) {
+	if len(opts.Signature) == 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("a signature is required to resign a bundle")
+	}
+	root, err := bundleRemote.FetchRoot(ctx)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return attachSignature(ctx, bundleRemote, bundle, root, opts)
+}
+
+// AttachSignature attaches a signature to the root manifest a create pushed, e.g. a signature an external signer (an
+// HSM or KMS) produced over the YAML of a bundle that was created unsigned, see Bundler.BundleYAMLDesc. Unlike Resign,
+// the signature is attached to the given root manifest instead of whichever one the bundle's tag points at, so it
+// fails if the bundle was re-created in between. It returns the desc of the new root manifest
+func AttachSignature(ctx context.Context, bundleRemote *zoci.Remote, bundle *types.UDSBundle, rootManifestDesc ocispec.Descriptor, opts ResignOptions) (ocispec.Descriptor, error) {
+	if len(opts.Signature) == 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("a signature is required to attach it to a bundle")
+	}
+	tagRef := bundleRemote.Repo().Reference
+	tagRef.Reference = bundle.Metadata.Version
+	index, err := utils.GetIndex(ctx, bundleRemote.OrasRemote, tagRef.String())
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if index != nil && !slices.ContainsFunc(index.Manifests, func(desc ocispec.Descriptor) bool { return desc.Digest == rootManifestDesc.Digest }) {
+		return ocispec.Descriptor{}, fmt.Errorf("%s no longer references the root manifest %s, the bundle was re-created after it was pushed", tagRef, rootManifestDesc.Digest)
+	}
+	root, err := bundleRemote.FetchManifest(ctx, rootManifestDesc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return attachSignature(ctx, bundleRemote, bundle, root, opts)
+}
+
+// attachSignature pushes the signature for the bundle's root manifest and points a new root manifest and the bundle's
+// index at it, the bundle's Zarf pkgs and YAML aren't pushed again
+func attachSignature(ctx context.Context, bundleRemote *zoci.Remote, bundle *types.UDSBundle, root *oci.Manifest, opts ResignOptions) (ocispec.Descriptor, error) {
+	log := utils.LoggerOrDiscard(opts.Logger)
+	bundleYAMLDesc := root.Locate(config.BundleYAML)
+	if oci.IsEmptyDescriptor(bundleYAMLDesc) {
+		return ocispec.Descriptor{}, fmt.Errorf("the root manifest doesn't have a %s layer, it isn't a UDS bundle", config.BundleYAML)
+	}
+	if opts.VerifySignatureKey != "" {
+		bundleYAML, err := bundleRemote.FetchLayer(ctx, bundleYAMLDesc)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if err := verifySignature(config.CommonOptions.TempDirectory, bundleYAML, opts.Signature, opts.VerifySignatureKey); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	// the new signature is pushed with the same media type as the old one so registries that accepted it accept this
+	signatureMediaType := bundleYAMLDesc.MediaType
+	if sigDesc := root.Locate(config.BundleYAMLSignature); !oci.IsEmptyDescriptor(sigDesc) {
+		signatureMediaType = sigDesc.MediaType
+	}
+	var signatureDesc ocispec.Descriptor
+	var err error
+	if _, detached := root.Annotations[config.BundleSignatureDigestAnnotation]; detached {
+		signatureDesc, err = pushDetachedSignature(ctx, bundleRemote, opts.Signature, signatureMediaType, log)
+	} else {
+		var pushedDesc *ocispec.Descriptor
+		err = utils.RetryOCI(ctx, "push "+config.BundleYAMLSignature, func() (err error) {
+			pushedDesc, err = bundleRemote.PushLayer(ctx, opts.Signature, signatureMediaType)
+			return err
+		})
+		if err == nil {
+			signatureDesc = *pushedDesc
+			logPushedMetadata(log, bundleRemote, config.BundleYAMLSignature, signatureDesc)
+		}
+	}
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	rootManifest := resignedRootManifest(root.Manifest, signatureDesc, opts.SignatureAnnotations)
+	var rootManifestDesc *ocispec.Descriptor
+	err = utils.RetryOCI(ctx, "push root manifest", func() (err error) {
+		rootManifestDesc, err = utils.PushRootManifest(ctx, rootManifest, bundleRemote.OrasRemote)
+		return err
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	message.Successf("Published %s@%s [%s]", bundleRemote.Repo().Reference.Repository, rootManifestDesc.Digest, rootManifestDesc.MediaType)
+	log.Info("pushed root manifest", "destination", bundleRemote.Repo().Reference.String(), "digest", rootManifestDesc.Digest.String(), "bytes", rootManifestDesc.Size)
+
+	// the bundle's version tag points at the index, which is updated to reference the new root manifest for this arch
+	tagRef := bundleRemote.Repo().Reference
+	tagRef.Reference = bundle.Metadata.Version
+	index, err := utils.GetIndex(ctx, bundleRemote.OrasRemote, tagRef.String())
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	err = utils.RetryOCI(ctx, "update index", func() error {
+		return utils.UpdateIndex(ctx, index, bundleRemote.OrasRemote, bundle, *rootManifestDesc)
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	log.Info("resigned bundle", "bundle", bundle.Metadata.Name, "version", bundle.Metadata.Version, "destination", tagRef.String(), "digest", rootManifestDesc.Digest.String())
+	return *rootManifestDesc, nil
+}
+
+// resignedRootManifest returns a copy of the root manifest that references the new signature instead of the old one,
+// a detached signature stays detached, a signature layer is replaced in place and an unsigned bundle gets a signature
+// layer after its YAML; the Zarf pkg manifests and the bundle's other layers are left untouched
+func resignedRootManifest(root ocispec.Manifest, signatureDesc ocispec.Descriptor, sigAnnotations map[string]string) ocispec.Manifest {
+	resigned := root
+	resigned.Annotations = maps.Clone(root.Annotations)
+	resigned.Layers = slices.Clone(root.Layers)
+
+	if _, detached := resigned.Annotations[config.BundleSignatureDigestAnnotation]; detached {
+		// the old signature's annotations (e.g. the certificate of a keyless signature) don't apply to the new one
+		delete(resigned.Annotations, config.BundleSignatureCertificateAnnotation)
+		delete(resigned.Annotations, config.BundleSignatureRekorLogIndexAnnotation)
+		delete(resigned.Annotations, config.BundleSignatureRekorLogIDAnnotation)
+		addDetachedSignatureAnnotations(&resigned, signatureDesc, sigAnnotations)
+		return resigned
+	}
+
+	signatureDesc.Annotations = signatureLayerAnnotations(sigAnnotations)
+	isTitle := func(title string) func(ocispec.Descriptor) bool {
+		return func(layer ocispec.Descriptor) bool { return layer.Annotations[ocispec.AnnotationTitle] == title }
+	}
+	if i := slices.IndexFunc(resigned.Layers, isTitle(config.BundleYAMLSignature)); i >= 0 {
+		resigned.Layers[i] = signatureDesc
+	} else if i := slices.IndexFunc(resigned.Layers, isTitle(config.BundleYAML)); i >= 0 {
+		resigned.Layers = slices.Insert(resigned.Layers, i+1, signatureDesc)
+	} else {
+		resigned.Layers = append(resigned.Layers, signatureDesc)
+	}
+	return resigned
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// Retag adds a tag to the bundle published at the bundle remote's reference in the same repository, nothing but the
+// index at the new tag is pushed. A bundle with an index is tagged as is so every arch keeps its root manifest, a
+// bundle without one gets an index at the new tag like a create would push. A tag that already points at another
+// bundle is only moved with force. It returns the desc the new tag points at
+func Retag(ctx context.Context, bundleRemote *zoci.Remote, bundle *types.UDSBundle, tag string, force bool) (ocispec.Descriptor, error) {
+	srcRef := bundleRemote.Repo().Reference
+	dstRef := srcRef
+	dstRef.Reference = tag
+	if err := dstRef.ValidateReferenceAsTag(); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("invalid tag %q: %w", tag, err)
+	}
+	if srcRef.Reference == tag {
+		return ocispec.Descriptor{}, fmt.Errorf("%s is already tagged %s", srcRef, tag)
+	}
+
+	// check the source exists before anything is tagged
+	srcDesc, err := bundleRemote.Repo().Resolve(ctx, srcRef.Reference)
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return ocispec.Descriptor{}, fmt.Errorf("%s doesn't exist", srcRef)
+		}
+		return ocispec.Descriptor{}, err
+	}
+	if srcDesc.MediaType == ocispec.MediaTypeImageManifest {
+		// the bundle's root manifest is added to the index at the new tag for its arch, like a create would, so the
+		// other arches at the new tag are kept
+		retagged := *bundle
+		retagged.Metadata.Version = tag
+		index, err := utils.GetIndex(ctx, bundleRemote.OrasRemote, dstRef.String())
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if index == nil {
+			if err := checkRetagDestination(ctx, bundleRemote, srcDesc, dstRef.String(), force); err != nil {
+				return ocispec.Descriptor{}, err
+			}
+		} else if existing, ok := utils.IndexConflict(index, &retagged, srcDesc); ok && !force {
+			return ocispec.Descriptor{}, fmt.Errorf("%s already has a %s bundle with digest %s, use --force to replace it with %s",
+				dstRef, bundle.Metadata.Architecture, existing.Digest, srcRef)
+		}
+		err = utils.RetryOCI(ctx, "update index", func() error {
+			return utils.UpdateIndex(ctx, index, bundleRemote.OrasRemote, &retagged, srcDesc)
+		})
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		return bundleRemote.Repo().Resolve(ctx, tag)
+	}
+
+	if err := checkRetagDestination(ctx, bundleRemote, srcDesc, dstRef.String(), force); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	err = utils.RetryOCI(ctx, "tag "+tag, func() error {
+		return bundleRemote.Repo().Tag(ctx, srcDesc, tag)
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return srcDesc, nil
+}
+
+// checkRetagDestination returns an error if the new tag already points at something other than the source, unless
+// it's forced to move
+func checkRetagDestination(ctx context.Context, bundleRemote *zoci.Remote, srcDesc ocispec.Descriptor, dst string, force bool) error {
+	dstDesc, err := bundleRemote.Repo().Resolve(ctx, dst)
+	if errors.Is(err, errdef.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if dstDesc.Digest != srcDesc.Digest && !force {
+		return fmt.Errorf("%s already points at %s, use --force to move it", dst, dstDesc.Digest)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package bundler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// manifestRegistry is a fake registry that only stores manifests, by digest and by tag, any blob request fails the test
+type manifestRegistry struct {
+	t         *testing.T
+	mu        sync.Mutex
+	manifests map[string][]byte
+	mediaType map[string]string
+	tags      map[string]string
+}
+
+func newManifestRegistry(t *testing.T) *manifestRegistry {
+	return &manifestRegistry{t: t, manifests: map[string][]byte{}, mediaType: map[string]string{}, tags: map[string]string{}}
+}
+
+func (m *manifestRegistry) put(ref, mediaType string, b []byte) digest.Digest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d := digest.FromBytes(b)
+	m.manifests[d.String()] = b
+	m.mediaType[d.String()] = mediaType
+	if _, err := digest.Parse(ref); err != nil {
+		m.tags[ref] = d.String()
+	}
+	return d
+}
+
+func (m *manifestRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/v2/dev/bundle/manifests/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		m.t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ref := strings.TrimPrefix(r.URL.Path, prefix)
+	if r.Method == http.MethodPut {
+		b, _ := io.ReadAll(r.Body)
+		d := m.put(ref, r.Header.Get("Content-Type"), b)
+		w.Header().Set("Docker-Content-Digest", d.String())
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	m.mu.Lock()
+	d, ok := m.tags[ref]
+	if !ok {
+		d = ref
+	}
+	b, ok := m.manifests[d]
+	mediaType := m.mediaType[d]
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", d)
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	if r.Method == http.MethodGet {
+		w.Write(b)
+	}
+}
+
+func Test_Retag(t *testing.T) {
+	ctx := context.Background()
+	registry := newManifestRegistry(t)
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Name: "bundle", Version: "1.0.0", Architecture: "amd64"}}
+	remote := func(tag string) *zoci.Remote {
+		r, err := zoci.NewRemote(host+"/dev/bundle:"+tag, ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+		require.NoError(t, err)
+		return r
+	}
+	pushManifest := func(tag string, manifest ocispec.Manifest) ocispec.Descriptor {
+		b, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		d := registry.put(tag, ocispec.MediaTypeImageManifest, b)
+		return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: d, Size: int64(len(b))}
+	}
+	pushIndex := func(tag string, manifests ...ocispec.Descriptor) digest.Digest {
+		b, err := json.Marshal(ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: manifests})
+		require.NoError(t, err)
+		return registry.put(tag, ocispec.MediaTypeImageIndex, b)
+	}
+	index := func(tag string) ocispec.Index {
+		var index ocispec.Index
+		require.NoError(t, json.Unmarshal(registry.manifests[registry.tags[tag]], &index))
+		return index
+	}
+
+	amd64 := pushManifest("", ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Annotations: map[string]string{"arch": "amd64"}})
+	amd64.Platform = &ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}
+	indexDigest := pushIndex("1.0.0", amd64)
+
+	// a bundle with an index is tagged as is
+	desc, err := Retag(ctx, remote("1.0.0"), bundle, "stable", false)
+	require.NoError(t, err)
+	require.Equal(t, indexDigest, desc.Digest)
+	require.Equal(t, indexDigest.String(), registry.tags["stable"])
+
+	_, err = Retag(ctx, remote("0.9.0"), bundle, "latest", false)
+	require.ErrorContains(t, err, "dev/bundle:0.9.0 doesn't exist")
+	_, err = Retag(ctx, remote("1.0.0"), bundle, "not a tag", false)
+	require.ErrorContains(t, err, `invalid tag "not a tag"`)
+	_, err = Retag(ctx, remote("1.0.0"), bundle, "1.0.0", false)
+	require.ErrorContains(t, err, "is already tagged 1.0.0")
+
+	// a tag that points at another bundle is only moved with force
+	otherDigest := pushIndex("1.1.0", amd64, amd64)
+	_, err = Retag(ctx, remote("1.1.0"), bundle, "stable", false)
+	require.ErrorContains(t, err, "already points at "+indexDigest.String()+", use --force to move it")
+	_, err = Retag(ctx, remote("1.1.0"), bundle, "stable", true)
+	require.NoError(t, err)
+	require.Equal(t, otherDigest.String(), registry.tags["stable"])
+
+	// a bundle without an index is added to the index at the new tag for its arch, keeping the other arches
+	arm64 := pushManifest("", ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Annotations: map[string]string{"arch": "arm64"}})
+	arm64.Platform = &ocispec.Platform{OS: oci.MultiOS, Architecture: "arm64"}
+	pushIndex("2.0.0", arm64)
+	pushManifest("legacy", ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Annotations: map[string]string{"arch": "amd64", "legacy": "true"}})
+	legacy := registry.tags["legacy"]
+	_, err = Retag(ctx, remote("legacy"), bundle, "2.0.0", false)
+	require.NoError(t, err)
+	manifests := index("2.0.0").Manifests
+	require.Len(t, manifests, 2)
+	require.Equal(t, arm64.Digest, manifests[0].Digest)
+	require.Equal(t, legacy, manifests[1].Digest.String())
+	require.Equal(t, "amd64", manifests[1].Platform.Architecture)
+
+	// the root manifest for the bundle's arch in the index at the new tag is only replaced with force
+	_, err = Retag(ctx, remote("legacy"), bundle, "1.0.0", false)
+	require.ErrorContains(t, err, "already has a amd64 bundle with digest "+amd64.Digest.String())
+}
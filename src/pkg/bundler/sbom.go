@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// SBOMFormatSPDX generates an SPDX 2.3 JSON bundle SBOM
+	SBOMFormatSPDX = "spdx"
+	// SBOMFormatCycloneDX generates a CycloneDX 1.5 JSON bundle SBOM
+	SBOMFormatCycloneDX = "cyclonedx"
+
+	// spdxMediaType and cycloneDXMediaType are the registered media types of JSON SBOMs, they're used as the artifact
+	// type of a bundle SBOM attached with the OCI referrers API
+	spdxMediaType      = "application/spdx+json"
+	cycloneDXMediaType = "application/vnd.cyclonedx+json"
+)
+
+// sbomMediaType returns the media type of a bundle SBOM in the given format
+func sbomMediaType(format string) string {
+	if format == SBOMFormatCycloneDX {
+		return cycloneDXMediaType
+	}
+	return spdxMediaType
+}
+
+// spdxIDPattern matches the characters that aren't allowed in an SPDX identifier
+var spdxIDPattern = regexp.MustCompile(`[^a-zA-Z0-9.-]`)
+
+type spdxDocument struct {
+	SPDXVersion       string           `json:"spdxVersion"`
+	DataLicense       string           `json:"dataLicense"`
+	SPDXID            string           `json:"SPDXID"`
+	Name              string           `json:"name"`
+	DocumentNamespace string           `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo `json:"creationInfo"`
+	Packages          []spdxPackage    `json:"packages"`
+	Relationships     []spdxRelation   `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string         `json:"SPDXID"`
+	Name             string         `json:"name"`
+	VersionInfo      string         `json:"versionInfo"`
+	DownloadLocation string         `json:"downloadLocation"`
+	Checksums        []spdxChecksum `json:"checksums,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelation struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type cycloneDXDocument struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    cycloneDXMetadata    `json:"metadata"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDXTool    `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cycloneDXComponent struct {
+	Type    string          `json:"type"`
+	Name    string          `json:"name"`
+	Version string          `json:"version"`
+	Hashes  []cycloneDXHash `json:"hashes,omitempty"`
+	PURL    string          `json:"purl,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+// generateBundleSBOM generates a bundle-level SBOM that references each Zarf pkg by the digest of its manifest in the bundle,
+// zarfManifestDescs must be in the same order as the bundle's packages
+func generateBundleSBOM(format string, bundle *types.UDSBundle, zarfManifestDescs []ocispec.Descriptor) ([]byte, error) {
+	created := time.Now().UTC().Format(time.RFC3339)
+	switch format {
+	case SBOMFormatSPDX:
+		doc := spdxDocument{
+			SPDXVersion:       "SPDX-2.3",
+			DataLicense:       "CC0-1.0",
+			SPDXID:            "SPDXRef-DOCUMENT",
+			Name:              bundle.Metadata.Name,
+			DocumentNamespace: fmt.Sprintf("https://github.com/defenseunicorns/uds-cli/spdx/%s/%s/%s", bundle.Metadata.Name, bundle.Metadata.Version, created),
+			CreationInfo: spdxCreationInfo{
+				Created:  created,
+				Creators: []string{"Tool: uds-cli-" + config.CLIVersion},
+			},
+		}
+		bundleID := "SPDXRef-Bundle-" + spdxIDPattern.ReplaceAllString(bundle.Metadata.Name, "-")
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:           bundleID,
+			Name:             bundle.Metadata.Name,
+			VersionInfo:      bundle.Metadata.Version,
+			DownloadLocation: "NOASSERTION",
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelation{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: bundleID})
+		for i, pkg := range bundle.Packages {
+			pkgID := fmt.Sprintf("SPDXRef-Package-%d-%s", i, spdxIDPattern.ReplaceAllString(pkg.Name, "-"))
+			doc.Packages = append(doc.Packages, spdxPackage{
+				SPDXID:           pkgID,
+				Name:             pkg.Name,
+				VersionInfo:      pkg.Ref,
+				DownloadLocation: pkgDownloadLocation(pkg),
+				Checksums:        []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: zarfManifestDescs[i].Digest.Encoded()}},
+			})
+			doc.Relationships = append(doc.Relationships, spdxRelation{SPDXElementID: bundleID, RelationshipType: "CONTAINS", RelatedSPDXElement: pkgID})
+		}
+		return json.MarshalIndent(doc, "", "  ")
+	case SBOMFormatCycloneDX:
+		doc := cycloneDXDocument{
+			BOMFormat:   "CycloneDX",
+			SpecVersion: "1.5",
+			Version:     1,
+			Metadata: cycloneDXMetadata{
+				Timestamp: created,
+				Tools:     []cycloneDXTool{{Name: "uds-cli", Version: config.CLIVersion}},
+				Component: cycloneDXComponent{Type: "application", Name: bundle.Metadata.Name, Version: bundle.Metadata.Version},
+			},
+		}
+		for i, pkg := range bundle.Packages {
+			component := cycloneDXComponent{
+				Type:    "application",
+				Name:    pkg.Name,
+				Version: pkg.Ref,
+				Hashes:  []cycloneDXHash{{Alg: "SHA-256", Content: zarfManifestDescs[i].Digest.Encoded()}},
+			}
+			if pkg.Repository != "" {
+				component.PURL = fmt.Sprintf("pkg:oci/%s@%s?repository_url=%s", pkg.Name, url.QueryEscape(zarfManifestDescs[i].Digest.String()), url.QueryEscape(pkg.Repository))
+			}
+			doc.Components = append(doc.Components, component)
+		}
+		return json.MarshalIndent(doc, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format %q, supported formats are %q and %q", format, SBOMFormatSPDX, SBOMFormatCycloneDX)
+	}
+}
+
+// pkgDownloadLocation returns where a Zarf pkg in the bundle was sourced from
+func pkgDownloadLocation(pkg types.Package) string {
+	if pkg.Repository != "" {
+		return pkg.Repository
+	}
+	return "NOASSERTION"
+}
@@ -0,0 +1,57 @@
+package bundler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func Test_generateBundleSBOM(t *testing.T) {
+	bundle := &types.UDSBundle{
+		Metadata: types.UDSMetadata{Name: "example", Version: "0.0.1"},
+		Packages: []types.Package{
+			{Name: "podinfo", Repository: "ghcr.io/defenseunicorns/uds-cli/podinfo", Ref: "0.0.1"},
+			{Name: "local pkg", Path: "../packages", Ref: "0.0.2"},
+		},
+	}
+	descs := []ocispec.Descriptor{
+		content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("podinfo")),
+		content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("local")),
+	}
+
+	t.Run("SPDX", func(t *testing.T) {
+		b, err := generateBundleSBOM(SBOMFormatSPDX, bundle, descs)
+		require.NoError(t, err)
+		var doc spdxDocument
+		require.NoError(t, json.Unmarshal(b, &doc))
+		require.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+		require.Len(t, doc.Packages, 3)
+		require.Equal(t, "SPDXRef-Package-1-local-pkg", doc.Packages[2].SPDXID)
+		require.Equal(t, "0.0.2", doc.Packages[2].VersionInfo)
+		require.Equal(t, "NOASSERTION", doc.Packages[2].DownloadLocation)
+		require.Equal(t, descs[0].Digest.Encoded(), doc.Packages[1].Checksums[0].ChecksumValue)
+		require.Len(t, doc.Relationships, 3)
+	})
+
+	t.Run("CycloneDX", func(t *testing.T) {
+		b, err := generateBundleSBOM(SBOMFormatCycloneDX, bundle, descs)
+		require.NoError(t, err)
+		var doc cycloneDXDocument
+		require.NoError(t, json.Unmarshal(b, &doc))
+		require.Equal(t, "CycloneDX", doc.BOMFormat)
+		require.Equal(t, "example", doc.Metadata.Component.Name)
+		require.Len(t, doc.Components, 2)
+		require.Equal(t, descs[1].Digest.Encoded(), doc.Components[1].Hashes[0].Content)
+		require.Contains(t, doc.Components[0].PURL, "pkg:oci/podinfo@sha256%3A")
+		require.Empty(t, doc.Components[1].PURL)
+	})
+
+	t.Run("UnsupportedFormat", func(t *testing.T) {
+		_, err := generateBundleSBOM("syft", bundle, descs)
+		require.Error(t, err)
+	})
+}
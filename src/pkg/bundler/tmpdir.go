@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// makeTmpDir creates the dir a create stages the bundle in under base, creating base if it doesn't exist yet, e.g. a
+// dir on a large volume. The OS's tmp dir is used if base is empty
+func makeTmpDir(base string) (string, error) {
+	if base != "" {
+		if err := os.MkdirAll(base, 0700); err != nil {
+			return "", fmt.Errorf("unable to create the tmp dir %s: %w", base, err)
+		}
+	}
+	dir, err := os.MkdirTemp(base, "uds-bundler-")
+	if err != nil {
+		return "", fmt.Errorf("unable to create a tmp dir in %s: %w", base, err)
+	}
+	return dir, nil
+}
+
+// removeTmpDir removes the dir from makeTmpDir and verifies that it's gone, so a create that leaks files, e.g. a file
+// that's still being written, is reported instead of filling up the disk
+func removeTmpDir(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("unable to remove the tmp dir %s: %w", dir, err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("the tmp dir %s still exists after it was removed", dir)
+	}
+	return nil
+}
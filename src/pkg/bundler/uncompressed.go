@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	zarfUtils "github.com/defenseunicorns/zarf/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// UncompressedSizeExact decompresses each compressed image layer to count its uncompressed size
+	UncompressedSizeExact = "exact"
+	// UncompressedSizeEstimate multiplies the size of each compressed image layer by estimatedCompressionRatio
+	UncompressedSizeEstimate = "estimate"
+	// estimatedCompressionRatio is the typical ratio of a gzip or zstd container image layer's uncompressed size to
+	// its compressed size
+	estimatedCompressionRatio = 3
+)
+
+// validateUncompressedSize checks the mode the total uncompressed size of the Zarf pkgs' layers is computed with
+func validateUncompressedSize(mode string) error {
+	if mode != "" && mode != UncompressedSizeExact && mode != UncompressedSizeEstimate {
+		return fmt.Errorf("unsupported uncompressed size %q, supported modes are %q and %q", mode, UncompressedSizeExact, UncompressedSizeEstimate)
+	}
+	return nil
+}
+
+// uncompressedSize sums the uncompressed size of every layer of the Zarf pkgs that's pushed to the bundle. Component
+// tarballs and the pkgs' metadata are uncompressed, the compressed image layers are decompressed to count their size
+// in exact mode or estimated from their compressed size. An image layer shared by several pkgs is only decompressed once
+func (r *RemoteBundle) uncompressedSize(ctx context.Context, srcRemotes []*zoci.Remote, pkgRootManifests []*oci.Manifest, prunedPkgs []*utils.PrunedPackage) (int64, error) {
+	spinner := message.NewProgressSpinner("Computing the uncompressed size of bundle %s", r.bundle.Metadata.Name)
+	defer spinner.Stop()
+
+	exact := make(map[digest.Digest]int64)
+	var total int64
+	for i, pkg := range r.bundle.Packages {
+		root := pkgRootManifests[i]
+		layers, err := utils.GetZarfLayers(ctx, *srcRemotes[i], root, pkg.OptionalComponents)
+		if err != nil {
+			return 0, err
+		}
+		if pruned := prunedPkgs[i]; pruned != nil {
+			root = pruned.Root
+			layers = pruned.Filter(layers)
+			for _, blob := range pruned.Blobs {
+				total += blob.Desc.Size
+			}
+		}
+		images, err := packageImages(ctx, srcRemotes[i], root)
+		if err != nil {
+			return 0, fmt.Errorf("unable to list the images of package %s: %w", pkg.Name, err)
+		}
+		mediaTypes := make(map[digest.Digest]string)
+		for _, image := range images {
+			for _, layer := range image.Layers {
+				mediaTypes[layer.Digest] = layer.MediaType
+			}
+		}
+
+		for _, layer := range oci.RemoveDuplicateDescriptors(layers) {
+			compression := layerCompression(mediaTypes[layer.Digest])
+			switch {
+			case compression == "":
+				size, err := utils.UncompressedSize(layer)
+				if err != nil {
+					return 0, err
+				}
+				total += size
+			case r.uncompressedSizeMode == UncompressedSizeEstimate:
+				total += layer.Size * estimatedCompressionRatio
+			default:
+				if _, ok := exact[layer.Digest]; !ok {
+					spinner.Updatef("Decompressing layer %s of package %s", layer.Digest.Encoded(), pkg.Name)
+					if exact[layer.Digest], err = decompressedSize(ctx, srcRemotes[i], layer, compression); err != nil {
+						return 0, fmt.Errorf("unable to decompress layer %s of package %s: %w", layer.Digest, pkg.Name, err)
+					}
+				}
+				total += exact[layer.Digest]
+			}
+		}
+	}
+	if !r.quiet {
+		spinner.Successf("The uncompressed size of bundle %s is %s", r.bundle.Metadata.Name, zarfUtils.ByteFormat(float64(total), 2))
+	}
+	return total, nil
+}
+
+// layerCompression returns the compression (gzip or zstd) of an image layer's media type, or "" if it's uncompressed
+func layerCompression(mediaType string) string {
+	switch {
+	case strings.HasSuffix(mediaType, "gzip"):
+		return "gzip"
+	case strings.HasSuffix(mediaType, "zstd"):
+		return "zstd"
+	default:
+		return ""
+	}
+}
+
+// decompressedSize streams a layer from the remote through a decompressor and counts the decompressed bytes, the
+// layer isn't written anywhere
+func decompressedSize(ctx context.Context, remote *zoci.Remote, layer ocispec.Descriptor, compression string) (int64, error) {
+	rc, err := remote.Repo().Fetch(ctx, layer)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	return countDecompressed(rc, compression)
+}
+
+// countDecompressed counts the bytes of r once it's decompressed
+func countDecompressed(r io.Reader, compression string) (int64, error) {
+	var dec io.Reader
+	switch compression {
+	case "gzip":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		dec = gz
+	case "zstd":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return 0, err
+		}
+		defer zr.Close()
+		dec = zr
+	default:
+		dec = r
+	}
+	return io.Copy(io.Discard, dec)
+}
@@ -0,0 +1,47 @@
+package bundler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_layerCompression(t *testing.T) {
+	require.Equal(t, "gzip", layerCompression(ocispec.MediaTypeImageLayerGzip))
+	require.Equal(t, "gzip", layerCompression("application/vnd.docker.image.rootfs.diff.tar.gzip"))
+	require.Equal(t, "zstd", layerCompression(ocispec.MediaTypeImageLayerZstd))
+	require.Equal(t, "", layerCompression(ocispec.MediaTypeImageLayer))
+	require.Equal(t, "", layerCompression(ocispec.MediaTypeImageConfig))
+	// a component tarball isn't an image layer so it doesn't have a media type
+	require.Equal(t, "", layerCompression(""))
+}
+
+func Test_countDecompressed(t *testing.T) {
+	layer := bytes.Repeat([]byte("uds bundle layer "), 4096)
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, err := gw.Write(layer)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	size, err := countDecompressed(&gz, "gzip")
+	require.NoError(t, err)
+	require.Equal(t, int64(len(layer)), size)
+
+	var zst bytes.Buffer
+	zw, err := zstd.NewWriter(&zst)
+	require.NoError(t, err)
+	_, err = zw.Write(layer)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	size, err = countDecompressed(&zst, "zstd")
+	require.NoError(t, err)
+	require.Equal(t, int64(len(layer)), size)
+
+	_, err = countDecompressed(bytes.NewReader(layer), "gzip")
+	require.Error(t, err)
+}
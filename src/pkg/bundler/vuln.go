@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package bundler defines behavior for bundling packages
+package bundler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	"github.com/defenseunicorns/zarf/src/pkg/layout"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// PackageImage is a container image in a Zarf pkg, as listed in the pkg's images/index.json
+type PackageImage struct {
+	// Reference is the image's reference in the pkg, e.g. ghcr.io/stefanprodan/podinfo:6.4.0
+	Reference string
+	// Digest is the digest of the image's manifest
+	Digest digest.Digest
+	// Layers are the image's config and layers
+	Layers []ocispec.Descriptor
+}
+
+// VulnFinding is an image in a Zarf pkg that a VulnScanner reported
+type VulnFinding struct {
+	Package string
+	Image   string
+	// Digest is the digest of the image's manifest or layer that matched
+	Digest digest.Digest
+	// Reason is why the image was reported, e.g. a CVE ID
+	Reason string
+}
+
+// VulnScanner reports the images of a Zarf pkg with known vulnerabilities, it's given the images of each pkg before
+// anything is pushed so it can e.g. look them up in a scanner's results
+type VulnScanner interface {
+	Scan(ctx context.Context, pkgName string, images []PackageImage) ([]VulnFinding, error)
+}
+
+// DenyList is a VulnScanner that reports the images whose manifest or any of whose layers is denied, it maps the
+// denied digests to the reason they're denied
+type DenyList map[digest.Digest]string
+
+// denyListEntry is an entry of a deny list in JSON, e.g. converted from a scanner's results
+type denyListEntry struct {
+	Digest string `json:"digest"`
+	Reason string `json:"reason"`
+}
+
+// LoadDenyList reads a deny list of image digests. A .json file is an array of {"digest": ..., "reason": ...}, any
+// other file has a digest per line, optionally followed by the reason; blank lines and lines starting with # are skipped
+func LoadDenyList(path string) (DenyList, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the deny list: %w", err)
+	}
+	var entries []denyListEntry
+	if filepath.Ext(path) == ".json" {
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return nil, fmt.Errorf("unable to parse the deny list %s: %w", path, err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.Fields(line)
+			entries = append(entries, denyListEntry{Digest: fields[0], Reason: strings.Join(fields[1:], " ")})
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("unable to read the deny list %s: %w", path, err)
+		}
+	}
+
+	denyList := make(DenyList, len(entries))
+	for _, entry := range entries {
+		d, err := digest.Parse(entry.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid digest %q in the deny list %s: %w", entry.Digest, path, err)
+		}
+		denyList[d] = entry.Reason
+	}
+	return denyList, nil
+}
+
+// Scan reports each image whose manifest or layers are in the deny list, once per image
+func (deny DenyList) Scan(_ context.Context, pkgName string, images []PackageImage) ([]VulnFinding, error) {
+	var findings []VulnFinding
+	for _, image := range images {
+		denied := []digest.Digest{image.Digest}
+		for _, layer := range image.Layers {
+			denied = append(denied, layer.Digest)
+		}
+		for _, d := range denied {
+			if reason, ok := deny[d]; ok {
+				findings = append(findings, VulnFinding{Package: pkgName, Image: image.Reference, Digest: d, Reason: reason})
+				break
+			}
+		}
+	}
+	return findings, nil
+}
+
+// checkVulns scans the images of each Zarf pkg that are pushed to the bundle, the images excluded from a pruned pkg
+// aren't scanned. The findings are warned about, or fail the create if failOnVuln is set
+func (r *RemoteBundle) checkVulns(ctx context.Context, srcRemotes []*zoci.Remote, pkgRootManifests []*oci.Manifest, prunedPkgs []*utils.PrunedPackage) error {
+	if r.vulnScanner == nil {
+		return nil
+	}
+	var findings []VulnFinding
+	for i, pkg := range r.bundle.Packages {
+		root := pkgRootManifests[i]
+		if prunedPkgs[i] != nil {
+			root = prunedPkgs[i].Root
+		}
+		images, err := packageImages(ctx, srcRemotes[i], root)
+		if err != nil {
+			return fmt.Errorf("unable to list the images of package %s: %w", pkg.Name, err)
+		}
+		pkgFindings, err := r.vulnScanner.Scan(ctx, pkg.Name, images)
+		if err != nil {
+			return fmt.Errorf("unable to scan the images of package %s: %w", pkg.Name, err)
+		}
+		findings = append(findings, pkgFindings...)
+	}
+	return vulnFindingsError(findings, r.failOnVuln)
+}
+
+// packageImages lists the images of a Zarf pkg from its images/index.json and the image manifests, the image layers
+// themselves aren't fetched. Only the images whose manifest is a layer of root are listed
+func packageImages(ctx context.Context, remote *zoci.Remote, root *oci.Manifest) ([]PackageImage, error) {
+	if oci.IsEmptyDescriptor(root.Locate(layout.IndexPath)) {
+		// the pkg doesn't have any images
+		return nil, nil
+	}
+	index, err := remote.FetchImagesIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inRoot := make(map[digest.Digest]bool, len(root.Layers))
+	for _, layer := range root.Layers {
+		inRoot[layer.Digest] = true
+	}
+	var images []PackageImage
+	for _, manifestDesc := range index.Manifests {
+		if !inRoot[manifestDesc.Digest] {
+			continue
+		}
+		// even though these are technically image manifests, they're stored as Zarf blobs
+		manifestDesc.MediaType = zoci.ZarfLayerMediaTypeBlob
+		manifest, err := remote.FetchManifest(ctx, manifestDesc)
+		if err != nil {
+			return nil, err
+		}
+		image := PackageImage{Reference: manifestDesc.Annotations[ocispec.AnnotationBaseImageName], Digest: manifestDesc.Digest}
+		image.Layers = append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...)
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// vulnFindingsError warns about each finding and returns an error listing them if failOnVuln is set
+func vulnFindingsError(findings []VulnFinding, failOnVuln bool) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	details := make([]string, len(findings))
+	for i, finding := range findings {
+		details[i] = fmt.Sprintf("%s in package %s (%s)", finding.Image, finding.Package, finding.Digest)
+		if finding.Reason != "" {
+			details[i] += ": " + finding.Reason
+		}
+		if !failOnVuln {
+			message.Warnf("Image %s has a known vulnerability", details[i])
+		}
+	}
+	if !failOnVuln {
+		return nil
+	}
+	return fmt.Errorf("%d images in the bundle's packages have known vulnerabilities: %s", len(findings), strings.Join(details, "; "))
+}
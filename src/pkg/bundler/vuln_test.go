@@ -0,0 +1,75 @@
+package bundler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadDenyList(t *testing.T) {
+	base := digest.FromString("base")
+	layer := digest.FromString("layer")
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		want     DenyList
+		wantErr  string
+	}{
+		{
+			name:     "Text",
+			file:     "deny.txt",
+			contents: "# vulnerable bases\n" + base.String() + "   CVE-2024-3094 xz backdoor\n\n" + layer.String() + "\n",
+			want:     DenyList{base: "CVE-2024-3094 xz backdoor", layer: ""},
+		},
+		{
+			name:     "JSON",
+			file:     "scan.json",
+			contents: `[{"digest":"` + base.String() + `","reason":"CVE-2024-3094"}]`,
+			want:     DenyList{base: "CVE-2024-3094"},
+		},
+		{name: "InvalidDigest", file: "deny.txt", contents: "sha256:abc\n", wantErr: `invalid digest "sha256:abc"`},
+		{name: "InvalidJSON", file: "scan.json", contents: "{", wantErr: "unable to parse the deny list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0600))
+			denyList, err := LoadDenyList(path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, denyList)
+		})
+	}
+}
+
+func Test_DenyListScan(t *testing.T) {
+	base := digest.FromString("base")
+	denyList := DenyList{base: "CVE-2024-3094"}
+	images := []PackageImage{
+		{Reference: "ghcr.io/stefanprodan/podinfo:6.4.0", Digest: digest.FromString("podinfo"), Layers: []ocispec.Descriptor{{Digest: digest.FromString("config")}, {Digest: base}}},
+		{Reference: "docker.io/library/nginx:1.25", Digest: digest.FromString("nginx"), Layers: []ocispec.Descriptor{{Digest: digest.FromString("nginx-layer")}}},
+		// an image is only reported once even if several of its layers are denied
+		{Reference: "docker.io/library/alpine:3.19", Digest: base, Layers: []ocispec.Descriptor{{Digest: base}}},
+	}
+	findings, err := denyList.Scan(context.Background(), "podinfo", images)
+	require.NoError(t, err)
+	require.Equal(t, []VulnFinding{
+		{Package: "podinfo", Image: "ghcr.io/stefanprodan/podinfo:6.4.0", Digest: base, Reason: "CVE-2024-3094"},
+		{Package: "podinfo", Image: "docker.io/library/alpine:3.19", Digest: base, Reason: "CVE-2024-3094"},
+	}, findings)
+
+	require.NoError(t, vulnFindingsError(findings, false))
+	require.NoError(t, vulnFindingsError(nil, true))
+	err = vulnFindingsError(findings, true)
+	require.EqualError(t, err, "2 images in the bundle's packages have known vulnerabilities: ghcr.io/stefanprodan/podinfo:6.4.0 in package podinfo ("+base.String()+"): CVE-2024-3094; docker.io/library/alpine:3.19 in package podinfo ("+base.String()+"): CVE-2024-3094")
+}
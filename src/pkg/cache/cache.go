@@ -5,6 +5,8 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -12,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/opencontainers/go-digest"
 )
 
 func expandTilde(cachePath string) string {
@@ -86,3 +89,31 @@ func Use(layerDigest, dstDir string) error {
 	_, err = io.Copy(dstFile, srcFile)
 	return err
 }
+
+// AddManifest caches a Zarf pkg's root manifest, keyed by the pkg's URL and the manifest's digest
+func AddManifest(pkgURL string, manifestDigest digest.Digest, manifestBytes []byte) error {
+	manifestCachePath := manifestPath(pkgURL, manifestDigest)
+	if err := os.MkdirAll(filepath.Dir(manifestCachePath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(manifestCachePath, manifestBytes, 0o644)
+}
+
+// GetManifest returns a Zarf pkg's cached root manifest, it isn't found if the cached content doesn't match the digest
+func GetManifest(pkgURL string, manifestDigest digest.Digest) ([]byte, bool) {
+	manifestBytes, err := os.ReadFile(manifestPath(pkgURL, manifestDigest))
+	if err != nil {
+		return nil, false
+	}
+	if manifestDigest.Validate() != nil || manifestDigest.Algorithm().FromBytes(manifestBytes) != manifestDigest {
+		return nil, false
+	}
+	return manifestBytes, true
+}
+
+// manifestPath returns the path of a Zarf pkg's root manifest in the cache
+func manifestPath(pkgURL string, manifestDigest digest.Digest) string {
+	urlSHA := sha256.Sum256([]byte(pkgURL))
+	filename := fmt.Sprintf("%s-%s", hex.EncodeToString(urlSHA[:]), manifestDigest.Encoded())
+	return filepath.Join(expandTilde(config.CommonOptions.CachePath), config.UDSCacheManifests, filename)
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"os"
+	"testing"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Manifest(t *testing.T) {
+	cachePath := config.CommonOptions.CachePath
+	t.Cleanup(func() { config.CommonOptions.CachePath = cachePath })
+	config.CommonOptions.CachePath = t.TempDir()
+
+	pkgURL := "ghcr.io/defenseunicorns/packages/init:v0.32.6"
+	manifestBytes := []byte(`{"schemaVersion":2}`)
+	manifestDigest := digest.FromBytes(manifestBytes)
+
+	_, ok := GetManifest(pkgURL, manifestDigest)
+	require.False(t, ok)
+
+	require.NoError(t, AddManifest(pkgURL, manifestDigest, manifestBytes))
+	got, ok := GetManifest(pkgURL, manifestDigest)
+	require.True(t, ok)
+	require.Equal(t, manifestBytes, got)
+
+	// the cache is keyed by the pkg's URL as well as the digest
+	_, ok = GetManifest("ghcr.io/defenseunicorns/packages/init:v0.33.0", manifestDigest)
+	require.False(t, ok)
+
+	// corrupt cache entries are ignored
+	require.NoError(t, os.WriteFile(manifestPath(pkgURL, manifestDigest), []byte("corrupt"), 0o644))
+	_, ok = GetManifest(pkgURL, manifestDigest)
+	require.False(t, ok)
+}
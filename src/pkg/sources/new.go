@@ -7,8 +7,8 @@ package sources
 import (
 	"strings"
 
-	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/utils"
 	zarfSources "github.com/defenseunicorns/zarf/src/pkg/packager/sources"
 	"github.com/defenseunicorns/zarf/src/pkg/zoci"
 	zarfTypes "github.com/defenseunicorns/zarf/src/types"
@@ -30,9 +30,9 @@ func New(pkgLocation string, pkgName string, opts zarfTypes.ZarfPackageOptions,
 	} else {
 		platform := ocispec.Platform{
 			Architecture: config.GetArch(),
-			OS:           oci.MultiOS,
+			OS:           config.GetOS(),
 		}
-		remote, err := zoci.NewRemote(pkgLocation, platform)
+		remote, err := zoci.NewRemote(pkgLocation, platform, utils.WithSkipTLSVerify(), utils.WithRetryAfter())
 		if err != nil {
 			return nil, err
 		}
@@ -243,6 +243,13 @@ func (r *RemoteBundle) downloadPkgFromRemoteBundle() ([]ocispec.Descriptor, erro
 		return nil, err
 	}
 
+	// component tarballs compressed when the bundle was created are restored so they match the pkg's checksums
+	for _, layer := range layersToPull {
+		if err := utils.DecompressLayerFile(layer, filepath.Join(r.TmpDir, layer.Annotations[ocispec.AnnotationTitle])); err != nil {
+			return nil, err
+		}
+	}
+
 	// need to substract 1 from layersInBundle because it includes the pkgManifestDesc and pkgManifest.Layers does not
 	if len(pkgManifest.Layers) != len(layersInBundle)-1 {
 		r.isPartial = true
@@ -260,7 +260,10 @@ func (t *TarballBundle) extractPkgFromBundle() ([]string, error) {
 			// throw an error for dangerous looking paths
 			return fmt.Errorf("invalid path detected: %s", path)
 		}
-		size := desc.Size
+		size, err := utils.UncompressedSize(desc)
+		if err != nil {
+			return err
+		}
 		layerDst := filepath.Join(t.TmpDir, cleanPath)
 		if err := helpers.CreateDirectory(filepath.Dir(layerDst), 0700); err != nil {
 			return err
@@ -272,7 +275,13 @@ func (t *TarballBundle) extractPkgFromBundle() ([]string, error) {
 		}
 		defer target.Close()
 
-		written, err := io.Copy(target, stream)
+		// component tarballs compressed when the bundle was created are restored so they match the pkg's checksums
+		layerStream, err := utils.DecompressLayer(desc, stream)
+		if err != nil {
+			return err
+		}
+		defer layerStream.Close()
+		written, err := io.Copy(target, layerStream)
 		if err != nil {
 			return err
 		}
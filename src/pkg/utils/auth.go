@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package utils provides utility fns for UDS-CLI
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// ParseCredential parses a username:password registry credential, an empty string is an empty credential
+func ParseCredential(creds string) (auth.Credential, error) {
+	if creds == "" {
+		return auth.EmptyCredential, nil
+	}
+	username, password, ok := strings.Cut(creds, ":")
+	if !ok || username == "" {
+		return auth.EmptyCredential, fmt.Errorf("invalid registry credentials, expected username:password")
+	}
+	return auth.Credential{Username: username, Password: password}, nil
+}
+
+// WithCredential gives an OCI remote its own auth client so it can authenticate with different credentials than the
+// other remotes, using cred for the remote's registry if it's set, otherwise the credentials the docker config has
+// for the registry's hostname.
+//
+// Every remote is created with the same shared auth client whose credentials are replaced each time a remote is
+// created, so this must be called right after the remote is created
+func WithCredential(remote *oci.OrasRemote, cred auth.Credential) {
+	shared := remote.Repo().Client.(*auth.Client)
+	client := *shared
+	httpClient := *shared.Client
+	client.Client = &httpClient
+	client.Header = shared.Header.Clone()
+	// tokens are cached by registry and scope, they can't be shared between remotes with different credentials
+	client.Cache = auth.NewCache()
+	if cred != auth.EmptyCredential {
+		client.Credential = auth.StaticCredential(remote.Repo().Reference.Registry, cred)
+	}
+	remote.Repo().Client = &client
+}
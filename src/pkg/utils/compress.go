@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package utils provides utility fns for UDS-CLI
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/zarf/src/pkg/layout"
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// MinCompressionLevel and MaxCompressionLevel are the zstd levels layers can be compressed at
+	MinCompressionLevel = 1
+	MaxCompressionLevel = 22
+)
+
+// CompressedLayer is a Zarf pkg layer compressed with zstd, its content is written to a temp file
+type CompressedLayer struct {
+	// Source is the layer in the source Zarf pkg
+	Source ocispec.Descriptor
+	// Desc is the compressed layer, it keeps the source layer's title
+	Desc ocispec.Descriptor
+	// Path is the file holding the compressed layer
+	Path string
+}
+
+// IsCompressibleLayer returns true if a Zarf pkg layer is a component tarball, these are uncompressed and only
+// referenced by their title; image blobs are already compressed and referenced by digest from their image manifests
+func IsCompressibleLayer(layer ocispec.Descriptor) bool {
+	title := layer.Annotations[ocispec.AnnotationTitle]
+	return filepath.Dir(title) == layout.ComponentsDir && filepath.Ext(title) == ".tar"
+}
+
+// IsCompressedLayer returns true if a Zarf pkg layer was compressed by CompressLayer
+func IsCompressedLayer(layer ocispec.Descriptor) bool {
+	return layer.MediaType == config.CompressedLayerMediaType
+}
+
+// CompressLayer streams a layer from the remote through a zstd encoder at level into a file in dir
+func CompressLayer(ctx context.Context, remote *oci.OrasRemote, layer ocispec.Descriptor, level int, dir string) (CompressedLayer, error) {
+	title := layer.Annotations[ocispec.AnnotationTitle]
+	rc, err := remote.Repo().Fetch(ctx, layer)
+	if err != nil {
+		return CompressedLayer{}, fmt.Errorf("unable to fetch %s: %w", title, err)
+	}
+	defer rc.Close()
+
+	f, err := os.CreateTemp(dir, "layer-*.zst")
+	if err != nil {
+		return CompressedLayer{}, err
+	}
+	defer f.Close()
+	digester := digest.Canonical.Digester()
+	counter := &countingWriter{w: io.MultiWriter(f, digester.Hash())}
+	enc, err := zstd.NewWriter(counter, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	if err != nil {
+		return CompressedLayer{}, err
+	}
+	if _, err := io.Copy(enc, rc); err != nil {
+		enc.Close()
+		return CompressedLayer{}, fmt.Errorf("unable to compress %s: %w", title, err)
+	}
+	if err := enc.Close(); err != nil {
+		return CompressedLayer{}, fmt.Errorf("unable to compress %s: %w", title, err)
+	}
+
+	annotations := make(map[string]string, len(layer.Annotations)+2)
+	for key, value := range layer.Annotations {
+		annotations[key] = value
+	}
+	annotations[config.LayerUncompressedDigestAnnotation] = layer.Digest.String()
+	annotations[config.LayerUncompressedSizeAnnotation] = strconv.FormatInt(layer.Size, 10)
+	return CompressedLayer{
+		Source: layer,
+		Desc: ocispec.Descriptor{
+			MediaType:   config.CompressedLayerMediaType,
+			Digest:      digester.Digest(),
+			Size:        counter.n,
+			Annotations: annotations,
+		},
+		Path: f.Name(),
+	}, nil
+}
+
+// UncompressedSize returns the size of a layer once it's decompressed, which is its size if it isn't compressed
+func UncompressedSize(layer ocispec.Descriptor) (int64, error) {
+	if !IsCompressedLayer(layer) {
+		return layer.Size, nil
+	}
+	size, err := strconv.ParseInt(layer.Annotations[config.LayerUncompressedSizeAnnotation], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation on compressed layer %s: %w", config.LayerUncompressedSizeAnnotation, layer.Digest, err)
+	}
+	return size, nil
+}
+
+// DecompressLayer returns a reader of a layer's original content, r is returned as is if the layer isn't compressed
+func DecompressLayer(layer ocispec.Descriptor, r io.Reader) (io.ReadCloser, error) {
+	if !IsCompressedLayer(layer) {
+		return io.NopCloser(r), nil
+	}
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
+
+// DecompressLayerFile replaces a compressed layer written to path with the original layer, verifying it against the
+// original digest and size; it does nothing if the layer isn't compressed
+func DecompressLayerFile(layer ocispec.Descriptor, path string) error {
+	if !IsCompressedLayer(layer) {
+		return nil
+	}
+	title := layer.Annotations[ocispec.AnnotationTitle]
+	want, err := digest.Parse(layer.Annotations[config.LayerUncompressedDigestAnnotation])
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation on compressed layer %s: %w", config.LayerUncompressedDigestAnnotation, title, err)
+	}
+	size, err := UncompressedSize(layer)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dec, err := DecompressLayer(layer, src)
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+
+	tmpPath := path + ".decompressed"
+	dst, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	digester := want.Algorithm().Digester()
+	written, err := io.Copy(io.MultiWriter(dst, digester.Hash()), dec)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to decompress %s: %w", title, err)
+	}
+	if written != size || digester.Digest() != want {
+		return fmt.Errorf("decompressed %s doesn't match the original layer: expected %s (%d bytes), got %s (%d bytes)", title, want, size, digester.Digest(), written)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ValidateCompressionLevel returns an error if level isn't 0 (no compression) or a zstd level
+func ValidateCompressionLevel(level int) error {
+	if level != 0 && (level < MinCompressionLevel || level > MaxCompressionLevel) {
+		return fmt.Errorf("invalid compression level %d, it must be between %d and %d", level, MinCompressionLevel, MaxCompressionLevel)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package utils provides utility fns for UDS-CLI
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+
+	// register the OIDC providers used to get an identity token in CI, e.g. GitHub Actions
+	_ "github.com/sigstore/cosign/v2/pkg/providers/all"
+)
+
+// KeylessSignature is a signature created with a short-lived Fulcio certificate and recorded in Rekor
+type KeylessSignature struct {
+	// Certificate is the PEM encoded Fulcio certificate the blob was signed with
+	Certificate []byte
+	// RekorLogIndex is the index of the signature's entry in the Rekor transparency log
+	RekorLogIndex int64
+	// RekorLogID is the ID of the Rekor transparency log the signature was recorded in
+	RekorLogID string
+}
+
+// CosignSignBlobKeyless signs a blob with a Fulcio certificate issued for the caller's OIDC identity, writing the
+// signature to outputSigPath and uploading it to Rekor
+func CosignSignBlobKeyless(blobPath string, outputSigPath string) (*KeylessSignature, error) {
+	rootOptions := &options.RootOptions{Verbose: false, Timeout: options.DefaultTimeout}
+
+	// cosign writes the certificate and the Rekor entry to a bundle file, they're read back out of it below
+	bundleFile, err := os.CreateTemp("", "cosign-bundle-*.json")
+	if err != nil {
+		return nil, err
+	}
+	bundleFile.Close()
+	defer os.Remove(bundleFile.Name())
+
+	keyOptions := options.KeyOpts{
+		FulcioURL:        options.DefaultFulcioURL,
+		RekorURL:         options.DefaultRekorURL,
+		OIDCIssuer:       options.DefaultOIDCIssuerURL,
+		OIDCClientID:     "sigstore",
+		BundlePath:       bundleFile.Name(),
+		SkipConfirmation: true,
+	}
+	b64 := true
+	outputCertificate := ""
+	tlogUpload := true
+
+	if _, err := sign.SignBlobCmd(rootOptions, keyOptions, blobPath, b64, outputSigPath, outputCertificate, tlogUpload); err != nil {
+		return nil, err
+	}
+
+	bundleBytes, err := os.ReadFile(bundleFile.Name())
+	if err != nil {
+		return nil, err
+	}
+	var signedPayload cosign.LocalSignedPayload
+	if err := json.Unmarshal(bundleBytes, &signedPayload); err != nil {
+		return nil, err
+	}
+	if signedPayload.Cert == "" || signedPayload.Bundle == nil {
+		return nil, fmt.Errorf("keyless signing didn't produce a certificate and Rekor entry")
+	}
+	cert, err := base64.StdEncoding.DecodeString(signedPayload.Cert)
+	if err != nil {
+		return nil, err
+	}
+	return &KeylessSignature{
+		Certificate:   cert,
+		RekorLogIndex: signedPayload.Bundle.Payload.LogIndex,
+		RekorLogID:    signedPayload.Bundle.Payload.LogID,
+	}, nil
+}
+
+// CosignVerifyBlobKeyless verifies a blob's signature against the Fulcio certificate it was signed with, checking that
+// the certificate chains up to the Fulcio root and that the signature was recorded in Rekor
+func CosignVerifyBlobKeyless(blobPath string, sigPath string, certPath string) error {
+	identity, issuer, err := certificateIdentity(certPath)
+	if err != nil {
+		return err
+	}
+	cmd := &verify.VerifyBlobCmd{
+		KeyOpts: options.KeyOpts{RekorURL: options.DefaultRekorURL},
+		CertVerifyOptions: options.CertVerifyOptions{
+			// the signer's identity isn't pinned, it's reported below so it can be checked
+			CertIdentityRegexp:   ".*",
+			CertOidcIssuerRegexp: ".*",
+		},
+		CertRef: certPath,
+		SigRef:  sigPath,
+	}
+	if err := cmd.Exec(context.TODO(), blobPath); err != nil {
+		return err
+	}
+	message.Successf("Bundle signature validated! Signed by %s (issuer %s)", identity, issuer)
+	return nil
+}
+
+// certificateIdentity returns the signer's identity and OIDC issuer from a Fulcio certificate
+func certificateIdentity(certPath string) (string, string, error) {
+	certBytes, err := os.ReadFile(certPath)
+	if err != nil {
+		return "", "", err
+	}
+	signer, err := ParseCertificateSigner(certBytes)
+	if err != nil {
+		return "", "", fmt.Errorf("%w in %s", err, certPath)
+	}
+	return signer.Identity, signer.Issuer, nil
+}
+
+// CertificateSigner is the signer of a keyless signature as recorded in its Fulcio certificate
+type CertificateSigner struct {
+	// Identity is the signer's OIDC identity, e.g. an email address or a CI workflow URI
+	Identity string
+	// Issuer is the OIDC issuer that vouched for the identity
+	Issuer string
+	// Algorithm is the algorithm of the certificate's public key, which the blob was signed with
+	Algorithm string
+}
+
+// ParseCertificateSigner returns the signer recorded in a PEM encoded Fulcio certificate
+func ParseCertificateSigner(certPEM []byte) (*CertificateSigner, error) {
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate found")
+	}
+	ce := cosign.CertExtensions{Cert: certs[0]}
+	return &CertificateSigner{
+		Identity:  strings.Join(cryptoutils.GetSubjectAlternateNames(certs[0]), ", "),
+		Issuer:    ce.GetIssuer(),
+		Algorithm: certs[0].PublicKeyAlgorithm.String(),
+	}, nil
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package utils provides utility fns for UDS-CLI
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/oci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// errIndexModified is returned by a conditional push of an index that another writer modified after it was fetched
+var errIndexModified = errors.New("the index was modified by another writer")
+
+// maxIndexSize caps how much of an index is read, like the limit oras puts on fetched manifests
+const maxIndexSize = 4 * 1024 * 1024
+
+// getIndexCondition gets the index at ref like GetIndex, along with the precondition to push an updated index with so
+// the push fails if another writer modified the index in between: If-Match with the index's ETag, or If-None-Match if
+// the tag doesn't exist yet. The precondition is nil if the registry didn't send an ETag, since then it can't be
+// conditioned on
+func getIndexCondition(ctx context.Context, remote *oci.OrasRemote, ref registry.Reference) (*ocispec.Index, http.Header, error) {
+	ctx = auth.AppendRepositoryScope(ctx, ref, auth.ActionPull)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, manifestURL(remote, ref), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", strings.Join([]string{ocispec.MediaTypeImageIndex, dockerManifestListMediaType, ocispec.MediaTypeImageManifest}, ", "))
+	resp, err := registryClient(remote).Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, http.Header{"If-None-Match": []string{"*"}}, nil
+	default:
+		return nil, nil, &errcode.ErrorResponse{Method: req.Method, URL: req.URL, StatusCode: resp.StatusCode}
+	}
+	var condition http.Header
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		condition = http.Header{"If-Match": []string{etag}}
+	}
+	// the tag may point at a root manifest of a bundle that was created before bundles had an index
+	mediaType, _, _ := strings.Cut(resp.Header.Get("Content-Type"), ";")
+	if !isIndexMediaType(mediaType) {
+		return nil, condition, nil
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	var index *ocispec.Index
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse the index at %s: %w", ref, err)
+	}
+	if index.MediaType == "" {
+		index.MediaType = mediaType
+	}
+	return index, condition, nil
+}
+
+// pushIndexIf pushes an index like pushIndex, but only if the precondition from getIndexCondition still holds. It
+// returns errIndexModified if the registry rejected the push because the index changed, a nil precondition pushes
+// the index unconditionally
+func pushIndexIf(ctx context.Context, index *ocispec.Index, remote *oci.OrasRemote, ref registry.Reference, condition http.Header) error {
+	if condition == nil {
+		return pushIndex(ctx, index, remote, ref.Reference)
+	}
+	indexBytes, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	mediaType := index.MediaType
+	if mediaType == "" {
+		mediaType = ocispec.MediaTypeImageIndex
+	}
+
+	// pushing requires both the pull and push actions, like oras asks for
+	ctx = auth.AppendRepositoryScope(ctx, ref, auth.ActionPull, auth.ActionPush)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, manifestURL(remote, ref), bytes.NewReader(indexBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mediaType)
+	for name, values := range condition {
+		req.Header[name] = values
+	}
+	resp, err := registryClient(remote).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return nil
+	case http.StatusPreconditionFailed:
+		return errIndexModified
+	default:
+		return &errcode.ErrorResponse{Method: req.Method, URL: req.URL, StatusCode: resp.StatusCode}
+	}
+}
+
+// manifestURL returns the URL of the manifest at ref in the remote's registry
+func manifestURL(remote *oci.OrasRemote, ref registry.Reference) string {
+	scheme := "https"
+	if remote.Repo().PlainHTTP {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, ref.Host(), ref.Repository, ref.Reference)
+}
+
+// registryClient returns the client the remote's requests are sent with, so requests made outside of oras are
+// authenticated and configured the same way
+func registryClient(remote *oci.OrasRemote) remote.Client {
+	if client := remote.Repo().Client; client != nil {
+		return client
+	}
+	return auth.DefaultClient
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package utils provides utility fns for UDS-CLI
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers"
+	"github.com/defenseunicorns/zarf/src/pkg/transform"
+)
+
+// ValidateMirror checks that a registry mirror is only a registry's host, optionally with a port, since it replaces the
+// host of the Zarf pkgs' URLs
+func ValidateMirror(mirror string) error {
+	host := strings.TrimPrefix(mirror, helpers.OCIURLPrefix)
+	if host == "" || strings.ContainsAny(host, "/@") {
+		return fmt.Errorf("invalid registry mirror %q, it must be a registry host, e.g. mirror.example.com:5000", mirror)
+	}
+	return nil
+}
+
+// MirrorURL returns the URL of a Zarf pkg in a registry mirror, the pkg's repository and ref are the same in the mirror
+// and only the registry host is replaced
+func MirrorURL(pkgURL string, mirror string) string {
+	prefix := ""
+	if strings.HasPrefix(pkgURL, helpers.OCIURLPrefix) {
+		prefix = helpers.OCIURLPrefix
+	}
+	_, path, _ := strings.Cut(strings.TrimPrefix(pkgURL, helpers.OCIURLPrefix), "/")
+	return prefix + strings.TrimPrefix(mirror, helpers.OCIURLPrefix) + "/" + path
+}
+
+// ValidateRegistryOverride checks that both sides of a registry override are a registry host, optionally followed by a
+// repository path, that an image's repository can be appended to
+func ValidateRegistryOverride(from, to string) error {
+	for _, prefix := range []string{from, to} {
+		if prefix == "" || strings.HasSuffix(prefix, "/") {
+			return fmt.Errorf("invalid registry override %s=%s, both sides must be a registry host with an optional path, e.g. docker.io=registry.internal:5000/mirror", from, to)
+		}
+		if _, err := transform.ParseImageRef(prefix + "/image"); err != nil {
+			return fmt.Errorf("invalid registry override %s=%s, %s isn't a valid registry: %w", from, to, prefix, err)
+		}
+	}
+	return nil
+}
+
+// OverrideRegistry rewrites the registry of an image with the longest override whose old registry (and path) prefixes
+// the image's fully qualified reference at a path boundary, e.g. nginx:1.0 matches docker.io and docker.io/library/nginx.
+// The rewritten reference must still parse, false is returned if no override matches
+func OverrideRegistry(image string, overrides map[string]string) (string, bool, error) {
+	refInfo, err := transform.ParseImageRef(image)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to parse image %s: %w", image, err)
+	}
+	match := ""
+	for old := range overrides {
+		rest, ok := strings.CutPrefix(refInfo.Reference, old)
+		if ok && rest != "" && strings.ContainsRune("/:@", rune(rest[0])) && len(old) > len(match) {
+			match = old
+		}
+	}
+	if match == "" {
+		return image, false, nil
+	}
+	rewritten := overrides[match] + strings.TrimPrefix(refInfo.Reference, match)
+	if _, err := transform.ParseImageRef(rewritten); err != nil {
+		return "", false, fmt.Errorf("overriding the registry of image %s gives %s, which isn't a valid reference: %w", image, rewritten, err)
+	}
+	return rewritten, true, nil
+}
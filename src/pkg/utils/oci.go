@@ -10,11 +10,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
 	"github.com/defenseunicorns/pkg/oci"
 	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/pkg/cache"
 	"github.com/defenseunicorns/uds-cli/src/types"
 	"github.com/defenseunicorns/zarf/src/pkg/message"
 	"github.com/defenseunicorns/zarf/src/pkg/zoci"
@@ -26,15 +30,23 @@ import (
 	"oras.land/oras-go/v2/errdef"
 )
 
+// dockerManifestListMediaType is the media type of a Docker manifest list, the Docker equivalent of an OCI index that
+// some registries and tools still create
+const dockerManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+// isIndexMediaType returns true if a media type is an OCI index or a Docker manifest list
+func isIndexMediaType(mediaType string) bool {
+	return mediaType == ocispec.MediaTypeImageIndex || mediaType == dockerManifestListMediaType
+}
+
 // FetchLayerAndStore fetches a remote layer and copies it to a local store
-func FetchLayerAndStore(layerDesc ocispec.Descriptor, remoteRepo *oci.OrasRemote, localStore *ocistore.Store) error {
-	ctx := context.TODO()
+func FetchLayerAndStore(ctx context.Context, layerDesc ocispec.Descriptor, remoteRepo *oci.OrasRemote, localStore *ocistore.Store) error {
 	layerBytes, err := remoteRepo.FetchLayer(ctx, layerDesc)
 	if err != nil {
 		return err
 	}
 	rootPkgDescBytes := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, layerBytes)
-	err = localStore.Push(context.TODO(), rootPkgDescBytes, bytes.NewReader(layerBytes))
+	err = localStore.Push(ctx, rootPkgDescBytes, bytes.NewReader(layerBytes))
 	return err
 }
 
@@ -45,6 +57,9 @@ func ToOCIStore(t any, mediaType string, store *ocistore.Store) (ocispec.Descrip
 		return ocispec.Descriptor{}, err
 	}
 	desc := content.NewDescriptorFromBytes(mediaType, b)
+	if mediaType == ocispec.MediaTypeImageManifest {
+		desc.ArtifactType = artifactType(t)
+	}
 	if exists, _ := store.Exists(context.Background(), desc); exists {
 		return desc, nil
 	}
@@ -55,8 +70,7 @@ func ToOCIStore(t any, mediaType string, store *ocistore.Store) (ocispec.Descrip
 }
 
 // ToOCIRemote takes an arbitrary type, typically a struct, marshals it into JSON and store it in a remote OCI store
-func ToOCIRemote(t any, mediaType string, remote *oci.OrasRemote) (*ocispec.Descriptor, error) {
-	ctx := context.TODO()
+func ToOCIRemote(ctx context.Context, t any, mediaType string, remote *oci.OrasRemote) (*ocispec.Descriptor, error) {
 	b, err := json.Marshal(t)
 	if err != nil {
 		return &ocispec.Descriptor{}, err
@@ -66,6 +80,7 @@ func ToOCIRemote(t any, mediaType string, remote *oci.OrasRemote) (*ocispec.Desc
 	// if image manifest media type, push to Manifests(), otherwise normal pushLayer()
 	if mediaType == ocispec.MediaTypeImageManifest {
 		descriptorFromBytes := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, b)
+		descriptorFromBytes.ArtifactType = artifactType(t)
 		layerDesc = &descriptorFromBytes
 		if err := remote.Repo().Manifests().PushReference(ctx, descriptorFromBytes, bytes.NewReader(b), remote.Repo().Reference.String()); err != nil {
 			return &ocispec.Descriptor{}, fmt.Errorf("failed to push manifest: %w", err)
@@ -77,10 +92,24 @@ func ToOCIRemote(t any, mediaType string, remote *oci.OrasRemote) (*ocispec.Desc
 		}
 	}
 
-	message.Successf("Published %s [%s]", remote.Repo().Reference.String(), layerDesc.MediaType)
 	return layerDesc, nil
 }
 
+// artifactType returns the artifact type of an OCI manifest, or an empty string if t isn't a manifest
+func artifactType(t any) string {
+	switch m := t.(type) {
+	case ocispec.Manifest:
+		return m.ArtifactType
+	case *ocispec.Manifest:
+		return m.ArtifactType
+	case oci.Manifest:
+		return m.ArtifactType
+	case *oci.Manifest:
+		return m.ArtifactType
+	}
+	return ""
+}
+
 // CreateCopyOpts creates the ORAS CopyOpts struct to use when copying OCI artifacts
 func CreateCopyOpts(layersToPull []ocispec.Descriptor, concurrency int) oras.CopyOptions {
 	var copyOpts oras.CopyOptions
@@ -97,7 +126,7 @@ func CreateCopyOpts(layersToPull []ocispec.Descriptor, concurrency int) oras.Cop
 		var nodes []ocispec.Descriptor
 		_, hasTitleAnnotation := desc.Annotations[ocispec.AnnotationTitle]
 
-		if desc.MediaType == ocispec.MediaTypeImageIndex {
+		if isIndexMediaType(desc.MediaType) {
 			// This block is triggered when ORAS initially hits the OCI repo and gets the image index (index.json)
 			// and it grabs the bundle root manifest corresponding to the proper arch
 			// todo: refactor to solve the arch problem using the shas var above instead of checking here
@@ -109,12 +138,12 @@ func CreateCopyOpts(layersToPull []ocispec.Descriptor, concurrency int) oras.Cop
 			}
 
 			// grab the proper bundle root manifest, based on arch
-			for _, node := range successors {
-				// todo: remove this check once we have a better way to handle arch
-				if node.Platform.Architecture == config.GetArch() {
-					return []ocispec.Descriptor{node}, nil
-				}
+			// todo: remove this check once we have a better way to handle arch
+			node, err := PlatformManifest(successors)
+			if err != nil {
+				return nil, err
 			}
+			return []ocispec.Descriptor{node}, nil
 		} else if desc.MediaType == zoci.ZarfLayerMediaTypeBlob && !hasTitleAnnotation {
 			// This if block is for used for finding successors from bundle root manifests during bundle pull/publish ops;
 			// note that ptrs to the Zarf pkg image manifests won't have title annotations, and will follow this code path
@@ -151,6 +180,32 @@ func CreateCopyOpts(layersToPull []ocispec.Descriptor, concurrency int) oras.Cop
 	return copyOpts
 }
 
+// RootManifestDesc returns the desc a bundle root manifest has once it's pushed with PushRootManifest
+func RootManifestDesc(rootManifest ocispec.Manifest) (ocispec.Descriptor, error) {
+	b, err := json.Marshal(rootManifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, b)
+	desc.ArtifactType = rootManifest.ArtifactType
+	return desc, nil
+}
+
+// PushRootManifest pushes a bundle root manifest by digest without tagging it, the bundle's tag points at the index
+// that references the root manifest of each arch (see UpdateIndex)
+func PushRootManifest(ctx context.Context, rootManifest ocispec.Manifest, remote *oci.OrasRemote) (*ocispec.Descriptor, error) {
+	b, err := json.Marshal(rootManifest)
+	if err != nil {
+		return nil, err
+	}
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, b)
+	desc.ArtifactType = rootManifest.ArtifactType
+	if err := remote.Repo().Manifests().Push(ctx, desc, bytes.NewReader(b)); err != nil {
+		return nil, fmt.Errorf("failed to push manifest: %w", err)
+	}
+	return &desc, nil
+}
+
 // createIndex creates an OCI index and pushes it to a remote based on ref
 func createIndex(bundle *types.UDSBundle, rootManifestDesc ocispec.Descriptor) *ocispec.Index {
 	var index ocispec.Index
@@ -158,72 +213,240 @@ func createIndex(bundle *types.UDSBundle, rootManifestDesc ocispec.Descriptor) *
 	index.Versioned.SchemaVersion = 2
 	index.Manifests = []ocispec.Descriptor{
 		{
-			MediaType: ocispec.MediaTypeImageManifest,
-			Digest:    rootManifestDesc.Digest,
-			Size:      rootManifestDesc.Size,
-			Platform: &ocispec.Platform{
-				Architecture: bundle.Metadata.Architecture,
-				OS:           oci.MultiOS,
-			},
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: rootManifestDesc.ArtifactType,
+			Digest:       rootManifestDesc.Digest,
+			Size:         rootManifestDesc.Size,
+			Platform:     bundlePlatform(bundle),
 		},
 	}
 	return &index
 }
 
+// PlatformManifest returns the manifest for the CLI's arch and OS from the entries of an index. If the index doesn't have
+// one, the arch's only entry is used without --os, e.g. for a bundle created with metadata.os, and with --os if it's for
+// the multi OS, e.g. a Zarf pkg's index
+func PlatformManifest(manifests []ocispec.Descriptor) (ocispec.Descriptor, error) {
+	arch, indexOS := config.GetArch(), config.GetOS()
+	var archManifests []ocispec.Descriptor
+	var platforms []string
+	for _, manifest := range manifests {
+		// an index created by another tool can have entries without a platform, e.g. attestations
+		if manifest.Platform == nil {
+			continue
+		}
+		platforms = append(platforms, manifest.Platform.OS+"/"+manifest.Platform.Architecture)
+		if manifest.Platform.Architecture != arch {
+			continue
+		}
+		if manifest.Platform.OS == indexOS {
+			return manifest, nil
+		}
+		archManifests = append(archManifests, manifest)
+	}
+	if len(archManifests) == 1 && (config.CLIOS == "" || archManifests[0].Platform.OS == oci.MultiOS) {
+		return archManifests[0], nil
+	}
+	if len(platforms) == 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("the index doesn't have a manifest for any platform")
+	}
+	return ocispec.Descriptor{}, fmt.Errorf("the index doesn't have a manifest for %s/%s, only for %s; pass --os or --architecture to choose one",
+		indexOS, arch, strings.Join(platforms, ", "))
+}
+
+// BundlePlatform returns the platform to fetch a bundle from source with, the platform of the entry PlatformManifest
+// selects from the bundle's index. The CLI's arch and OS are returned if the bundle can't be resolved or isn't an index,
+// fetching the bundle then reports the error
+func BundlePlatform(ctx context.Context, source string) ocispec.Platform {
+	platform := ocispec.Platform{
+		Architecture: config.GetArch(),
+		OS:           config.GetOS(),
+	}
+	remote, err := zoci.NewRemote(source, platform, WithSkipTLSVerify(), WithRetryAfter())
+	if err != nil {
+		return platform
+	}
+	desc, err := remote.Repo().Resolve(ctx, remote.Repo().Reference.Reference)
+	if err != nil || !isIndexMediaType(desc.MediaType) {
+		return platform
+	}
+	index, err := oci.FetchUnmarshal[ocispec.Index](ctx, remote.FetchLayer, json.Unmarshal, desc)
+	if err != nil {
+		return platform
+	}
+	manifest, err := PlatformManifest(index.Manifests)
+	if err != nil {
+		return platform
+	}
+	return *manifest.Platform
+}
+
+// bundlePlatform returns the platform of a bundle's entry in an OCI index
+func bundlePlatform(bundle *types.UDSBundle) *ocispec.Platform {
+	return &ocispec.Platform{
+		Architecture: bundle.Metadata.Architecture,
+		OS:           config.GetOS(bundle.Metadata.OS),
+	}
+}
+
+// isBundlePlatform returns true if an index entry's platform has the bundle's arch and OS, bundles for the same arch
+// but a different OS are separate entries
+func isBundlePlatform(platform *ocispec.Platform, bundle *types.UDSBundle) bool {
+	return platform != nil && platform.Architecture == bundle.Metadata.Architecture && platform.OS == config.GetOS(bundle.Metadata.OS)
+}
+
 // addToIndex adds or replaces a bundle root manifest to an OCI index
 func addToIndex(index *ocispec.Index, bundle *types.UDSBundle, newManifestDesc ocispec.Descriptor) *ocispec.Index {
 	manifestExists := false
 	for i, manifest := range index.Manifests {
-		// if existing manifest has the same arch as the bundle, don't append new bundle root manifest to index
-		if manifest.Platform != nil && manifest.Platform.Architecture == bundle.Metadata.Architecture {
+		// if existing manifest has the same platform as the bundle, don't append new bundle root manifest to index
+		if isBundlePlatform(manifest.Platform, bundle) {
 			// update digest and size in case they changed with the new bundle root manifest
 			index.Manifests[i].Digest = newManifestDesc.Digest
 			index.Manifests[i].Size = newManifestDesc.Size
+			index.Manifests[i].ArtifactType = newManifestDesc.ArtifactType
 			manifestExists = true
 		}
 	}
 	if !manifestExists {
-		newManifestDesc.Platform = &ocispec.Platform{
-			Architecture: bundle.Metadata.Architecture,
-			OS:           oci.MultiOS,
-		}
+		newManifestDesc.Platform = bundlePlatform(bundle)
 		index.Manifests = append(index.Manifests, newManifestDesc)
 	}
 	return index
 }
 
-func pushIndex(index *ocispec.Index, remote *oci.OrasRemote, ref string) error {
+// pushIndex pushes an index with its own media type, an existing Docker manifest list is pushed back as one so tools
+// that created it can still read it
+func pushIndex(ctx context.Context, index *ocispec.Index, remote *oci.OrasRemote, ref string) error {
 	indexBytes, err := json.Marshal(index)
 	if err != nil {
 		return err
 	}
-	indexDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageIndex, indexBytes)
-	err = remote.Repo().Manifests().PushReference(context.TODO(), indexDesc, bytes.NewReader(indexBytes), ref)
+	mediaType := index.MediaType
+	if mediaType == "" {
+		mediaType = ocispec.MediaTypeImageIndex
+	}
+	indexDesc := content.NewDescriptorFromBytes(mediaType, indexBytes)
+	err = remote.Repo().Manifests().PushReference(ctx, indexDesc, bytes.NewReader(indexBytes), ref)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// UpdateIndex updates or creates a new OCI index based on the index arg, then pushes to the remote OCI repo
-func UpdateIndex(index *ocispec.Index, remote *oci.OrasRemote, bundle *types.UDSBundle, newManifestDesc ocispec.Descriptor) error {
-	var newIndex *ocispec.Index
-	ref := bundle.Metadata.Version
+// IndexConflict returns the desc of the root manifest an existing index has for the bundle's platform, and true if it's a
+// different root manifest than newManifestDesc that UpdateIndex would replace
+func IndexConflict(index *ocispec.Index, bundle *types.UDSBundle, newManifestDesc ocispec.Descriptor) (ocispec.Descriptor, bool) {
 	if index == nil {
-		newIndex = createIndex(bundle, newManifestDesc)
-	} else {
-		newIndex = addToIndex(index, bundle, newManifestDesc)
+		return ocispec.Descriptor{}, false
+	}
+	for _, manifest := range index.Manifests {
+		if isBundlePlatform(manifest.Platform, bundle) && manifest.Digest != newManifestDesc.Digest {
+			return manifest, true
+		}
+	}
+	return ocispec.Descriptor{}, false
+}
+
+// UpdateIndex updates or creates a new OCI index based on the index arg, then pushes to the remote OCI repo. An existing
+// index keeps its media type, e.g. a Docker manifest list created by another tool
+func UpdateIndex(ctx context.Context, index *ocispec.Index, remote *oci.OrasRemote, bundle *types.UDSBundle, newManifestDesc ocispec.Descriptor) error {
+	err := pushIndex(ctx, mergeIndex(index, bundle, newManifestDesc), remote, bundle.Metadata.Version)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// mergeIndex adds a bundle root manifest to an index, or creates an index for it if there isn't one
+func mergeIndex(index *ocispec.Index, bundle *types.UDSBundle, newManifestDesc ocispec.Descriptor) *ocispec.Index {
+	if index == nil {
+		return createIndex(bundle, newManifestDesc)
 	}
-	err := pushIndex(newIndex, remote, ref)
+	return addToIndex(index, bundle, newManifestDesc)
+}
+
+// indexMergeAttempts bounds how many times MergeIndex merges the root manifest into an index that another writer
+// replaced while it was being updated
+const indexMergeAttempts = 5
+
+// MergeIndex adds a bundle root manifest to the latest index at the bundle's version tag, unlike UpdateIndex the index
+// is fetched right before it's pushed so entries another writer added since the create started (e.g. another arch)
+// aren't lost. If the registry sends the index's ETag, the index is pushed conditionally on it so a push over an index
+// another writer modified in the meantime is rejected, and the root manifest is merged into the latest index again.
+// Registries that don't support conditional requests ignore the precondition, so the index is also fetched again
+// after it's pushed and merged again if another writer replaced it in between. If the latest index has a different
+// root manifest for the bundle's arch, it's only replaced with force
+func MergeIndex(ctx context.Context, remote *oci.OrasRemote, bundle *types.UDSBundle, newManifestDesc ocispec.Descriptor, force bool) error {
+	ref := remote.Repo().Reference
+	ref.Reference = bundle.Metadata.Version
+	for attempt := 1; attempt <= indexMergeAttempts; attempt++ {
+		index, condition, err := getIndexCondition(ctx, remote, ref)
+		if err != nil {
+			return err
+		}
+		if existing, ok := IndexConflict(index, bundle, newManifestDesc); ok && !force {
+			return fmt.Errorf("%s was updated with a %s bundle with digest %s during the create, refusing to overwrite it with %s, use --force to overwrite it",
+				ref, bundle.Metadata.Architecture, existing.Digest, newManifestDesc.Digest)
+		}
+		err = pushIndexIf(ctx, mergeIndex(index, bundle, newManifestDesc), remote, ref, condition)
+		if errors.Is(err, errIndexModified) {
+			message.Debugf("The index at %s was modified since it was fetched, merging again (attempt %d of %d)", ref, attempt, indexMergeAttempts)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		latest, err := GetIndex(ctx, remote, ref.String())
+		if err != nil {
+			return err
+		}
+		if indexHasManifest(latest, bundle, newManifestDesc) {
+			return nil
+		}
+		message.Debugf("The index at %s was replaced while it was being updated, merging again (attempt %d of %d)", ref, attempt, indexMergeAttempts)
+	}
+	return fmt.Errorf("unable to update the index at %s, it was modified by another writer %d times", ref, indexMergeAttempts)
+}
+
+// indexHasManifest returns true if the index references the root manifest for the bundle's platform
+func indexHasManifest(index *ocispec.Index, bundle *types.UDSBundle, manifestDesc ocispec.Descriptor) bool {
+	if index == nil {
+		return false
+	}
+	return slices.ContainsFunc(index.Manifests, func(manifest ocispec.Descriptor) bool {
+		return isBundlePlatform(manifest.Platform, bundle) && manifest.Digest == manifestDesc.Digest
+	})
+}
+
+// DigestTag returns the tag of a root manifest named after its digest, e.g. sha256-<hex>, tags can't contain the digest's
+// colon; a short tag only keeps the first 12 characters of the hex
+func DigestTag(desc ocispec.Descriptor, short bool) string {
+	encoded := desc.Digest.Encoded()
+	if short && len(encoded) > 12 {
+		encoded = encoded[:12]
+	}
+	return fmt.Sprintf("%s-%s", desc.Digest.Algorithm(), encoded)
+}
+
+// TagRootManifest tags a bundle root manifest that was pushed with PushRootManifest, unlike the version tag the tag
+// points at the root manifest itself and not the index so it always references the same content
+func TagRootManifest(ctx context.Context, rootManifest ocispec.Manifest, remote *oci.OrasRemote, tag string) error {
+	b, err := json.Marshal(rootManifest)
 	if err != nil {
 		return err
 	}
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, b)
+	desc.ArtifactType = rootManifest.ArtifactType
+	if err := remote.Repo().Manifests().PushReference(ctx, desc, bytes.NewReader(b), tag); err != nil {
+		return fmt.Errorf("failed to tag manifest %s with %s: %w", desc.Digest, tag, err)
+	}
 	return nil
 }
 
-// GetIndex gets the OCI index from a remote repository if the index exists, otherwise returns a
-func GetIndex(remote *oci.OrasRemote, ref string) (*ocispec.Index, error) {
-	ctx := context.TODO()
+// GetIndex gets the OCI index or Docker manifest list from a remote repository if the index exists, otherwise returns
+// nil. The index's media type is set from the registry's if the index doesn't declare one, so it's pushed back as is
+func GetIndex(ctx context.Context, remote *oci.OrasRemote, ref string) (*ocispec.Index, error) {
 	var index *ocispec.Index
 	existingRootDesc, err := remote.Repo().Resolve(ctx, ref)
 	if err != nil {
@@ -234,7 +457,7 @@ func GetIndex(remote *oci.OrasRemote, ref string) (*ocispec.Index, error) {
 		}
 	}
 	// if an index exists, save it so we can update it after pushing the bundle's root manifest
-	if existingRootDesc.MediaType == ocispec.MediaTypeImageIndex {
+	if isIndexMediaType(existingRootDesc.MediaType) {
 		rc, err := remote.Repo().Fetch(ctx, existingRootDesc)
 		if err != nil {
 			return nil, err
@@ -247,10 +470,71 @@ func GetIndex(remote *oci.OrasRemote, ref string) (*ocispec.Index, error) {
 		if err := json.Unmarshal(b, &index); err != nil {
 			return nil, err
 		}
+		if index.MediaType == "" {
+			index.MediaType = existingRootDesc.MediaType
+		}
 	}
 	return index, nil
 }
 
+// FetchRoot fetches a Zarf pkg's root manifest, when useCache is set the pkg's ref is only resolved and the manifest is
+// read from the on-disk cache if the ref still resolves to the cached digest
+func FetchRoot(ctx context.Context, remote *oci.OrasRemote, pkgURL string, useCache bool) (*oci.Manifest, error) {
+	if !useCache {
+		return remote.FetchRoot(ctx)
+	}
+	desc, err := remote.ResolveRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manifestBytes, ok := cache.GetManifest(pkgURL, desc.Digest)
+	if ok {
+		message.Debugf("Using the cached root manifest of %s (%s)", pkgURL, desc.Digest)
+	} else {
+		if manifestBytes, err = remote.FetchLayer(ctx, desc); err != nil {
+			return nil, err
+		}
+		if err := cache.AddManifest(pkgURL, desc.Digest, manifestBytes); err != nil {
+			// the cache is only an optimization, the manifest is fetched again next time
+			message.Debugf("Unable to cache the root manifest of %s: %s", pkgURL, err)
+		}
+	}
+	var root *oci.Manifest
+	if err := json.Unmarshal(manifestBytes, &root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// GetPkgPlatform returns the platform used to fetch a remote Zarf pkg, the pkg's arch takes precedence over the bundle's;
+// Zarf always publishes pkgs for the multi OS so the bundle's OS doesn't apply
+func GetPkgPlatform(pkg types.Package) ocispec.Platform {
+	arch := pkg.Arch
+	if arch == "" {
+		arch = config.GetArch()
+	}
+	return ocispec.Platform{
+		Architecture: arch,
+		OS:           oci.MultiOS,
+	}
+}
+
+// CheckPkgArch returns an error if the arch a Zarf pkg declares it's built for isn't the arch the bundle requires of
+// it, the pkg's own arch or the bundle's. Older Zarf pkgs that don't declare their arch aren't checked
+func CheckPkgArch(pkg types.Package, arch string) error {
+	if arch == "" {
+		return nil
+	}
+	if want := GetPkgPlatform(pkg).Architecture; arch != want {
+		src := fmt.Sprintf("%s:%s", pkg.Repository, pkg.Ref)
+		if !IsRemotePkg(pkg) {
+			src = pkg.Path
+		}
+		return fmt.Errorf("package %s at %s is built for %s but the bundle requires %s, set the package's arch to bundle it for a different arch", pkg.Name, src, arch, want)
+	}
+	return nil
+}
+
 // EnsureOCIPrefix ensures oci prefix is part of provided remote source path, and adds it if it's not
 func EnsureOCIPrefix(source string) string {
 	var ociPrefix = "oci://"
@@ -261,8 +545,7 @@ func EnsureOCIPrefix(source string) string {
 }
 
 // GetZarfLayers grabs the necessary Zarf pkg layers from a remote OCI registry
-func GetZarfLayers(remote zoci.Remote, pkgRootManifest *oci.Manifest, optionalComponents []string) ([]ocispec.Descriptor, error) {
-	ctx := context.TODO()
+func GetZarfLayers(ctx context.Context, remote zoci.Remote, pkgRootManifest *oci.Manifest, optionalComponents []string) ([]ocispec.Descriptor, error) {
 	zarfPkg, err := remote.FetchZarfYAML(ctx)
 	if err != nil {
 		return nil, err
@@ -292,3 +575,24 @@ func GetZarfLayers(remote zoci.Remote, pkgRootManifest *oci.Manifest, optionalCo
 	layersToCopy = append(layersToCopy, pkgRootManifest.Config)
 	return layersToCopy, err
 }
+
+// IsValidBlob returns true if the blob for the given descriptor exists in blobsDir and matches the descriptor's size and digest
+func IsValidBlob(blobsDir string, desc ocispec.Descriptor) bool {
+	if err := desc.Digest.Validate(); err != nil {
+		return false
+	}
+	blob, err := os.Open(filepath.Join(blobsDir, desc.Digest.Encoded()))
+	if err != nil {
+		return false
+	}
+	defer blob.Close()
+	info, err := blob.Stat()
+	if err != nil || info.Size() != desc.Size {
+		return false
+	}
+	verifier := desc.Digest.Verifier()
+	if _, err := io.Copy(verifier, blob); err != nil {
+		return false
+	}
+	return verifier.Verified()
+}
@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package utils provides utility fns for UDS-CLI
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/zarf/src/pkg/layout"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"github.com/defenseunicorns/zarf/src/pkg/transform"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// PrunedPackage is a Zarf pkg with the images excluded by the bundle removed and the registries of its images overridden
+type PrunedPackage struct {
+	// Root is the pkg's root manifest, rewritten to only reference the images that are kept
+	Root *oci.Manifest
+	// Blobs are the pkg metadata blobs that were rewritten, they don't exist in the source pkg
+	Blobs []RewrittenBlob
+	// ExcludedImages are the images removed from the pkg
+	ExcludedImages []string
+	// OverriddenImages maps the images whose registry was overridden to their rewritten references
+	OverriddenImages map[string]string
+
+	removedBlobs map[digest.Digest]bool
+	rewritten    map[string][]byte
+}
+
+// RewrittenBlob is a Zarf pkg metadata blob rewritten by pruning or overriding registries, e.g. zarf.yaml
+type RewrittenBlob struct {
+	Desc    ocispec.Descriptor
+	Content []byte
+}
+
+// pkgImages is the content of a Zarf pkg that's needed to prune its images
+type pkgImages struct {
+	zarfPkg   zarfTypes.ZarfPackage
+	index     *ocispec.Index
+	manifests map[digest.Digest]*oci.Manifest
+	checksums []byte
+}
+
+// MatchesImage returns true if an image matches any of the patterns, a pattern is either an image reference or a glob
+// as understood by path.Match
+func MatchesImage(image string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == image {
+			return true
+		}
+		if ok, _ := path.Match(pattern, image); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneImages removes the images excluded by the bundle from a Zarf pkg and rewrites the references of the images a
+// registry override (old registry => new registry) matches, returning nil if no images are excluded or overridden
+func PruneImages(ctx context.Context, remote zoci.Remote, pkgRootManifest *oci.Manifest, pkg types.Package, overrides map[string]string) (*PrunedPackage, error) {
+	if len(pkg.ExcludeImages) == 0 && len(overrides) == 0 {
+		return nil, nil
+	}
+	zarfPkg, err := remote.FetchZarfYAML(ctx)
+	if err != nil {
+		return nil, err
+	}
+	images := pkgImages{zarfPkg: zarfPkg, manifests: make(map[digest.Digest]*oci.Manifest)}
+	if oci.IsEmptyDescriptor(pkgRootManifest.Locate(layout.IndexPath)) {
+		// the pkg doesn't have any images
+		warnUnmatchedPatterns(pkg, nil)
+		return nil, nil
+	}
+	if images.index, err = remote.FetchImagesIndex(ctx); err != nil {
+		return nil, err
+	}
+	for _, manifestDesc := range images.index.Manifests {
+		// even though these are technically image manifests, they're stored as Zarf blobs
+		manifestDesc.MediaType = zoci.ZarfLayerMediaTypeBlob
+		manifest, err := remote.FetchManifest(ctx, manifestDesc)
+		if err != nil {
+			return nil, err
+		}
+		images.manifests[manifestDesc.Digest] = manifest
+	}
+	if images.checksums, err = remote.FetchLayer(ctx, pkgRootManifest.Locate(layout.Checksums)); err != nil {
+		return nil, err
+	}
+	return pruneImages(pkgRootManifest, pkg, images, overrides)
+}
+
+// pruneImages rewrites a Zarf pkg's zarf.yaml, checksums.txt, images/index.json and root manifest so they don't
+// reference the excluded images and reference the kept images by their overridden registries
+func pruneImages(pkgRootManifest *oci.Manifest, pkg types.Package, images pkgImages, overrides map[string]string) (*PrunedPackage, error) {
+	zarfPkg := images.zarfPkg
+	zarfPkg.Components = slices.Clone(zarfPkg.Components)
+
+	// remove the excluded images from every component, warning about the components that are bundled, and override the
+	// registries of the images that are kept
+	var excluded []string
+	overridden := make(map[string]string)
+	for i, component := range zarfPkg.Components {
+		var kept []string
+		for _, image := range component.Images {
+			if !MatchesImage(image, pkg.ExcludeImages) {
+				rewritten, ok, err := OverrideRegistry(image, overrides)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					overridden[image] = rewritten
+					image = rewritten
+				}
+				kept = append(kept, image)
+				continue
+			}
+			if !slices.Contains(excluded, image) {
+				excluded = append(excluded, image)
+			}
+			if component.Required != nil || slices.Contains(pkg.OptionalComponents, component.Name) {
+				message.Warnf("Excluding image %s from package %s, component %s references it and may not work without it", image, pkg.Name, component.Name)
+			}
+		}
+		zarfPkg.Components[i].Images = kept
+	}
+	warnUnmatchedPatterns(pkg, excluded)
+	if len(excluded) == 0 && len(overridden) == 0 {
+		return nil, nil
+	}
+
+	// drop the excluded images from the image index, keeping track of the blobs that only they reference
+	var keptManifests []ocispec.Descriptor
+	removedBlobs := make(map[digest.Digest]bool)
+	for _, manifestDesc := range images.index.Manifests {
+		if indexEntryMatches(manifestDesc, excluded) {
+			removedBlobs[manifestDesc.Digest] = true
+			if manifest, ok := images.manifests[manifestDesc.Digest]; ok {
+				removedBlobs[manifest.Config.Digest] = true
+				for _, layer := range manifest.Layers {
+					removedBlobs[layer.Digest] = true
+				}
+			}
+			continue
+		}
+		// Zarf finds an image in the index by its reference, so the entry is renamed along with the image
+		for image, rewritten := range overridden {
+			if !indexEntryMatches(manifestDesc, []string{image}) {
+				continue
+			}
+			refInfo, err := transform.ParseImageRef(rewritten)
+			if err != nil {
+				return nil, err
+			}
+			manifestDesc.Annotations = maps.Clone(manifestDesc.Annotations)
+			manifestDesc.Annotations[ocispec.AnnotationBaseImageName] = refInfo.Reference
+			break
+		}
+		keptManifests = append(keptManifests, manifestDesc)
+	}
+	for _, manifestDesc := range keptManifests {
+		// layers shared with an image that's kept (e.g. a common base image) stay in the pkg
+		delete(removedBlobs, manifestDesc.Digest)
+		if manifest, ok := images.manifests[manifestDesc.Digest]; ok {
+			delete(removedBlobs, manifest.Config.Digest)
+			for _, layer := range manifest.Layers {
+				delete(removedBlobs, layer.Digest)
+			}
+		}
+	}
+	index := *images.index
+	index.Manifests = keptManifests
+	indexBytes, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+
+	// update the checksums of the rewritten index and drop the removed blobs, then the pkg's aggregate checksum
+	checksums := rewriteChecksums(images.checksums, removedBlobs, map[string][]byte{layout.IndexPath: indexBytes})
+	checksumsSHA := sha256.Sum256(checksums)
+	zarfPkg.Metadata.AggregateChecksum = hex.EncodeToString(checksumsSHA[:])
+	zarfYAMLBytes, err := goyaml.Marshal(zarfPkg)
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := map[string][]byte{
+		layout.ZarfYAML:  zarfYAMLBytes,
+		layout.Checksums: checksums,
+		layout.IndexPath: indexBytes,
+	}
+	pruned := PrunedPackage{ExcludedImages: excluded, OverriddenImages: overridden, removedBlobs: removedBlobs, rewritten: rewritten}
+	root := &oci.Manifest{Manifest: pkgRootManifest.Manifest}
+	root.Layers = nil
+	for _, layer := range pkgRootManifest.Layers {
+		title := layer.Annotations[ocispec.AnnotationTitle]
+		switch {
+		case pruned.isRemoved(layer):
+			continue
+		case title == layout.Signature:
+			// the signature is over the original zarf.yaml, it can't be kept once zarf.yaml is rewritten
+			message.Warnf("Removing the signature of package %s, it doesn't apply once its images are excluded or overridden", pkg.Name)
+			continue
+		case rewritten[title] != nil:
+			desc := content.NewDescriptorFromBytes(layer.MediaType, rewritten[title])
+			desc.Annotations = layer.Annotations
+			pruned.Blobs = append(pruned.Blobs, RewrittenBlob{Desc: desc, Content: rewritten[title]})
+			root.Layers = append(root.Layers, desc)
+		default:
+			root.Layers = append(root.Layers, layer)
+		}
+	}
+	pruned.Root = root
+	return &pruned, nil
+}
+
+// Filter removes the layers that aren't copied from the source pkg from layers to copy (see GetZarfLayers), i.e. the
+// excluded images' blobs, the rewritten metadata and the pkg's signature
+func (p *PrunedPackage) Filter(layers []ocispec.Descriptor) []ocispec.Descriptor {
+	var filtered []ocispec.Descriptor
+	for _, layer := range layers {
+		title := layer.Annotations[ocispec.AnnotationTitle]
+		if p.isRemoved(layer) || p.rewritten[title] != nil || title == layout.Signature {
+			continue
+		}
+		filtered = append(filtered, layer)
+	}
+	return filtered
+}
+
+// isRemoved returns true if the layer is a blob of an excluded image that no kept image references
+func (p *PrunedPackage) isRemoved(layer ocispec.Descriptor) bool {
+	return p.removedBlobs[layer.Digest] && strings.HasPrefix(layer.Annotations[ocispec.AnnotationTitle], layout.ImagesBlobsDir)
+}
+
+// indexEntryMatches returns true if an image index entry is one of the images, mirroring how Zarf finds images
+func indexEntryMatches(manifestDesc ocispec.Descriptor, images []string) bool {
+	baseName := manifestDesc.Annotations[ocispec.AnnotationBaseImageName]
+	for _, image := range images {
+		refInfo, err := transform.ParseImageRef(image)
+		if err != nil {
+			continue
+		}
+		// older Zarf versions left docker.io off of image annotations
+		if baseName == refInfo.Reference || (refInfo.Host == "docker.io" && baseName == refInfo.Path+refInfo.TagOrDigest) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteChecksums drops the lines of removed image blobs from a Zarf pkg's checksums.txt and updates the checksums
+// of rewritten files
+func rewriteChecksums(checksums []byte, removedBlobs map[digest.Digest]bool, rewritten map[string][]byte) []byte {
+	var lines []string
+	for _, line := range strings.Split(string(checksums), "\n") {
+		sha, rel, ok := strings.Cut(line, " ")
+		if !ok {
+			lines = append(lines, line)
+			continue
+		}
+		if filepath.Dir(rel) == layout.ImagesBlobsDir && removedBlobs[digest.NewDigestFromEncoded(digest.SHA256, filepath.Base(rel))] {
+			continue
+		}
+		if b, ok := rewritten[rel]; ok {
+			newSHA := sha256.Sum256(b)
+			sha = hex.EncodeToString(newSHA[:])
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", sha, rel))
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// warnUnmatchedPatterns warns about the exclude patterns that didn't match any of the pkg's images
+func warnUnmatchedPatterns(pkg types.Package, excluded []string) {
+	for _, pattern := range pkg.ExcludeImages {
+		if !slices.ContainsFunc(excluded, func(image string) bool { return MatchesImage(image, []string{pattern}) }) {
+			message.Warnf("Image pattern %q doesn't match any image in package %s", pattern, pkg.Name)
+		}
+	}
+}
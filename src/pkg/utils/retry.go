@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package utils provides utility fns for UDS-CLI
+package utils
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/zarf/src/pkg/message"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// RetryBackoff is the delay before the first retry of an OCI operation, it doubles after each attempt
+var RetryBackoff = time.Second
+
+// MaxRetryAfter caps the delay a registry can ask for with a Retry-After header
+var MaxRetryAfter = 2 * time.Minute
+
+// retryJitter returns a random delay of up to half of d, it's added to each retry so concurrent operations that were
+// rate limited together don't all retry at the same moment
+var retryJitter = func(d time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(d)/2 + 1))
+}
+
+// RetryOCI runs an OCI operation, retrying with exponential backoff and jitter up to config.CommonOptions.OCIRetries
+// attempts if the operation fails with a retriable error. A rate limited or unavailable registry's Retry-After is
+// waited for instead of the backoff
+func RetryOCI(ctx context.Context, operation string, fn func() error) error {
+	attempts := config.CommonOptions.OCIRetries
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := RetryBackoff
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(); err == nil || !IsRetriableOCIError(err) || attempt == attempts {
+			return err
+		}
+		delay := retryDelay(err, backoff)
+		message.Debugf("Retrying %s in %s (attempt %d of %d): %s", operation, delay, attempt+1, attempts, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+// retryDelay returns how long to wait before retrying an operation that failed with err, the Retry-After the registry
+// sent with the failed response if it sent one, otherwise the backoff, plus jitter
+func retryDelay(err error, backoff time.Duration) time.Duration {
+	if retryAfter, ok := takeRetryAfter(err); ok {
+		if retryAfter > MaxRetryAfter {
+			message.Debugf("The registry asked to retry in %s, waiting %s instead", retryAfter, MaxRetryAfter)
+			retryAfter = MaxRetryAfter
+		}
+		return retryAfter + retryJitter(RetryBackoff)
+	}
+	return backoff + retryJitter(backoff)
+}
+
+// IsRetriableOCIError returns true if an OCI operation failed with a rate limit, a server error or a network error
+func IsRetriableOCIError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var errResp *errcode.ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.StatusCode == http.StatusTooManyRequests || errResp.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2023-Present The UDS Authors
+
+// Package utils provides utility fns for UDS-CLI
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// retryAfters holds the delay a registry asked for with the Retry-After header of a rate limited or unavailable
+// response, keyed by the request's method and URL since ORAS's ErrorResponse doesn't keep the response's headers
+var retryAfters sync.Map
+
+// TrustCACert adds a PEM encoded CA certificate to the roots trusted by the default HTTP transport.
+//
+// Every OCI remote clones the default transport, and remotes share a single auth client whose transport is replaced
+// each time a remote is created, so the default transport is the only place the CA applies to every source and
+// destination. Proxies are still picked up from HTTPS_PROXY / NO_PROXY by the cloned transport
+func TrustCACert(certPath string) error {
+	certBytes, err := os.ReadFile(certPath)
+	if err != nil {
+		return err
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(certBytes) {
+		return fmt.Errorf("no PEM encoded certificates found in %s", certPath)
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unable to configure the default HTTP transport")
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.RootCAs = roots
+	return nil
+}
+
+// WithSkipTLSVerify skips verifying the registry's TLS certificate if --insecure or --insecure-skip-tls-verify is set.
+//
+// zoci.NewRemote ties skipping the verification to --insecure, which also allows plain HTTP; this modifier is applied
+// after that so --insecure-skip-tls-verify skips the verification on its own and the registry must still use HTTPS
+func WithSkipTLSVerify() oci.Modifier {
+	return oci.WithInsecureSkipVerify(config.CommonOptions.Insecure || config.CommonOptions.InsecureSkipTLSVerify)
+}
+
+// WithRetryAfter records the Retry-After header of the remote's 429 and 503 responses so RetryOCI waits as long as the
+// registry asked instead of backing off blindly.
+//
+// Remotes share a single auth client whose transport is replaced each time a remote is created (see TrustCACert), so
+// every remote must be created with this modifier for the header to be recorded
+func WithRetryAfter() oci.Modifier {
+	return func(o *oci.OrasRemote) {
+		client, ok := o.Repo().Client.(*auth.Client)
+		if !ok || client.Client == nil {
+			return
+		}
+		client.Client.Transport = &retryAfterTransport{base: client.Client.Transport}
+	}
+}
+
+// retryAfterTransport records the Retry-After of 429 and 503 responses in retryAfters
+type retryAfterTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request with the base transport, recording the response's Retry-After if it has one
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return resp, err
+	}
+	if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		retryAfters.Store(retryAfterKey(req.Method, req.URL), delay)
+	}
+	return resp, nil
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
+
+// takeRetryAfter returns and forgets the Retry-After recorded for the request that failed with err
+func takeRetryAfter(err error) (time.Duration, bool) {
+	var errResp *errcode.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.URL == nil {
+		return 0, false
+	}
+	delay, ok := retryAfters.LoadAndDelete(retryAfterKey(errResp.Method, errResp.URL))
+	if !ok {
+		return 0, false
+	}
+	return delay.(time.Duration), true
+}
+
+// retryAfterKey identifies a request in retryAfters
+func retryAfterKey(method string, u *url.URL) string {
+	return method + " " + u.String()
+}
@@ -1,9 +1,45 @@
 package utils
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-cli/src/types"
+	zarfConfig "github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/pkg/layout"
+	"github.com/defenseunicorns/zarf/src/pkg/zoci"
+	zarfTypes "github.com/defenseunicorns/zarf/src/types"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
 )
 
 func Test_IsRegistryURL(t *testing.T) {
@@ -91,3 +127,1073 @@ func Test_IsRegistryURL(t *testing.T) {
 		})
 	}
 }
+
+func Test_IsValidBlob(t *testing.T) {
+	blobsDir := t.TempDir()
+	blob := []byte("uds blob")
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, blob)
+	require.NoError(t, os.WriteFile(filepath.Join(blobsDir, desc.Digest.Encoded()), blob, 0600))
+
+	corrupt := []byte("corrupt")
+	corruptDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, corrupt)
+	require.NoError(t, os.WriteFile(filepath.Join(blobsDir, corruptDesc.Digest.Encoded()), []byte("tampered"), 0600))
+
+	missingDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, []byte("missing"))
+
+	tests := []struct {
+		name       string
+		desc       ocispec.Descriptor
+		wantResult bool
+	}{
+		{name: "ValidBlob", desc: desc, wantResult: true},
+		{name: "CorruptBlob", desc: corruptDesc, wantResult: false},
+		{name: "MissingBlob", desc: missingDesc, wantResult: false},
+		{name: "InvalidDigest", desc: ocispec.Descriptor{Digest: "sha256:nope"}, wantResult: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantResult, IsValidBlob(blobsDir, tt.desc))
+		})
+	}
+}
+
+func Test_IsRetriableOCIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantResult bool
+	}{
+		{name: "TooManyRequests", err: &errcode.ErrorResponse{StatusCode: http.StatusTooManyRequests}, wantResult: true},
+		{name: "ServerError", err: &errcode.ErrorResponse{StatusCode: http.StatusBadGateway}, wantResult: true},
+		{name: "Unauthorized", err: &errcode.ErrorResponse{StatusCode: http.StatusUnauthorized}, wantResult: false},
+		{name: "NotFound", err: &errcode.ErrorResponse{StatusCode: http.StatusNotFound}, wantResult: false},
+		{name: "NetworkError", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, wantResult: true},
+		{name: "Canceled", err: context.Canceled, wantResult: false},
+		{name: "OtherError", err: errors.New("bad manifest"), wantResult: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantResult, IsRetriableOCIError(tt.err))
+		})
+	}
+}
+
+func Test_RetryOCI(t *testing.T) {
+	originalBackoff, originalRetries := RetryBackoff, config.CommonOptions.OCIRetries
+	RetryBackoff, config.CommonOptions.OCIRetries = time.Millisecond, 3
+	defer func() {
+		RetryBackoff, config.CommonOptions.OCIRetries = originalBackoff, originalRetries
+	}()
+
+	t.Run("RetriesRetriableErrors", func(t *testing.T) {
+		calls := 0
+		err := RetryOCI(context.Background(), "test", func() error {
+			calls++
+			if calls < 3 {
+				return &errcode.ErrorResponse{StatusCode: http.StatusServiceUnavailable}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("StopsAfterMaxAttempts", func(t *testing.T) {
+		calls := 0
+		err := RetryOCI(context.Background(), "test", func() error {
+			calls++
+			return &errcode.ErrorResponse{StatusCode: http.StatusInternalServerError}
+		})
+		require.Error(t, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("DoesNotRetryAuthErrors", func(t *testing.T) {
+		calls := 0
+		err := RetryOCI(context.Background(), "test", func() error {
+			calls++
+			return &errcode.ErrorResponse{StatusCode: http.StatusForbidden}
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "Seconds", header: "30", want: 30 * time.Second, wantOK: true},
+		{name: "Date", header: "Mon, 01 Jan 2024 00:01:00 GMT", want: time.Minute, wantOK: true},
+		{name: "PastDate", header: "Sun, 31 Dec 2023 23:59:00 GMT", want: 0, wantOK: true},
+		{name: "Empty", header: "", wantOK: false},
+		{name: "Negative", header: "-1", wantOK: false},
+		{name: "Invalid", header: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.header, now)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_retryDelay(t *testing.T) {
+	originalJitter, originalMax := retryJitter, MaxRetryAfter
+	retryJitter = func(d time.Duration) time.Duration { return d / 2 }
+	defer func() { retryJitter, MaxRetryAfter = originalJitter, originalMax }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", r.URL.Query().Get("after"))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &retryAfterTransport{}}
+	rateLimited := func(after string) error {
+		resp, err := client.Get(server.URL + "/v2/dev/bundle/manifests/0.0.1?after=" + after)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return &errcode.ErrorResponse{Method: resp.Request.Method, URL: resp.Request.URL, StatusCode: resp.StatusCode}
+	}
+
+	// the registry's Retry-After is waited for instead of the backoff
+	err := rateLimited("7")
+	require.Equal(t, 7*time.Second+RetryBackoff/2, retryDelay(err, 4*time.Second))
+	// the Retry-After is only used for the retry of the response it came with
+	require.Equal(t, 6*time.Second, retryDelay(err, 4*time.Second))
+
+	MaxRetryAfter = 10 * time.Second
+	require.Equal(t, 10*time.Second+RetryBackoff/2, retryDelay(rateLimited("3600"), time.Second))
+
+	require.Equal(t, 3*time.Second, retryDelay(&errcode.ErrorResponse{StatusCode: http.StatusServiceUnavailable}, 2*time.Second))
+}
+
+func Test_retryJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		jitter := retryJitter(time.Second)
+		require.GreaterOrEqual(t, jitter, time.Duration(0))
+		require.LessOrEqual(t, jitter, time.Second/2)
+	}
+}
+
+func Test_TrustCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := http.DefaultTransport.(*http.Transport)
+	// the transport's TLS config is set when it's first cloned and TrustCACert changes it in place, so a copy of it is
+	// restored for later clones
+	transport.Clone()
+	tlsConfig := transport.TLSClientConfig.Clone()
+	t.Cleanup(func() { transport.TLSClientConfig = tlsConfig })
+
+	// OCI remotes clone the default transport, so test against a clone
+	get := func() error {
+		client := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	require.Error(t, get())
+
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not-a-cert.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a cert"), 0600))
+	require.EqualError(t, TrustCACert(notPEM), "no PEM encoded certificates found in "+notPEM)
+
+	caCert := filepath.Join(dir, "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caCert, certPEM, 0600))
+	require.NoError(t, TrustCACert(caCert))
+	require.NoError(t, get())
+}
+
+func Test_TrustCACertThroughProxy(t *testing.T) {
+	// the transport reads HTTPS_PROXY once per process, so the request is made by a fresh test binary with the proxy set
+	if caCert := os.Getenv("UDS_TEST_CA_CERT"); caCert != "" {
+		get := func() error {
+			client := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+			// the httptest cert is valid for example.com, which unlike localhost isn't excluded from proxying
+			resp, err := client.Get("https://example.com/")
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+		require.ErrorContains(t, get(), "certificate")
+		require.NoError(t, TrustCACert(caCert))
+		require.NoError(t, get())
+		return
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// the proxy tunnels every CONNECT to the server, like a corporate proxy whose CA re-signs the registry's cert
+	var mu sync.Mutex
+	var connects []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		mu.Lock()
+		connects = append(connects, r.Host)
+		mu.Unlock()
+		upstream, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+		go func() { _, _ = io.Copy(upstream, conn) }()
+		_, _ = io.Copy(conn, upstream)
+	}))
+	defer proxy.Close()
+
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caCert, certPEM, 0600))
+
+	cmd := exec.Command(os.Args[0], "-test.run=^Test_TrustCACertThroughProxy$")
+	cmd.Env = append(os.Environ(), "UDS_TEST_CA_CERT="+caCert, "HTTPS_PROXY="+proxy.URL, "https_proxy=", "NO_PROXY=", "no_proxy=")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"example.com:443", "example.com:443"}, connects)
+}
+
+func Test_WithSkipTLSVerify(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json"}`)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Content-Length", strconv.Itoa(len(manifest)))
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+	}))
+	defer server.Close()
+	t.Cleanup(func() {
+		config.CommonOptions.Insecure = false
+		config.CommonOptions.InsecureSkipTLSVerify = false
+	})
+
+	// zoci.NewRemote skips the verification with --insecure before applying WithSkipTLSVerify
+	resolve := func(insecure, skipTLSVerify bool) error {
+		config.CommonOptions.Insecure = insecure
+		config.CommonOptions.InsecureSkipTLSVerify = skipTLSVerify
+		remote, err := oci.NewOrasRemote(strings.TrimPrefix(server.URL, "https://")+"/bundle:0.0.1", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"},
+			oci.WithPlainHTTP(insecure), oci.WithInsecureSkipVerify(insecure), WithSkipTLSVerify())
+		require.NoError(t, err)
+		_, err = remote.Repo().Resolve(context.Background(), "0.0.1")
+		return err
+	}
+	require.ErrorContains(t, resolve(false, false), "certificate")
+	// the registry is still reached over HTTPS
+	require.NoError(t, resolve(false, true))
+	// --insecure also allows plain HTTP, so the HTTPS registry can't be reached
+	require.Error(t, resolve(true, false))
+}
+
+func Test_MatchesImage(t *testing.T) {
+	patterns := []string{"ghcr.io/acme/test:1.0", "ghcr.io/*/dev:*"}
+	require.True(t, MatchesImage("ghcr.io/acme/test:1.0", patterns))
+	require.True(t, MatchesImage("ghcr.io/acme/dev:2.0", patterns))
+	require.False(t, MatchesImage("ghcr.io/acme/test:2.0", patterns))
+	// * doesn't match across path separators
+	require.False(t, MatchesImage("ghcr.io/acme/tools/dev:2.0", patterns))
+}
+
+func Test_pruneImages(t *testing.T) {
+	blob := func(title string, b []byte) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, b)
+		if title == "" {
+			title = filepath.Join(layout.ImagesBlobsDir, desc.Digest.Encoded())
+		}
+		desc.Annotations = map[string]string{ocispec.AnnotationTitle: title}
+		return desc
+	}
+	shared, keepLayer, testLayer, devLayer := blob("", []byte("shared")), blob("", []byte("keep")), blob("", []byte("test")), blob("", []byte("dev"))
+	keepConfig, testConfig, devConfig := blob("", []byte("keep-config")), blob("", []byte("test-config")), blob("", []byte("dev-config"))
+
+	images := pkgImages{index: &ocispec.Index{}, manifests: map[digest.Digest]*oci.Manifest{}}
+	var imageBlobs []ocispec.Descriptor
+	for _, image := range []struct {
+		ref    string
+		config ocispec.Descriptor
+		layers []ocispec.Descriptor
+	}{
+		{"ghcr.io/acme/keep:1.0", keepConfig, []ocispec.Descriptor{shared, keepLayer}},
+		{"ghcr.io/acme/test:1.0", testConfig, []ocispec.Descriptor{shared, testLayer}},
+		{"ghcr.io/acme/dev:1.0", devConfig, []ocispec.Descriptor{devLayer}},
+	} {
+		manifest := &oci.Manifest{Manifest: ocispec.Manifest{Config: image.config, Layers: image.layers}}
+		manifestDesc := blob("", []byte(image.ref))
+		images.manifests[manifestDesc.Digest] = manifest
+		images.index.Manifests = append(images.index.Manifests, ocispec.Descriptor{Digest: manifestDesc.Digest, Annotations: map[string]string{ocispec.AnnotationBaseImageName: image.ref}})
+		imageBlobs = append(imageBlobs, manifestDesc, image.config)
+	}
+	imageBlobs = append(imageBlobs, shared, keepLayer, testLayer, devLayer)
+
+	required := true
+	images.zarfPkg = zarfTypes.ZarfPackage{Components: []zarfTypes.ZarfComponent{
+		{Name: "app", Required: &required, Images: []string{"ghcr.io/acme/keep:1.0", "ghcr.io/acme/test:1.0"}},
+		{Name: "tools", Images: []string{"ghcr.io/acme/dev:1.0"}},
+	}}
+	var checksums []string
+	checksums = append(checksums, "index-sha "+layout.IndexPath)
+	for _, desc := range imageBlobs {
+		checksums = append(checksums, desc.Digest.Encoded()+" "+desc.Annotations[ocispec.AnnotationTitle])
+	}
+	images.checksums = []byte(strings.Join(checksums, "\n"))
+
+	root := &oci.Manifest{}
+	root.Layers = append([]ocispec.Descriptor{
+		blob(layout.ZarfYAML, []byte("zarf.yaml")),
+		blob(layout.Checksums, images.checksums),
+		blob(layout.Signature, []byte("sig")),
+		blob(layout.IndexPath, []byte("index")),
+	}, imageBlobs...)
+
+	pkg := types.Package{Name: "acme", ExcludeImages: []string{"ghcr.io/acme/test:1.0", "ghcr.io/*/dev:*"}}
+	pruned, err := pruneImages(root, pkg, images, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghcr.io/acme/test:1.0", "ghcr.io/acme/dev:1.0"}, pruned.ExcludedImages)
+
+	var titles []string
+	for _, layer := range pruned.Root.Layers {
+		titles = append(titles, layer.Annotations[ocispec.AnnotationTitle])
+	}
+	// the signature and the blobs only referenced by excluded images are removed, the shared layer is kept
+	require.ElementsMatch(t, []string{
+		layout.ZarfYAML, layout.Checksums, layout.IndexPath,
+		imageBlobs[0].Annotations[ocispec.AnnotationTitle], keepConfig.Annotations[ocispec.AnnotationTitle],
+		shared.Annotations[ocispec.AnnotationTitle], keepLayer.Annotations[ocispec.AnnotationTitle],
+	}, titles)
+
+	rewritten := map[string][]byte{}
+	for _, b := range pruned.Blobs {
+		require.Equal(t, content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, b.Content).Digest, b.Desc.Digest)
+		rewritten[b.Desc.Annotations[ocispec.AnnotationTitle]] = b.Content
+	}
+	require.Len(t, rewritten, 3)
+
+	var index ocispec.Index
+	require.NoError(t, json.Unmarshal(rewritten[layout.IndexPath], &index))
+	require.Len(t, index.Manifests, 1)
+	require.Equal(t, "ghcr.io/acme/keep:1.0", index.Manifests[0].Annotations[ocispec.AnnotationBaseImageName])
+
+	indexSHA := sha256.Sum256(rewritten[layout.IndexPath])
+	require.Equal(t, strings.Join([]string{
+		hex.EncodeToString(indexSHA[:]) + " " + layout.IndexPath,
+		checksums[1], checksums[2], checksums[7], checksums[8],
+	}, "\n"), string(rewritten[layout.Checksums]))
+
+	var zarfPkg zarfTypes.ZarfPackage
+	require.NoError(t, goyaml.Unmarshal(rewritten[layout.ZarfYAML], &zarfPkg))
+	require.Equal(t, []string{"ghcr.io/acme/keep:1.0"}, zarfPkg.Components[0].Images)
+	require.Empty(t, zarfPkg.Components[1].Images)
+	checksumsSHA := sha256.Sum256(rewritten[layout.Checksums])
+	require.Equal(t, hex.EncodeToString(checksumsSHA[:]), zarfPkg.Metadata.AggregateChecksum)
+
+	// only the source layers that are kept as is are copied
+	filtered := pruned.Filter(root.Layers)
+	require.Equal(t, []ocispec.Descriptor{imageBlobs[0], keepConfig, shared, keepLayer}, filtered)
+
+	// nothing is pruned when no image matches
+	pkg.ExcludeImages = []string{"ghcr.io/acme/missing:*"}
+	pruned, err = pruneImages(root, pkg, images, nil)
+	require.NoError(t, err)
+	require.Nil(t, pruned)
+
+	// overriding a registry renames the images in zarf.yaml and the index without dropping any blobs
+	pkg.ExcludeImages = nil
+	pruned, err = pruneImages(root, pkg, images, map[string]string{"ghcr.io": "registry.internal:5000/mirror", "ghcr.io/acme/dev": "registry.internal:5000/dev"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"ghcr.io/acme/keep:1.0": "registry.internal:5000/mirror/acme/keep:1.0",
+		"ghcr.io/acme/test:1.0": "registry.internal:5000/mirror/acme/test:1.0",
+		"ghcr.io/acme/dev:1.0":  "registry.internal:5000/dev:1.0",
+	}, pruned.OverriddenImages)
+	rewritten = map[string][]byte{}
+	for _, b := range pruned.Blobs {
+		rewritten[b.Desc.Annotations[ocispec.AnnotationTitle]] = b.Content
+	}
+	require.NoError(t, goyaml.Unmarshal(rewritten[layout.ZarfYAML], &zarfPkg))
+	require.Equal(t, []string{"registry.internal:5000/mirror/acme/keep:1.0", "registry.internal:5000/mirror/acme/test:1.0"}, zarfPkg.Components[0].Images)
+	require.Equal(t, []string{"registry.internal:5000/dev:1.0"}, zarfPkg.Components[1].Images)
+	index = ocispec.Index{}
+	require.NoError(t, json.Unmarshal(rewritten[layout.IndexPath], &index))
+	require.Len(t, index.Manifests, 3)
+	require.Equal(t, "registry.internal:5000/dev:1.0", index.Manifests[2].Annotations[ocispec.AnnotationBaseImageName])
+	// the source index isn't modified
+	require.Equal(t, "ghcr.io/acme/dev:1.0", images.index.Manifests[2].Annotations[ocispec.AnnotationBaseImageName])
+	require.Equal(t, append([]ocispec.Descriptor{}, imageBlobs...), pruned.Filter(root.Layers))
+}
+
+func Test_OverrideRegistry(t *testing.T) {
+	overrides := map[string]string{"docker.io": "registry.internal", "docker.io/library": "registry.internal/hub", "quay.io": "Bad Registry"}
+	tests := []struct {
+		image   string
+		want    string
+		ok      bool
+		wantErr bool
+	}{
+		{image: "nginx:1.25", want: "registry.internal/hub/nginx:1.25", ok: true},
+		{image: "docker.io/bitnami/redis@sha256:" + strings.Repeat("a", 64), want: "registry.internal/bitnami/redis@sha256:" + strings.Repeat("a", 64), ok: true},
+		{image: "ghcr.io/acme/app:1.0", want: "ghcr.io/acme/app:1.0"},
+		{image: "docker.io.example.com/app:1.0", want: "docker.io.example.com/app:1.0"},
+		{image: "quay.io/acme/app:1.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, ok, err := OverrideRegistry(tt.image, overrides)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	require.NoError(t, ValidateRegistryOverride("docker.io", "registry.internal:5000/mirror"))
+	require.Error(t, ValidateRegistryOverride("docker.io", ""))
+	require.Error(t, ValidateRegistryOverride("docker.io/", "registry.internal"))
+	require.Error(t, ValidateRegistryOverride("docker.io", "Registry Internal"))
+}
+
+func Test_addToIndex(t *testing.T) {
+	bundleForArch := func(arch string) *types.UDSBundle {
+		return &types.UDSBundle{Metadata: types.UDSMetadata{Architecture: arch}}
+	}
+	amd64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("amd64"))
+	arm64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("arm64"))
+
+	// a multi-arch bundle adds a root manifest per arch to the same index
+	index := createIndex(bundleForArch("amd64"), amd64Desc)
+	index = addToIndex(index, bundleForArch("arm64"), arm64Desc)
+	require.Len(t, index.Manifests, 2)
+	require.Equal(t, amd64Desc.Digest, index.Manifests[0].Digest)
+	require.Equal(t, "amd64", index.Manifests[0].Platform.Architecture)
+	require.Equal(t, arm64Desc.Digest, index.Manifests[1].Digest)
+	require.Equal(t, "arm64", index.Manifests[1].Platform.Architecture)
+	require.Equal(t, oci.MultiOS, index.Manifests[1].Platform.OS)
+
+	// recreating an arch replaces its root manifest
+	newAmd64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("amd64 v2"))
+	index = addToIndex(index, bundleForArch("amd64"), newAmd64Desc)
+	require.Len(t, index.Manifests, 2)
+	require.Equal(t, newAmd64Desc.Digest, index.Manifests[0].Digest)
+	require.Equal(t, newAmd64Desc.Size, index.Manifests[0].Size)
+
+	// a bundle for the same arch but another OS is a separate entry
+	windowsDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("windows amd64"))
+	windowsBundle := &types.UDSBundle{Metadata: types.UDSMetadata{Architecture: "amd64", OS: "windows"}}
+	index = addToIndex(index, windowsBundle, windowsDesc)
+	require.Len(t, index.Manifests, 3)
+	require.Equal(t, newAmd64Desc.Digest, index.Manifests[0].Digest)
+	require.Equal(t, windowsDesc.Digest, index.Manifests[2].Digest)
+	require.Equal(t, ocispec.Platform{Architecture: "amd64", OS: "windows"}, *index.Manifests[2].Platform)
+	_, conflict := IndexConflict(index, windowsBundle, windowsDesc)
+	require.False(t, conflict)
+}
+
+func Test_RootManifestDescArtifactType(t *testing.T) {
+	rootManifest := ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: config.BundleArtifactType}
+	rootManifest.SchemaVersion = 2
+	desc, err := RootManifestDesc(rootManifest)
+	require.NoError(t, err)
+	require.Equal(t, config.BundleArtifactType, desc.ArtifactType)
+	require.Equal(t, config.BundleArtifactType, artifactType(&oci.Manifest{Manifest: rootManifest}))
+	require.Empty(t, artifactType(oci.ConfigPartial{}))
+
+	// the index entry carries the artifact type so registries can categorize the bundle without fetching the manifest
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Architecture: "amd64"}}
+	index := createIndex(bundle, desc)
+	require.Equal(t, config.BundleArtifactType, index.Manifests[0].ArtifactType)
+	index = addToIndex(index, bundle, content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("no artifact type")))
+	require.Empty(t, index.Manifests[0].ArtifactType)
+}
+
+func Test_IndexConflict(t *testing.T) {
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Architecture: "amd64"}}
+	amd64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("amd64"))
+	arm64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("arm64"))
+
+	// there's nothing to conflict with before the bundle is first pushed
+	_, conflict := IndexConflict(nil, bundle, amd64Desc)
+	require.False(t, conflict)
+
+	// a different arch or an identical root manifest isn't a conflict
+	index := createIndex(&types.UDSBundle{Metadata: types.UDSMetadata{Architecture: "arm64"}}, arm64Desc)
+	_, conflict = IndexConflict(index, bundle, amd64Desc)
+	require.False(t, conflict)
+	index = addToIndex(index, bundle, amd64Desc)
+	_, conflict = IndexConflict(index, bundle, amd64Desc)
+	require.False(t, conflict)
+
+	newAmd64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("amd64 v2"))
+	existing, conflict := IndexConflict(index, bundle, newAmd64Desc)
+	require.True(t, conflict)
+	require.Equal(t, amd64Desc.Digest, existing.Digest)
+}
+
+func Test_MergeIndex(t *testing.T) {
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Version: "0.0.1", Architecture: "amd64"}}
+	amd64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("amd64"))
+	arm64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("arm64"))
+	arm64Index, err := json.Marshal(createIndex(&types.UDSBundle{Metadata: types.UDSMetadata{Architecture: "arm64"}}, arm64Desc))
+	require.NoError(t, err)
+
+	// a fake registry with a single tag, replaceAfterPush simulates another writer that read the index before the
+	// first push and pushes its own version right after it
+	newRegistry := func(t *testing.T, index []byte, replaceAfterPush []byte) (*oci.OrasRemote, func() ocispec.Index) {
+		var pushes int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the index is resolved by its tag, then fetched by its digest
+			reference, ok := strings.CutPrefix(r.URL.Path, "/v2/test/bundle/manifests/")
+			if !ok || (reference != "0.0.1" && reference != digest.FromBytes(index).String()) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			switch r.Method {
+			case http.MethodPut:
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				index = b
+				if pushes++; pushes == 1 && replaceAfterPush != nil {
+					index = replaceAfterPush
+				}
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(b).String())
+				w.WriteHeader(http.StatusCreated)
+			case http.MethodHead, http.MethodGet:
+				if index == nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(index).String())
+				w.Header().Set("Content-Length", strconv.Itoa(len(index)))
+				if r.Method == http.MethodGet {
+					_, _ = w.Write(index)
+				}
+			}
+		}))
+		t.Cleanup(server.Close)
+		remote, err := oci.NewOrasRemote(strings.TrimPrefix(server.URL, "http://")+"/test/bundle:0.0.1", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+		require.NoError(t, err)
+		latest := func() ocispec.Index {
+			var latest ocispec.Index
+			require.NoError(t, json.Unmarshal(index, &latest))
+			return latest
+		}
+		return remote, latest
+	}
+	digests := func(index ocispec.Index) []digest.Digest {
+		var digests []digest.Digest
+		for _, manifest := range index.Manifests {
+			digests = append(digests, manifest.Digest)
+		}
+		return digests
+	}
+
+	t.Run("new index", func(t *testing.T) {
+		remote, latest := newRegistry(t, nil, nil)
+		require.NoError(t, MergeIndex(context.Background(), remote, bundle, amd64Desc, false))
+		require.Equal(t, []digest.Digest{amd64Desc.Digest}, digests(latest()))
+	})
+
+	t.Run("replaced by another writer", func(t *testing.T) {
+		remote, latest := newRegistry(t, nil, arm64Index)
+		require.NoError(t, MergeIndex(context.Background(), remote, bundle, amd64Desc, false))
+		require.Equal(t, []digest.Digest{arm64Desc.Digest, amd64Desc.Digest}, digests(latest()))
+	})
+
+	t.Run("arch updated by another writer", func(t *testing.T) {
+		otherAmd64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("amd64 v2"))
+		otherIndex, err := json.Marshal(createIndex(bundle, otherAmd64Desc))
+		require.NoError(t, err)
+		remote, latest := newRegistry(t, otherIndex, nil)
+		err = MergeIndex(context.Background(), remote, bundle, amd64Desc, false)
+		require.ErrorContains(t, err, "refusing to overwrite it")
+		require.Equal(t, []digest.Digest{otherAmd64Desc.Digest}, digests(latest()))
+
+		require.NoError(t, MergeIndex(context.Background(), remote, bundle, amd64Desc, true))
+		require.Equal(t, []digest.Digest{amd64Desc.Digest}, digests(latest()))
+	})
+}
+
+func Test_MergeIndexConditional(t *testing.T) {
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Version: "0.0.1", Architecture: "amd64"}}
+	amd64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("amd64"))
+	arm64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("arm64"))
+	arm64Index, err := json.Marshal(createIndex(&types.UDSBundle{Metadata: types.UDSMetadata{Architecture: "arm64"}}, arm64Desc))
+	require.NoError(t, err)
+
+	// a fake registry that sends the index's digest as its ETag and honors If-Match and If-None-Match on pushes,
+	// modifyAfterGet simulates another writer that pushes its own index right after the first fetch
+	var index []byte
+	var conditions []string
+	var rejected int
+	modifyAfterGet := arm64Index
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the index is fetched by its tag, and by its digest once it's resolved to check it after the push
+		reference, ok := strings.CutPrefix(r.URL.Path, "/v2/test/bundle/manifests/")
+		if !ok || (reference != "0.0.1" && (index == nil || reference != digest.FromBytes(index).String())) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		etag := ""
+		if index != nil {
+			etag = fmt.Sprintf("%q", digest.FromBytes(index))
+		}
+		switch r.Method {
+		case http.MethodPut:
+			ifMatch, ifNoneMatch := r.Header.Get("If-Match"), r.Header.Get("If-None-Match")
+			conditions = append(conditions, ifMatch+ifNoneMatch)
+			if (ifMatch != "" && ifMatch != etag) || (ifNoneMatch == "*" && index != nil) {
+				rejected++
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			index = b
+			w.Header().Set("Docker-Content-Digest", digest.FromBytes(b).String())
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodHead, http.MethodGet:
+			if index == nil {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(index).String())
+				w.Header().Set("ETag", etag)
+				w.Header().Set("Content-Length", strconv.Itoa(len(index)))
+				if r.Method == http.MethodGet {
+					_, _ = w.Write(index)
+				}
+			}
+			if modifyAfterGet != nil {
+				index, modifyAfterGet = modifyAfterGet, nil
+			}
+		}
+	}))
+	defer server.Close()
+	remote, err := oci.NewOrasRemote(strings.TrimPrefix(server.URL, "http://")+"/test/bundle:0.0.1", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	require.NoError(t, MergeIndex(context.Background(), remote, bundle, amd64Desc, false))
+	// the push over the missing tag is rejected since the other writer created it, the root manifest is merged into
+	// the other writer's index and pushed on its ETag
+	require.Equal(t, 1, rejected)
+	require.Equal(t, []string{"*", fmt.Sprintf("%q", digest.FromBytes(arm64Index))}, conditions)
+	var latest ocispec.Index
+	require.NoError(t, json.Unmarshal(index, &latest))
+	require.Len(t, latest.Manifests, 2)
+	require.Equal(t, arm64Desc.Digest, latest.Manifests[0].Digest)
+	require.Equal(t, amd64Desc.Digest, latest.Manifests[1].Digest)
+}
+
+func Test_UpdateIndexMediaType(t *testing.T) {
+	bundle := &types.UDSBundle{Metadata: types.UDSMetadata{Version: "0.0.1", Architecture: "amd64"}}
+	amd64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("amd64"))
+	arm64Desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("arm64"))
+
+	for _, mediaType := range []string{ocispec.MediaTypeImageIndex, dockerManifestListMediaType} {
+		t.Run(mediaType, func(t *testing.T) {
+			// an index created by another tool, which only declares its media type in the registry's response
+			index := []byte(fmt.Sprintf(`{"schemaVersion":2,"manifests":[{"mediaType":%q,"digest":%q,"size":%d,"platform":{"architecture":"arm64","os":"multi"}}]}`,
+				ocispec.MediaTypeImageManifest, arm64Desc.Digest, arm64Desc.Size))
+			var pushedMediaType string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reference, ok := strings.CutPrefix(r.URL.Path, "/v2/test/bundle/manifests/")
+				if !ok || (reference != "0.0.1" && reference != digest.FromBytes(index).String()) {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if r.Method == http.MethodPut {
+					b, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					index, pushedMediaType = b, r.Header.Get("Content-Type")
+					w.Header().Set("Docker-Content-Digest", digest.FromBytes(b).String())
+					w.WriteHeader(http.StatusCreated)
+					return
+				}
+				w.Header().Set("Content-Type", mediaType)
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(index).String())
+				w.Header().Set("Content-Length", strconv.Itoa(len(index)))
+				if r.Method == http.MethodGet {
+					_, _ = w.Write(index)
+				}
+			}))
+			defer server.Close()
+			remote, err := oci.NewOrasRemote(strings.TrimPrefix(server.URL, "http://")+"/test/bundle:0.0.1", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+			require.NoError(t, err)
+
+			existing, err := GetIndex(context.Background(), remote, "0.0.1")
+			require.NoError(t, err)
+			require.Equal(t, mediaType, existing.MediaType)
+			require.NoError(t, UpdateIndex(context.Background(), existing, remote, bundle, amd64Desc))
+
+			// the index keeps its media type and its existing entries
+			require.Equal(t, mediaType, pushedMediaType)
+			var updated ocispec.Index
+			require.NoError(t, json.Unmarshal(index, &updated))
+			require.Equal(t, mediaType, updated.MediaType)
+			require.Len(t, updated.Manifests, 2)
+			require.Equal(t, arm64Desc.Digest, updated.Manifests[0].Digest)
+			require.Equal(t, amd64Desc.Digest, updated.Manifests[1].Digest)
+		})
+	}
+}
+
+func Test_CreateCopyOptsIndex(t *testing.T) {
+	ctx := context.Background()
+	for _, mediaType := range []string{ocispec.MediaTypeImageIndex, dockerManifestListMediaType} {
+		t.Run(mediaType, func(t *testing.T) {
+			src := memory.New()
+			push := func(mediaType string, b []byte) ocispec.Descriptor {
+				desc := content.NewDescriptorFromBytes(mediaType, b)
+				require.NoError(t, src.Push(ctx, desc, bytes.NewReader(b)))
+				return desc
+			}
+			rootManifest := func(arch string) (ocispec.Descriptor, ocispec.Descriptor) {
+				layer := push(zoci.ZarfLayerMediaTypeBlob, []byte("uds-bundle.yaml for "+arch))
+				layer.Annotations = map[string]string{ocispec.AnnotationTitle: config.BundleYAML}
+				b, err := json.Marshal(ocispec.Manifest{
+					Versioned: specs.Versioned{SchemaVersion: 2},
+					MediaType: ocispec.MediaTypeImageManifest,
+					Config:    ocispec.DescriptorEmptyJSON,
+					Layers:    []ocispec.Descriptor{layer},
+				})
+				require.NoError(t, err)
+				root := push(ocispec.MediaTypeImageManifest, b)
+				root.Platform = &ocispec.Platform{Architecture: arch, OS: oci.MultiOS}
+				return root, layer
+			}
+			amd64Root, amd64Layer := rootManifest("amd64")
+			arm64Root, arm64Layer := rootManifest("arm64")
+			// an index created by another tool, with an entry that has no platform
+			attestation := push(ocispec.MediaTypeImageManifest, []byte(`{"schemaVersion":2}`))
+			b, err := json.Marshal(ocispec.Index{
+				Versioned: specs.Versioned{SchemaVersion: 2},
+				MediaType: mediaType,
+				Manifests: []ocispec.Descriptor{attestation, arm64Root, amd64Root},
+			})
+			require.NoError(t, err)
+			index := push(mediaType, b)
+			require.NoError(t, src.Tag(ctx, index, "0.0.1"))
+
+			originalArch := config.CLIArch
+			config.CLIArch = "amd64"
+			t.Cleanup(func() { config.CLIArch = originalArch })
+
+			// pull the amd64 bundle like the OCI provider does, from the tag through the root manifest
+			dst := memory.New()
+			copyOpts := CreateCopyOpts([]ocispec.Descriptor{amd64Root, amd64Layer}, 1)
+			_, err = oras.Copy(ctx, src, "0.0.1", dst, "0.0.1", copyOpts)
+			require.NoError(t, err)
+			for _, desc := range []ocispec.Descriptor{amd64Root, amd64Layer} {
+				exists, err := dst.Exists(ctx, desc)
+				require.NoError(t, err)
+				require.True(t, exists, desc.Digest)
+			}
+			for _, desc := range []ocispec.Descriptor{arm64Root, arm64Layer, attestation} {
+				exists, err := dst.Exists(ctx, desc)
+				require.NoError(t, err)
+				require.False(t, exists, desc.Digest)
+			}
+		})
+	}
+}
+
+func Test_PlatformManifest(t *testing.T) {
+	entry := func(arch, os string) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte(os+"/"+arch))
+		desc.Platform = &ocispec.Platform{Architecture: arch, OS: os}
+		return desc
+	}
+	attestation := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("attestation"))
+	multiAmd64, windowsAmd64, linuxAmd64, multiArm64 := entry("amd64", oci.MultiOS), entry("amd64", "windows"), entry("amd64", "linux"), entry("arm64", oci.MultiOS)
+
+	tests := []struct {
+		name      string
+		os        string
+		manifests []ocispec.Descriptor
+		want      ocispec.Descriptor
+		wantErr   string
+	}{
+		{name: "MultiOS", manifests: []ocispec.Descriptor{attestation, multiArm64, windowsAmd64, multiAmd64}, want: multiAmd64},
+		{name: "ArchOnlyWithoutOSFlag", manifests: []ocispec.Descriptor{attestation, multiArm64, windowsAmd64}, want: windowsAmd64},
+		{name: "OSFlag", os: "windows", manifests: []ocispec.Descriptor{multiAmd64, windowsAmd64}, want: windowsAmd64},
+		{name: "MultiOSWithOSFlag", os: "windows", manifests: []ocispec.Descriptor{multiArm64, multiAmd64}, want: multiAmd64},
+		{
+			name:      "OtherOSWithOSFlag",
+			os:        "windows",
+			manifests: []ocispec.Descriptor{linuxAmd64},
+			wantErr:   "the index doesn't have a manifest for windows/amd64, only for linux/amd64; pass --os or --architecture to choose one",
+		},
+		{
+			name:      "SeveralOSes",
+			manifests: []ocispec.Descriptor{windowsAmd64, linuxAmd64},
+			wantErr:   "the index doesn't have a manifest for multi/amd64, only for windows/amd64, linux/amd64; pass --os or --architecture to choose one",
+		},
+		{
+			name:      "OtherArch",
+			manifests: []ocispec.Descriptor{multiArm64},
+			wantErr:   "the index doesn't have a manifest for multi/amd64, only for multi/arm64; pass --os or --architecture to choose one",
+		},
+		{name: "NoPlatforms", manifests: []ocispec.Descriptor{attestation}, wantErr: "the index doesn't have a manifest for any platform"},
+	}
+
+	originalArch, originalOS := config.CLIArch, config.CLIOS
+	t.Cleanup(func() { config.CLIArch, config.CLIOS = originalArch, originalOS })
+	config.CLIArch = "amd64"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.CLIOS = tt.os
+			manifest, err := PlatformManifest(tt.manifests)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, manifest)
+		})
+	}
+}
+
+func Test_BundlePlatform(t *testing.T) {
+	windowsAmd64 := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("windows amd64"))
+	windowsAmd64.Platform = &ocispec.Platform{Architecture: "amd64", OS: "windows"}
+	index, err := json.Marshal(ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}, MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{windowsAmd64}})
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reference, ok := strings.CutPrefix(r.URL.Path, "/v2/test/bundle/manifests/")
+		if !ok || (reference != "0.0.1" && reference != digest.FromBytes(index).String()) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(index).String())
+		w.Header().Set("Content-Length", strconv.Itoa(len(index)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(index)
+		}
+	}))
+	defer server.Close()
+
+	// zoci remotes use plain HTTP with Zarf's --insecure
+	originalArch, originalOS, originalInsecure := config.CLIArch, config.CLIOS, zarfConfig.CommonOptions.Insecure
+	t.Cleanup(func() {
+		config.CLIArch, config.CLIOS, zarfConfig.CommonOptions.Insecure = originalArch, originalOS, originalInsecure
+	})
+	config.CLIArch, zarfConfig.CommonOptions.Insecure = "amd64", true
+	source := "oci://" + strings.TrimPrefix(server.URL, "http://") + "/test/bundle"
+
+	// a bundle created with metadata.os is fetched for its OS without --os
+	require.Equal(t, *windowsAmd64.Platform, BundlePlatform(context.Background(), source+":0.0.1"))
+	// the CLI's platform is used if the bundle can't be resolved, fetching it reports the error
+	require.Equal(t, ocispec.Platform{Architecture: "amd64", OS: oci.MultiOS}, BundlePlatform(context.Background(), source+":0.0.2"))
+	config.CLIOS = "linux"
+	require.Equal(t, ocispec.Platform{Architecture: "amd64", OS: "linux"}, BundlePlatform(context.Background(), source+":0.0.1"))
+}
+
+func Test_MirrorURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		pkgURL    string
+		mirror    string
+		want      string
+		wantError bool
+	}{
+		{name: "host", pkgURL: "ghcr.io/defenseunicorns/packages/podinfo:0.0.1", mirror: "mirror.example.com", want: "mirror.example.com/defenseunicorns/packages/podinfo:0.0.1"},
+		{name: "port and oci prefixes", pkgURL: "oci://ghcr.io/podinfo@sha256:abc", mirror: "oci://localhost:5000", want: "oci://localhost:5000/podinfo@sha256:abc"},
+		{name: "path", mirror: "mirror.example.com/packages", wantError: true},
+		{name: "empty", mirror: "oci://", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMirror(tt.mirror)
+			if tt.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, MirrorURL(tt.pkgURL, tt.mirror))
+		})
+	}
+}
+
+func Test_DigestTag(t *testing.T) {
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("root manifest"))
+	require.Equal(t, "sha256-"+desc.Digest.Encoded(), DigestTag(desc, false))
+	require.Equal(t, "sha256-"+desc.Digest.Encoded()[:12], DigestTag(desc, true))
+}
+
+func Test_ParseCredential(t *testing.T) {
+	cred, err := ParseCredential("")
+	require.NoError(t, err)
+	require.Equal(t, auth.EmptyCredential, cred)
+
+	cred, err = ParseCredential("robot:pass:word")
+	require.NoError(t, err)
+	require.Equal(t, auth.Credential{Username: "robot", Password: "pass:word"}, cred)
+
+	_, err = ParseCredential("robot")
+	require.Error(t, err)
+	_, err = ParseCredential(":password")
+	require.Error(t, err)
+}
+
+func Test_WithCredential(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	ctx := context.Background()
+	platform := oci.PlatformForArch("amd64")
+	srcCred := auth.Credential{Username: "src", Password: "src-password"}
+	dstCred := auth.Credential{Username: "dst", Password: "dst-password"}
+
+	// the remotes share an auth client until they're given their own
+	src, err := oci.NewOrasRemote("src.example.com/packages/test:1.0", platform)
+	require.NoError(t, err)
+	WithCredential(src, srcCred)
+	dst, err := oci.NewOrasRemote("dst.example.com/bundles/test:1.0", platform)
+	require.NoError(t, err)
+	WithCredential(dst, dstCred)
+
+	srcClient := src.Repo().Client.(*auth.Client)
+	dstClient := dst.Repo().Client.(*auth.Client)
+	require.NotSame(t, srcClient, dstClient)
+
+	// credentials are matched by the registry's hostname
+	cred, err := srcClient.Credential(ctx, "src.example.com")
+	require.NoError(t, err)
+	require.Equal(t, srcCred, cred)
+	cred, err = srcClient.Credential(ctx, "dst.example.com")
+	require.NoError(t, err)
+	require.Equal(t, auth.EmptyCredential, cred)
+	cred, err = dstClient.Credential(ctx, "dst.example.com")
+	require.NoError(t, err)
+	require.Equal(t, dstCred, cred)
+}
+
+func Test_LoggerOrDiscard(t *testing.T) {
+	require.NotNil(t, LoggerOrDiscard(nil))
+	require.False(t, LoggerOrDiscard(nil).Enabled(context.Background(), slog.LevelError))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	require.Same(t, logger, LoggerOrDiscard(logger))
+	LoggerOrDiscard(logger).Info("pushed package", "package", "podinfo", "bytes", int64(42))
+	require.Contains(t, buf.String(), `"package":"podinfo"`)
+	require.Contains(t, buf.String(), `"bytes":42`)
+}
+
+func Test_IsCompressibleLayer(t *testing.T) {
+	layer := func(title string) ocispec.Descriptor {
+		return ocispec.Descriptor{Annotations: map[string]string{ocispec.AnnotationTitle: title}}
+	}
+	require.True(t, IsCompressibleLayer(layer("components/podinfo.tar")))
+	require.False(t, IsCompressibleLayer(layer("images/blobs/sha256/abc")))
+	require.False(t, IsCompressibleLayer(layer("zarf.yaml")))
+	require.False(t, IsCompressibleLayer(layer("sboms.tar")))
+}
+
+func Test_ValidateCompressionLevel(t *testing.T) {
+	require.NoError(t, ValidateCompressionLevel(0))
+	require.NoError(t, ValidateCompressionLevel(19))
+	require.EqualError(t, ValidateCompressionLevel(23), "invalid compression level 23, it must be between 1 and 22")
+	require.Error(t, ValidateCompressionLevel(-1))
+}
+
+func Test_CompressLayer(t *testing.T) {
+	original := bytes.Repeat([]byte("component tarball "), 1024)
+	layer := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, original)
+	layer.Annotations = map[string]string{ocispec.AnnotationTitle: "components/podinfo.tar"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/test/pkg/blobs/"+layer.Digest.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", layer.Digest.String())
+		_, _ = w.Write(original)
+	}))
+	defer server.Close()
+	remote, err := oci.NewOrasRemote(strings.TrimPrefix(server.URL, "http://")+"/test/pkg:0.0.1", ocispec.Platform{OS: oci.MultiOS, Architecture: "amd64"}, oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	compressed, err := CompressLayer(context.Background(), remote, layer, 19, t.TempDir())
+	require.NoError(t, err)
+	require.True(t, IsCompressedLayer(compressed.Desc))
+	require.Less(t, compressed.Desc.Size, layer.Size)
+	require.Equal(t, "components/podinfo.tar", compressed.Desc.Annotations[ocispec.AnnotationTitle])
+	require.Equal(t, layer.Digest.String(), compressed.Desc.Annotations[config.LayerUncompressedDigestAnnotation])
+	size, err := UncompressedSize(compressed.Desc)
+	require.NoError(t, err)
+	require.Equal(t, layer.Size, size)
+
+	// the compressed file matches its desc
+	b, err := os.ReadFile(compressed.Path)
+	require.NoError(t, err)
+	require.Equal(t, compressed.Desc.Digest, digest.FromBytes(b))
+	require.Equal(t, compressed.Desc.Size, int64(len(b)))
+
+	t.Run("DecompressLayerFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "podinfo.tar")
+		require.NoError(t, os.WriteFile(path, b, 0600))
+		require.NoError(t, DecompressLayerFile(compressed.Desc, path))
+		decompressed, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, original, decompressed)
+
+		// uncompressed layers are left as is
+		require.NoError(t, DecompressLayerFile(layer, path))
+	})
+
+	t.Run("DecompressLayerFileMismatch", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "podinfo.tar")
+		require.NoError(t, os.WriteFile(path, b, 0600))
+		tampered := compressed.Desc
+		tampered.Annotations = map[string]string{
+			ocispec.AnnotationTitle:                  "components/podinfo.tar",
+			config.LayerUncompressedDigestAnnotation: digest.FromString("other").String(),
+			config.LayerUncompressedSizeAnnotation:   compressed.Desc.Annotations[config.LayerUncompressedSizeAnnotation],
+		}
+		require.ErrorContains(t, DecompressLayerFile(tampered, path), "decompressed components/podinfo.tar doesn't match the original layer")
+		// the compressed file isn't replaced
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, b, got)
+	})
+
+	t.Run("DecompressLayer", func(t *testing.T) {
+		rc, err := DecompressLayer(compressed.Desc, bytes.NewReader(b))
+		require.NoError(t, err)
+		defer rc.Close()
+		got, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, original, got)
+	})
+}
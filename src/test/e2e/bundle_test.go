@@ -406,7 +406,7 @@ func TestPackageNaming(t *testing.T) {
 	remove(t, tarballPath)
 
 	// Test create -o with zarf package names that don't match the zarf package name in the bundle
-	createRemoteInsecure(t, bundleDir, bundleRef.Registry, e2e.Arch)
+	overwriteRemoteInsecure(t, bundleDir, bundleRef.Registry, e2e.Arch)
 	deployAndRemoveLocalAndRemoteInsecure(t, bundleRef.String(), tarballPath)
 }
 
@@ -435,8 +435,12 @@ func TestBundleIndexInRemoteOnPublish(t *testing.T) {
 	pull(t, fmt.Sprintf("localhost:888/%s:0.0.1", bundleName), tarballPath) // test no oci prefix
 	deployAndRemoveLocalAndRemoteInsecure(t, fmt.Sprintf("oci://localhost:888/%s:0.0.1", bundleName), tarballPath)
 
-	// now test by running 'create -o' over the bundle that was published
-	createRemoteInsecure(t, bundleDir, "oci://localhost:888", e2e.Arch)
+	// now test by running 'create -o' over the bundle that was published, which requires --force
+	cmd := strings.Split(fmt.Sprintf("create %s -o oci://localhost:888 --confirm --insecure -a %s", bundleDir, e2e.Arch), " ")
+	_, stderr, err := e2e.UDS(cmd...)
+	require.Error(t, err)
+	require.Contains(t, stderr, "use --force to overwrite it")
+	overwriteRemoteInsecure(t, bundleDir, "oci://localhost:888", e2e.Arch)
 	index, err = queryIndex(t, "http://localhost:888", bundleName)
 	require.NoError(t, err)
 	validateMultiArchIndex(t, index)
@@ -49,8 +49,16 @@ func createRemoteInsecure(t *testing.T, bundlePath, registry, arch string) {
 	require.NoError(t, err)
 }
 
+// overwriteRemoteInsecure creates a bundle over one that was already pushed with the same name, version and arch
+func overwriteRemoteInsecure(t *testing.T, bundlePath, registry, arch string) {
+	cmd := strings.Split(fmt.Sprintf("create %s -o %s --confirm --insecure -a %s --force", bundlePath, registry, arch), " ")
+	_, _, err := e2e.UDS(cmd...)
+	require.NoError(t, err)
+}
+
+// createRemote overwrites existing bundles since the remote registry persists between runs
 func createRemote(t *testing.T, bundlePath, registry, arch string) {
-	cmd := strings.Split(fmt.Sprintf("create %s -o %s --confirm -a %s", bundlePath, registry, arch), " ")
+	cmd := strings.Split(fmt.Sprintf("create %s -o %s --confirm -a %s --force", bundlePath, registry, arch), " ")
 	_, _, err := e2e.UDS(cmd...)
 	require.NoError(t, err)
 }
@@ -6,10 +6,12 @@ package types
 
 // UDSBundle is the top-level structure of a UDS bundle
 type UDSBundle struct {
-	Kind     string       `json:"kind" jsonschema:"description=The kind of UDS package,enum=UDSBundle"`
-	Metadata UDSMetadata  `json:"metadata" jsonschema:"description=UDSBundle metadata"`
-	Build    UDSBuildData `json:"build,omitempty" jsonschema:"description=Generated bundle build data"`
-	Packages []Package    `json:"packages" jsonschema:"description=List of Zarf packages"`
+	Kind       string                 `json:"kind" jsonschema:"description=The kind of UDS package,enum=UDSBundle"`
+	Metadata   UDSMetadata            `json:"metadata" jsonschema:"description=UDSBundle metadata"`
+	Build      UDSBuildData           `json:"build,omitempty" jsonschema:"description=Generated bundle build data"`
+	Packages   []Package              `json:"packages" jsonschema:"description=List of Zarf packages"`
+	ExtraFiles []string               `json:"extraFiles,omitempty" jsonschema:"description=List of files relative to the bundle's directory to ship in the bundle alongside its YAML such as a LICENSE"`
+	Variables  map[string]interface{} `json:"variables,omitempty" jsonschema:"description=Map of variable names to default values shared by every Zarf package in the bundle, overridden by UDS_VAR_ environment variables and --set"`
 }
 
 // Package represents a Zarf package in a UDS bundle
@@ -19,7 +21,10 @@ type Package struct {
 	Repository         string                                     `json:"repository,omitempty" jsonschema:"description=The repository to import the package from"`
 	Path               string                                     `json:"path,omitempty" jsonschema:"description=The local path to import the package from"`
 	Ref                string                                     `json:"ref" jsonschema:"description=Ref (tag) of the Zarf package"`
+	Arch               string                                     `json:"arch,omitempty" jsonschema:"description=Architecture of the Zarf package, defaults to the bundle's architecture"`
+	DependsOn          []string                                   `json:"dependsOn,omitempty" jsonschema:"description=Names of the Zarf packages in the bundle to deploy before this package"`
 	OptionalComponents []string                                   `json:"optionalComponents,omitempty" jsonschema:"description=List of optional components to include from the package (required components are always included)"`
+	ExcludeImages      []string                                   `json:"excludeImages,omitempty" jsonschema:"description=List of image references or glob patterns of images to exclude from the package when bundling it"`
 	PublicKey          string                                     `json:"publicKey,omitempty" jsonschema:"description=The public key to use to verify the package"`
 	Imports            []BundleVariableImport                     `json:"imports,omitempty" jsonschema:"description=List of Zarf variables to import from another Zarf package"`
 	Exports            []BundleVariableExport                     `json:"exports,omitempty" jsonschema:"description=List of Zarf variables to export from the Zarf package"`
@@ -71,24 +76,68 @@ type BundleVariableExport struct {
 
 // UDSMetadata lists information about the current UDS Bundle.
 type UDSMetadata struct {
-	Name              string `json:"name" jsonschema:"description=Name to identify this Zarf package,pattern=^[a-z0-9\\-]+$"`
-	Description       string `json:"description,omitempty" jsonschema:"description=Additional information about this package"`
-	Version           string `json:"version,omitempty" jsonschema:"description=Generic string set by a package author to track the package version"`
-	URL               string `json:"url,omitempty" jsonschema:"description=Link to package information when online"`
-	Uncompressed      bool   `json:"uncompressed,omitempty" jsonschema:"description=Disable compression of this package"`
-	Architecture      string `json:"architecture,omitempty" jsonschema:"description=The target cluster architecture for this package,example=arm64,example=amd64"`
-	Authors           string `json:"authors,omitempty" jsonschema:"description=Comma-separated list of package authors (including contact info),example=Doug &#60;hello@defenseunicorns.com&#62;&#44; Pepr &#60;hello@defenseunicorns.com&#62;"`
-	Documentation     string `json:"documentation,omitempty" jsonschema:"description=Link to package documentation when online"`
-	Source            string `json:"source,omitempty" jsonschema:"description=Link to package source code when online"`
-	Vendor            string `json:"vendor,omitempty" jsonschema_description:"Name of the distributing entity, organization or individual."`
-	AggregateChecksum string `json:"aggregateChecksum,omitempty" jsonschema:"description=Checksum of a checksums.txt file that contains checksums all the layers within the package."`
+	Name              string            `json:"name" jsonschema:"description=Name to identify this Zarf package,pattern=^[a-z0-9\\-]+$"`
+	Description       string            `json:"description,omitempty" jsonschema:"description=Additional information about this package"`
+	Version           string            `json:"version,omitempty" jsonschema:"description=Generic string set by a package author to track the package version"`
+	URL               string            `json:"url,omitempty" jsonschema:"description=Link to package information when online"`
+	Uncompressed      bool              `json:"uncompressed,omitempty" jsonschema:"description=Disable compression of this package"`
+	Architecture      string            `json:"architecture,omitempty" jsonschema:"description=The target cluster architecture for this package,example=arm64,example=amd64"`
+	OS                string            `json:"os,omitempty" jsonschema:"description=The OS of the bundle's platform (defaults to multi),example=linux,example=windows"`
+	Authors           string            `json:"authors,omitempty" jsonschema:"description=Comma-separated list of package authors (including contact info),example=Doug &#60;hello@defenseunicorns.com&#62;&#44; Pepr &#60;hello@defenseunicorns.com&#62;"`
+	Documentation     string            `json:"documentation,omitempty" jsonschema:"description=Link to package documentation when online"`
+	Source            string            `json:"source,omitempty" jsonschema:"description=Link to package source code when online"`
+	Vendor            string            `json:"vendor,omitempty" jsonschema_description:"Name of the distributing entity, organization or individual."`
+	AggregateChecksum string            `json:"aggregateChecksum,omitempty" jsonschema:"description=Checksum of a checksums.txt file that contains checksums all the layers within the package."`
+	Annotations       map[string]string `json:"annotations,omitempty" jsonschema:"description=Additional annotations to add to the bundle's root manifest"`
 }
 
 // UDSBuildData is written during the bundle.Create() operation to track details of the created package.
 type UDSBuildData struct {
-	Terminal     string `json:"terminal" jsonschema:"description=The machine name that created this package"`
-	User         string `json:"user" jsonschema:"description=The username who created this package"`
-	Architecture string `json:"architecture" jsonschema:"description=The architecture this package was created on"`
-	Timestamp    string `json:"timestamp" jsonschema:"description=The timestamp when this package was created"`
-	Version      string `json:"version" jsonschema:"description=The version of Zarf used to build this package"`
+	Terminal     string         `json:"terminal" jsonschema:"description=The machine name that created this package"`
+	User         string         `json:"user" jsonschema:"description=The username who created this package"`
+	Architecture string         `json:"architecture" jsonschema:"description=The architecture this package was created on"`
+	Timestamp    string         `json:"timestamp" jsonschema:"description=The timestamp when this package was created"`
+	Version      string         `json:"version" jsonschema:"description=The version of Zarf used to build this package"`
+	Provenance   *UDSProvenance `json:"provenance,omitempty" jsonschema:"description=How the bundle was produced (only recorded when the bundle is created with --provenance)"`
+}
+
+// UDSProvenance records how a bundle was produced so it can be audited, it's also written to the bundle's manifest config
+type UDSProvenance struct {
+	CLIVersion string              `json:"cliVersion" jsonschema:"description=The version of UDS CLI that created the bundle"`
+	Timestamp  string              `json:"timestamp" jsonschema:"description=When the bundle was created in RFC 3339 format (taken from SOURCE_DATE_EPOCH if it's set)"`
+	GitCommit  string              `json:"gitCommit,omitempty" jsonschema:"description=The git commit of the repository containing the bundle definition"`
+	Packages   []PackageProvenance `json:"packages" jsonschema:"description=The resolved digest of each Zarf package in the bundle"`
+}
+
+// PackageProvenance is the source of a Zarf package in a bundle
+type PackageProvenance struct {
+	Name       string `json:"name" jsonschema:"description=Name of the Zarf package"`
+	Repository string `json:"repository,omitempty" jsonschema:"description=The repository the package was imported from"`
+	Path       string `json:"path,omitempty" jsonschema:"description=The local path the package was imported from"`
+	Digest     string `json:"digest" jsonschema:"description=The digest of the package's manifest or of the tarball for a local package"`
+}
+
+// BundlePackageDigest is a Zarf package in a bundle as listed in the bundle's root manifest annotations
+type BundlePackageDigest struct {
+	Name   string `json:"name"`
+	Ref    string `json:"ref"`
+	Digest string `json:"digest"`
+}
+
+// BundleDeployRecord is a deploy of a bundle recorded in the cluster, the record of the previous deploy is kept so the
+// bundle can be rolled back to it
+type BundleDeployRecord struct {
+	Name      string                `json:"name"`
+	Version   string                `json:"version"`
+	Source    string                `json:"source"`
+	Status    string                `json:"status"`
+	Timestamp string                `json:"timestamp"`
+	Packages  []DeployRecordPackage `json:"packages"`
+}
+
+// DeployRecordPackage is a Zarf package deployed by a bundle
+type DeployRecordPackage struct {
+	Name    string `json:"name"`
+	Ref     string `json:"ref"`
+	Version string `json:"version,omitempty"`
 }
@@ -4,23 +4,75 @@
 // Package types contains all the types used by UDS.
 package types
 
+import "time"
+
 // BundleConfig is the main struct that the bundler uses to hold high-level options.
 type BundleConfig struct {
-	CreateOpts  BundleCreateOptions
-	DeployOpts  BundleDeployOptions
-	PublishOpts BundlePublishOptions
-	PullOpts    BundlePullOptions
-	InspectOpts BundleInspectOptions
-	RemoveOpts  BundleRemoveOptions
+	CreateOpts   BundleCreateOptions
+	DeployOpts   BundleDeployOptions
+	PublishOpts  BundlePublishOptions
+	CopyOpts     BundleCopyOptions
+	PullOpts     BundlePullOptions
+	InspectOpts  BundleInspectOptions
+	RemoveOpts   BundleRemoveOptions
+	DiffOpts     BundleDiffOptions
+	VerifyOpts   BundleVerifyOptions
+	ResignOpts   BundleResignOptions
+	RetagOpts    BundleRetagOptions
+	LintOpts     BundleLintOptions
+	VersionsOpts BundleVersionsOptions
+	RollbackOpts BundleRollbackOptions
 }
 
 // BundleCreateOptions is the options for the bundler.Create() function
 type BundleCreateOptions struct {
-	SourceDirectory    string
-	Output             string
-	SigningKeyPath     string
-	SigningKeyPassword string
-	BundleFile         string
+	SourceDirectory     string
+	Outputs             []string
+	SigningKeyPath      string
+	SigningKeyPassword  string
+	VerifySignatureKey  string
+	BundleFile          string
+	MaxConcurrency      int
+	DryRun              bool
+	VerifySourceKeys    []string
+	OutputFormat        string
+	OutputDigestOnly    bool
+	SignatureReferrer   bool
+	DetachedSignature   bool
+	SBOMFormat          string
+	SBOMReferrers       bool
+	SignKeyless         bool
+	Platform            string
+	PlatformFromPackage bool
+	SrcCreds            string
+	SourceMirrors       []string
+	RegistryOverrides   map[string]string
+	DstCreds            string
+	RequireSignature    bool
+	RequireDigests      bool
+	AllowDuplicateNames bool
+	AllowedMediaTypes   []string
+	VulnDenyList        string
+	FailOnVuln          bool
+	UncompressedSize    string
+	PruneOldVersions    bool
+	PruneKeep           int
+	Quiet               bool
+	Provenance          bool
+	NoSignaturePrompt   bool
+	NoCache             bool
+	MetadataMediaType   string
+	ConfigMediaType     string
+	Force               bool
+	MetricsFile         string
+	DigestFile          string
+	Registry            string
+	DigestTag           string
+	CleanupOnFailure    bool
+	CompressionLevel    int
+	LayerConcurrency    int
+	ConcurrencyLimit    int
+	Timeout             time.Duration
 }
 
 // BundleDeployOptions is the options for the bundler.Deploy() function
@@ -28,20 +80,83 @@ type BundleDeployOptions struct {
 	Resume        bool
 	Source        string
 	Packages      []string
+	SkipPackages  []string
 	PublicKeyPath string
 	SetVariables  map[string]string `json:"setVariables" jsonschema:"description=Key-Value map of variable names and their corresponding values that will be used by Zarf packages in a bundle"`
 	// Variables and SharedVariables are read in from uds-config.yaml
 	Variables       map[string]map[string]interface{} `yaml:"variables,omitempty"`
 	SharedVariables map[string]interface{}            `yaml:"shared,omitempty"`
-	Retries         int                               `yaml:"retries"`
+	// VarsFiles maps Zarf pkg names to a variables file, the files are read into FileVariables when the bundle is deployed
+	VarsFiles     map[string]string                 `yaml:"-"`
+	FileVariables map[string]map[string]interface{} `yaml:"-"`
+	// SetValues are the Helm values set with --set for a pkg's component (ex. packageName.componentName.path), they're
+	// mapped to Zarf pkgs -> components -> charts when the bundle is deployed
+	SetValues map[string]map[string]map[string][]BundleChartValue `yaml:"-"`
+	Retries   int                                                 `yaml:"retries"`
+	// DryRun prints the deploy plan instead of deploying, as JSON if JSON is set
+	DryRun bool `yaml:"-"`
+	JSON   bool `yaml:"-"`
+}
+
+// BundleDiffOptions is the options for the bundler.Diff() function
+type BundleDiffOptions struct {
+	From string
+	To   string
+	JSON bool
+}
+
+// BundleLintOptions is the options for the bundler.Lint() function
+type BundleLintOptions struct {
+	Source string
+}
+
+// BundleVersionsOptions is the options for the bundler.Versions() function
+type BundleVersionsOptions struct {
+	Source string
+	JSON   bool
+}
+
+// BundleRollbackOptions is the options for the bundler.Rollback() function, the rollback is deployed with DeployOpts
+type BundleRollbackOptions struct {
+	BundleName string
+}
+
+// BundleVerifyOptions is the options for the bundler.Verify() function
+type BundleVerifyOptions struct {
+	Source        string
+	PublicKeyPath string
+}
+
+// BundleResignOptions is the options for the bundler.Resign() function
+type BundleResignOptions struct {
+	Source             string
+	SigningKeyPath     string
+	SigningKeyPassword string
+	SignKeyless        bool
+	PublicKeyPath      string
+	// SignaturePath is a signature of the published YAML made outside of UDS CLI, it's attached instead of signing
+	SignaturePath string
+}
+
+// BundleRetagOptions is the options for the bundle.Retag() function
+type BundleRetagOptions struct {
+	Source string
+	Tag    string
+	Force  bool
 }
 
 // BundleInspectOptions is the options for the bundler.Inspect() function
 type BundleInspectOptions struct {
-	PublicKeyPath string
-	Source        string
-	IncludeSBOM   bool
-	ExtractSBOM   bool
+	PublicKeyPath  string
+	PublicKeyPaths []string
+	VerifyAll      bool
+	Source         string
+	IncludeSBOM    bool
+	ExtractSBOM    bool
+	ListImages     bool
+	ShowSignature  bool
+	JSON           bool
+	ExtractLayers  []string
 }
 
 // BundlePublishOptions is the options for the bundle.Publish() function
@@ -50,11 +165,19 @@ type BundlePublishOptions struct {
 	Destination string
 }
 
+// BundleCopyOptions is the options for the bundle.Copy() function
+type BundleCopyOptions struct {
+	Source      string
+	Destination string
+}
+
 // BundlePullOptions is the options for the bundler.Pull() function
 type BundlePullOptions struct {
 	OutputDirectory string
 	PublicKeyPath   string
 	Source          string
+	Packages        []string
+	Since           string
 }
 
 // BundleRemoveOptions is the options for the bundler.Remove() function
@@ -70,7 +193,11 @@ type BundleCommonOptions struct {
 	CachePath      string `json:"cachePath" jsonschema:"description=Path to use to cache images and git repos on package create"`
 	TempDirectory  string `json:"tempDirectory" jsonschema:"description=Location Zarf should use as a staging ground when managing files and images for package creation and deployment"`
 	OCIConcurrency int    `jsonschema:"description=Number of concurrent layer operations to perform when interacting with a remote package"`
+	OCIRetries     int    `jsonschema:"description=Number of attempts to make for OCI operations that fail with a retriable error"`
 	NoTea          bool   `json:"useTea" jsonschema:"description=Don't use BubbleTea TUI"`
+	CACert         string `jsonschema:"description=Path to a CA certificate to trust when connecting to OCI registries"`
+	// InsecureSkipTLSVerify skips verifying registries' TLS certificates, unlike Insecure it doesn't allow plain HTTP
+	InsecureSkipTLSVerify bool `jsonschema:"description=Skip verifying the TLS certificates of OCI registries"`
 }
 
 // PathMap is a map of either absolute paths to relative paths or relative paths to absolute paths